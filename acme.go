@@ -0,0 +1,177 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/georgejenkins/jwt/jwk"
+)
+
+// ACMEContentType is the media type of ACME request bodies.
+const ACMEContentType = "application/jose+json"
+
+// ACMERequest is an ACME request body: a JWS in the flattened JSON
+// serialization (RFC 7515, section 7.2.2).
+type ACMERequest struct {
+	Protected string `json:"protected"`
+	Payload   string `json:"payload"`
+	Signature string `json:"signature"`
+}
+
+// acmeHeader is the protected header of an ACME request. Exactly one of
+// JWK and KeyID is set.
+type acmeHeader struct {
+	Algorithm string          `json:"alg"`
+	JWK       json.RawMessage `json:"jwk,omitempty"`
+	KeyID     string          `json:"kid,omitempty"`
+	Nonce     string          `json:"nonce"`
+	URL       string          `json:"url"`
+}
+
+// ACMESigner signs ACME requests (RFC 8555, section 6.2) with an account
+// key. Until the account URL is set, requests identify the key with a
+// 'jwk' header, as newAccount and revokeCert requests signed by the
+// certificate key require; afterwards with a 'kid' header holding the
+// account URL. Every request carries a fresh nonce from the NonceSource,
+// usually a NoncePool observing the server's responses, and the request
+// URL.
+type ACMESigner struct {
+	sv     *JOSESignerVerifier
+	jwk    json.RawMessage
+	nonces NonceSource
+
+	mu         sync.Mutex
+	accountURL string
+}
+
+// NewACMESigner creates an ACMESigner for the account key, which must be
+// an RSA, ECDSA or Ed25519 private key; ACME doesn't allow MAC algorithms.
+func NewACMESigner(alg Algorithm, accountKey crypto.Signer, nonces NonceSource) (*ACMESigner, error) {
+	if nil == accountKey || nil == nonces {
+		return nil, errors.New("ACME signer requires an account key and a nonce source")
+	}
+
+	var key interface{} = accountKey
+	if edKey, ok := accountKey.(ed25519.PrivateKey); ok {
+		key = &edKey
+	}
+
+	sv, err := NewJOSESignerVerifier(alg, key)
+	if nil != err {
+		return nil, err
+	}
+
+	publicJWK, err := json.Marshal(&jwk.Key{Key: accountKey.Public()})
+	if nil != err {
+		return nil, err
+	}
+
+	return &ACMESigner{sv: sv, jwk: publicJWK, nonces: nonces}, nil
+}
+
+// SetAccountURL sets the account URL returned by the server on account
+// creation, used as the 'kid' of subsequent requests.
+func (s *ACMESigner) SetAccountURL(accountURL string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.accountURL = accountURL
+}
+
+// SignRequest signs a request to the URL. The payload is encoded as JSON;
+// a nil payload signs a POST-as-GET request, whose payload is empty.
+func (s *ACMESigner) SignRequest(url string, payload interface{}) (*ACMERequest, error) {
+	if url == "" {
+		return nil, errors.New("ACME requests require a URL")
+	}
+
+	var encodedPayload string
+	if nil != payload {
+		jsonPayload, err := json.Marshal(payload)
+		if nil != err {
+			return nil, err
+		}
+		encodedPayload = Base64URLEncode(jsonPayload)
+	}
+
+	nonce, err := s.nonces.Nonce()
+	if nil != err {
+		return nil, err
+	}
+	if !validNonceSyntax(nonce) {
+		return nil, fmt.Errorf("Invalid nonce %q", nonce)
+	}
+
+	header := acmeHeader{
+		Algorithm: string(s.sv.algorithm),
+		Nonce:     nonce,
+		URL:       url,
+	}
+	s.mu.Lock()
+	if s.accountURL != "" {
+		header.KeyID = s.accountURL
+	} else {
+		header.JWK = s.jwk
+	}
+	s.mu.Unlock()
+
+	jsonHeader, err := json.Marshal(header)
+	if nil != err {
+		return nil, err
+	}
+	encodedHeader := Base64URLEncode(jsonHeader)
+
+	signature, err := s.sv.sign(appendWithDot(encodedHeader, encodedPayload))
+	if nil != err {
+		return nil, err
+	}
+
+	return &ACMERequest{
+		Protected: encodedHeader,
+		Payload:   encodedPayload,
+		Signature: Base64URLEncode(signature),
+	}, nil
+}
+
+// NewRequest returns a signed POST request to the URL, see SignRequest.
+func (s *ACMESigner) NewRequest(url string, payload interface{}) (*http.Request, error) {
+	signed, err := s.SignRequest(url, payload)
+	if nil != err {
+		return nil, err
+	}
+
+	body, err := json.Marshal(signed)
+	if nil != err {
+		return nil, err
+	}
+
+	request, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if nil != err {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", ACMEContentType)
+
+	return request, nil
+}
+
+// sign signs a JWS signing input, the encoded header and payload joined
+// by a '.'. A panic while signing is returned as an InternalError.
+func (sv *JOSESignerVerifier) sign(signingInput []byte) (signature []byte, err error) {
+	defer recoverInternal("Sign", &err)
+
+	if nil == sv.signer {
+		return nil, errors.New("JOSESignerVerifier not configured for signing - did you provide the correct key type?")
+	}
+
+	if err := sv.checkAlgorithm(sv.algorithm, true); nil != err {
+		return nil, err
+	}
+
+	return sv.signer.Sign(signingInput)
+}
@@ -0,0 +1,116 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/georgejenkins/jwt/jwk"
+)
+
+func TestNewACMESigner(t *testing.T) {
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	nonces := NonceSourceFunc(func() (string, error) { return "bm9uY2U", nil })
+
+	tests := []struct {
+		name    string
+		alg     Algorithm
+		key     crypto.Signer
+		nonces  NonceSource
+		wantErr bool
+	}{
+		{"Must create a signer with an ECDSA key", ES256, mustGenerateP256(), nonces, false},
+		{"Must create a signer with an RSA key", RS256, rsaKey, nonces, false},
+		{"Must create a signer with an Ed25519 key", EdDSA, edKey, nonces, false},
+		{"Must fail a key for another algorithm", RS256, mustGenerateP256(), nonces, true},
+		{"Must fail without a key", ES256, nil, nonces, true},
+		{"Must fail without a nonce source", ES256, mustGenerateP256(), nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewACMESigner(tt.alg, tt.key, tt.nonces)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewACMESigner() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestACMESigner_SignRequest(t *testing.T) {
+	accountKey := mustGenerateP256()
+	verifier, _ := NewJOSESignerVerifier(ES256, &accountKey.PublicKey)
+	issued := 0
+	signer, _ := NewACMESigner(ES256, accountKey, NonceSourceFunc(func() (string, error) {
+		issued++
+		return "bm9uY2U" + string(rune('A'+issued)), nil
+	}))
+
+	type protected struct {
+		Algorithm string          `json:"alg"`
+		JWK       json.RawMessage `json:"jwk"`
+		KeyID     string          `json:"kid"`
+		Nonce     string          `json:"nonce"`
+		URL       string          `json:"url"`
+	}
+	verify := func(request *ACMERequest) protected {
+		signature, _ := Base64URLDecode(request.Signature)
+		valid, err := verifier.verifier.Verify(appendWithDot(request.Protected, request.Payload), signature)
+		if !valid || nil != err {
+			t.Fatalf("ACME request signature = %v, %v", valid, err)
+		}
+
+		decoded, _ := Base64URLDecode(request.Protected)
+		var header protected
+		json.Unmarshal(decoded, &header)
+		return header
+	}
+
+	newAccount, err := signer.SignRequest("https://acme.example/new-account", map[string]bool{"termsOfServiceAgreed": true})
+	if nil != err {
+		t.Fatalf("ACMESigner.SignRequest() error = %v", err)
+	}
+	header := verify(newAccount)
+	if header.Algorithm != "ES256" || header.URL != "https://acme.example/new-account" || header.Nonce != "bm9uY2UB" || header.KeyID != "" {
+		t.Errorf("ACMESigner.SignRequest() header = %+v", header)
+	}
+	key, err := jwk.Parse(header.JWK)
+	if nil != err {
+		t.Fatalf("jwk.Parse() error = %v", err)
+	}
+	if !keysEqual(key, &accountKey.PublicKey) {
+		t.Errorf("ACMESigner.SignRequest() jwk = %s, want the public account key", header.JWK)
+	}
+	if payload, _ := Base64URLDecode(newAccount.Payload); string(payload) != `{"termsOfServiceAgreed":true}` {
+		t.Errorf("ACMESigner.SignRequest() payload = %s", payload)
+	}
+
+	signer.SetAccountURL("https://acme.example/acct/1")
+	postAsGet, _ := signer.SignRequest("https://acme.example/order/1", nil)
+	header = verify(postAsGet)
+	if header.KeyID != "https://acme.example/acct/1" || nil != header.JWK || header.Nonce != "bm9uY2UC" {
+		t.Errorf("ACMESigner.SignRequest() header = %+v", header)
+	}
+	if postAsGet.Payload != "" {
+		t.Errorf("ACMESigner.SignRequest() payload = %q for a POST-as-GET request, want empty", postAsGet.Payload)
+	}
+
+	if _, err := signer.SignRequest("", nil); nil == err {
+		t.Errorf("ACMESigner.SignRequest() expected error without a URL")
+	}
+
+	request, err := signer.NewRequest("https://acme.example/order/1", nil)
+	if nil != err {
+		t.Fatalf("ACMESigner.NewRequest() error = %v", err)
+	}
+	body, _ := ioutil.ReadAll(request.Body)
+	var signed ACMERequest
+	if err := json.Unmarshal(body, &signed); nil != err || request.Header.Get("Content-Type") != ACMEContentType {
+		t.Errorf("ACMESigner.NewRequest() = %s %q, %v", body, request.Header.Get("Content-Type"), err)
+	}
+	verify(&signed)
+}
@@ -0,0 +1,123 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Policy describes the behaviour configured on a JOSESignerVerifier.
+type Policy struct {
+	Algorithm            Algorithm         `json:"alg"`
+	CanSign              bool              `json:"can_sign"`
+	CanonicalClaims      bool              `json:"canonical_claims,omitempty"`
+	TTLBudgets           map[string]string `json:"ttl_budgets,omitempty"`
+	ClampTTL             bool              `json:"clamp_ttl,omitempty"`
+	ClaimLimits          ClaimLimits       `json:"claim_limits"`
+	PseudonymousSubjects bool              `json:"pseudonymous_subjects,omitempty"`
+	DeprecatedAlgorithms []Algorithm       `json:"deprecated_algs,omitempty"`
+	DeniedAlgorithms     []Algorithm       `json:"denied_algs,omitempty"`
+	AllowedAlgorithms    []Algorithm       `json:"allowed_algs,omitempty"`
+	AllowNone            bool              `json:"allow_none,omitempty"`
+	RequiredTypes        []string          `json:"required_types,omitempty"`
+}
+
+// Policy returns the algorithm and options in effect.
+func (sv *JOSESignerVerifier) Policy() Policy {
+	policy := Policy{
+		Algorithm:            sv.algorithm,
+		CanSign:              nil != sv.signer,
+		CanonicalClaims:      sv.canonicalClaims,
+		ClampTTL:             sv.clampTTL,
+		ClaimLimits:          sv.claimLimits,
+		PseudonymousSubjects: nil != sv.subjectMapper,
+		DeprecatedAlgorithms: sv.algorithmsWithStatus(AlgorithmWarn),
+		DeniedAlgorithms:     sv.algorithmsWithStatus(AlgorithmDeny),
+		AllowedAlgorithms:    sv.allowedAlgorithmList(),
+		AllowNone:            sv.allowNone,
+		RequiredTypes:        sv.requiredTypes,
+	}
+
+	if len(sv.ttlBudgets) > 0 {
+		policy.TTLBudgets = make(map[string]string, len(sv.ttlBudgets))
+		for tokenType, budget := range sv.ttlBudgets {
+			policy.TTLBudgets[tokenType] = fmt.Sprintf("[%v, %v]", budget.Min, budget.Max)
+		}
+	}
+
+	return policy
+}
+
+// AdminConfig configures the admin handler.
+type AdminConfig struct {
+	// Verifier and Criteria authenticate the bearer tokens of admin
+	// requests. They should be separate from those of the tokens served.
+	Verifier *JOSESignerVerifier
+	Criteria *ValidationClaims
+
+	// Policies are the JOSESignerVerifiers to describe, by name.
+	Policies map[string]*JOSESignerVerifier
+
+	// JWKS are the JWKS fetchers to report the keys and cache state of.
+	JWKS []*JWKSFetcher
+}
+
+// AdminStatus is the document served by the admin handler.
+type AdminStatus struct {
+	Policies             map[string]Policy            `json:"policies"`
+	JWKS                 []JWKSStats                  `json:"jwks"`
+	VerificationFailures map[string]uint64            `json:"recent_verification_failures"`
+	ClockSkewFailures    ClockSkewCounts              `json:"clock_skew_failures"`
+	Algorithms           map[Algorithm]AlgorithmUsage `json:"algorithms"`
+}
+
+// NewAdminHandler returns an http.Handler serving an AdminStatus document,
+// so operators can inspect the keys, policies and failures of a running
+// service. Requests must carry a bearer token accepted by the configured
+// Verifier and Criteria.
+func NewAdminHandler(config AdminConfig) (http.Handler, error) {
+	if nil == config.Verifier || nil == config.Criteria {
+		return nil, errors.New("Admin handler requires a verifier and validation criteria")
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		const prefix = "Bearer "
+		authorization := r.Header.Get("Authorization")
+		if !strings.HasPrefix(authorization, prefix) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		_, valid, err := config.Verifier.VerifyToken([]byte(authorization[len(prefix):]), config.Criteria)
+		if nil != err || !valid {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		status := AdminStatus{
+			Policies:             make(map[string]Policy, len(config.Policies)),
+			JWKS:                 make([]JWKSStats, 0, len(config.JWKS)),
+			VerificationFailures: RecentVerificationFailures(),
+			ClockSkewFailures:    ClockSkewFailures(),
+			Algorithms:           AlgorithmMetrics(),
+		}
+		for name, sv := range config.Policies {
+			status.Policies[name] = sv.Policy()
+		}
+		for _, fetcher := range config.JWKS {
+			status.JWKS = append(status.JWKS, fetcher.Stats())
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		json.NewEncoder(w).Encode(status)
+	}), nil
+}
@@ -0,0 +1,84 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewAdminHandler(t *testing.T) {
+	jwksServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(exampleJWKS))
+	}))
+	defer jwksServer.Close()
+	fetcher, _ := NewJWKSFetcher(jwksServer.URL, time.Hour, jwksServer.Client())
+	fetcher.Keys()
+
+	service, _ := NewJOSESignerVerifier(HS256, exampleKey, WithTTLBudget("", TTLBudget{Max: time.Hour}))
+	admin, _ := NewJOSESignerVerifier(HS512, bytes.Repeat([]byte{7}, 64))
+	criteria := &ValidationClaims{Issuer: []string{"ops"}, Subject: []string{"operator"}, Audience: []string{"admin"}}
+
+	handler, err := NewAdminHandler(AdminConfig{
+		Verifier: admin,
+		Criteria: criteria,
+		Policies: map[string]*JOSESignerVerifier{"service": service},
+		JWKS:     []*JWKSFetcher{fetcher},
+	})
+	if nil != err {
+		t.Fatalf("NewAdminHandler() error = %v", err)
+	}
+
+	// Record a verification failure to report.
+	service.VerifyToken([]byte("not a token"), nil)
+
+	adminClaims := Claims{Issuer: "ops", Subject: "operator", Audience: "admin"}
+	adminToken, _ := admin.GenerateToken(Header{Algorithm: string(HS512)}, adminClaims)
+	serviceClaims := adminClaims
+	serviceClaims.Expiration = NewNumericDate(time.Now().Add(time.Minute))
+	serviceToken, err := service.GenerateToken(Header{Algorithm: string(HS256)}, serviceClaims)
+	if nil != err {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+		want          int
+	}{
+		{"Must serve the status to an operator", "Bearer " + string(adminToken), http.StatusOK},
+		{"Must not serve the status with a token of the service", "Bearer " + string(serviceToken), http.StatusUnauthorized},
+		{"Must not serve the status without a token", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/admin", nil)
+			request.Header.Set("Authorization", tt.authorization)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.want {
+				t.Fatalf("AdminHandler status = %v, want %v", recorder.Code, tt.want)
+			}
+			if tt.want != http.StatusOK {
+				return
+			}
+
+			var status AdminStatus
+			if err := json.Unmarshal(recorder.Body.Bytes(), &status); nil != err {
+				t.Fatalf("AdminHandler response error = %v", err)
+			}
+			if policy := status.Policies["service"]; policy.Algorithm != HS256 || policy.TTLBudgets[""] != "[0s, 1h0m0s]" {
+				t.Errorf("AdminHandler policy = %+v", policy)
+			}
+			if len(status.JWKS) != 1 || len(status.JWKS[0].KeyIDs) != 1 || !status.JWKS[0].Fresh {
+				t.Errorf("AdminHandler JWKS = %+v", status.JWKS)
+			}
+			if status.VerificationFailures[FailureMalformed] == 0 {
+				t.Errorf("AdminHandler verification failures = %v, want a malformed token", status.VerificationFailures)
+			}
+		})
+	}
+}
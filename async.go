@@ -0,0 +1,129 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+)
+
+// ErrAsyncVerifierClosed is returned for verifications requested after
+// the AsyncVerifier has been closed.
+var ErrAsyncVerifierClosed = errors.New("AsyncVerifier is closed")
+
+// VerificationResult is the outcome of a token verification.
+type VerificationResult struct {
+	Token *Token
+	Valid bool
+	Err   error
+
+	// Provenance of the key that verified the token, if any.
+	Provenance *Provenance
+}
+
+// AsyncVerifier verifies tokens on a pool of worker goroutines, allowing
+// event-driven consumers to overlap token verification with other work.
+type AsyncVerifier struct {
+	sv                 *JOSESignerVerifier
+	validationCriteria *ValidationClaims
+
+	jobs    chan asyncVerification
+	workers sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+}
+
+type asyncVerification struct {
+	ctx      context.Context
+	rawToken []byte
+	result   chan VerificationResult
+}
+
+// NewAsyncVerifier starts an AsyncVerifier with the given number of
+// workers, verifying tokens with VerifyToken against the validation
+// criteria. If workers is not positive, one worker per CPU is started.
+//
+// Close must be called to stop the workers.
+func NewAsyncVerifier(sv *JOSESignerVerifier, validationCriteria *ValidationClaims, workers int) (*AsyncVerifier, error) {
+	if nil == sv {
+		return nil, errors.New("Cannot init AsyncVerifier without a JOSESignerVerifier")
+	}
+
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	av := &AsyncVerifier{
+		sv:                 sv,
+		validationCriteria: validationCriteria,
+		jobs:               make(chan asyncVerification, workers),
+	}
+
+	av.workers.Add(workers)
+	for i := 0; i < workers; i++ {
+		go av.work()
+	}
+
+	return av, nil
+}
+
+// VerifyAsync queues a token for verification and returns a channel that
+// receives exactly one VerificationResult before being closed.
+//
+// VerifyAsync blocks while the work queue is full, applying back-pressure
+// to the caller, until a worker is free or the context is done. If the
+// context is done before verification starts, the result carries the
+// context's error.
+func (av *AsyncVerifier) VerifyAsync(ctx context.Context, rawToken []byte) <-chan VerificationResult {
+	result := make(chan VerificationResult, 1)
+
+	av.mu.RLock()
+	defer av.mu.RUnlock()
+
+	if av.closed {
+		result <- VerificationResult{Err: ErrAsyncVerifierClosed}
+		close(result)
+		return result
+	}
+
+	select {
+	case av.jobs <- asyncVerification{ctx: ctx, rawToken: rawToken, result: result}:
+	case <-ctx.Done():
+		result <- VerificationResult{Err: ctx.Err()}
+		close(result)
+	}
+
+	return result
+}
+
+// Close stops accepting new verifications and waits for the workers to
+// finish all queued verifications.
+func (av *AsyncVerifier) Close() {
+	av.mu.Lock()
+	if av.closed {
+		av.mu.Unlock()
+		return
+	}
+	av.closed = true
+	close(av.jobs)
+	av.mu.Unlock()
+
+	av.workers.Wait()
+}
+
+func (av *AsyncVerifier) work() {
+	defer av.workers.Done()
+
+	for job := range av.jobs {
+		if err := job.ctx.Err(); nil != err {
+			job.result <- VerificationResult{Err: err}
+			close(job.result)
+			continue
+		}
+
+		token, valid, err := av.sv.VerifyToken(job.rawToken, av.validationCriteria)
+		job.result <- newVerificationResult(token, valid, err)
+		close(job.result)
+	}
+}
@@ -0,0 +1,57 @@
+package jwt
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAsyncVerifier_VerifyAsync(t *testing.T) {
+	sv, err := NewJOSESignerVerifier(HS256, exampleKey)
+	if nil != err {
+		t.Fatalf("NewJOSESignerVerifier() error = %v", err)
+	}
+
+	validToken, err := sv.GenerateToken(Header{Algorithm: string(HS256)}, Claims{Issuer: "vesemir"})
+	if nil != err {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+	invalidToken := append(append([]byte{}, validToken[:len(validToken)-2]...), "AA"...)
+
+	av, err := NewAsyncVerifier(sv, &ValidationClaims{Issuer: []string{"vesemir"}}, 2)
+	if nil != err {
+		t.Fatalf("NewAsyncVerifier() error = %v", err)
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		rawToken  []byte
+		wantValid bool
+		wantErr   bool
+	}{
+		{"Must verify a valid token", context.Background(), validToken, true, false},
+		{"Must not verify a token with an invalid signature", context.Background(), invalidToken, false, false},
+		{"Must return the context error given a cancelled context", cancelled, validToken, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := <-av.VerifyAsync(tt.ctx, tt.rawToken)
+			if (result.Err != nil) != tt.wantErr {
+				t.Errorf("VerifyAsync() error = %v, wantErr %v", result.Err, tt.wantErr)
+				return
+			}
+			if result.Valid != tt.wantValid {
+				t.Errorf("VerifyAsync() valid = %v, want %v", result.Valid, tt.wantValid)
+			}
+		})
+	}
+
+	av.Close()
+
+	if result := <-av.VerifyAsync(context.Background(), validToken); result.Err != ErrAsyncVerifierClosed {
+		t.Errorf("VerifyAsync() after Close error = %v, want %v", result.Err, ErrAsyncVerifierClosed)
+	}
+}
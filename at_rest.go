@@ -0,0 +1,74 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/georgejenkins/jwt/jwe"
+	"github.com/georgejenkins/jwt/jwk"
+)
+
+// StoredTokenContentType is the JWE content type of encrypted stored tokens.
+const StoredTokenContentType = "JWT"
+
+// StoredTokenCipher encrypts tokens for storage, such as persisted refresh
+// or action tokens, as a JWE encrypted directly with a data key
+// ("alg":"dir", "enc":"A256GCM"). Data keys are oct JWKs with a key ID,
+// loaded with the jwk package. Tokens are sealed with the current data key
+// and opened with whichever data key their 'kid' names, so data keys can be
+// rotated without re-encrypting stored tokens.
+type StoredTokenCipher struct {
+	current string
+	keys    map[string][]byte
+}
+
+// NewStoredTokenCipher creates a StoredTokenCipher sealing with current,
+// and opening tokens sealed with current or any of the previous keys.
+func NewStoredTokenCipher(current *jwk.Key, previous ...*jwk.Key) (*StoredTokenCipher, error) {
+	if nil == current {
+		return nil, errors.New("Stored token cipher requires a current data key")
+	}
+
+	c := &StoredTokenCipher{
+		current: current.KeyID,
+		keys:    make(map[string][]byte),
+	}
+	for _, key := range append([]*jwk.Key{current}, previous...) {
+		secret, ok := key.Key.([]byte)
+		if !ok || len(secret) != 32 {
+			return nil, fmt.Errorf("Data key %q must be a 256-bit oct key", key.KeyID)
+		}
+		if key.KeyID == "" {
+			return nil, errors.New("Data keys must have a key ID")
+		}
+		if _, duplicate := c.keys[key.KeyID]; duplicate {
+			return nil, fmt.Errorf("Duplicate data key ID %q", key.KeyID)
+		}
+		c.keys[key.KeyID] = secret
+	}
+
+	return c, nil
+}
+
+// Seal encrypts a token for storage with the current data key.
+func (c *StoredTokenCipher) Seal(token []byte) ([]byte, error) {
+	return jwe.EncryptDirect(token, c.keys[c.current], jwe.Header{
+		KeyID:       c.current,
+		ContentType: StoredTokenContentType,
+	})
+}
+
+// Open decrypts a stored token sealed with any of the cipher's data keys.
+func (c *StoredTokenCipher) Open(stored []byte) ([]byte, error) {
+	parts, err := jwe.Parse(stored)
+	if nil != err {
+		return nil, err
+	}
+
+	key, ok := c.keys[parts.Header.KeyID]
+	if !ok {
+		return nil, fmt.Errorf("Unknown data key %q", parts.Header.KeyID)
+	}
+
+	return jwe.DecryptDirect(stored, key)
+}
@@ -0,0 +1,52 @@
+package jwt
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/georgejenkins/jwt/jwk"
+)
+
+func TestStoredTokenCipher(t *testing.T) {
+	oldKey := &jwk.Key{KeyID: "data-2020", Key: bytes.Repeat([]byte{1}, 32)}
+	newKey := &jwk.Key{KeyID: "data-2021", Key: bytes.Repeat([]byte{2}, 32)}
+
+	oldCipher, err := NewStoredTokenCipher(oldKey)
+	if nil != err {
+		t.Fatalf("NewStoredTokenCipher() error = %v", err)
+	}
+	rotated, err := NewStoredTokenCipher(newKey, oldKey)
+	if nil != err {
+		t.Fatalf("NewStoredTokenCipher() error = %v", err)
+	}
+
+	sv, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	token, _ := sv.GenerateToken(Header{Algorithm: string(HS256)}, Claims{Subject: "alice"})
+
+	sealedBefore, err := oldCipher.Seal(token)
+	if nil != err {
+		t.Fatalf("StoredTokenCipher.Seal() error = %v", err)
+	}
+	sealedAfter, _ := rotated.Seal(token)
+
+	for _, sealed := range [][]byte{sealedBefore, sealedAfter} {
+		if bytes.Contains(sealed, token) {
+			t.Errorf("StoredTokenCipher.Seal() did not encrypt the token")
+		}
+		opened, err := rotated.Open(sealed)
+		if nil != err || !bytes.Equal(opened, token) {
+			t.Errorf("StoredTokenCipher.Open() = %s, %v, want %s", opened, err, token)
+		}
+	}
+
+	if _, err := oldCipher.Open(sealedAfter); nil == err {
+		t.Errorf("StoredTokenCipher.Open() expected error for an unknown data key")
+	}
+
+	if _, err := NewStoredTokenCipher(&jwk.Key{KeyID: "short", Key: []byte("short")}); nil == err {
+		t.Errorf("NewStoredTokenCipher() expected error for a short data key")
+	}
+	if _, err := NewStoredTokenCipher(&jwk.Key{Key: bytes.Repeat([]byte{3}, 32)}); nil == err {
+		t.Errorf("NewStoredTokenCipher() expected error for a data key without an ID")
+	}
+}
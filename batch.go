@@ -0,0 +1,71 @@
+package jwt
+
+import (
+	"encoding/json"
+	"runtime"
+	"sync"
+)
+
+// IssueRequest is a single token to generate in a batch.
+type IssueRequest struct {
+	Claims interface{}
+}
+
+// IssueResult is the outcome of a single IssueRequest.
+type IssueResult struct {
+	Token []byte
+	Err   error
+}
+
+// IssueBatch generates a token for each request, all sharing the same
+// JOSE header, for jobs pre-minting large numbers of device or invitation
+// tokens. The header is encoded once and tokens are signed on a pool of
+// one worker per CPU.
+//
+// Results are returned in the order of the requests. A failure to
+// generate one token doesn't affect the others.
+func (sv *JOSESignerVerifier) IssueBatch(header interface{}, requests []IssueRequest) []IssueResult {
+	results := make([]IssueResult, len(requests))
+
+	joseHeader, err := json.Marshal(header)
+	if nil == err {
+		joseHeader, err = sv.populateHeader(joseHeader)
+	}
+	if nil != err {
+		for i := range results {
+			results[i].Err = err
+		}
+		return results
+	}
+	encodedHeader := Base64URLEncode(joseHeader)
+
+	workers := runtime.NumCPU()
+	if workers > len(requests) {
+		workers = len(requests)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				token, err := sv.generateToken(joseHeader, encodedHeader, requests[i].Claims)
+				results[i] = IssueResult{
+					Token: token,
+					Err:   err,
+				}
+			}
+		}()
+	}
+
+	for i := range requests {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
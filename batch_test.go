@@ -0,0 +1,54 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"fmt"
+	"testing"
+)
+
+func TestJOSESignerVerifier_IssueBatch(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	sv, err := NewJOSESignerVerifier(ES256, key)
+	if nil != err {
+		t.Fatalf("NewJOSESignerVerifier() error = %v", err)
+	}
+
+	requests := make([]IssueRequest, 50)
+	for i := range requests {
+		requests[i] = IssueRequest{Claims: Claims{Subject: fmt.Sprintf("device-%d", i)}}
+	}
+	requests[7] = IssueRequest{Claims: func() {}}
+
+	results := sv.IssueBatch(Header{Algorithm: string(ES256)}, requests)
+	if len(results) != len(requests) {
+		t.Fatalf("IssueBatch() returned %d results, want %d", len(results), len(requests))
+	}
+
+	for i, result := range results {
+		if i == 7 {
+			if nil == result.Err {
+				t.Errorf("IssueBatch() expected error for unencodable claims")
+			}
+			continue
+		}
+
+		if nil != result.Err {
+			t.Errorf("IssueBatch() result %d error = %v", i, result.Err)
+			continue
+		}
+
+		token, valid, err := sv.VerifySignature(result.Token)
+		if !valid || nil != err {
+			t.Errorf("IssueBatch() result %d does not verify: %v", i, err)
+			continue
+		}
+
+		var claims Claims
+		GetClaims(token, &claims)
+		if want := fmt.Sprintf("device-%d", i); claims.Subject != want {
+			t.Errorf("IssueBatch() result %d subject = %v, want %v", i, claims.Subject, want)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// CAEP event types (OpenID Continuous Access Evaluation Profile 1.0).
+const (
+	CAEPSessionRevoked   = "https://schemas.openid.net/secevent/caep/event-type/session-revoked"
+	CAEPCredentialChange = "https://schemas.openid.net/secevent/caep/event-type/credential-change"
+)
+
+// CAEP initiating entities.
+const (
+	CAEPInitiatedByAdmin  = "admin"
+	CAEPInitiatedByUser   = "user"
+	CAEPInitiatedByPolicy = "policy"
+	CAEPInitiatedBySystem = "system"
+)
+
+// CAEP credential change types.
+const (
+	CAEPChangeCreate = "create"
+	CAEPChangeRevoke = "revoke"
+	CAEPChangeUpdate = "update"
+	CAEPChangeDelete = "delete"
+)
+
+var caepCredentialTypes = []string{
+	"password", "pin", "x509", "fido2-platform", "fido2-roaming", "fido-u2f",
+	"verifiable-credential", "phone-voice", "phone-sms", "app",
+}
+
+// SubjectIdentifier identifies the subject of a security event (RFC 9493),
+// e.g. {"format": "email", "email": "user@example.com"}.
+type SubjectIdentifier struct {
+	Format      string `json:"format"`
+	Email       string `json:"email,omitempty"`
+	PhoneNumber string `json:"phone_number,omitempty"`
+	Issuer      string `json:"iss,omitempty"`
+	Subject     string `json:"sub,omitempty"`
+	ID          string `json:"id,omitempty"`
+	URI         string `json:"uri,omitempty"`
+}
+
+// CAEPEvent is a typed CAEP event payload.
+type CAEPEvent interface {
+	EventType() string
+	Validate() error
+}
+
+// CAEPEventMetadata holds the members common to all CAEP events.
+type CAEPEventMetadata struct {
+	EventTimestamp   int64             `json:"event_timestamp,omitempty"`
+	InitiatingEntity string            `json:"initiating_entity,omitempty"`
+	ReasonAdmin      map[string]string `json:"reason_admin,omitempty"`
+	ReasonUser       map[string]string `json:"reason_user,omitempty"`
+}
+
+func (m *CAEPEventMetadata) validate() error {
+	switch m.InitiatingEntity {
+	case "", CAEPInitiatedByAdmin, CAEPInitiatedByUser, CAEPInitiatedByPolicy, CAEPInitiatedBySystem:
+		return nil
+	}
+
+	return fmt.Errorf("Unknown CAEP initiating entity %q", m.InitiatingEntity)
+}
+
+// SessionRevokedEvent signals that sessions of the subject were revoked.
+type SessionRevokedEvent struct {
+	CAEPEventMetadata
+}
+
+// EventType returns the CAEP session revoked event type URI.
+func (e *SessionRevokedEvent) EventType() string {
+	return CAEPSessionRevoked
+}
+
+// Validate validates the event payload.
+func (e *SessionRevokedEvent) Validate() error {
+	return e.validate()
+}
+
+// CredentialChangeEvent signals that a credential of the subject was
+// created, changed, revoked or deleted.
+type CredentialChangeEvent struct {
+	CAEPEventMetadata
+	CredentialType string `json:"credential_type"`
+	ChangeType     string `json:"change_type"`
+	FriendlyName   string `json:"friendly_name,omitempty"`
+	X509Issuer     string `json:"x509_issuer,omitempty"`
+	X509Serial     string `json:"x509_serial,omitempty"`
+	FIDO2AAGUID    string `json:"fido2_aaguid,omitempty"`
+}
+
+// EventType returns the CAEP credential change event type URI.
+func (e *CredentialChangeEvent) EventType() string {
+	return CAEPCredentialChange
+}
+
+// Validate validates the event payload.
+func (e *CredentialChangeEvent) Validate() error {
+	if err := e.validate(); nil != err {
+		return err
+	}
+
+	if !anyEquals(caepCredentialTypes, e.CredentialType) {
+		return fmt.Errorf("Unknown CAEP credential type %q", e.CredentialType)
+	}
+
+	switch e.ChangeType {
+	case CAEPChangeCreate, CAEPChangeRevoke, CAEPChangeUpdate, CAEPChangeDelete:
+		return nil
+	}
+
+	return fmt.Errorf("Unknown CAEP change type %q", e.ChangeType)
+}
+
+// NewCAEPSET builds the claim set of a SET carrying a single CAEP event
+// about the subject, ready to pass to IssueSET.
+func NewCAEPSET(issuer string, audience string, subject SubjectIdentifier, event CAEPEvent) (SETClaims, error) {
+	if subject.Format == "" {
+		return SETClaims{}, errors.New("CAEP event subject must have a format")
+	}
+
+	if err := event.Validate(); nil != err {
+		return SETClaims{}, err
+	}
+
+	payload, err := json.Marshal(event)
+	if nil != err {
+		return SETClaims{}, err
+	}
+
+	return SETClaims{
+		Claims: Claims{
+			Issuer:   issuer,
+			Audience: audience,
+		},
+		SubjectID: &subject,
+		Events: map[string]json.RawMessage{
+			event.EventType(): payload,
+		},
+	}, nil
+}
+
+// CAEPEvent decodes and validates the event of the SET matching the type
+// of the event provided, e.g.
+//
+//	var revoked SessionRevokedEvent
+//	found, err := claims.CAEPEvent(&revoked)
+//
+// found is false if the SET doesn't carry an event of that type.
+func (claims *SETClaims) CAEPEvent(event CAEPEvent) (bool, error) {
+	payload, ok := claims.Events[event.EventType()]
+	if !ok {
+		return false, nil
+	}
+
+	if nil == claims.SubjectID || claims.SubjectID.Format == "" {
+		return true, errors.New("CAEP events must have a subject identifier")
+	}
+
+	if err := json.Unmarshal(payload, event); nil != err {
+		return true, err
+	}
+
+	return true, event.Validate()
+}
@@ -0,0 +1,96 @@
+package jwt
+
+import (
+	"testing"
+)
+
+func TestNewCAEPSET(t *testing.T) {
+	sv, err := NewJOSESignerVerifier(HS256, exampleKey)
+	if nil != err {
+		t.Fatalf("NewJOSESignerVerifier() error = %v", err)
+	}
+
+	subject := SubjectIdentifier{Format: "email", Email: "yennefer@vengerberg.example"}
+
+	tests := []struct {
+		name    string
+		subject SubjectIdentifier
+		event   CAEPEvent
+		wantErr bool
+	}{
+		{
+			"Must build a session revoked SET",
+			subject,
+			&SessionRevokedEvent{CAEPEventMetadata{EventTimestamp: 1615304991, InitiatingEntity: CAEPInitiatedByPolicy}},
+			false,
+		},
+		{
+			"Must build a credential change SET",
+			subject,
+			&CredentialChangeEvent{CredentialType: "fido2-roaming", ChangeType: CAEPChangeCreate, FriendlyName: "Jade YubiKey"},
+			false,
+		},
+		{
+			"Must fail given an unknown credential type",
+			subject,
+			&CredentialChangeEvent{CredentialType: "megascope", ChangeType: CAEPChangeCreate},
+			true,
+		},
+		{
+			"Must fail given an unknown initiating entity",
+			subject,
+			&SessionRevokedEvent{CAEPEventMetadata{InitiatingEntity: "sorceress"}},
+			true,
+		},
+		{
+			"Must fail given a subject without a format",
+			SubjectIdentifier{Email: "yennefer@vengerberg.example"},
+			&SessionRevokedEvent{},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := NewCAEPSET("https://idp.example.com", "https://rp.example.com", tt.subject, tt.event)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewCAEPSET() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			rawToken, err := sv.IssueSET(claims)
+			if nil != err {
+				t.Fatalf("IssueSET() error = %v", err)
+			}
+
+			_, received, err := sv.VerifySET(rawToken, &ValidationClaims{
+				Issuer:   []string{"https://idp.example.com"},
+				Audience: []string{"https://rp.example.com"},
+			})
+			if nil != err {
+				t.Fatalf("VerifySET() error = %v", err)
+			}
+
+			var revoked SessionRevokedEvent
+			var changed CredentialChangeEvent
+			foundRevoked, errRevoked := received.CAEPEvent(&revoked)
+			foundChanged, errChanged := received.CAEPEvent(&changed)
+			if nil != errRevoked || nil != errChanged {
+				t.Errorf("SETClaims.CAEPEvent() errors = %v, %v", errRevoked, errChanged)
+			}
+
+			switch tt.event.(type) {
+			case *SessionRevokedEvent:
+				if !foundRevoked || foundChanged || revoked.InitiatingEntity != CAEPInitiatedByPolicy {
+					t.Errorf("SETClaims.CAEPEvent() did not decode the session revoked event: %+v", revoked)
+				}
+			case *CredentialChangeEvent:
+				if foundRevoked || !foundChanged || changed.FriendlyName != "Jade YubiKey" {
+					t.Errorf("SETClaims.CAEPEvent() did not decode the credential change event: %+v", changed)
+				}
+			}
+		})
+	}
+}
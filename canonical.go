@@ -0,0 +1,199 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// CanonicalizeJSON returns the JSON Canonicalization Scheme (RFC 8785)
+// form of a JSON document: object members sorted by their UTF-16 encoded
+// names, no insignificant whitespace, minimal string escaping and numbers
+// serialized as ECMAScript does. Systems which independently hash claim
+// sets can reproduce the canonical bytes exactly.
+func CanonicalizeJSON(document []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); nil != err {
+		return nil, err
+	}
+
+	if decoder.More() {
+		return nil, errors.New("Unexpected data after the JSON document")
+	}
+
+	var buffer bytes.Buffer
+	if err := writeCanonicalJSON(&buffer, value); nil != err {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func writeCanonicalJSON(buffer *bytes.Buffer, value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		buffer.WriteString("null")
+	case bool:
+		buffer.WriteString(strconv.FormatBool(v))
+	case string:
+		writeCanonicalString(buffer, v)
+	case json.Number:
+		f, err := strconv.ParseFloat(string(v), 64)
+		if nil != err {
+			return fmt.Errorf("Cannot canonicalize number %s: %s", v, err)
+		}
+		number, err := formatCanonicalNumber(f)
+		if nil != err {
+			return err
+		}
+		buffer.WriteString(number)
+	case []interface{}:
+		buffer.WriteByte('[')
+		for i, member := range v {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+			if err := writeCanonicalJSON(buffer, member); nil != err {
+				return err
+			}
+		}
+		buffer.WriteByte(']')
+	case map[string]interface{}:
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Slice(names, func(i, j int) bool {
+			return lessUTF16(names[i], names[j])
+		})
+
+		buffer.WriteByte('{')
+		for i, name := range names {
+			if i > 0 {
+				buffer.WriteByte(',')
+			}
+			writeCanonicalString(buffer, name)
+			buffer.WriteByte(':')
+			if err := writeCanonicalJSON(buffer, v[name]); nil != err {
+				return err
+			}
+		}
+		buffer.WriteByte('}')
+	default:
+		return fmt.Errorf("Cannot canonicalize JSON value of type %T", value)
+	}
+
+	return nil
+}
+
+// lessUTF16 compares strings by their UTF-16 code units, as RFC 8785
+// requires for sorting object member names.
+func lessUTF16(a string, b string) bool {
+	ua := utf16.Encode([]rune(a))
+	ub := utf16.Encode([]rune(b))
+
+	for i := 0; i < len(ua) && i < len(ub); i++ {
+		if ua[i] != ub[i] {
+			return ua[i] < ub[i]
+		}
+	}
+
+	return len(ua) < len(ub)
+}
+
+// writeCanonicalString writes a string with the minimal escaping of
+// ECMAScript's JSON.stringify.
+func writeCanonicalString(buffer *bytes.Buffer, s string) {
+	buffer.WriteByte('"')
+
+	for _, r := range s {
+		switch r {
+		case '"':
+			buffer.WriteString(`\"`)
+		case '\\':
+			buffer.WriteString(`\\`)
+		case '\b':
+			buffer.WriteString(`\b`)
+		case '\f':
+			buffer.WriteString(`\f`)
+		case '\n':
+			buffer.WriteString(`\n`)
+		case '\r':
+			buffer.WriteString(`\r`)
+		case '\t':
+			buffer.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(buffer, `\u%04x`, r)
+			} else {
+				buffer.WriteRune(r)
+			}
+		}
+	}
+
+	buffer.WriteByte('"')
+}
+
+// formatCanonicalNumber serializes a number as ECMAScript's
+// Number.prototype.toString does (ECMA-262 Section 7.1.12.1).
+func formatCanonicalNumber(f float64) (string, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", errors.New("Cannot canonicalize NaN or Infinity")
+	}
+
+	if f == 0 {
+		return "0", nil
+	}
+
+	sign := ""
+	if f < 0 {
+		sign = "-"
+		f = -f
+	}
+
+	// Shortest round-tripping digits, in the form d.ddde±xx
+	exponential := strconv.FormatFloat(f, 'e', -1, 64)
+	mantissa, exponent := exponential, ""
+	if i := strings.IndexByte(exponential, 'e'); i >= 0 {
+		mantissa, exponent = exponential[:i], exponential[i+1:]
+	}
+
+	digits := strings.Replace(mantissa, ".", "", 1)
+	k := len(digits)
+	e, err := strconv.Atoi(exponent)
+	if nil != err {
+		return "", err
+	}
+	// The value is digits × 10^(n-k)
+	n := e + 1
+
+	switch {
+	case k <= n && n <= 21:
+		return sign + digits + strings.Repeat("0", n-k), nil
+	case 0 < n && n <= 21:
+		return sign + digits[:n] + "." + digits[n:], nil
+	case -6 < n && n <= 0:
+		return sign + "0." + strings.Repeat("0", -n) + digits, nil
+	}
+
+	exponentSign := "+"
+	if n-1 < 0 {
+		exponentSign = "-"
+	}
+	exponentValue := strconv.Itoa(int(math.Abs(float64(n - 1))))
+
+	if k == 1 {
+		return sign + digits + "e" + exponentSign + exponentValue, nil
+	}
+
+	return sign + digits[:1] + "." + digits[1:] + "e" + exponentSign + exponentValue, nil
+}
@@ -0,0 +1,96 @@
+package jwt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCanonicalizeJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		want     string
+		wantErr  bool
+	}{
+		{
+			// RFC 8785 Section 3.2.2
+			"Must canonicalize the RFC 8785 example",
+			`{
+				"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001],
+				"string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/",
+				"literals": [null, true, false]
+			}`,
+			`{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`,
+			false,
+		},
+		{
+			// RFC 8785 Section 3.2.3
+			"Must sort members by UTF-16 code units",
+			`{"\u20ac":"Euro Sign","\r":"Carriage Return","\ufb33":"Hebrew Letter Dalet With Dagesh","1":"One","\ud83d\ude00":"Emoji: Grinning Face","\u0080":"Control","\u00f6":"Latin Small Letter O With Diaeresis"}`,
+			"{\"\\r\":\"Carriage Return\",\"1\":\"One\",\"\u0080\":\"Control\",\"\u00f6\":\"Latin Small Letter O With Diaeresis\",\"\u20ac\":\"Euro Sign\",\"\U0001f600\":\"Emoji: Grinning Face\",\"\ufb33\":\"Hebrew Letter Dalet With Dagesh\"}",
+			false,
+		},
+		{
+			"Must serialize numbers as ECMAScript does",
+			`[0, -0, 1, -1.5, 100, 1e21, 1e20, 123456789012345680000, 0.000001, 1e-7, 5e-324, 1.7976931348623157e308, 9007199254740993]`,
+			`[0,0,1,-1.5,100,1e+21,100000000000000000000,123456789012345680000,0.000001,1e-7,5e-324,1.7976931348623157e+308,9007199254740992]`,
+			false,
+		},
+		{
+			"Must not escape HTML characters",
+			`{"html":"<a href=\"x\">&</a>"}`,
+			`{"html":"<a href=\"x\">&</a>"}`,
+			false,
+		},
+		{
+			"Must fail given trailing data",
+			`{} {}`,
+			"",
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CanonicalizeJSON([]byte(tt.document))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CanonicalizeJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("CanonicalizeJSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithCanonicalClaims(t *testing.T) {
+	sv, err := NewJOSESignerVerifier(HS256, exampleKey, WithCanonicalClaims())
+	if nil != err {
+		t.Fatalf("NewJOSESignerVerifier() error = %v", err)
+	}
+
+	rawToken, err := sv.GenerateToken(Header{Algorithm: string(HS256)}, struct {
+		Zeta  float64 `json:"zeta"`
+		Alpha string  `json:"alpha"`
+	}{4.50, "<ciri>"})
+	if nil != err {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	token, err := GetRawTokenParts(rawToken)
+	if nil != err {
+		t.Fatalf("GetRawTokenParts() error = %v", err)
+	}
+
+	if want := `{"alpha":"<ciri>","zeta":4.5}`; string(token.DecodedBody) != want {
+		t.Errorf("GenerateToken() claims = %s, want %s", token.DecodedBody, want)
+	}
+
+	if _, valid, err := sv.VerifySignature(rawToken); !valid || nil != err {
+		t.Errorf("VerifySignature() = %v, %v, want valid", valid, err)
+	}
+
+	if strings.Contains(string(rawToken), " ") {
+		t.Errorf("GenerateToken() = %s, want no whitespace", rawToken)
+	}
+}
@@ -0,0 +1,140 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/georgejenkins/jwt/jwk"
+	"github.com/georgejenkins/jwt/shamir"
+)
+
+// CeremonyKey is an issuer key generated in a key ceremony. Its private
+// key exists only as custodian shares, any threshold of which reconstruct
+// it with ReconstructCeremonyKey.
+type CeremonyKey struct {
+	// Public is the public key, for the issuer's published key set. Its
+	// key ID is its JWK thumbprint (RFC 7638).
+	Public *jwk.Key
+
+	// Shares are the custodians' shares of the private key, one each.
+	// Every share is prefixed with the key ID and a '.', so custodians can
+	// tell which key their share belongs to.
+	Shares []string
+}
+
+// GenerateCeremonyKey generates an issuer key for the algorithm, which
+// must be asymmetric, on an offline machine, splitting the PKCS#8 encoded
+// private key into shares for custodians, any threshold of whom can
+// reconstruct it for import into a KMS or HSM. The private key is not
+// returned, and is cleared from memory once split.
+func GenerateCeremonyKey(alg Algorithm, custodians int, threshold int) (*CeremonyKey, error) {
+	switch alg {
+	case None, HS256, HS384, HS512:
+		return nil, fmt.Errorf("Key ceremonies require an asymmetric algorithm, not %s", alg)
+	}
+
+	key, err := generateKey(alg)
+	if nil != err {
+		return nil, err
+	}
+	if edKey, ok := key.(*ed25519.PrivateKey); ok {
+		defer zeroBytes(*edKey)
+		key = *edKey
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if nil != err {
+		return nil, err
+	}
+	defer zeroBytes(der)
+
+	public := key.(crypto.Signer).Public()
+	kid, err := jwk.Thumbprint(public)
+	if nil != err {
+		return nil, err
+	}
+
+	shares, err := shamir.Split(der, custodians, threshold)
+	if nil != err {
+		return nil, err
+	}
+
+	ceremonyKey := &CeremonyKey{
+		Public: &jwk.Key{KeyID: kid, Algorithm: alg, Use: jwk.UseSignature, Key: nativeKey(public)},
+		Shares: make([]string, len(shares)),
+	}
+	for i, share := range shares {
+		ceremonyKey.Shares[i] = kid + "." + Base64URLEncode(share)
+		zeroBytes(share)
+	}
+
+	return ceremonyKey, nil
+}
+
+// CeremonyKeySet returns the key set to publish for the ceremony keys, for
+// example the issuer's current key and the next one it will rotate to.
+func CeremonyKeySet(keys ...*CeremonyKey) *jwk.Set {
+	set := &jwk.Set{Keys: make([]*jwk.Key, 0, len(keys))}
+	for _, key := range keys {
+		set.Keys = append(set.Keys, key.Public)
+	}
+
+	return set
+}
+
+// ReconstructCeremonyKey reconstructs a ceremony key's PKCS#8 encoded
+// private key from the shares of at least the threshold of custodians,
+// returning it with its key ID. The key is checked against the key ID of
+// the shares, so too few shares, or shares of different keys, fail rather
+// than producing the wrong key. ParseKey parses the returned key.
+func ReconstructCeremonyKey(shares []string) ([]byte, string, error) {
+	var kid string
+	decoded := make([][]byte, len(shares))
+	for i, share := range shares {
+		dot := strings.IndexByte(share, '.')
+		if dot < 0 {
+			return nil, "", errors.New("Ceremony shares must be prefixed with their key ID")
+		}
+		if i == 0 {
+			kid = share[:dot]
+		} else if share[:dot] != kid {
+			return nil, "", errors.New("Ceremony shares belong to different keys")
+		}
+
+		var err error
+		if decoded[i], err = Base64URLDecode(share[dot+1:]); nil != err {
+			return nil, "", err
+		}
+	}
+
+	der, err := shamir.Combine(decoded)
+	if nil != err {
+		return nil, "", err
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if nil != err {
+		return nil, "", errors.New("Ceremony shares do not reconstruct a key - are there enough shares?")
+	}
+
+	thumbprint, err := jwk.Thumbprint(key.(crypto.Signer).Public())
+	if nil != err {
+		return nil, "", err
+	}
+	if thumbprint != kid {
+		return nil, "", errors.New("Reconstructed key does not match the key ID of the shares")
+	}
+
+	return der, kid, nil
+}
+
+// zeroBytes clears key material from memory.
+func zeroBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,65 @@
+package jwt
+
+import (
+	"testing"
+)
+
+func TestKeyCeremony(t *testing.T) {
+	for _, alg := range []Algorithm{ES256, EdDSA, PS256} {
+		t.Run("Must reconstruct a "+string(alg)+" key from the threshold of shares", func(t *testing.T) {
+			key, err := GenerateCeremonyKey(alg, 5, 3)
+			if nil != err {
+				t.Fatalf("GenerateCeremonyKey() error = %v", err)
+			}
+			if len(key.Shares) != 5 || key.Public.Algorithm != alg {
+				t.Fatalf("GenerateCeremonyKey() = %+v", key)
+			}
+
+			der, kid, err := ReconstructCeremonyKey([]string{key.Shares[4], key.Shares[0], key.Shares[2]})
+			if nil != err || kid != key.Public.KeyID {
+				t.Fatalf("ReconstructCeremonyKey() kid = %q, error = %v", kid, err)
+			}
+
+			private, err := ParseKey(der)
+			if nil != err {
+				t.Fatalf("ParseKey() error = %v", err)
+			}
+			signer, _ := NewJOSESignerVerifier(alg, private)
+			verifier, _ := NewJOSESignerVerifier(alg, key.Public.Key)
+			token, _ := signer.GenerateToken(Header{Algorithm: string(alg)}, Claims{})
+			if _, valid, err := verifier.VerifyToken(token, nil); !valid || nil != err {
+				t.Errorf("VerifyToken() = %v, %v with the reconstructed key", valid, err)
+			}
+		})
+	}
+
+	if _, err := GenerateCeremonyKey(HS256, 5, 3); nil == err {
+		t.Errorf("GenerateCeremonyKey() expected error for a symmetric algorithm")
+	}
+
+	key, _ := GenerateCeremonyKey(ES256, 3, 2)
+	other, _ := GenerateCeremonyKey(ES256, 3, 2)
+	threshold3, _ := GenerateCeremonyKey(ES256, 3, 3)
+
+	set := CeremonyKeySet(key, other)
+	if len(set.Keys) != 2 || len(set.Find(other.Public.KeyID, ES256, "sig")) != 1 {
+		t.Errorf("CeremonyKeySet() = %+v", set.Keys)
+	}
+
+	tests := []struct {
+		name   string
+		shares []string
+	}{
+		{"Must not reconstruct from fewer than the threshold of shares", threshold3.Shares[:2]},
+		{"Must not reconstruct from shares of different keys", []string{key.Shares[0], other.Shares[1]}},
+		{"Must not reconstruct from shares relabelled with another key ID", []string{key.Shares[0], other.Public.KeyID + key.Shares[1][len(key.Public.KeyID):]}},
+		{"Must not reconstruct from shares without a key ID", []string{key.Shares[0][len(key.Public.KeyID)+1:], key.Shares[1][len(key.Public.KeyID)+1:]}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := ReconstructCeremonyKey(tt.shares); nil == err {
+				t.Errorf("ReconstructCeremonyKey() expected error")
+			}
+		})
+	}
+}
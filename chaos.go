@@ -0,0 +1,157 @@
+//go:build chaos
+// +build chaos
+
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Failure injection for chaos experiments, built only with the "chaos"
+// build tag so it can never be enabled in production builds:
+//
+//	go build -tags chaos ./...
+//
+// Without the tag, the hooks below compile to no-ops.
+
+// ErrChaosInjected is returned for verifications failed by failure
+// injection.
+var ErrChaosInjected = errors.New("Verification failure injected by chaos testing")
+
+// ChaosConfig configures the failures injected into every verifier, each
+// at a rate between 0 (never) and 1 (always).
+type ChaosConfig struct {
+	// VerificationFailureRate fails verifications with ErrChaosInjected.
+	VerificationFailureRate float64
+
+	// KeyFetchTimeoutRate fails key resolutions, of verifiers created with
+	// NewKeyResolvingVerifier, with a timeout after KeyFetchDelay.
+	KeyFetchTimeoutRate float64
+	KeyFetchDelay       time.Duration
+
+	// ClockSkewRate validates claims against a clock off by ClockSkew,
+	// which may be negative.
+	ClockSkewRate float64
+	ClockSkew     time.Duration
+
+	// Seed seeds the random source deciding which operations fail, so
+	// experiments can be repeated. Zero seeds from the current time.
+	Seed int64
+}
+
+// ChaosCounts are the numbers of failures injected.
+type ChaosCounts struct {
+	VerificationFailures int64
+	KeyFetchTimeouts     int64
+	ClockSkews           int64
+}
+
+var chaos struct {
+	mu      sync.Mutex
+	enabled bool
+	config  ChaosConfig
+	random  *rand.Rand
+	counts  ChaosCounts
+}
+
+// EnableChaos starts injecting failures as configured, replacing any
+// previous configuration and resetting the counts.
+func EnableChaos(config ChaosConfig) error {
+	for _, rate := range []float64{config.VerificationFailureRate, config.KeyFetchTimeoutRate, config.ClockSkewRate} {
+		if rate < 0 || rate > 1 {
+			return fmt.Errorf("Chaos rate %v must be between 0 and 1", rate)
+		}
+	}
+
+	seed := config.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	chaos.mu.Lock()
+	defer chaos.mu.Unlock()
+
+	chaos.enabled = true
+	chaos.config = config
+	chaos.random = rand.New(rand.NewSource(seed))
+	chaos.counts = ChaosCounts{}
+	return nil
+}
+
+// DisableChaos stops injecting failures.
+func DisableChaos() {
+	chaos.mu.Lock()
+	defer chaos.mu.Unlock()
+
+	chaos.enabled = false
+}
+
+// ChaosFailures returns the numbers of failures injected since chaos was
+// enabled.
+func ChaosFailures() ChaosCounts {
+	chaos.mu.Lock()
+	defer chaos.mu.Unlock()
+
+	return chaos.counts
+}
+
+// chaosInject reports whether to inject a failure at the rate, counting it
+// in counter if so.
+func chaosInject(rate func(ChaosConfig) float64, counter func(*ChaosCounts) *int64) (ChaosConfig, bool) {
+	chaos.mu.Lock()
+	defer chaos.mu.Unlock()
+
+	if !chaos.enabled || chaos.random.Float64() >= rate(chaos.config) {
+		return chaos.config, false
+	}
+
+	*counter(&chaos.counts)++
+	return chaos.config, true
+}
+
+// chaosVerificationFailure returns ErrChaosInjected for verifications to
+// fail.
+func chaosVerificationFailure() error {
+	_, inject := chaosInject(
+		func(c ChaosConfig) float64 { return c.VerificationFailureRate },
+		func(c *ChaosCounts) *int64 { return &c.VerificationFailures },
+	)
+	if inject {
+		return ErrChaosInjected
+	}
+
+	return nil
+}
+
+// chaosKeyFetch returns a timeout, after the configured delay, for key
+// resolutions to fail.
+func chaosKeyFetch() error {
+	config, inject := chaosInject(
+		func(c ChaosConfig) float64 { return c.KeyFetchTimeoutRate },
+		func(c *ChaosCounts) *int64 { return &c.KeyFetchTimeouts },
+	)
+	if !inject {
+		return nil
+	}
+
+	time.Sleep(config.KeyFetchDelay)
+	return fmt.Errorf("Key fetch timed out after %v, injected by chaos testing", config.KeyFetchDelay)
+}
+
+// chaosClockSkew returns the skew to add to the clock claims are
+// validated against.
+func chaosClockSkew() time.Duration {
+	config, inject := chaosInject(
+		func(c ChaosConfig) float64 { return c.ClockSkewRate },
+		func(c *ChaosCounts) *int64 { return &c.ClockSkews },
+	)
+	if !inject {
+		return 0
+	}
+
+	return config.ClockSkew
+}
@@ -0,0 +1,21 @@
+//go:build !chaos
+// +build !chaos
+
+package jwt
+
+import "time"
+
+// Failure injection is only built with the "chaos" build tag, see
+// chaos.go.
+
+func chaosVerificationFailure() error {
+	return nil
+}
+
+func chaosKeyFetch() error {
+	return nil
+}
+
+func chaosClockSkew() time.Duration {
+	return 0
+}
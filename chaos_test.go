@@ -0,0 +1,71 @@
+//go:build chaos
+// +build chaos
+
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/georgejenkins/jwt/jwk"
+)
+
+func TestEnableChaos(t *testing.T) {
+	defer DisableChaos()
+
+	if err := EnableChaos(ChaosConfig{VerificationFailureRate: 1.5}); nil == err {
+		t.Errorf("EnableChaos() expected error for a rate above 1")
+	}
+
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	signer, _ := NewJOSESignerVerifier(ES256, ecKey)
+	resolving, _ := NewKeyResolvingVerifier(KeySetResolver(&jwk.Set{Keys: []*jwk.Key{
+		{KeyID: "ec", Algorithm: ES256, Use: jwk.UseSignature, Key: &ecKey.PublicKey},
+	}}))
+	expiration := NewNumericDate(time.Now().Add(time.Hour))
+	token, _ := signer.GenerateToken(Header{Algorithm: string(ES256), KeyID: "ec"}, Claims{Subject: "alice", Expiration: expiration})
+	criteria := &ValidationClaims{Subject: []string{"alice"}}
+
+	tests := []struct {
+		name      string
+		config    ChaosConfig
+		verifier  *JOSESignerVerifier
+		wantValid bool
+		wantErr   bool
+		wantCount ChaosCounts
+	}{
+		{"Must verify without injected failures", ChaosConfig{}, resolving, true, false, ChaosCounts{}},
+		{"Must inject verification failures", ChaosConfig{VerificationFailureRate: 1}, signer, false, true, ChaosCounts{VerificationFailures: 1}},
+		{"Must inject key fetch timeouts", ChaosConfig{KeyFetchTimeoutRate: 1, KeyFetchDelay: time.Millisecond}, resolving, false, true, ChaosCounts{KeyFetchTimeouts: 1}},
+		{"Must not inject key fetch timeouts without key resolution", ChaosConfig{KeyFetchTimeoutRate: 1}, signer, true, false, ChaosCounts{}},
+		{"Must inject clock skew", ChaosConfig{ClockSkewRate: 1, ClockSkew: 2 * time.Hour}, signer, false, true, ChaosCounts{ClockSkews: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := EnableChaos(tt.config); nil != err {
+				t.Fatalf("EnableChaos() error = %v", err)
+			}
+
+			_, valid, err := tt.verifier.VerifyToken(token, criteria)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifyToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if valid != tt.wantValid {
+				t.Errorf("VerifyToken() valid = %v, want %v", valid, tt.wantValid)
+			}
+			if counts := ChaosFailures(); counts != tt.wantCount {
+				t.Errorf("ChaosFailures() = %+v, want %+v", counts, tt.wantCount)
+			}
+		})
+	}
+
+	EnableChaos(ChaosConfig{VerificationFailureRate: 1})
+	DisableChaos()
+	if _, _, err := signer.VerifySignature(token); errors.Is(err, ErrChaosInjected) {
+		t.Errorf("VerifySignature() error = %v after DisableChaos()", err)
+	}
+}
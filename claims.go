@@ -1,8 +1,7 @@
-package main
+package jwt
 
 import (
 	"encoding/json"
-	"strconv"
 	"time"
 )
 
@@ -18,20 +17,28 @@ type Claims struct {
 	Audience string `json:"aud,omitempty"`
 
 	//Expiration Time
-	Expiration string `json:"exp,omitempty"`
+	Expiration *NumericDate `json:"exp,omitempty"`
 
 	//Not Before
-	NotBefore string `json:"nbf,omitempty"`
+	NotBefore *NumericDate `json:"nbf,omitempty"`
 
 	//Issued At
-	IssuedAt string `json:"iat,omitempty"`
+	IssuedAt *NumericDate `json:"iat,omitempty"`
 
 	//JWT ID
 	JWTID string `json:"jti,omitempty"`
+
+	// AuthTime is the time the end-user authenticated, an OpenID Connect
+	// ID token claim.
+	AuthTime *NumericDate `json:"auth_time,omitempty"`
+
+	// AuthorizedParty is the client ID of the party the ID token was
+	// issued to, an OpenID Connect ID token claim.
+	AuthorizedParty string `json:"azp,omitempty"`
 }
 
 func GetClaims(token *Token, outputType interface{}) error {
-	return json.Unmarshal(token.RawBody, outputType)
+	return json.Unmarshal(token.DecodedBody, outputType)
 }
 
 // ValidationClaims provides configuration for server-side claim
@@ -58,20 +65,60 @@ type ValidationClaims struct {
 
 	NotBefore       time.Time
 	NotBeforeLeeway time.Duration
+
+	// MaxAuthAge, if provided, requires an 'auth_time' claim no older than
+	// MaxAuthAge, as OpenID Connect relying parties requesting a max_age
+	// must check.
+	MaxAuthAge time.Duration
+
+	// AuthorizedParty are the client IDs an 'azp' claim may name, usually
+	// the relying party's own. OpenID Connect requires an 'azp' claim
+	// naming the client when an ID token has several audiences; Claims
+	// hold a single audience, so such tokens are rejected when decoded.
+	AuthorizedParty []string
+
+	// ClockOffset, if provided, corrects the system time used for
+	// Expiration and Not Before, for hosts whose clocks are known to drift.
+	ClockOffset OffsetProvider
 }
 
 // ValidateRegisteredClaims validates registed claims against a
 // set of predefined validation parameters.
 func (claims *Claims) ValidateRegisteredClaims(validationClaims *ValidationClaims) (bool, error) {
-	nbfValid, err := claims.VerifyNotBefore(validationClaims.NotBefore, validationClaims.NotBeforeLeeway)
-	if !nbfValid || err != nil {
+	// Expiration and Not Before are checked against the system time unless
+	// an explicit time is configured.
+	now := time.Now().Add(chaosClockSkew())
+	if validationClaims != nil && nil != validationClaims.ClockOffset {
+		now = now.Add(validationClaims.ClockOffset.Offset())
+	}
+	expirationTime, notBeforeTime := now, now
+	var expirationLeeway, notBeforeLeeway time.Duration
+	if validationClaims != nil {
+		if !validationClaims.Expiration.IsZero() {
+			expirationTime = validationClaims.Expiration
+		}
+		if !validationClaims.NotBefore.IsZero() {
+			notBeforeTime = validationClaims.NotBefore
+		}
+		expirationLeeway = validationClaims.ExpirationLeeway
+		notBeforeLeeway = validationClaims.NotBeforeLeeway
+	}
+
+	nbfValid, err := claims.VerifyNotBefore(notBeforeTime, notBeforeLeeway)
+	if nil != err {
 		return false, err
 	}
+	if !nbfValid {
+		return false, newClockSkewError("nbf", claims.NotBefore, notBeforeTime, notBeforeLeeway)
+	}
 
-	expirationValid, err := claims.VerifyExpiration(validationClaims.Expiration, validationClaims.ExpirationLeeway)
-	if !expirationValid || err != nil {
+	expirationValid, err := claims.VerifyExpiration(expirationTime, expirationLeeway)
+	if nil != err {
 		return false, err
 	}
+	if !expirationValid {
+		return false, newClockSkewError("exp", claims.Expiration, expirationTime, expirationLeeway)
+	}
 
 	// If no validation claims are provided, we still want to validate the
 	// token expiration an nbf values (if they exist). It is for this reason
@@ -95,6 +142,17 @@ func (claims *Claims) ValidateRegisteredClaims(validationClaims *ValidationClaim
 		return false, nil
 	}
 
+	if len(validationClaims.AuthorizedParty) > 0 && !claims.VerifyAuthorizedParty(validationClaims.AuthorizedParty) {
+		return false, nil
+	}
+
+	if validationClaims.MaxAuthAge > 0 {
+		authTimeValid, err := claims.VerifyAuthTime(now, validationClaims.MaxAuthAge)
+		if nil != err || !authTimeValid {
+			return false, err
+		}
+	}
+
 	return true, nil
 }
 
@@ -128,21 +186,38 @@ func (claims *Claims) VerifyAudience(expAudience []string) bool {
 	return anyEquals(expAudience, claims.Audience)
 }
 
+// VerifyAuthorizedParty verifies the Authorized Party ('azp') claim, if
+// one exists. If it doesn't exist in the claimset, true is returned.
+func (claims *Claims) VerifyAuthorizedParty(expParty []string) bool {
+	if claims.AuthorizedParty == "" {
+		return true
+	}
+
+	return anyEquals(expParty, claims.AuthorizedParty)
+}
+
+// VerifyAuthTime verifies the Authentication Time ('auth_time') claim is
+// no more than maxAge before the currentTime. Unlike the other claims,
+// it must exist.
+func (claims *Claims) VerifyAuthTime(currentTime time.Time, maxAge time.Duration) (bool, error) {
+	if nil == claims.AuthTime {
+		return false, nil
+	}
+
+	authTime := claims.AuthTime.Time()
+	return !currentTime.Add(-maxAge).After(authTime), nil
+}
+
 // VerifyNotBefore verifies the Not Before ('nbf') claim, if it exists.
 // If it doesn't exist in the claimset, true is returned. If there is
 // a Not Before claim, it is parsed and compared to the currentTime
 // plus any leeway value.
 func (claims *Claims) VerifyNotBefore(currentTime time.Time, leeway time.Duration) (bool, error) {
-	if claims.NotBefore == "" {
+	if nil == claims.NotBefore {
 		return true, nil
 	}
 
-	timeInt, err := strconv.ParseInt(claims.NotBefore, 10, 64)
-	if nil != err {
-		return false, err
-	}
-
-	nbfClaim := time.Unix(timeInt, 0)
+	nbfClaim := claims.NotBefore.Time()
 	return (currentTime.Add(leeway).After(nbfClaim)), nil
 }
 
@@ -151,16 +226,11 @@ func (claims *Claims) VerifyNotBefore(currentTime time.Time, leeway time.Duratio
 // a Expiration claim, it is parsed and compared to the currentTime
 // plus any leeway value.
 func (claims *Claims) VerifyExpiration(currentTime time.Time, leeway time.Duration) (bool, error) {
-	if claims.Expiration == "" {
+	if nil == claims.Expiration {
 		return true, nil
 	}
 
-	timeInt, err := strconv.ParseInt(claims.Expiration, 10, 64)
-	if nil != err {
-		return false, err
-	}
-
-	expClaim := time.Unix(timeInt, 0)
+	expClaim := claims.Expiration.Time()
 	return (currentTime.Add(-leeway).Before(expClaim)), nil
 }
 
@@ -0,0 +1,138 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// claimTagName is the struct tag used to map a struct field to a claim
+// that does not line up with the field's JSON name. The tag value is a
+// dot separated path into the claim set, e.g. `claim:"realm_access.roles"`.
+const claimTagName = "claim"
+
+// DecodeClaims decodes the claim set of a token into outputType, which
+// must be a pointer to a struct.
+//
+// Fields are first populated using their regular `json` tags. Any field
+// carrying a `claim` tag is then populated from the claim at that path,
+// allowing nested claims (for example Keycloak's `realm_access.roles`)
+// to be decoded directly into flat, Go-friendly structs. Claims that
+// are not present in the claim set leave the field untouched.
+func DecodeClaims(token *Token, outputType interface{}) error {
+	return decodeClaimsJSON(token.DecodedBody, outputType)
+}
+
+// decodeClaimsJSON decodes a JSON claim set into outputType, honouring
+// any `claim` struct tags.
+func decodeClaimsJSON(rawClaims []byte, outputType interface{}) error {
+	target := reflect.ValueOf(outputType)
+	if target.Kind() != reflect.Ptr || target.IsNil() || target.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("Cannot decode claims into %T, expected a pointer to a struct", outputType)
+	}
+
+	if err := json.Unmarshal(rawClaims, outputType); nil != err {
+		return err
+	}
+
+	claims, err := decodeClaimsMap(rawClaims)
+	if nil != err {
+		return err
+	}
+
+	return applyClaimTags(claims, target.Elem())
+}
+
+// decodeClaimsMap decodes a JSON claim set into a generic map, retaining
+// numeric claims as json.Number so no precision is lost.
+func decodeClaimsMap(rawClaims []byte) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(rawClaims))
+	decoder.UseNumber()
+
+	var claims map[string]interface{}
+	if err := decoder.Decode(&claims); nil != err {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// applyClaimTags walks the fields of the struct value and populates any
+// field with a `claim` tag from the claim set. Embedded structs are
+// walked recursively.
+func applyClaimTags(claims map[string]interface{}, value reflect.Value) error {
+	valueType := value.Type()
+
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		fieldValue := value.Field(i)
+
+		// Unexported fields can't be set.
+		if field.PkgPath != "" && !field.Anonymous {
+			continue
+		}
+
+		path, tagged := field.Tag.Lookup(claimTagName)
+		if !tagged {
+			if field.Anonymous && fieldValue.Kind() == reflect.Struct {
+				if err := applyClaimTags(claims, fieldValue); nil != err {
+					return err
+				}
+			}
+			continue
+		}
+
+		if path == "" || path == "-" {
+			continue
+		}
+
+		claim, ok := lookupClaimPath(claims, path)
+		if !ok {
+			continue
+		}
+
+		if err := assignClaim(claim, fieldValue); nil != err {
+			return fmt.Errorf("Cannot decode claim %q into field %s: %s", path, field.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// lookupClaimPath resolves a dot separated path within a claim set.
+func lookupClaimPath(claims map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = claims
+
+	for _, segment := range strings.Split(path, ".") {
+		object, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		current, ok = object[segment]
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+// assignClaim stores a decoded claim value in the target field. The value
+// is round-tripped through encoding/json so the usual JSON coercion rules
+// apply to the destination type.
+func assignClaim(claim interface{}, target reflect.Value) error {
+	if !target.CanAddr() {
+		return errors.New("field is not addressable")
+	}
+
+	encoded, err := json.Marshal(claim)
+	if nil != err {
+		return err
+	}
+
+	return json.Unmarshal(encoded, target.Addr().Interface())
+}
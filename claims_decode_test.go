@@ -0,0 +1,107 @@
+package jwt
+
+import (
+	"reflect"
+	"testing"
+)
+
+type keycloakTestClaims struct {
+	Claims
+	TenantID   string   `claim:"tenant_id"`
+	RealmRoles []string `claim:"realm_access.roles"`
+	Level      int      `claim:"profile.security.level"`
+	Missing    string   `claim:"does.not.exist"`
+	Email      string   `json:"email"`
+}
+
+func TestDecodeClaims(t *testing.T) {
+	type args struct {
+		body   []byte
+		output interface{}
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    interface{}
+		wantErr bool
+	}{
+		{
+			"Must decode flat, nested and embedded claims",
+			args{
+				[]byte(`{"iss":"https://idp.example.com","sub":"geralt","email":"geralt@kaer.morhen","tenant_id":"wolf","realm_access":{"roles":["witcher","admin"]},"profile":{"security":{"level":3}}}`),
+				&keycloakTestClaims{},
+			},
+			&keycloakTestClaims{
+				Claims: Claims{
+					Issuer:  "https://idp.example.com",
+					Subject: "geralt",
+				},
+				TenantID:   "wolf",
+				RealmRoles: []string{"witcher", "admin"},
+				Level:      3,
+				Email:      "geralt@kaer.morhen",
+			},
+			false,
+		},
+		{
+			"Must leave fields untouched when the claim path is missing",
+			args{
+				[]byte(`{"realm_access":"not-an-object"}`),
+				&keycloakTestClaims{Missing: "unchanged"},
+			},
+			&keycloakTestClaims{Missing: "unchanged"},
+			false,
+		},
+		{
+			"Must fail when the claim type does not match the field",
+			args{
+				[]byte(`{"tenant_id":{"nested":true}}`),
+				&keycloakTestClaims{},
+			},
+			nil,
+			true,
+		},
+		{
+			"Must fail when output is not a pointer to a struct",
+			args{
+				[]byte(`{}`),
+				keycloakTestClaims{},
+			},
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := DecodeClaims(&Token{DecodedBody: tt.args.body}, tt.args.output)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DecodeClaims() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(tt.args.output, tt.want) {
+				t.Errorf("DecodeClaims() = %+v, want %+v", tt.args.output, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetClaims(t *testing.T) {
+	sv, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	rawToken, err := sv.GenerateToken(Header{Algorithm: string(HS256)}, Claims{Issuer: "vesemir", Subject: "ciri"})
+	if nil != err {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	token, err := GetRawTokenParts(rawToken)
+	if nil != err {
+		t.Fatalf("GetRawTokenParts() error = %v", err)
+	}
+
+	var claims Claims
+	if err := GetClaims(token, &claims); nil != err {
+		t.Fatalf("GetClaims() error = %v", err)
+	}
+	if claims.Issuer != "vesemir" || claims.Subject != "ciri" {
+		t.Errorf("GetClaims() = %+v, want the token's claims", claims)
+	}
+}
@@ -0,0 +1,75 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateRegisteredClaims_OpenIDConnect(t *testing.T) {
+	authTime := func(ago time.Duration) *NumericDate {
+		return NewNumericDate(time.Now().Add(-ago))
+	}
+
+	tests := []struct {
+		name      string
+		claims    Claims
+		criteria  ValidationClaims
+		wantValid bool
+		wantErr   bool
+	}{
+		{"Must accept a recent authentication", Claims{AuthTime: authTime(time.Minute)}, ValidationClaims{MaxAuthAge: time.Hour}, true, false},
+		{"Must not accept an authentication older than the max age", Claims{AuthTime: authTime(2 * time.Hour)}, ValidationClaims{MaxAuthAge: time.Hour}, false, false},
+		{"Must not accept a missing auth_time with a max age", Claims{}, ValidationClaims{MaxAuthAge: time.Hour}, false, false},
+		{"Must ignore auth_time without a max age", Claims{AuthTime: authTime(48 * time.Hour)}, ValidationClaims{}, true, false},
+		{"Must accept the expected authorized party", Claims{AuthorizedParty: "client"}, ValidationClaims{AuthorizedParty: []string{"client"}}, true, false},
+		{"Must not accept another authorized party", Claims{AuthorizedParty: "other"}, ValidationClaims{AuthorizedParty: []string{"client"}}, false, false},
+		{"Must accept a missing authorized party", Claims{}, ValidationClaims{AuthorizedParty: []string{"client"}}, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, err := tt.claims.ValidateRegisteredClaims(&tt.criteria)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateRegisteredClaims() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if valid != tt.wantValid {
+				t.Errorf("ValidateRegisteredClaims() = %v, want %v", valid, tt.wantValid)
+			}
+		})
+	}
+}
+
+func TestValidateRegisteredClaims_Defaults(t *testing.T) {
+	now := time.Now()
+	at := func(d time.Duration) *NumericDate {
+		return NewNumericDate(now.Add(d))
+	}
+
+	tests := []struct {
+		name      string
+		claims    Claims
+		criteria  *ValidationClaims
+		wantValid bool
+		wantErr   bool
+	}{
+		{"Must accept a current token with nil criteria", Claims{NotBefore: at(-time.Minute), Expiration: at(time.Hour)}, nil, true, false},
+		{"Must accept a token without times with nil criteria", Claims{Subject: "alice"}, nil, true, false},
+		{"Must reject an expired token with nil criteria", Claims{Expiration: at(-time.Minute)}, nil, false, true},
+		{"Must reject a token not yet valid with nil criteria", Claims{NotBefore: at(time.Hour)}, nil, false, true},
+		{"Must check expiration against now when unset", Claims{Expiration: at(-time.Minute)}, &ValidationClaims{}, false, true},
+		{"Must check not before against now when unset", Claims{NotBefore: at(time.Hour)}, &ValidationClaims{}, false, true},
+		{"Must apply leeway to now when unset", Claims{Expiration: at(-time.Minute)}, &ValidationClaims{ExpirationLeeway: 2 * time.Minute}, true, false},
+		{"Must check expiration against the configured time", Claims{Expiration: at(-time.Minute)}, &ValidationClaims{Expiration: now.Add(-time.Hour)}, true, false},
+		{"Must check not before against the configured time", Claims{NotBefore: at(time.Hour)}, &ValidationClaims{NotBefore: now.Add(2 * time.Hour)}, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, err := tt.claims.ValidateRegisteredClaims(tt.criteria)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateRegisteredClaims() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if valid != tt.wantValid {
+				t.Errorf("ValidateRegisteredClaims() = %v, want %v", valid, tt.wantValid)
+			}
+		})
+	}
+}
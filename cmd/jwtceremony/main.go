@@ -0,0 +1,140 @@
+// Command jwtceremony runs key ceremonies bootstrapping an issuer's keys,
+// on an offline machine.
+//
+// The generate command generates keys, writing the key set to publish and
+// each custodian's share of every private key to the output directory:
+//
+//	jwtceremony generate -alg ES256 -keys 2 -custodians 5 -threshold 3 -out ceremony
+//
+// The reconstruct command reconstructs a private key from the share files
+// of at least the threshold of custodians, writing it PKCS#8 encoded, the
+// form KMS and HSM key imports take:
+//
+//	jwtceremony reconstruct -out key.p8 share-1.txt share-3.txt share-4.txt
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/georgejenkins/jwt"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "generate":
+		err = generate(os.Args[2:])
+	case "reconstruct":
+		err = reconstruct(os.Args[2:])
+	default:
+		usage()
+	}
+
+	if nil != err {
+		fmt.Fprintln(os.Stderr, "jwtceremony:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: jwtceremony generate|reconstruct [flags]")
+	os.Exit(2)
+}
+
+// generate generates ceremony keys, writing the key set to jwks.json and
+// the shares of each key to custodian-N/KID.share in the output directory.
+func generate(args []string) error {
+	flags := flag.NewFlagSet("generate", flag.ExitOnError)
+	alg := flags.String("alg", "ES256", "signing algorithm of the keys")
+	keys := flags.Int("keys", 2, "number of keys, the current key then those to rotate to")
+	custodians := flags.Int("custodians", 5, "number of custodians to share each key between")
+	threshold := flags.Int("threshold", 3, "number of custodians needed to reconstruct a key")
+	out := flags.String("out", "ceremony", "output directory, which must not exist")
+	flags.Parse(args)
+
+	if *keys < 1 {
+		return errors.New("At least one key is required")
+	}
+
+	if err := os.Mkdir(*out, 0700); nil != err {
+		return err
+	}
+
+	ceremonyKeys := make([]*jwt.CeremonyKey, *keys)
+	for i := range ceremonyKeys {
+		key, err := jwt.GenerateCeremonyKey(jwt.Algorithm(*alg), *custodians, *threshold)
+		if nil != err {
+			return err
+		}
+		ceremonyKeys[i] = key
+
+		for custodian, share := range key.Shares {
+			dir := filepath.Join(*out, fmt.Sprintf("custodian-%d", custodian+1))
+			if err := os.MkdirAll(dir, 0700); nil != err {
+				return err
+			}
+			if err := ioutil.WriteFile(filepath.Join(dir, key.Public.KeyID+".share"), []byte(share+"\n"), 0600); nil != err {
+				return err
+			}
+		}
+		fmt.Printf("Generated key %s\n", key.Public.KeyID)
+	}
+
+	set, err := json.MarshalIndent(jwt.CeremonyKeySet(ceremonyKeys...), "", "  ")
+	if nil != err {
+		return err
+	}
+
+	return ioutil.WriteFile(filepath.Join(*out, "jwks.json"), append(set, '\n'), 0644)
+}
+
+// reconstruct reconstructs a private key from share files.
+func reconstruct(args []string) error {
+	flags := flag.NewFlagSet("reconstruct", flag.ExitOnError)
+	out := flags.String("out", "", "file to write the PKCS#8 encoded private key to, which must not exist")
+	flags.Parse(args)
+
+	if *out == "" || flags.NArg() == 0 {
+		return errors.New("Reconstructing a key requires an output file and share files")
+	}
+
+	shares := make([]string, flags.NArg())
+	for i, path := range flags.Args() {
+		share, err := ioutil.ReadFile(path)
+		if nil != err {
+			return err
+		}
+		shares[i] = strings.TrimSpace(string(share))
+	}
+
+	der, kid, err := jwt.ReconstructCeremonyKey(shares)
+	if nil != err {
+		return err
+	}
+
+	f, err := os.OpenFile(*out, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if nil != err {
+		return err
+	}
+	if _, err := f.Write(der); nil != err {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); nil != err {
+		return err
+	}
+
+	fmt.Printf("Reconstructed key %s\n", kid)
+	return nil
+}
@@ -0,0 +1,175 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/georgejenkins/jwt/jwk"
+)
+
+// ConfigTokenType is the 'typ' header value configuration tokens must
+// carry, so no other token signed by the operations key is applied.
+const ConfigTokenType = "config+jwt"
+
+// ErrTokenRevoked is returned for tokens whose JWT ID has been revoked by
+// a configuration token.
+var ErrTokenRevoked = errors.New("Token has been revoked")
+
+// ConfigDocument is the configuration carried in the 'config' claim of a
+// configuration token.
+type ConfigDocument struct {
+	// Version must increase with every configuration, so older
+	// configuration tokens can't be replayed.
+	Version uint64 `json:"version"`
+
+	// DeniedAlgorithms are rejected, as by WithDeniedAlgorithms.
+	DeniedAlgorithms []Algorithm `json:"denied_algs,omitempty"`
+
+	// RevokedTokenIDs are the JWT IDs of revoked tokens.
+	RevokedTokenIDs []string `json:"revoked_jtis,omitempty"`
+
+	// TrustedKeys is the trust store: the keys tokens are verified with.
+	TrustedKeys *jwk.Set `json:"trusted_keys"`
+}
+
+// configState is an applied ConfigDocument, indexed for verification.
+type configState struct {
+	version uint64
+	denied  map[Algorithm]bool
+	revoked map[string]bool
+	keys    *jwk.Set
+}
+
+// ControlPlane applies configuration tokens, JWTs carrying a
+// ConfigDocument signed by an operations key, to running verifiers. Each
+// configuration is verified in full, then replaces the previous one in a
+// single atomic step, so a configuration is never partially applied.
+type ControlPlane struct {
+	ops         *JOSESignerVerifier
+	opsCriteria *ValidationClaims
+	verifier    *JOSESignerVerifier
+
+	mu    sync.Mutex
+	state atomic.Value
+}
+
+// NewControlPlane creates a ControlPlane accepting configuration tokens
+// verified by ops against the criteria. The operations key should be
+// distinct from every key in the trust store. Until a configuration is
+// applied no tokens verify. The options configure verification with the
+// trusted keys.
+func NewControlPlane(ops *JOSESignerVerifier, criteria *ValidationClaims, opts ...Option) (*ControlPlane, error) {
+	if nil == ops || nil == criteria {
+		return nil, errors.New("Control plane requires an operations verifier and validation criteria")
+	}
+
+	cp := &ControlPlane{ops: ops, opsCriteria: criteria}
+	cp.state.Store(&configState{keys: &jwk.Set{}})
+
+	verifier, err := NewKeyResolvingVerifier(cp, opts...)
+	if nil != err {
+		return nil, err
+	}
+	cp.verifier = verifier
+
+	return cp, nil
+}
+
+// Apply verifies a configuration token and applies its configuration. The
+// token must have the 'config+jwt' type, and a higher version than the
+// configuration in effect.
+func (cp *ControlPlane) Apply(rawToken []byte) error {
+	token, valid, err := cp.ops.VerifyToken(rawToken, cp.opsCriteria)
+	if nil != err {
+		return err
+	}
+	if !valid {
+		return errors.New("Configuration token is invalid")
+	}
+	if token.RegisteredHeader.Type != ConfigTokenType {
+		return fmt.Errorf("Configuration token must have type %q", ConfigTokenType)
+	}
+
+	var claims struct {
+		Config json.RawMessage `json:"config"`
+	}
+	if err := json.Unmarshal(token.DecodedBody, &claims); nil != err {
+		return err
+	}
+	if len(claims.Config) == 0 {
+		return errors.New("Configuration token has no 'config' claim")
+	}
+
+	// Unknown members are rejected, so a misspelt policy isn't ignored.
+	var document ConfigDocument
+	decoder := json.NewDecoder(bytes.NewReader(claims.Config))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&document); nil != err {
+		return fmt.Errorf("Malformed configuration: %v", err)
+	}
+	if nil == document.TrustedKeys {
+		return errors.New("Configuration has no trusted keys")
+	}
+
+	state := &configState{
+		version: document.Version,
+		denied:  make(map[Algorithm]bool, len(document.DeniedAlgorithms)),
+		revoked: make(map[string]bool, len(document.RevokedTokenIDs)),
+		keys:    document.TrustedKeys,
+	}
+	for _, alg := range document.DeniedAlgorithms {
+		state.denied[alg] = true
+	}
+	for _, jti := range document.RevokedTokenIDs {
+		state.revoked[jti] = true
+	}
+
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+
+	if current := cp.current(); state.version <= current.version {
+		return fmt.Errorf("Configuration version %d is not newer than version %d", state.version, current.version)
+	}
+	cp.state.Store(state)
+	return nil
+}
+
+// Version returns the version of the configuration in effect, or zero if
+// none has been applied.
+func (cp *ControlPlane) Version() uint64 {
+	return cp.current().version
+}
+
+// ResolveKey returns the trusted key with the header's key ID, unless the
+// header's algorithm is denied.
+func (cp *ControlPlane) ResolveKey(header Header) (*jwk.Key, error) {
+	state := cp.current()
+	if state.denied[Algorithm(header.Algorithm)] {
+		return nil, ErrAlgorithmDenied
+	}
+
+	return KeySetResolver(state.keys).ResolveKey(header)
+}
+
+// VerifyToken verifies a token with the trusted keys against the
+// validation criteria, rejecting denied algorithms and revoked tokens.
+func (cp *ControlPlane) VerifyToken(rawToken []byte, validationCriteria *ValidationClaims) (*Token, bool, error) {
+	token, valid, err := cp.verifier.VerifyToken(rawToken, validationCriteria)
+	if nil != err || !valid {
+		return token, valid, err
+	}
+
+	if token.RegisteredClaims.JWTID != "" && cp.current().revoked[token.RegisteredClaims.JWTID] {
+		return token, false, ErrTokenRevoked
+	}
+
+	return token, true, nil
+}
+
+func (cp *ControlPlane) current() *configState {
+	return cp.state.Load().(*configState)
+}
@@ -0,0 +1,105 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+
+	"github.com/georgejenkins/jwt/jwk"
+)
+
+func TestControlPlane(t *testing.T) {
+	_, opsKey, _ := ed25519.GenerateKey(rand.Reader)
+	opsSigner, _ := NewJOSESignerVerifier(EdDSA, &opsKey)
+	opsCriteria := &ValidationClaims{Issuer: []string{"ops"}}
+
+	serviceKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	trusted := &jwk.Set{Keys: []*jwk.Key{{KeyID: "svc", Key: &serviceKey.PublicKey}}}
+
+	configToken := func(typ string, config interface{}) []byte {
+		token, err := opsSigner.GenerateToken(
+			Header{Algorithm: string(EdDSA), Type: typ},
+			map[string]interface{}{"iss": "ops", "config": config},
+		)
+		if nil != err {
+			t.Fatalf("GenerateToken() error = %v", err)
+		}
+		return token
+	}
+
+	cp, err := NewControlPlane(opsSigner, opsCriteria)
+	if nil != err {
+		t.Fatalf("NewControlPlane() error = %v", err)
+	}
+
+	serviceSigner, _ := NewJOSESignerVerifier(ES256, serviceKey)
+	serviceToken, _ := serviceSigner.GenerateToken(Header{Algorithm: string(ES256), KeyID: "svc"}, Claims{Subject: "alice", JWTID: "jti-1"})
+	criteria := &ValidationClaims{Subject: []string{"alice"}}
+
+	if _, valid, _ := cp.VerifyToken(serviceToken, criteria); valid {
+		t.Errorf("ControlPlane.VerifyToken() = true before any configuration")
+	}
+
+	if err := cp.Apply(configToken(ConfigTokenType, ConfigDocument{Version: 1, TrustedKeys: trusted})); nil != err {
+		t.Fatalf("ControlPlane.Apply() error = %v", err)
+	}
+	if _, valid, err := cp.VerifyToken(serviceToken, criteria); !valid || nil != err {
+		t.Errorf("ControlPlane.VerifyToken() = %v, %v with the key trusted", valid, err)
+	}
+
+	tests := []struct {
+		name    string
+		token   []byte
+		wantErr bool
+	}{
+		{"Must not apply a replayed version", configToken(ConfigTokenType, ConfigDocument{Version: 1, TrustedKeys: trusted}), true},
+		{"Must not apply a token of another type", configToken("JWT", ConfigDocument{Version: 2, TrustedKeys: trusted}), true},
+		{"Must not apply a configuration without trusted keys", configToken(ConfigTokenType, ConfigDocument{Version: 2}), true},
+		{"Must not apply a configuration with unknown members", configToken(ConfigTokenType, map[string]interface{}{"version": 2, "trusted_keys": trusted, "revoked_jti": []string{"jti-1"}}), true},
+		{"Must not apply a token without a configuration", configToken(ConfigTokenType, nil), true},
+		{"Must not apply a token not signed by the operations key", mustSignConfig(t, serviceSigner, ES256, ConfigDocument{Version: 2, TrustedKeys: trusted}), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := cp.Apply(tt.token); (err != nil) != tt.wantErr {
+				t.Errorf("ControlPlane.Apply() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	if cp.Version() != 1 {
+		t.Errorf("ControlPlane.Version() = %d after rejected configurations, want 1", cp.Version())
+	}
+
+	// Revoking the token's JWT ID.
+	cp.Apply(configToken(ConfigTokenType, ConfigDocument{Version: 2, TrustedKeys: trusted, RevokedTokenIDs: []string{"jti-1"}}))
+	if _, valid, err := cp.VerifyToken(serviceToken, criteria); valid || err != ErrTokenRevoked {
+		t.Errorf("ControlPlane.VerifyToken() = %v, %v, want %v", valid, err, ErrTokenRevoked)
+	}
+
+	// Denying the token's algorithm.
+	cp.Apply(configToken(ConfigTokenType, ConfigDocument{Version: 3, TrustedKeys: trusted, DeniedAlgorithms: []Algorithm{ES256}}))
+	if _, valid, err := cp.VerifyToken(serviceToken, criteria); valid || err != ErrAlgorithmDenied {
+		t.Errorf("ControlPlane.VerifyToken() = %v, %v, want %v", valid, err, ErrAlgorithmDenied)
+	}
+
+	// Removing the key from the trust store.
+	cp.Apply(configToken(ConfigTokenType, ConfigDocument{Version: 4, TrustedKeys: &jwk.Set{}}))
+	if _, valid, _ := cp.VerifyToken(serviceToken, criteria); valid {
+		t.Errorf("ControlPlane.VerifyToken() = true after the key was removed from the trust store")
+	}
+}
+
+func mustSignConfig(t *testing.T, sv *JOSESignerVerifier, alg Algorithm, document ConfigDocument) []byte {
+	config, _ := json.Marshal(document)
+	token, err := sv.GenerateToken(
+		Header{Algorithm: string(alg), Type: ConfigTokenType},
+		map[string]interface{}{"iss": "ops", "config": json.RawMessage(config)},
+	)
+	if nil != err {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+	return token
+}
@@ -0,0 +1,95 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// registeredHeaderParameters are the header parameters defined by RFC 7515
+// and RFC 7518 for JWSs, which must not be listed as critical.
+var registeredHeaderParameters = map[string]bool{
+	"alg": true, "jku": true, "jwk": true, "kid": true, "x5u": true, "x5c": true,
+	"x5t": true, "x5t#S256": true, "typ": true, "cty": true, "crit": true,
+}
+
+// CriticalHeaderHandler processes a critical header parameter of a token
+// whose signature is valid, given the parameter's JSON value. Returning an
+// error rejects the token.
+type CriticalHeaderHandler func(token *Token, value json.RawMessage) error
+
+// WithCriticalHeader registers a handler for a custom header parameter,
+// so tokens listing it in their 'crit' header are accepted, provided the
+// handler accepts its value. Tokens listing parameters without a handler
+// are rejected (RFC 7515, section 4.1.11).
+func WithCriticalHeader(name string, handler CriticalHeaderHandler) Option {
+	return func(sv *JOSESignerVerifier) error {
+		if registeredHeaderParameters[name] || name == "b64" {
+			return fmt.Errorf("Cannot register a handler for header parameter %q", name)
+		}
+		if nil == handler {
+			return errors.New("Critical header handler cannot be nil")
+		}
+
+		if nil == sv.criticalHeaders {
+			sv.criticalHeaders = make(map[string]CriticalHeaderHandler)
+		}
+		sv.criticalHeaders[name] = handler
+		return nil
+	}
+}
+
+// checkCritical checks the 'crit' header of a token lists only parameters
+// present in its JSON header and understood, either registered with
+// WithCriticalHeader or listed in understood, returning the values of the
+// registered parameters. It runs before the signature is verified, so no
+// handler sees a forged value.
+func (sv *JOSESignerVerifier) checkCritical(joseHeader []byte, understood ...string) (map[string]json.RawMessage, error) {
+	var header map[string]json.RawMessage
+	if err := json.Unmarshal(joseHeader, &header); nil != err {
+		return nil, err
+	}
+
+	rawCritical, ok := header["crit"]
+	if !ok {
+		return nil, nil
+	}
+	var critical []string
+	if err := json.Unmarshal(rawCritical, &critical); nil != err {
+		return nil, errors.New("The 'crit' header parameter must be an array of names")
+	}
+	if len(critical) == 0 {
+		return nil, errors.New("The 'crit' header parameter must not be empty")
+	}
+
+	values := make(map[string]json.RawMessage)
+	for _, name := range critical {
+		if registeredHeaderParameters[name] {
+			return nil, fmt.Errorf("Registered header parameter %q cannot be critical", name)
+		}
+		value, ok := header[name]
+		if !ok {
+			return nil, fmt.Errorf("Critical header parameter %q is missing", name)
+		}
+
+		if _, ok := sv.criticalHeaders[name]; ok {
+			values[name] = value
+		} else if !anyEquals(understood, name) {
+			return nil, fmt.Errorf("Critical header parameter %q is not understood", name)
+		}
+	}
+
+	return values, nil
+}
+
+// processCritical calls the handler of each critical header parameter of
+// a token with a valid signature.
+func (sv *JOSESignerVerifier) processCritical(token *Token, values map[string]json.RawMessage) error {
+	for name, value := range values {
+		if err := sv.criticalHeaders[name](token, value); nil != err {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestWithCriticalHeader(t *testing.T) {
+	if _, err := NewJOSESignerVerifier(HS256, exampleKey, WithCriticalHeader("alg", func(*Token, json.RawMessage) error { return nil })); nil == err {
+		t.Errorf("WithCriticalHeader() expected error for a registered header parameter")
+	}
+
+	checkTenant := func(token *Token, value json.RawMessage) error {
+		var tenant string
+		if err := json.Unmarshal(value, &tenant); nil != err || tenant != "acme" {
+			return errors.New("Unknown tenant")
+		}
+		return nil
+	}
+	plain, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	sv, _ := NewJOSESignerVerifier(HS256, exampleKey, WithCriticalHeader("tenant", checkTenant))
+	claims := Claims{Subject: "alice", Issuer: "issuer"}
+
+	generate := func(header map[string]interface{}) []byte {
+		header["alg"] = string(HS256)
+		token, _ := sv.GenerateToken(header, claims)
+		return token
+	}
+
+	tests := []struct {
+		name      string
+		sv        *JOSESignerVerifier
+		token     []byte
+		wantValid bool
+		wantErr   bool
+	}{
+		{"Must verify a token without critical parameters", sv, generate(map[string]interface{}{"tenant": "acme"}), true, false},
+		{"Must verify a token with a critical parameter its handler accepts", sv, generate(map[string]interface{}{"tenant": "acme", "crit": []string{"tenant"}}), true, false},
+		{"Must not verify a token with a critical parameter its handler rejects", sv, generate(map[string]interface{}{"tenant": "evil", "crit": []string{"tenant"}}), false, true},
+		{"Must not verify a token with a critical parameter without a handler", plain, generate(map[string]interface{}{"tenant": "acme", "crit": []string{"tenant"}}), false, true},
+		{"Must not verify a token with a missing critical parameter", sv, generate(map[string]interface{}{"crit": []string{"tenant"}}), false, true},
+		{"Must not verify a token with an empty 'crit'", sv, generate(map[string]interface{}{"crit": []string{}}), false, true},
+		{"Must not verify a token with a critical registered parameter", sv, generate(map[string]interface{}{"crit": []string{"alg"}}), false, true},
+		{"Must not verify a token with an unencoded payload", sv, generate(map[string]interface{}{"b64": false, "crit": []string{"b64"}}), false, true},
+		{"Must verify a token with an encoded payload", sv, generate(map[string]interface{}{"b64": true, "crit": []string{"b64"}}), true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, valid, err := tt.sv.VerifyToken(tt.token, &ValidationClaims{Issuer: []string{"issuer"}, Subject: []string{"alice"}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifyToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if valid != tt.wantValid {
+				t.Errorf("VerifyToken() valid = %v, want %v", valid, tt.wantValid)
+			}
+		})
+	}
+}
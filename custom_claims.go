@@ -0,0 +1,38 @@
+package jwt
+
+// RegisteredClaimsHolder is implemented by every claim struct embedding
+// Claims, through the promoted Registered method, so custom claims can be
+// generated and verified alongside the registered ones:
+//
+//	type AccessClaims struct {
+//		jwt.Claims
+//		Scope  string          `json:"scope"`
+//		Tenant json.RawMessage `json:"tenant"`
+//	}
+type RegisteredClaimsHolder interface {
+	Registered() *Claims
+}
+
+// Registered returns the registered claims.
+func (claims *Claims) Registered() *Claims {
+	return claims
+}
+
+// VerifyTokenClaims verifies the token as VerifyToken does, and decodes
+// its claim set into claims, a struct embedding Claims. Registered claims
+// are validated as decoded from the token, so a custom field shadowing a
+// registered claim can't bypass validation, and custom fields of type
+// json.RawMessage hold their values verbatim. The claims are only decoded
+// for valid tokens.
+func (sv *JOSESignerVerifier) VerifyTokenClaims(rawToken []byte, validationCriteria *ValidationClaims, claims RegisteredClaimsHolder) (*Token, bool, error) {
+	token, valid, err := sv.VerifyToken(rawToken, validationCriteria)
+	if nil != err || !valid {
+		return token, valid, err
+	}
+
+	if err := GetClaims(token, claims); nil != err {
+		return token, false, err
+	}
+
+	return token, true, nil
+}
@@ -0,0 +1,84 @@
+package jwt
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type customTestClaims struct {
+	Claims
+	Scope  string          `json:"scope"`
+	Tenant json.RawMessage `json:"tenant"`
+}
+
+type shadowingTestClaims struct {
+	Claims
+	Issuer string `json:"iss"`
+}
+
+func TestVerifyTokenClaims(t *testing.T) {
+	sv, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	now := time.Now()
+	criteria := &ValidationClaims{Issuer: []string{"https://issuer.example.com"}, Subject: []string{"alice"}}
+
+	generate := func(claims interface{}) []byte {
+		token, err := sv.GenerateToken(Header{Algorithm: string(HS256)}, claims)
+		if nil != err {
+			t.Fatalf("GenerateToken() error = %v", err)
+		}
+		return token
+	}
+	registered := Claims{Issuer: "https://issuer.example.com", Subject: "alice", Expiration: NewNumericDate(now.Add(time.Hour))}
+	expired := registered
+	expired.Expiration = NewNumericDate(now.Add(-time.Hour))
+	tenant := json.RawMessage(`{"id":"t1","regions":["eu", "us"]}`)
+
+	tests := []struct {
+		name       string
+		token      []byte
+		wantValid  bool
+		wantErr    bool
+		wantClaims customTestClaims
+	}{
+		{
+			"Must verify custom claims",
+			generate(customTestClaims{Claims: registered, Scope: "orders:read", Tenant: tenant}),
+			true, false,
+			customTestClaims{Claims: registered, Scope: "orders:read", Tenant: json.RawMessage(`{"id":"t1","regions":["eu","us"]}`)},
+		},
+		{
+			"Must validate the embedded registered claims",
+			generate(customTestClaims{Claims: expired, Scope: "orders:read"}),
+			false, true, customTestClaims{},
+		},
+		{
+			"Must validate registered claims shadowed by a custom field",
+			generate(shadowingTestClaims{Claims: registered, Issuer: "https://attacker.example.com"}),
+			false, false, customTestClaims{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var claims customTestClaims
+			token, valid, err := sv.VerifyTokenClaims(tt.token, criteria, &claims)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifyTokenClaims() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if valid != tt.wantValid {
+				t.Fatalf("VerifyTokenClaims() valid = %v, want %v", valid, tt.wantValid)
+			}
+			if !valid {
+				return
+			}
+
+			if !reflect.DeepEqual(claims.Claims, tt.wantClaims.Claims) || claims.Scope != tt.wantClaims.Scope || string(claims.Tenant) != string(tt.wantClaims.Tenant) {
+				t.Errorf("VerifyTokenClaims() claims = %+v, want %+v", claims, tt.wantClaims)
+			}
+			if !reflect.DeepEqual(token.RegisteredClaims, *claims.Registered()) {
+				t.Errorf("VerifyTokenClaims() registered claims = %+v, want %+v", token.RegisteredClaims, claims.Claims)
+			}
+		})
+	}
+}
@@ -0,0 +1,328 @@
+package jwt
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// DecisionBundleType is the 'typ' header of decision log bundles.
+const DecisionBundleType = "decision-log+jwt"
+
+// Results of verification decisions.
+const (
+	DecisionValid   = "valid"
+	DecisionInvalid = "invalid"
+)
+
+// maxPendingBatches is the number of batches of decisions a DecisionLog
+// holds while its sink fails, before dropping the oldest.
+const maxPendingBatches = 16
+
+// Decision is a verification outcome recorded by a DecisionLog. Tokens are
+// credentials, so a decision identifies its token only by fingerprint.
+type Decision struct {
+	Time time.Time `json:"time"`
+
+	// Fingerprint is the base64url encoded SHA-256 hash of the token.
+	Fingerprint string `json:"fingerprint"`
+
+	// KeyID is the token's 'kid' header. It is taken from invalid tokens
+	// too, so may not name a trusted key.
+	KeyID string `json:"kid,omitempty"`
+
+	Result string `json:"result"`
+	Reason string `json:"reason,omitempty"`
+
+	// PolicyVersion is the version of the verification policy in effect.
+	PolicyVersion uint64 `json:"policy_version"`
+}
+
+// DecisionBundle is the claim set of a signed bundle of decisions.
+// Bundles are numbered in sequence and chained by the hash of the
+// previous bundle, so removed or reordered bundles are evident.
+type DecisionBundle struct {
+	IssuedAt int64  `json:"iat"`
+	Sequence uint64 `json:"seq"`
+
+	// Previous is the base64url encoded SHA-256 hash of the previous
+	// bundle, empty for the first.
+	Previous string `json:"prev,omitempty"`
+
+	// Dropped is the number of decisions dropped since the previous
+	// bundle, while the sink was failing.
+	Dropped uint64 `json:"dropped,omitempty"`
+
+	Decisions []Decision `json:"decisions"`
+}
+
+// DecisionSink receives signed decision bundles, such as a file, queue or
+// audit service. A bundle that fails to be written is retried at the next
+// flush.
+type DecisionSink interface {
+	WriteBundle(bundle []byte) error
+}
+
+// DecisionSinkFunc adapts a function to a DecisionSink.
+type DecisionSinkFunc func(bundle []byte) error
+
+// WriteBundle calls the function.
+func (f DecisionSinkFunc) WriteBundle(bundle []byte) error {
+	return f(bundle)
+}
+
+// DecisionLog batches verification decisions and periodically writes them
+// to a sink as JWS bundles signed by the DecisionLog's own key, giving
+// regulated workloads a tamper-evident audit trail. Decisions are flushed
+// every interval, or as soon as a batch fills.
+//
+// Close must be called to flush the remaining decisions and stop the
+// DecisionLog.
+type DecisionLog struct {
+	signer        *JOSESignerVerifier
+	sink          DecisionSink
+	policyVersion func() uint64
+	maxBatch      int
+
+	// OnError is called with sink and signing failures, or they are
+	// logged if nil. It must be set before decisions are recorded.
+	OnError func(error)
+
+	mu      sync.Mutex
+	pending []Decision
+	dropped uint64
+
+	// flushMu serializes flushes, so bundles are written in sequence.
+	flushMu  sync.Mutex
+	sequence uint64
+	previous string
+
+	flush     chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+	stopped   sync.WaitGroup
+}
+
+// NewDecisionLog starts a DecisionLog signing bundles of up to maxBatch
+// decisions with signer, and writing them to sink every interval. Each
+// decision records the policy version returned by policyVersion, such as
+// ControlPlane.Version, if it is not nil.
+func NewDecisionLog(signer *JOSESignerVerifier, sink DecisionSink, interval time.Duration, maxBatch int, policyVersion func() uint64) (*DecisionLog, error) {
+	if nil == signer || nil == signer.signer {
+		return nil, errors.New("Decision log requires a JOSESignerVerifier able to sign")
+	}
+	if nil == sink {
+		return nil, errors.New("Decision log requires a sink")
+	}
+	if interval <= 0 || maxBatch <= 0 {
+		return nil, errors.New("Decision log interval and batch size must be positive")
+	}
+
+	l := &DecisionLog{
+		signer:        signer,
+		sink:          sink,
+		policyVersion: policyVersion,
+		maxBatch:      maxBatch,
+		flush:         make(chan struct{}, 1),
+		done:          make(chan struct{}),
+	}
+
+	l.stopped.Add(1)
+	go l.run(interval)
+
+	return l, nil
+}
+
+// Record records the outcome of verifying a token: valid if err is nil,
+// otherwise invalid for the reason err gives.
+func (l *DecisionLog) Record(rawToken []byte, err error) {
+	sum := sha256.Sum256(rawToken)
+	decision := Decision{
+		Time:        time.Now().UTC(),
+		Fingerprint: Base64URLEncode(sum[:]),
+		Result:      DecisionValid,
+	}
+	if nil != err {
+		decision.Result = DecisionInvalid
+		decision.Reason = err.Error()
+	}
+	if nil != l.policyVersion {
+		decision.PolicyVersion = l.policyVersion()
+	}
+
+	var header Header
+	if token, err := GetRawTokenParts(rawToken); nil == err && nil == GetHeader(token, &header) {
+		decision.KeyID = header.KeyID
+	}
+
+	l.mu.Lock()
+	l.pending = append(l.pending, decision)
+	l.trim()
+	full := len(l.pending) >= l.maxBatch
+	l.mu.Unlock()
+
+	if full {
+		select {
+		case l.flush <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Wrap returns a VerifyFunc verifying tokens with verify and recording
+// every decision.
+func (l *DecisionLog) Wrap(verify VerifyFunc) VerifyFunc {
+	return func(rawToken []byte) (*Token, error) {
+		token, err := verify(rawToken)
+		l.Record(rawToken, err)
+		return token, err
+	}
+}
+
+// Flush signs and writes all pending decisions, returning the first error.
+// Decisions of bundles that fail to be written are retried at the next
+// flush.
+func (l *DecisionLog) Flush() error {
+	l.flushMu.Lock()
+	defer l.flushMu.Unlock()
+
+	for {
+		l.mu.Lock()
+		n := len(l.pending)
+		if n > l.maxBatch {
+			n = l.maxBatch
+		}
+		batch := append([]Decision(nil), l.pending[:n]...)
+		l.pending = l.pending[n:]
+		dropped := l.dropped
+		l.dropped = 0
+		l.mu.Unlock()
+
+		if n == 0 && dropped == 0 {
+			return nil
+		}
+
+		if err := l.write(batch, dropped); nil != err {
+			l.mu.Lock()
+			l.pending = append(batch, l.pending...)
+			l.dropped += dropped
+			l.trim()
+			l.mu.Unlock()
+			return err
+		}
+	}
+}
+
+// trim drops the oldest pending decisions beyond the pending limit. l.mu
+// must be held.
+func (l *DecisionLog) trim() {
+	if overflow := len(l.pending) - maxPendingBatches*l.maxBatch; overflow > 0 {
+		l.pending = l.pending[overflow:]
+		l.dropped += uint64(overflow)
+	}
+}
+
+// write signs a bundle of the decisions and writes it to the sink.
+func (l *DecisionLog) write(decisions []Decision, dropped uint64) error {
+	bundle, err := l.signer.GenerateToken(
+		Header{
+			Algorithm: string(l.signer.algorithm),
+			Type:      DecisionBundleType,
+		},
+		DecisionBundle{
+			IssuedAt:  time.Now().Unix(),
+			Sequence:  l.sequence + 1,
+			Previous:  l.previous,
+			Dropped:   dropped,
+			Decisions: decisions,
+		},
+	)
+	if nil != err {
+		return err
+	}
+
+	if err := l.sink.WriteBundle(bundle); nil != err {
+		return err
+	}
+
+	l.sequence++
+	l.previous = DecisionBundleHash(bundle)
+	return nil
+}
+
+// Close flushes the pending decisions and stops the DecisionLog, returning
+// the error of the final flush. Decisions recorded after Close are only
+// written by further calls to Flush.
+func (l *DecisionLog) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.done)
+	})
+	l.stopped.Wait()
+
+	return l.Flush()
+}
+
+func (l *DecisionLog) run(interval time.Duration) {
+	defer l.stopped.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.done:
+			return
+		case <-ticker.C:
+		case <-l.flush:
+		}
+
+		if err := l.Flush(); nil != err {
+			l.reportError(err)
+		}
+	}
+}
+
+func (l *DecisionLog) reportError(err error) {
+	if nil != l.OnError {
+		l.OnError(err)
+		return
+	}
+
+	log.Printf("Decision log flush failed: %v", err)
+}
+
+// VerifyDecisionBundle verifies a decision bundle's signature and type,
+// returning its claims. Auditors should also check that sequence numbers
+// are consecutive and each bundle's Previous is the hash of the bundle
+// before it, see DecisionBundleHash.
+func (sv *JOSESignerVerifier) VerifyDecisionBundle(rawBundle []byte) (*DecisionBundle, error) {
+	token, valid, err := sv.VerifySignature(rawBundle)
+	if nil != err {
+		return nil, err
+	}
+	if !valid {
+		return nil, errors.New("Decision bundle signature is invalid")
+	}
+
+	if token.RegisteredHeader.Type != DecisionBundleType {
+		return nil, fmt.Errorf("Expected typ %q, received %q", DecisionBundleType, token.RegisteredHeader.Type)
+	}
+
+	var bundle DecisionBundle
+	if err := json.Unmarshal(token.DecodedBody, &bundle); nil != err {
+		return nil, err
+	}
+
+	return &bundle, nil
+}
+
+// DecisionBundleHash returns the hash chaining the bundle to the next, as
+// held in the next bundle's Previous.
+func DecisionBundleHash(rawBundle []byte) string {
+	sum := sha256.Sum256(rawBundle)
+	return Base64URLEncode(sum[:])
+}
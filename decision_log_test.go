@@ -0,0 +1,165 @@
+package jwt
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// bundleSink collects bundles, failing while failing is set.
+type bundleSink struct {
+	mu      sync.Mutex
+	failing bool
+	bundles [][]byte
+}
+
+func (s *bundleSink) WriteBundle(bundle []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failing {
+		return errors.New("Sink unavailable")
+	}
+	s.bundles = append(s.bundles, bundle)
+	return nil
+}
+
+func (s *bundleSink) setFailing(failing bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failing = failing
+}
+
+// verifyBundles verifies the chain of bundles, returning their decisions
+// and the number dropped.
+func verifyBundles(t *testing.T, sv *JOSESignerVerifier, bundles [][]byte) ([]Decision, uint64) {
+	t.Helper()
+
+	var decisions []Decision
+	var dropped uint64
+	previous := ""
+	for i, rawBundle := range bundles {
+		bundle, err := sv.VerifyDecisionBundle(rawBundle)
+		if nil != err {
+			t.Fatalf("VerifyDecisionBundle() error = %v", err)
+		}
+		if bundle.Sequence != uint64(i+1) || bundle.Previous != previous {
+			t.Errorf("Bundle %d has sequence %d and previous %q, want %d and %q", i, bundle.Sequence, bundle.Previous, i+1, previous)
+		}
+		previous = DecisionBundleHash(rawBundle)
+		decisions = append(decisions, bundle.Decisions...)
+		dropped += bundle.Dropped
+	}
+
+	return decisions, dropped
+}
+
+func TestDecisionLog(t *testing.T) {
+	auditor, _ := NewJOSESignerVerifier(HS256, bytes.Repeat([]byte{7}, 32))
+	sv, _ := NewJOSESignerVerifier(HS256, bytes.Repeat([]byte{1}, 32))
+	other, _ := NewJOSESignerVerifier(HS256, bytes.Repeat([]byte{2}, 32))
+
+	sink := &bundleSink{}
+	decisionLog, err := NewDecisionLog(auditor, sink, time.Hour, 2, func() uint64 { return 42 })
+	if nil != err {
+		t.Fatalf("NewDecisionLog() error = %v", err)
+	}
+	verify := decisionLog.Wrap(VerifyTokenFunc(sv, &ValidationClaims{Issuer: []string{"issuer"}}))
+
+	valid, _ := sv.GenerateToken(Header{Algorithm: "HS256", KeyID: "key-1"}, Claims{Issuer: "issuer"})
+	forged, _ := other.GenerateToken(Header{Algorithm: "HS256", KeyID: "key-1"}, Claims{Issuer: "issuer"})
+	for _, token := range [][]byte{valid, forged, valid} {
+		verify(token)
+	}
+
+	if err := decisionLog.Close(); nil != err {
+		t.Fatalf("DecisionLog.Close() error = %v", err)
+	}
+
+	decisions, dropped := verifyBundles(t, auditor, sink.bundles)
+	if len(decisions) != 3 || dropped != 0 {
+		t.Fatalf("DecisionLog wrote %d decisions, dropped %d, want 3 and 0", len(decisions), dropped)
+	}
+	wantResults := []string{DecisionValid, DecisionInvalid, DecisionValid}
+	for i, decision := range decisions {
+		if decision.Result != wantResults[i] || decision.KeyID != "key-1" || decision.PolicyVersion != 42 {
+			t.Errorf("Decision %d = %+v", i, decision)
+		}
+		if (decision.Reason == "") != (decision.Result == DecisionValid) {
+			t.Errorf("Decision %d reason = %q", i, decision.Reason)
+		}
+	}
+	if decisions[0].Fingerprint != decisions[2].Fingerprint || decisions[0].Fingerprint == decisions[1].Fingerprint {
+		t.Errorf("Decision fingerprints = %q, %q, %q", decisions[0].Fingerprint, decisions[1].Fingerprint, decisions[2].Fingerprint)
+	}
+
+	if _, err := sv.VerifyDecisionBundle(sink.bundles[0]); nil == err {
+		t.Errorf("VerifyDecisionBundle() expected error for a bundle signed by another key")
+	}
+	if _, err := auditor.VerifyDecisionBundle(valid); nil == err {
+		t.Errorf("VerifyDecisionBundle() expected error for a token of another type")
+	}
+}
+
+func TestDecisionLog_FailingSink(t *testing.T) {
+	auditor, _ := NewJOSESignerVerifier(HS256, bytes.Repeat([]byte{7}, 32))
+
+	sink := &bundleSink{failing: true}
+	decisionLog, _ := NewDecisionLog(auditor, sink, time.Hour, 1, nil)
+	decisionLog.OnError = func(error) {}
+
+	// More decisions than the log holds while the sink fails.
+	records := maxPendingBatches + 4
+	for i := 0; i < records; i++ {
+		decisionLog.Record([]byte("token"), nil)
+	}
+
+	if err := decisionLog.Flush(); nil == err {
+		t.Errorf("DecisionLog.Flush() expected error while the sink fails")
+	}
+
+	sink.setFailing(false)
+	if err := decisionLog.Close(); nil != err {
+		t.Fatalf("DecisionLog.Close() error = %v", err)
+	}
+
+	decisions, dropped := verifyBundles(t, auditor, sink.bundles)
+	if len(decisions) != maxPendingBatches || dropped != 4 {
+		t.Errorf("DecisionLog wrote %d decisions, dropped %d, want %d and 4", len(decisions), dropped, maxPendingBatches)
+	}
+}
+
+func TestNewDecisionLog(t *testing.T) {
+	signer, _ := NewJOSESignerVerifier(HS256, bytes.Repeat([]byte{7}, 32))
+	verifier, _ := NewJOSESignerVerifier(ES256, &mustGenerateP256().PublicKey)
+	sink := DecisionSinkFunc(func([]byte) error { return nil })
+
+	tests := []struct {
+		name     string
+		signer   *JOSESignerVerifier
+		sink     DecisionSink
+		interval time.Duration
+		maxBatch int
+		wantErr  bool
+	}{
+		{"Must create a decision log", signer, sink, time.Second, 100, false},
+		{"Must fail without a signing key", verifier, sink, time.Second, 100, true},
+		{"Must fail without a sink", signer, nil, time.Second, 100, true},
+		{"Must fail without an interval", signer, sink, 0, 100, true},
+		{"Must fail without a batch size", signer, sink, time.Second, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decisionLog, err := NewDecisionLog(tt.signer, tt.sink, tt.interval, tt.maxBatch, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewDecisionLog() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if nil != decisionLog {
+				decisionLog.Close()
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package jwt
+
+import (
+	"errors"
+	"log"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/georgejenkins/jwt/jws"
+)
+
+// AlgorithmStatus is the deprecation status of an algorithm.
+type AlgorithmStatus string
+
+const (
+	// AlgorithmWarn accepts the algorithm, but counts and logs its use.
+	AlgorithmWarn AlgorithmStatus = "warn"
+	// AlgorithmDeny rejects the algorithm.
+	AlgorithmDeny AlgorithmStatus = "deny"
+)
+
+// ErrAlgorithmDenied is returned when signing or verifying with an
+// algorithm denied by WithDeniedAlgorithms.
+var ErrAlgorithmDenied = errors.New("Algorithm is denied by policy")
+
+// ErrNoneNotAllowed is returned when verifying an unsigned token, with the
+// 'none' algorithm, without AllowNone.
+var ErrNoneNotAllowed = errors.New("Unsigned tokens with the 'none' algorithm are not allowed")
+
+// ErrAlgorithmNotAllowed is returned when verifying a token whose 'alg' is
+// not allowed by WithAllowedAlgorithms.
+var ErrAlgorithmNotAllowed = errors.New("Algorithm is not allowed by policy")
+
+// AlgorithmUsage counts the tokens signed and verified with an algorithm.
+type AlgorithmUsage struct {
+	Signed   uint64 `json:"signed"`
+	Verified uint64 `json:"verified"`
+	// Warned counts the tokens signed or verified while the algorithm was
+	// deprecated, and Denied those rejected because it was denied.
+	Warned uint64 `json:"warned"`
+	Denied uint64 `json:"denied"`
+}
+
+// OtherAlgorithms is the key in AlgorithmMetrics counting the tokens
+// signed or verified with an algorithm that is neither supported nor
+// configured by WithDeprecatedAlgorithms or WithDeniedAlgorithms, so
+// arbitrary 'alg' headers can't grow the metrics.
+const OtherAlgorithms Algorithm = "other"
+
+// algorithmCounter holds the usage counts of an algorithm, updated
+// atomically so counting doesn't serialise verification.
+type algorithmCounter struct {
+	signed   uint64
+	verified uint64
+	warned   uint64
+	denied   uint64
+	logged   uint32
+}
+
+// algorithmCounters maps algorithms to their counters. The map is never
+// modified once stored: algorithms configured with a status are added by
+// copying it, so it is read without locking.
+var algorithmCounters struct {
+	mu       sync.Mutex
+	counters atomic.Value
+}
+
+func init() {
+	counters := make(map[Algorithm]*algorithmCounter)
+	for _, alg := range []Algorithm{
+		HS256, HS384, HS512, RS256, RS384, RS512, ES256, ES384, ES512,
+		PS256, PS384, PS512, EdDSA, None, OtherAlgorithms,
+	} {
+		counters[alg] = &algorithmCounter{}
+	}
+	algorithmCounters.counters.Store(counters)
+}
+
+// registerAlgorithms adds counters for the algorithms, which are
+// configured rather than read from tokens.
+func registerAlgorithms(algs []Algorithm) {
+	algorithmCounters.mu.Lock()
+	defer algorithmCounters.mu.Unlock()
+
+	counters := algorithmCounters.counters.Load().(map[Algorithm]*algorithmCounter)
+	updated := make(map[Algorithm]*algorithmCounter, len(counters)+len(algs))
+	for alg, counter := range counters {
+		updated[alg] = counter
+	}
+	for _, alg := range algs {
+		if _, ok := updated[alg]; !ok {
+			updated[alg] = &algorithmCounter{}
+		}
+	}
+	algorithmCounters.counters.Store(updated)
+}
+
+// algorithmCounterFor returns the counter of the algorithm, or that of
+// OtherAlgorithms if the algorithm has none.
+func algorithmCounterFor(alg Algorithm) *algorithmCounter {
+	counters := algorithmCounters.counters.Load().(map[Algorithm]*algorithmCounter)
+	if counter, ok := counters[alg]; ok {
+		return counter
+	}
+
+	return counters[OtherAlgorithms]
+}
+
+// WithDeprecatedAlgorithms marks algorithms as deprecated: tokens are
+// still signed and verified with them, but each use is counted in
+// AlgorithmMetrics and the first is logged. Together with
+// WithDeniedAlgorithms this allows an algorithm to be retired across a
+// fleet without downtime: deprecate it, watch its use fall, then deny it.
+func WithDeprecatedAlgorithms(algs ...Algorithm) Option {
+	return withAlgorithmStatus(AlgorithmWarn, algs)
+}
+
+// WithDeniedAlgorithms rejects tokens signed with the algorithms, and
+// refuses to sign with them, returning ErrAlgorithmDenied.
+func WithDeniedAlgorithms(algs ...Algorithm) Option {
+	return withAlgorithmStatus(AlgorithmDeny, algs)
+}
+
+// WithAllowedAlgorithms rejects tokens whose 'alg' header is not one of
+// the algorithms with ErrAlgorithmNotAllowed, before their key is resolved
+// or their signature checked. Pinning the algorithms a verifier accepts
+// protects against algorithm confusion, such as HS256 tokens verified
+// with the bytes of an RSA public key, particularly for verifiers
+// resolving keys per token.
+func WithAllowedAlgorithms(algs ...Algorithm) Option {
+	return func(sv *JOSESignerVerifier) error {
+		if len(algs) == 0 {
+			return errors.New("At least one algorithm is required")
+		}
+
+		sv.allowedAlgs = make(map[Algorithm]bool, len(algs))
+		for _, alg := range algs {
+			sv.allowedAlgs[alg] = true
+		}
+		return nil
+	}
+}
+
+// AllowNone accepts unsigned tokens, with the 'none' algorithm, when
+// verifying with a JOSESignerVerifier created by
+// NewInsecureJOSESignerVerifier. Without it, unsigned tokens are rejected
+// with ErrNoneNotAllowed by every verifier, so accepting them can't be
+// wired in by accident. Unsigned tokens are not authenticated at all.
+func AllowNone() Option {
+	return func(sv *JOSESignerVerifier) error {
+		if sv.algorithm != None {
+			return errors.New("AllowNone can only be used with NewInsecureJOSESignerVerifier")
+		}
+
+		sv.allowNone = true
+		return nil
+	}
+}
+
+func withAlgorithmStatus(status AlgorithmStatus, algs []Algorithm) Option {
+	return func(sv *JOSESignerVerifier) error {
+		if len(algs) == 0 {
+			return errors.New("At least one algorithm is required")
+		}
+
+		if nil == sv.algorithmStatus {
+			sv.algorithmStatus = make(map[Algorithm]AlgorithmStatus)
+		}
+		for _, alg := range algs {
+			sv.algorithmStatus[alg] = status
+		}
+		registerAlgorithms(algs)
+		return nil
+	}
+}
+
+// AlgorithmMetrics returns the number of tokens signed, verified, warned
+// about and denied since the process started, by algorithm. Algorithms
+// that are neither supported nor configured are counted together under
+// OtherAlgorithms. Algorithms that haven't been used are omitted.
+func AlgorithmMetrics() map[Algorithm]AlgorithmUsage {
+	counters := algorithmCounters.counters.Load().(map[Algorithm]*algorithmCounter)
+
+	metrics := make(map[Algorithm]AlgorithmUsage)
+	for alg, counter := range counters {
+		usage := AlgorithmUsage{
+			Signed:   atomic.LoadUint64(&counter.signed),
+			Verified: atomic.LoadUint64(&counter.verified),
+			Warned:   atomic.LoadUint64(&counter.warned),
+			Denied:   atomic.LoadUint64(&counter.denied),
+		}
+		if usage != (AlgorithmUsage{}) {
+			metrics[alg] = usage
+		}
+	}
+
+	return metrics
+}
+
+// checkAlgorithm counts the use of the algorithm to sign or verify a token,
+// returning ErrAlgorithmDenied if the policy denies it, or
+// ErrAlgorithmNotAllowed or ErrNoneNotAllowed if it is not allowed to
+// verify. Algorithms not allowed are not counted, and algorithms without
+// a counter are counted under OtherAlgorithms, so tokens can't grow the
+// usage counts with arbitrary 'alg' values.
+func (sv *JOSESignerVerifier) checkAlgorithm(alg Algorithm, signing bool) error {
+	if !signing && alg == None && !sv.allowNone {
+		return ErrNoneNotAllowed
+	}
+	if !signing && nil != sv.allowedAlgs && !sv.allowedAlgs[alg] {
+		return ErrAlgorithmNotAllowed
+	}
+
+	counter := algorithmCounterFor(alg)
+
+	switch sv.algorithmStatus[alg] {
+	case AlgorithmDeny:
+		atomic.AddUint64(&counter.denied, 1)
+		return ErrAlgorithmDenied
+	case AlgorithmWarn:
+		atomic.AddUint64(&counter.warned, 1)
+		if atomic.CompareAndSwapUint32(&counter.logged, 0, 1) {
+			log.Printf("Deprecated algorithm %s in use, see AlgorithmMetrics", alg)
+		}
+	}
+
+	if signing {
+		atomic.AddUint64(&counter.signed, 1)
+	} else {
+		atomic.AddUint64(&counter.verified, 1)
+	}
+	return nil
+}
+
+// checkVerifierAlgorithm returns an error wrapping ErrAlgorithmKeyMismatch
+// if the token's 'alg' is not of the family of the verifier's key, however
+// the verifier was configured, so a key is never used with an algorithm
+// of another family.
+func checkVerifierAlgorithm(verifier TokenVerifier, header Header) error {
+	checker, ok := verifier.(jws.AlgorithmChecker)
+	if !ok {
+		return nil
+	}
+
+	return checker.CheckAlgorithm(Algorithm(header.Algorithm))
+}
+
+// allowedAlgorithmList returns the algorithms allowed to verify, sorted.
+func (sv *JOSESignerVerifier) allowedAlgorithmList() []Algorithm {
+	var algs []Algorithm
+	for alg := range sv.allowedAlgs {
+		algs = append(algs, alg)
+	}
+
+	sort.Slice(algs, func(i, j int) bool { return algs[i] < algs[j] })
+	return algs
+}
+
+// algorithmsWithStatus returns the algorithms with the status, sorted.
+func (sv *JOSESignerVerifier) algorithmsWithStatus(status AlgorithmStatus) []Algorithm {
+	var algs []Algorithm
+	for alg, s := range sv.algorithmStatus {
+		if s == status {
+			algs = append(algs, alg)
+		}
+	}
+
+	sort.Slice(algs, func(i, j int) bool { return algs[i] < algs[j] })
+	return algs
+}
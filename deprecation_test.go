@@ -0,0 +1,235 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/georgejenkins/jwt/jwk"
+)
+
+func TestAlgorithmDeprecation(t *testing.T) {
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	set := &jwk.Set{Keys: []*jwk.Key{
+		{KeyID: "ec", Key: &ecKey.PublicKey},
+		{KeyID: "hmac", Key: exampleKey},
+	}}
+	verifier, err := NewKeyResolvingVerifier(KeySetResolver(set),
+		WithDeprecatedAlgorithms(ES256),
+		WithDeniedAlgorithms(HS256),
+	)
+	if nil != err {
+		t.Fatalf("NewKeyResolvingVerifier() error = %v", err)
+	}
+
+	ecSigner, _ := NewJOSESignerVerifier(ES256, ecKey)
+	hmacSigner, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	ecToken, _ := ecSigner.GenerateToken(Header{Algorithm: string(ES256), KeyID: "ec"}, Claims{Subject: "alice"})
+	hmacToken, _ := hmacSigner.GenerateToken(Header{Algorithm: string(HS256), KeyID: "hmac"}, Claims{Subject: "alice"})
+
+	before := AlgorithmMetrics()
+
+	tests := []struct {
+		name      string
+		token     []byte
+		wantValid bool
+		wantErr   error
+	}{
+		{"Must verify a deprecated algorithm", ecToken, true, nil},
+		{"Must not verify a denied algorithm", hmacToken, false, ErrAlgorithmDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, valid, err := verifier.VerifySignature(tt.token)
+			if valid != tt.wantValid || err != tt.wantErr {
+				t.Errorf("VerifySignature() = %v, %v, want %v, %v", valid, err, tt.wantValid, tt.wantErr)
+			}
+		})
+	}
+
+	after := AlgorithmMetrics()
+	if got := after[ES256].Warned - before[ES256].Warned; got != 1 {
+		t.Errorf("AlgorithmMetrics() ES256 warned %d times, want 1", got)
+	}
+	if got := after[ES256].Verified - before[ES256].Verified; got != 1 {
+		t.Errorf("AlgorithmMetrics() ES256 verified %d times, want 1", got)
+	}
+	if got := after[HS256].Denied - before[HS256].Denied; got != 1 {
+		t.Errorf("AlgorithmMetrics() HS256 denied %d times, want 1", got)
+	}
+
+	deniedSigner, _ := NewJOSESignerVerifier(HS256, exampleKey, WithDeniedAlgorithms(HS256))
+	if _, err := deniedSigner.GenerateToken(Header{Algorithm: string(HS256)}, Claims{}); err != ErrAlgorithmDenied {
+		t.Errorf("GenerateToken() error = %v, want %v", err, ErrAlgorithmDenied)
+	}
+
+	policy := verifier.Policy()
+	if len(policy.DeprecatedAlgorithms) != 1 || policy.DeprecatedAlgorithms[0] != ES256 ||
+		len(policy.DeniedAlgorithms) != 1 || policy.DeniedAlgorithms[0] != HS256 {
+		t.Errorf("Policy() = %+v, want ES256 deprecated and HS256 denied", policy)
+	}
+
+	if _, err := NewJOSESignerVerifier(HS256, exampleKey, WithDeniedAlgorithms()); nil == err {
+		t.Errorf("NewJOSESignerVerifier() expected error for an empty algorithm list")
+	}
+}
+
+func TestWithAllowedAlgorithms(t *testing.T) {
+	if _, err := NewJOSESignerVerifier(HS256, exampleKey, WithAllowedAlgorithms()); nil == err {
+		t.Errorf("WithAllowedAlgorithms() expected error without algorithms")
+	}
+
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	resolved := 0
+	resolver := KeyResolverFunc(func(header Header) (*jwk.Key, error) {
+		resolved++
+		if header.KeyID == "hmac" {
+			return &jwk.Key{KeyID: "hmac", Key: exampleKey}, nil
+		}
+		return &jwk.Key{KeyID: "ec", Key: &ecKey.PublicKey}, nil
+	})
+	verifier, _ := NewKeyResolvingVerifier(resolver, WithAllowedAlgorithms(RS256, ES256))
+
+	ecSigner, _ := NewJOSESignerVerifier(ES256, ecKey)
+	hmacSigner, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	ecToken, _ := ecSigner.GenerateToken(Header{Algorithm: string(ES256), KeyID: "ec"}, Claims{Subject: "alice"})
+	hmacToken, _ := hmacSigner.GenerateToken(Header{Algorithm: string(HS256), KeyID: "hmac"}, Claims{Subject: "alice"})
+
+	tests := []struct {
+		name         string
+		token        []byte
+		wantValid    bool
+		wantErr      error
+		wantResolved int
+	}{
+		{"Must verify a token with an allowed algorithm", ecToken, true, nil, 1},
+		{"Must reject a token with another algorithm before resolving its key", hmacToken, false, ErrAlgorithmNotAllowed, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved = 0
+			_, valid, err := verifier.VerifySignature(tt.token)
+			if err != tt.wantErr {
+				t.Fatalf("VerifySignature() error = %v, want %v", err, tt.wantErr)
+			}
+			if valid != tt.wantValid {
+				t.Errorf("VerifySignature() valid = %v, want %v", valid, tt.wantValid)
+			}
+			if resolved != tt.wantResolved {
+				t.Errorf("VerifySignature() resolved %d keys, want %d", resolved, tt.wantResolved)
+			}
+		})
+	}
+
+	restrictedSigner, _ := NewJOSESignerVerifier(HS256, exampleKey, WithAllowedAlgorithms(ES256))
+	if _, err := restrictedSigner.GenerateToken(Header{Algorithm: string(HS256)}, Claims{}); nil != err {
+		t.Errorf("GenerateToken() error = %v, signing is not restricted", err)
+	}
+	if policy := verifier.Policy(); len(policy.AllowedAlgorithms) != 2 || policy.AllowedAlgorithms[0] != ES256 {
+		t.Errorf("Policy() allowed algorithms = %v, want [ES256 RS256]", policy.AllowedAlgorithms)
+	}
+}
+
+func TestVerifySignature_AlgorithmKeyMismatch(t *testing.T) {
+	_, rsaSV, _ := GenerateKey(RS256)
+	_, ecSV, _ := GenerateKey(ES256)
+	hmacSV, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	_, ec384SV, _ := GenerateKey(ES384)
+
+	sign := func(sv *JOSESignerVerifier, alg Algorithm) []byte {
+		token, _ := sv.GenerateToken(Header{Algorithm: string(alg)}, Claims{Subject: "alice"})
+		return token
+	}
+
+	tests := []struct {
+		name         string
+		verifier     *JOSESignerVerifier
+		token        []byte
+		wantValid    bool
+		wantMismatch bool
+	}{
+		{"Must verify a token with the key's algorithm", rsaSV, sign(rsaSV, RS256), true, false},
+		{"Must reject an HS256 token for an RSA key", rsaSV, sign(hmacSV, HS256), false, true},
+		{"Must reject an RS256 token for an HMAC key", hmacSV, sign(rsaSV, RS256), false, true},
+		{"Must reject an ES384 token for a P-256 key", ecSV, sign(ec384SV, ES384), false, true},
+		{"Must reject an ES256 token for an RSA key", rsaSV, sign(ecSV, ES256), false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, valid, err := tt.verifier.VerifySignature(tt.token)
+			if errors.Is(err, ErrAlgorithmKeyMismatch) != tt.wantMismatch {
+				t.Fatalf("VerifySignature() error = %v, wantMismatch %v", err, tt.wantMismatch)
+			}
+			if valid != tt.wantValid {
+				t.Errorf("VerifySignature() valid = %v, want %v", valid, tt.wantValid)
+			}
+		})
+	}
+}
+
+func TestAllowNone(t *testing.T) {
+	if _, err := NewJOSESignerVerifier(HS256, exampleKey, AllowNone()); nil == err {
+		t.Errorf("AllowNone() expected error for a keyed JOSESignerVerifier")
+	}
+
+	insecure, _ := NewInsecureJOSESignerVerifier(None)
+	allowing, _ := NewInsecureJOSESignerVerifier(None, AllowNone())
+	hmacSV, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	resolving, _ := NewKeyResolvingVerifier(KeySetResolver(&jwk.Set{}))
+	unsigned, _ := insecure.GenerateToken(Header{Algorithm: string(None)}, Claims{Subject: "alice"})
+	signed, _ := hmacSV.GenerateToken(Header{Algorithm: string(HS256)}, Claims{Subject: "alice"})
+
+	tests := []struct {
+		name      string
+		verifier  *JOSESignerVerifier
+		token     []byte
+		wantValid bool
+		wantErr   error
+	}{
+		{"Must verify an unsigned token with AllowNone", allowing, unsigned, true, nil},
+		{"Must reject an unsigned token without AllowNone", insecure, unsigned, false, ErrNoneNotAllowed},
+		{"Must reject an unsigned token for a keyed verifier", hmacSV, unsigned, false, ErrNoneNotAllowed},
+		{"Must reject an unsigned token for a key resolving verifier", resolving, unsigned, false, ErrNoneNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, valid, err := tt.verifier.VerifySignature(tt.token)
+			if err != tt.wantErr {
+				t.Fatalf("VerifySignature() error = %v, want %v", err, tt.wantErr)
+			}
+			if valid != tt.wantValid {
+				t.Errorf("VerifySignature() valid = %v, want %v", valid, tt.wantValid)
+			}
+		})
+	}
+
+	if _, valid, _ := allowing.VerifySignature(signed); valid {
+		t.Errorf("VerifySignature() = true for a signed token with AllowNone")
+	}
+}
+
+func TestAlgorithmMetrics_ForgedAlgorithms(t *testing.T) {
+	hmacSV, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	before := AlgorithmMetrics()
+
+	for i := 0; i < 100; i++ {
+		header := Base64URLEncode([]byte(fmt.Sprintf(`{"alg":"forged-%d","typ":"JWT"}`, i)))
+		token := header + "." + Base64URLEncode([]byte(`{"sub":"alice"}`)) + ".c2lnbmF0dXJl"
+		if _, valid, _ := hmacSV.VerifySignature([]byte(token)); valid {
+			t.Fatalf("VerifySignature() = true for a forged algorithm")
+		}
+	}
+
+	after := AlgorithmMetrics()
+	for alg := range after {
+		if _, ok := before[alg]; !ok && alg != OtherAlgorithms {
+			t.Errorf("AlgorithmMetrics() counts forged algorithm %s", alg)
+		}
+	}
+	if got := after[OtherAlgorithms].Verified - before[OtherAlgorithms].Verified; got != 100 {
+		t.Errorf("AlgorithmMetrics() other verified %d times, want 100", got)
+	}
+}
@@ -0,0 +1,219 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/georgejenkins/jwt/jws"
+)
+
+// SignDetached signs a payload read from r, such as a multi-megabyte
+// document or build artifact, returning a JWS with a detached payload
+// (RFC 7515, appendix F): the header and signature with an empty payload
+// part. The payload is base64url encoded into the hash as it is read, so
+// it is never held in memory; with a 'b64' header of false, as set by
+// UnencodedPayloadHeader, it is hashed as is (RFC 7797). EdDSA can't sign
+// incrementally, and is not supported.
+func (sv *JOSESignerVerifier) SignDetached(header interface{}, payload io.Reader) (token []byte, err error) {
+	defer recoverInternal("SignDetached", &err)
+
+	if nil == sv.signer {
+		return nil, errors.New("JOSESignerVerifier not configured for signing - did you provide the correct key type?")
+	}
+
+	signer, ok := sv.signer.(jws.HashSigner)
+	if !ok {
+		return nil, fmt.Errorf("Algorithm %s cannot sign streamed payloads", sv.algorithm)
+	}
+
+	if err := sv.checkAlgorithm(sv.algorithm, true); nil != err {
+		return nil, err
+	}
+
+	joseHeader, err := json.Marshal(header)
+	if nil != err {
+		return nil, err
+	}
+	encodedHeader := Base64URLEncode(joseHeader)
+
+	encodePayload, err := payloadEncoding(joseHeader)
+	if nil != err {
+		return nil, err
+	}
+
+	h, err := signer.NewHash()
+	if nil != err {
+		return nil, err
+	}
+	if err := hashSigningInput(h, encodedHeader, payload, encodePayload); nil != err {
+		return nil, err
+	}
+
+	signature, err := signer.SignHash(h)
+	if nil != err {
+		return nil, err
+	}
+
+	return []byte(encodedHeader + ".." + Base64URLEncode(signature)), nil
+}
+
+// VerifyDetached verifies the signature of a JWS with a detached payload
+// over the payload read from r, streaming it through the hash as
+// SignDetached does, unencoded if the 'b64' header is false. It does NO
+// other validation of the header, and the payload is not a claim set.
+func (sv *JOSESignerVerifier) VerifyDetached(rawToken []byte, payload io.Reader) (token *Token, valid bool, err error) {
+	defer func() {
+		if r := recover(); nil != r {
+			token, valid, err = nil, false, newInternalError("VerifyDetached", r)
+		}
+	}()
+
+	limits := sv.tokenSizeLimits.withDefaults()
+	if err := limits.checkTokenSize(rawToken); nil != err {
+		return nil, false, err
+	}
+
+	parts := bytes.Split(rawToken, []byte("."))
+	if len(parts) != 3 || len(parts[1]) != 0 {
+		return nil, false, errors.New("Detached JWS must have three parts with an empty payload")
+	}
+	if err := limits.checkPartSizes([]string{string(parts[0]), "", string(parts[2])}); nil != err {
+		return nil, false, err
+	}
+
+	token = &Token{
+		RawToken:     rawToken,
+		RawHeader:    parts[0],
+		RawSignature: parts[2],
+	}
+	if token.DecodedHeader, err = Base64URLDecode(string(parts[0])); nil != err {
+		return nil, false, err
+	}
+	if token.DecodedSignature, err = Base64URLDecode(string(parts[2])); nil != err {
+		return nil, false, err
+	}
+
+	if err := sv.checkStrictHeader(token.DecodedHeader); nil != err {
+		return nil, false, err
+	}
+
+	var header Header
+	if err := GetHeader(token, &header); nil != err {
+		return nil, false, err
+	}
+	token.RegisteredHeader = header
+
+	encodePayload, err := payloadEncoding(token.DecodedHeader)
+	if nil != err {
+		return nil, false, err
+	}
+	critical, err := sv.checkCritical(token.DecodedHeader, "b64")
+	if nil != err {
+		return nil, false, err
+	}
+	if err := sv.checkType(header); nil != err {
+		return nil, false, err
+	}
+
+	if err := sv.checkAlgorithm(Algorithm(header.Algorithm), false); nil != err {
+		return nil, false, err
+	}
+
+	verifier, provenance := sv.verifier, sv.provenance
+	if nil != sv.keyResolver {
+		verifier, provenance, err = sv.resolveVerifier(header)
+		if nil != err {
+			return nil, false, err
+		}
+	}
+
+	if err := checkVerifierAlgorithm(verifier, header); nil != err {
+		return nil, false, err
+	}
+
+	hashVerifier, ok := verifier.(jws.HashVerifier)
+	if !ok {
+		return nil, false, fmt.Errorf("Algorithm %s cannot verify streamed payloads", header.Algorithm)
+	}
+
+	h, err := hashVerifier.NewHash()
+	if nil != err {
+		return nil, false, err
+	}
+	if err := hashSigningInput(h, string(parts[0]), payload, encodePayload); nil != err {
+		return nil, false, err
+	}
+
+	valid, err = hashVerifier.VerifyHash(h, token.DecodedSignature)
+	token.signatureValid = valid
+	token.provenance = provenance.verifiedAt(time.Now())
+
+	if valid && nil == err {
+		if err := sv.processCritical(token, critical); nil != err {
+			return token, false, err
+		}
+	}
+
+	return token, valid, err
+}
+
+// UnencodedPayloadHeader returns the header with a 'b64' header of false,
+// listed as critical as RFC 7797 requires, so SignDetached signs the
+// payload without base64url encoding it. Recipients must support RFC 7797.
+func UnencodedPayloadHeader(header Header) Header {
+	b64 := false
+	header.Base64Payload = &b64
+	if !anyEquals(header.Critical, "b64") {
+		header.Critical = append(append([]string{}, header.Critical...), "b64")
+	}
+
+	return header
+}
+
+// payloadEncoding reports whether the payload of a JWS with the JSON
+// header is base64url encoded, as it is unless the 'b64' header is false.
+// A 'b64' header must be listed as critical.
+func payloadEncoding(joseHeader []byte) (bool, error) {
+	var header struct {
+		Critical      []string `json:"crit"`
+		Base64Payload *bool    `json:"b64"`
+	}
+	if err := json.Unmarshal(joseHeader, &header); nil != err {
+		return false, err
+	}
+
+	if nil == header.Base64Payload {
+		return true, nil
+	}
+	if !anyEquals(header.Critical, "b64") {
+		return false, errors.New("The 'b64' header parameter must be listed as critical")
+	}
+
+	return *header.Base64Payload, nil
+}
+
+// hashSigningInput writes the JWS signing input, the encoded header and
+// the payload joined by a '.', to w. The payload is base64url encoded if
+// encodePayload is set.
+func hashSigningInput(w io.Writer, encodedHeader string, payload io.Reader, encodePayload bool) error {
+	if _, err := io.WriteString(w, encodedHeader+"."); nil != err {
+		return err
+	}
+
+	if !encodePayload {
+		_, err := io.Copy(w, payload)
+		return err
+	}
+
+	encoder := base64.NewEncoder(base64.RawURLEncoding, w)
+	if _, err := io.Copy(encoder, payload); nil != err {
+		return err
+	}
+
+	return encoder.Close()
+}
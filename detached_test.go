@@ -0,0 +1,94 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"strings"
+	"testing"
+)
+
+func TestJOSESignerVerifier_SignDetached(t *testing.T) {
+	payload := bytes.Repeat([]byte("The Blue Stripes will ambush Radovid. "), 100000)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	edSV, _ := NewJOSESignerVerifier(EdDSA, &edKey)
+
+	for _, alg := range []Algorithm{HS256, ES256, RS256} {
+		t.Run("Must sign and verify a streamed payload with "+string(alg), func(t *testing.T) {
+			_, sv, _ := GenerateKey(alg)
+			header := Header{Algorithm: string(alg)}
+
+			token, err := sv.SignDetached(header, bytes.NewReader(payload))
+			if nil != err {
+				t.Fatalf("SignDetached() error = %v", err)
+			}
+			parts := strings.Split(string(token), ".")
+			if len(parts) != 3 || parts[1] != "" {
+				t.Fatalf("SignDetached() = %s, want a detached JWS", token)
+			}
+
+			if _, valid, err := sv.VerifyDetached(token, bytes.NewReader(payload)); !valid || nil != err {
+				t.Errorf("VerifyDetached() = %v, %v", valid, err)
+			}
+
+			// The detached JWS is the compact JWS without its payload.
+			attached := parts[0] + "." + Base64URLEncode(payload) + "." + parts[2]
+			if _, valid, err := sv.VerifySignature([]byte(attached)); !valid || nil != err {
+				t.Errorf("VerifySignature() = %v, %v with the payload attached", valid, err)
+			}
+
+			tampered := append(append([]byte{}, payload...), '!')
+			if _, valid, _ := sv.VerifyDetached(token, bytes.NewReader(tampered)); valid {
+				t.Errorf("VerifyDetached() = true for another payload")
+			}
+			if _, valid, _ := sv.VerifyDetached([]byte(attached), bytes.NewReader(payload)); valid {
+				t.Errorf("VerifyDetached() = true for a JWS with an attached payload")
+			}
+		})
+	}
+
+	if _, err := edSV.SignDetached(Header{Algorithm: string(EdDSA)}, bytes.NewReader(payload)); nil == err {
+		t.Errorf("SignDetached() expected error for EdDSA")
+	}
+}
+
+func TestJOSESignerVerifier_SignDetached_Unencoded(t *testing.T) {
+	// RFC 7797, section 4.2.
+	key, _ := Base64URLDecode("AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS0gZH75aKtMN3Yj0iPS4hcgUuTwjAzZr1Z9CAow")
+	sv, _ := NewJOSESignerVerifier(HS256, key)
+	payload := []byte("$.02")
+	want := "eyJhbGciOiJIUzI1NiIsImI2NCI6ZmFsc2UsImNyaXQiOlsiYjY0Il19..A5dxf2s96_n5FLueVuW1Z_vh161FwXZC4YLPff6dmDY"
+
+	token, err := sv.SignDetached(UnencodedPayloadHeader(Header{Algorithm: string(HS256)}), bytes.NewReader(payload))
+	if nil != err {
+		t.Fatalf("SignDetached() error = %v", err)
+	}
+	if string(token) != want {
+		t.Errorf("SignDetached() = %s, want %s", token, want)
+	}
+
+	notCritical := Base64URLEncode([]byte(`{"alg":"HS256","b64":false}`)) + "..A5dxf2s96_n5FLueVuW1Z_vh161FwXZC4YLPff6dmDY"
+	tests := []struct {
+		name      string
+		token     string
+		payload   []byte
+		wantValid bool
+		wantErr   bool
+	}{
+		{"Must verify an unencoded payload", want, payload, true, false},
+		{"Must not verify another unencoded payload", want, []byte("$.03"), false, false},
+		{"Must not verify the encoded payload", want, []byte(Base64URLEncode(payload)), false, false},
+		{"Must not verify an unencoded payload without a critical 'b64'", notCritical, payload, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, valid, err := sv.VerifyDetached([]byte(tt.token), bytes.NewReader(tt.payload))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifyDetached() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if valid != tt.wantValid {
+				t.Errorf("VerifyDetached() valid = %v, want %v", valid, tt.wantValid)
+			}
+		})
+	}
+}
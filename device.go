@@ -0,0 +1,176 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DeviceProfile issues and verifies minimal EdDSA-signed tokens for
+// memory-constrained devices. Tokens carry only a subject and a numeric
+// expiration, and are checked against a size budget as they are minted.
+type DeviceProfile struct {
+	sv         *JOSESignerVerifier
+	ttl        time.Duration
+	sizeBudget int
+}
+
+// DeviceToken is a minted device token and its size in bytes.
+type DeviceToken struct {
+	Token      []byte
+	Size       int
+	SizeBudget int
+	Expiration time.Time
+}
+
+// TokenSizeError is returned when a minted token exceeds its size budget.
+type TokenSizeError struct {
+	Size       int
+	SizeBudget int
+}
+
+func (e *TokenSizeError) Error() string {
+	return fmt.Sprintf("Token size %d bytes exceeds the budget of %d bytes", e.Size, e.SizeBudget)
+}
+
+// deviceClaims is the minimal claim set of device tokens.
+type deviceClaims struct {
+	Subject    string `json:"sub"`
+	Expiration int64  `json:"exp"`
+}
+
+// NewDeviceProfile initializes a DeviceProfile issuing tokens valid for
+// ttl which may be no larger than sizeBudget bytes. The JOSESignerVerifier
+// must use EdDSA, whose small keys and signatures suit constrained devices.
+func NewDeviceProfile(sv *JOSESignerVerifier, ttl time.Duration, sizeBudget int) (*DeviceProfile, error) {
+	if nil == sv || sv.algorithm != EdDSA {
+		return nil, errors.New("Device tokens must be signed with EdDSA")
+	}
+
+	if ttl <= 0 {
+		return nil, errors.New("Device token TTL must be positive")
+	}
+
+	if sizeBudget <= 0 {
+		return nil, errors.New("Device token size budget must be positive")
+	}
+
+	return &DeviceProfile{
+		sv:         sv,
+		ttl:        ttl,
+		sizeBudget: sizeBudget,
+	}, nil
+}
+
+// Mint issues a token for the device. A TokenSizeError is returned if the
+// token exceeds the size budget.
+func (p *DeviceProfile) Mint(deviceID string) (*DeviceToken, error) {
+	if deviceID == "" {
+		return nil, errors.New("Device ID cannot be empty")
+	}
+
+	expiration := time.Now().Add(p.ttl)
+	token, err := p.sv.GenerateToken(
+		struct {
+			Algorithm Algorithm `json:"alg"`
+		}{EdDSA},
+		deviceClaims{
+			Subject:    deviceID,
+			Expiration: expiration.Unix(),
+		},
+	)
+	if nil != err {
+		return nil, err
+	}
+
+	if len(token) > p.sizeBudget {
+		return nil, &TokenSizeError{
+			Size:       len(token),
+			SizeBudget: p.sizeBudget,
+		}
+	}
+
+	return &DeviceToken{
+		Token:      token,
+		Size:       len(token),
+		SizeBudget: p.sizeBudget,
+		Expiration: time.Unix(expiration.Unix(), 0),
+	}, nil
+}
+
+// Verify verifies a device token and returns the device ID.
+func (p *DeviceProfile) Verify(rawToken []byte) (string, error) {
+	if len(rawToken) > p.sizeBudget {
+		return "", &TokenSizeError{
+			Size:       len(rawToken),
+			SizeBudget: p.sizeBudget,
+		}
+	}
+
+	token, valid, err := p.sv.VerifySignature(rawToken)
+	if nil != err {
+		return "", err
+	}
+	if !valid {
+		return "", errors.New("Device token signature is invalid")
+	}
+
+	var claims deviceClaims
+	if err := json.Unmarshal(token.DecodedBody, &claims); nil != err {
+		return "", err
+	}
+
+	if claims.Subject == "" {
+		return "", errors.New("Device token has no subject")
+	}
+
+	if !time.Now().Before(time.Unix(claims.Expiration, 0)) {
+		return "", errors.New("Device token has expired")
+	}
+
+	return claims.Subject, nil
+}
+
+// Renew verifies a device's current token and mints a replacement for the
+// same device. The current token authenticates the renewal, so it must
+// still be valid.
+func (p *DeviceProfile) Renew(currentToken []byte) (*DeviceToken, error) {
+	deviceID, err := p.Verify(currentToken)
+	if nil != err {
+		return nil, err
+	}
+
+	return p.Mint(deviceID)
+}
+
+// RenewalHandler returns an http.Handler renewing device tokens. Devices
+// POST with their current token as a bearer token, and receive the new
+// token as the response body. It should only be served over TLS.
+func (p *DeviceProfile) RenewalHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		const prefix = "Bearer "
+		authorization := r.Header.Get("Authorization")
+		if !strings.HasPrefix(authorization, prefix) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		renewed, err := p.Renew([]byte(authorization[len(prefix):]))
+		if nil != err {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/jwt")
+		w.Write(renewed.Token)
+	})
+}
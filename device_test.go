@@ -0,0 +1,84 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeviceProfile(t *testing.T) {
+	_, key, _ := ed25519.GenerateKey(rand.Reader)
+	sv, err := NewJOSESignerVerifier(EdDSA, &key)
+	if nil != err {
+		t.Fatalf("NewJOSESignerVerifier() error = %v", err)
+	}
+
+	profile, err := NewDeviceProfile(sv, time.Hour, 200)
+	if nil != err {
+		t.Fatalf("NewDeviceProfile() error = %v", err)
+	}
+
+	minted, err := profile.Mint("thermostat-42")
+	if nil != err {
+		t.Fatalf("DeviceProfile.Mint() error = %v", err)
+	}
+	if minted.Size != len(minted.Token) || minted.SizeBudget != 200 {
+		t.Errorf("DeviceProfile.Mint() size = %d/%d, token is %d bytes", minted.Size, minted.SizeBudget, len(minted.Token))
+	}
+
+	if deviceID, err := profile.Verify(minted.Token); nil != err || deviceID != "thermostat-42" {
+		t.Errorf("DeviceProfile.Verify() = %v, %v, want thermostat-42", deviceID, err)
+	}
+
+	if _, err := profile.Mint(strings.Repeat("x", 250)); nil == err {
+		t.Errorf("DeviceProfile.Mint() expected error exceeding the size budget")
+	} else if _, ok := err.(*TokenSizeError); !ok {
+		t.Errorf("DeviceProfile.Mint() error = %T, want *TokenSizeError", err)
+	}
+
+	hmacSV, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	if _, err := NewDeviceProfile(hmacSV, time.Hour, 200); nil == err {
+		t.Errorf("NewDeviceProfile() expected error for HS256")
+	}
+
+	// Flip a bit of the decoded signature, so the tampered token is never
+	// re-encoded to the same signature.
+	dot := strings.LastIndexByte(string(minted.Token), '.')
+	signature, err := Base64URLDecode(string(minted.Token[dot+1:]))
+	if nil != err {
+		t.Fatalf("Base64URLDecode() error = %v", err)
+	}
+	signature[0] ^= 0x01
+	tampered := string(minted.Token[:dot+1]) + Base64URLEncode(signature)
+
+	tests := []struct {
+		name          string
+		authorization string
+		want          int
+	}{
+		{"Must renew a valid device token", "Bearer " + string(minted.Token), http.StatusOK},
+		{"Must not renew a tampered device token", "Bearer " + tampered, http.StatusUnauthorized},
+		{"Must not renew without a token", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodPost, "/renew", nil)
+			request.Header.Set("Authorization", tt.authorization)
+			recorder := httptest.NewRecorder()
+			profile.RenewalHandler().ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.want {
+				t.Errorf("RenewalHandler() status = %v, want %v", recorder.Code, tt.want)
+			}
+			if tt.want == http.StatusOK {
+				if deviceID, err := profile.Verify(recorder.Body.Bytes()); nil != err || deviceID != "thermostat-42" {
+					t.Errorf("RenewalHandler() token = %v, %v, want thermostat-42", deviceID, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// maxClaimSources bounds the number of claim sources resolved for a token.
+const maxClaimSources = 16
+
+// ClaimSource is a member of the '_claim_sources' claim (OpenID Connect
+// Core 1.0, section 5.6.2). Aggregated claims are carried in JWT, and
+// distributed claims fetched from Endpoint with AccessToken, if set.
+type ClaimSource struct {
+	JWT         string `json:"JWT,omitempty"`
+	Endpoint    string `json:"endpoint,omitempty"`
+	AccessToken string `json:"access_token,omitempty"`
+}
+
+// ClaimSources are the '_claim_names' and '_claim_sources' claims of a
+// token, naming the source of each aggregated or distributed claim.
+type ClaimSources struct {
+	Names   map[string]string      `json:"_claim_names,omitempty"`
+	Sources map[string]ClaimSource `json:"_claim_sources,omitempty"`
+}
+
+// ClaimSourceResolver is a ClaimResolver resolving the aggregated and
+// distributed claims of tokens (OpenID Connect Core 1.0, section 5.6.2),
+// which are asserted in JWTs by claims providers other than the token's
+// issuer. Each claims JWT is verified by the verifier configured for its
+// issuer, and must be about the token's subject, if it names one. Use it
+// with a ClaimFederator to merge the claims into a Principal.
+type ClaimSourceResolver struct {
+	providers map[string]*JOSESignerVerifier
+	client    *http.Client
+}
+
+// NewClaimSourceResolver creates a ClaimSourceResolver trusting the claims
+// providers, keyed by issuer, and fetching distributed claims with client,
+// or a client with a 10 second timeout if nil.
+func NewClaimSourceResolver(providers map[string]*JOSESignerVerifier, client *http.Client) (*ClaimSourceResolver, error) {
+	if len(providers) == 0 {
+		return nil, errors.New("Claim source resolution requires at least one claims provider")
+	}
+
+	if nil == client {
+		client = &http.Client{Timeout: defaultRemoteTimeout}
+	}
+
+	return &ClaimSourceResolver{providers: providers, client: client}, nil
+}
+
+// ParseClaimSources returns the claim sources of a token. A claim naming a
+// source that is not defined is an error.
+func ParseClaimSources(token *Token) (*ClaimSources, error) {
+	var sources ClaimSources
+	if err := json.Unmarshal(token.DecodedBody, &sources); nil != err {
+		return nil, err
+	}
+
+	for name, source := range sources.Names {
+		if _, ok := sources.Sources[source]; !ok {
+			return nil, fmt.Errorf("Claim %q names undefined claim source %q", name, source)
+		}
+	}
+
+	return &sources, nil
+}
+
+// ResolveClaims resolves the aggregated and distributed claims of the
+// token. Only claims named in '_claim_names' are taken from each claims
+// JWT, and sources no claim names are not resolved.
+func (r *ClaimSourceResolver) ResolveClaims(token *Token) (map[string]interface{}, error) {
+	sources, err := ParseClaimSources(token)
+	if nil != err {
+		return nil, err
+	}
+
+	names := make(map[string][]string)
+	for name, source := range sources.Names {
+		names[source] = append(names[source], name)
+	}
+	if len(names) > maxClaimSources {
+		return nil, fmt.Errorf("Token has %d claim sources, at most %d are resolved", len(names), maxClaimSources)
+	}
+
+	resolved := make(map[string]interface{})
+	for sourceName, claimNames := range names {
+		claims, err := r.resolveSource(sources.Sources[sourceName], token.RegisteredClaims.Subject)
+		if nil != err {
+			return nil, fmt.Errorf("Claim source %q: %s", sourceName, err)
+		}
+
+		for _, name := range claimNames {
+			if value, ok := claims[name]; ok {
+				resolved[name] = value
+			}
+		}
+	}
+
+	return resolved, nil
+}
+
+// resolveSource returns the verified claims of an aggregated or distributed
+// claim source.
+func (r *ClaimSourceResolver) resolveSource(source ClaimSource, subject string) (map[string]interface{}, error) {
+	rawClaims := []byte(source.JWT)
+	switch {
+	case source.JWT != "" && source.Endpoint != "":
+		return nil, errors.New("Claim source is both aggregated and distributed")
+	case source.Endpoint != "":
+		fetched, err := r.fetch(source)
+		if nil != err {
+			return nil, err
+		}
+		rawClaims = fetched
+	case source.JWT == "":
+		return nil, errors.New("Claim source has neither a JWT nor an endpoint")
+	}
+
+	issuer, err := unverifiedIssuer(rawClaims)
+	if nil != err {
+		return nil, err
+	}
+	verifier, ok := r.providers[issuer]
+	if !ok {
+		return nil, fmt.Errorf("Claims provider %q is not trusted", issuer)
+	}
+
+	token, valid, err := verifier.VerifyToken(rawClaims, &ValidationClaims{Issuer: []string{issuer}, Subject: []string{subject}})
+	if nil != err {
+		return nil, err
+	}
+	if !valid {
+		return nil, errors.New("Claims JWT is not valid, or not about the token's subject")
+	}
+
+	return decodeClaimsMap(token.DecodedBody)
+}
+
+// fetch requests the claims JWT of a distributed claim source from its
+// HTTPS endpoint.
+func (r *ClaimSourceResolver) fetch(source ClaimSource) ([]byte, error) {
+	parsed, err := url.Parse(source.Endpoint)
+	if nil != err {
+		return nil, err
+	}
+	if parsed.Scheme != "https" || parsed.Host == "" {
+		return nil, fmt.Errorf("Claim source endpoint %q must be an absolute HTTPS URL", source.Endpoint)
+	}
+
+	request, err := http.NewRequest(http.MethodGet, source.Endpoint, nil)
+	if nil != err {
+		return nil, err
+	}
+	if source.AccessToken != "" {
+		request.Header.Set("Authorization", "Bearer "+source.AccessToken)
+	}
+	request.Header.Set("Accept", "application/jwt")
+
+	response, err := r.client.Do(request)
+	if nil != err {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Claim source request failed with status %d", response.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(response.Body, defaultRemoteMaxResponseSize+1))
+	if nil != err {
+		return nil, err
+	}
+	if len(body) > defaultRemoteMaxResponseSize {
+		return nil, fmt.Errorf("Claim source response exceeds %d bytes", defaultRemoteMaxResponseSize)
+	}
+
+	return []byte(strings.TrimSpace(string(body))), nil
+}
+
+// unverifiedIssuer returns the 'iss' claim of a token without verifying
+// it, to select the key to verify it with.
+func unverifiedIssuer(rawToken []byte) (string, error) {
+	parts := strings.Split(string(rawToken), ".")
+	if len(parts) != 3 {
+		return "", errors.New("Claims JWT must be a compact JWS")
+	}
+
+	body, err := Base64URLDecode(parts[1])
+	if nil != err {
+		return "", err
+	}
+
+	var claims struct {
+		Issuer string `json:"iss"`
+	}
+	if err := json.Unmarshal(body, &claims); nil != err {
+		return "", err
+	}
+	if claims.Issuer == "" {
+		return "", errors.New("Claims JWT has no issuer")
+	}
+
+	return claims.Issuer, nil
+}
@@ -0,0 +1,114 @@
+package jwt
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClaimSourceResolver(t *testing.T) {
+	issuer, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	provider, _ := NewJOSESignerVerifier(HS256, []byte("key of the claims provider"))
+	header := Header{Algorithm: string(HS256), Type: "JWT"}
+
+	aggregated, _ := provider.GenerateToken(header, map[string]interface{}{"iss": "provider", "sub": "alice", "address": "1 Main St", "birthdate": "1990-01-01"})
+	distributed, _ := provider.GenerateToken(header, map[string]interface{}{"iss": "provider", "credit_score": 650})
+	otherSubject, _ := provider.GenerateToken(header, map[string]interface{}{"iss": "provider", "sub": "mallory", "address": "2 Main St"})
+	untrusted, _ := issuer.GenerateToken(header, map[string]interface{}{"iss": "issuer", "address": "3 Main St"})
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer ksj3n283dke" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/jwt")
+		w.Write(distributed)
+	}))
+	defer server.Close()
+
+	if _, err := NewClaimSourceResolver(nil, nil); nil == err {
+		t.Errorf("NewClaimSourceResolver() expected error without claims providers")
+	}
+	resolver, _ := NewClaimSourceResolver(map[string]*JOSESignerVerifier{"provider": provider}, server.Client())
+	federator, _ := NewClaimFederator(issuer, resolver)
+
+	tests := []struct {
+		name       string
+		claims     map[string]interface{}
+		wantErr    bool
+		wantClaims map[string]interface{}
+	}{
+		{
+			"Must resolve aggregated and distributed claims",
+			map[string]interface{}{
+				"_claim_names": map[string]string{"address": "src1", "credit_score": "src2"},
+				"_claim_sources": map[string]interface{}{
+					"src1": map[string]string{"JWT": string(aggregated)},
+					"src2": map[string]string{"endpoint": server.URL, "access_token": "ksj3n283dke"},
+				},
+			},
+			false,
+			map[string]interface{}{"address": "1 Main St", "credit_score": 650, "birthdate": nil},
+		},
+		{
+			"Must not resolve a claim naming an undefined source",
+			map[string]interface{}{"_claim_names": map[string]string{"address": "src1"}},
+			true,
+			nil,
+		},
+		{
+			"Must not resolve claims about another subject",
+			map[string]interface{}{
+				"_claim_names":   map[string]string{"address": "src1"},
+				"_claim_sources": map[string]interface{}{"src1": map[string]string{"JWT": string(otherSubject)}},
+			},
+			true,
+			nil,
+		},
+		{
+			"Must not resolve claims from an untrusted provider",
+			map[string]interface{}{
+				"_claim_names":   map[string]string{"address": "src1"},
+				"_claim_sources": map[string]interface{}{"src1": map[string]string{"JWT": string(untrusted)}},
+			},
+			true,
+			nil,
+		},
+		{
+			"Must not resolve distributed claims without the access token",
+			map[string]interface{}{
+				"_claim_names":   map[string]string{"credit_score": "src2"},
+				"_claim_sources": map[string]interface{}{"src2": map[string]string{"endpoint": server.URL}},
+			},
+			true,
+			nil,
+		},
+		{
+			"Must not fetch distributed claims over HTTP",
+			map[string]interface{}{
+				"_claim_names":   map[string]string{"credit_score": "src2"},
+				"_claim_sources": map[string]interface{}{"src2": map[string]string{"endpoint": "http://claims.example"}},
+			},
+			true,
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.claims["iss"] = "issuer"
+			tt.claims["sub"] = "alice"
+			rawToken, _ := issuer.GenerateToken(header, tt.claims)
+
+			principal, _, err := federator.Principal(rawToken, &ValidationClaims{Issuer: []string{"issuer"}, Subject: []string{"alice"}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ClaimSourceResolver.ResolveClaims() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for name, want := range tt.wantClaims {
+				if got, _ := principal.Claim(name); fmt.Sprint(got) != fmt.Sprint(want) {
+					t.Errorf("ClaimSourceResolver.ResolveClaims() claim %s = %v, want %v", name, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/georgejenkins/jwt/jwk"
+)
+
+// DistributionTarget is a location the issuer's JWKS is distributed to,
+// such as a storage bucket or CDN origin in another region.
+type DistributionTarget interface {
+	// Name identifies the target in reports.
+	Name() string
+	// Get returns the JWKS document currently served by the target.
+	Get(ctx context.Context) ([]byte, error)
+	// Put uploads the JWKS document to the target.
+	Put(ctx context.Context, document []byte) error
+}
+
+// HTTPTarget is a DistributionTarget uploaded to with HTTP PUT, such as a
+// pre-signed S3 or GCS object URL, and read back from a possibly different
+// URL, such as the CDN edge in front of it.
+type HTTPTarget struct {
+	name   string
+	getURL string
+	putURL string
+	client *http.Client
+}
+
+// NewHTTPTarget creates an HTTPTarget. If client is nil,
+// http.DefaultClient is used.
+func NewHTTPTarget(name, getURL, putURL string, client *http.Client) (*HTTPTarget, error) {
+	for _, u := range []string{getURL, putURL} {
+		if !strings.HasPrefix(u, "https://") {
+			return nil, fmt.Errorf("Distribution URL %q must be an HTTPS URL", u)
+		}
+	}
+
+	if nil == client {
+		client = http.DefaultClient
+	}
+
+	return &HTTPTarget{name: name, getURL: getURL, putURL: putURL, client: client}, nil
+}
+
+// Name returns the target's name.
+func (t *HTTPTarget) Name() string {
+	return t.name
+}
+
+// Get fetches the JWKS document from the target's read URL.
+func (t *HTTPTarget) Get(ctx context.Context) ([]byte, error) {
+	request, err := http.NewRequest(http.MethodGet, t.getURL, nil)
+	if nil != err {
+		return nil, err
+	}
+	request.Header.Set("Cache-Control", "no-cache")
+
+	response, err := t.client.Do(request.WithContext(ctx))
+	if nil != err {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d", t.getURL, response.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(response.Body, maxJWKSSize+1))
+	if nil != err {
+		return nil, err
+	}
+	if len(body) > maxJWKSSize {
+		return nil, fmt.Errorf("JWKS document exceeds %d bytes", maxJWKSSize)
+	}
+
+	return body, nil
+}
+
+// Put uploads the JWKS document to the target's write URL.
+func (t *HTTPTarget) Put(ctx context.Context, document []byte) error {
+	request, err := http.NewRequest(http.MethodPut, t.putURL, bytes.NewReader(document))
+	if nil != err {
+		return err
+	}
+	request.Header.Set("Content-Type", "application/jwk-set+json")
+
+	response, err := t.client.Do(request.WithContext(ctx))
+	if nil != err {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		return fmt.Errorf("%s returned status %d", t.putURL, response.StatusCode)
+	}
+
+	return nil
+}
+
+// TargetStatus is the outcome of reconciling one DistributionTarget.
+type TargetStatus struct {
+	Target string `json:"target"`
+	// Pushed is true if the JWKS was uploaded to the target.
+	Pushed bool `json:"pushed"`
+	// Converged is true if the target serves the issuer's keys.
+	Converged bool  `json:"converged"`
+	Err       error `json:"-"`
+}
+
+// ReconcileReport is the outcome of reconciling every target.
+type ReconcileReport struct {
+	Time    time.Time      `json:"time"`
+	Targets []TargetStatus `json:"targets"`
+}
+
+// Converged reports whether every target serves the issuer's keys.
+func (r ReconcileReport) Converged() bool {
+	for _, target := range r.Targets {
+		if !target.Converged {
+			return false
+		}
+	}
+
+	return true
+}
+
+// JWKSReconciler distributes the issuer's JWKS to multiple targets and
+// verifies they converge, so verifiers around the world have fresh keys
+// even if the issuer's own JWKS endpoint is regional.
+type JWKSReconciler struct {
+	source  KeySetSource
+	targets []DistributionTarget
+}
+
+// NewJWKSReconciler creates a JWKSReconciler distributing the public
+// halves of the source's keys to the targets.
+func NewJWKSReconciler(source KeySetSource, targets ...DistributionTarget) (*JWKSReconciler, error) {
+	if nil == source {
+		return nil, errors.New("JWKS reconciler requires a key set source")
+	}
+	if len(targets) == 0 {
+		return nil, errors.New("JWKS reconciler requires at least one target")
+	}
+
+	return &JWKSReconciler{source: source, targets: targets}, nil
+}
+
+// Reconcile pulls the JWKS from each target and, where it doesn't hold the
+// issuer's keys, pushes the issuer's JWKS and pulls it again to verify the
+// target converged. Targets are compared by key ID and thumbprint, so
+// formatting differences don't trigger uploads. An error is returned only
+// if the issuer's keys can't be read; target failures are reported.
+func (r *JWKSReconciler) Reconcile(ctx context.Context) (ReconcileReport, error) {
+	document, err := publicJWKS(r.source)
+	if nil != err {
+		return ReconcileReport{}, err
+	}
+	want, err := jwksFingerprint(document)
+	if nil != err {
+		return ReconcileReport{}, err
+	}
+
+	report := ReconcileReport{Time: time.Now()}
+	for _, target := range r.targets {
+		status := TargetStatus{Target: target.Name()}
+		status.Converged, status.Err = r.converged(ctx, target, want)
+
+		if !status.Converged {
+			if err := target.Put(ctx, document); nil != err {
+				status.Err = err
+			} else {
+				status.Pushed = true
+				status.Converged, status.Err = r.converged(ctx, target, want)
+			}
+		}
+
+		report.Targets = append(report.Targets, status)
+	}
+
+	return report, nil
+}
+
+// Run reconciles every interval until the context is done, passing each
+// report to the callback.
+func (r *JWKSReconciler) Run(ctx context.Context, interval time.Duration, report func(ReconcileReport, error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		report(r.Reconcile(ctx))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// converged reports whether the target serves the keys with the
+// fingerprint. A missing or unreadable document is reported as not
+// converged, with the error.
+func (r *JWKSReconciler) converged(ctx context.Context, target DistributionTarget, want string) (bool, error) {
+	document, err := target.Get(ctx)
+	if nil != err {
+		return false, err
+	}
+
+	got, err := jwksFingerprint(document)
+	if nil != err {
+		return false, err
+	}
+
+	return got == want, nil
+}
+
+// jwksFingerprint identifies the keys of a JWKS document by their key IDs
+// and thumbprints.
+func jwksFingerprint(document []byte) (string, error) {
+	set, err := jwk.ParseSet(document)
+	if nil != err {
+		return "", err
+	}
+
+	keys := make([]string, 0, len(set.Keys))
+	for _, key := range set.Keys {
+		thumbprint, err := jwk.Thumbprint(key.Key)
+		if nil != err {
+			return "", err
+		}
+		keys = append(keys, key.KeyID+":"+string(key.Algorithm)+":"+thumbprint)
+	}
+
+	sort.Strings(keys)
+	return strings.Join(keys, ","), nil
+}
@@ -0,0 +1,116 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memoryTarget is a DistributionTarget held in memory. A stale target
+// keeps serving its original document, as a CDN that hasn't expired it.
+type memoryTarget struct {
+	name     string
+	mu       sync.Mutex
+	document []byte
+	stale    bool
+	putErr   error
+	puts     int
+}
+
+func (t *memoryTarget) Name() string { return t.name }
+
+func (t *memoryTarget) Get(ctx context.Context) ([]byte, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if nil == t.document {
+		return nil, errors.New("Not found")
+	}
+	return t.document, nil
+}
+
+func (t *memoryTarget) Put(ctx context.Context, document []byte) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if nil != t.putErr {
+		return t.putErr
+	}
+	t.puts++
+	if !t.stale {
+		t.document = document
+	}
+	return nil
+}
+
+func TestJWKSReconciler(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	kr, _ := NewKeyring(time.Hour)
+	kr.Add(KeyringKey{KeyID: "ec-1", Algorithm: ES256, Key: key})
+	current, _ := publicJWKS(kr)
+
+	// A reformatted copy of the current keys is in sync.
+	reformatted := append([]byte("\n"), current...)
+	var stored []byte
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			stored, _ = ioutil.ReadAll(r.Body)
+			return
+		}
+		if nil == stored {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(stored)
+	}))
+	defer server.Close()
+	bucket, _ := NewHTTPTarget("bucket", server.URL+"/jwks.json", server.URL+"/jwks.json", server.Client())
+
+	tests := []struct {
+		name          string
+		target        DistributionTarget
+		wantPushed    bool
+		wantConverged bool
+		wantErr       bool
+	}{
+		{"Must push to an empty target", &memoryTarget{name: "empty"}, true, true, false},
+		{"Must push to a target with old keys", &memoryTarget{name: "old", document: []byte(`{"keys":[]}`)}, true, true, false},
+		{"Must not push to a target in sync", &memoryTarget{name: "synced", document: reformatted}, false, true, false},
+		{"Must report a target that fails to upload", &memoryTarget{name: "failing", putErr: errors.New("Access denied")}, false, false, true},
+		{"Must report a target that has not converged", &memoryTarget{name: "stale", document: []byte(`{"keys":[]}`), stale: true}, true, false, false},
+		{"Must push to an HTTP target", bucket, true, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewJWKSReconciler(kr, tt.target)
+			if nil != err {
+				t.Fatalf("NewJWKSReconciler() error = %v", err)
+			}
+
+			report, err := r.Reconcile(context.Background())
+			if nil != err {
+				t.Fatalf("JWKSReconciler.Reconcile() error = %v", err)
+			}
+			status := report.Targets[0]
+			if status.Pushed != tt.wantPushed || status.Converged != tt.wantConverged || (nil != status.Err) != tt.wantErr {
+				t.Errorf("JWKSReconciler.Reconcile() = %+v, want pushed %v, converged %v, error %v", status, tt.wantPushed, tt.wantConverged, tt.wantErr)
+			}
+			if report.Converged() != tt.wantConverged {
+				t.Errorf("ReconcileReport.Converged() = %v, want %v", report.Converged(), tt.wantConverged)
+			}
+		})
+	}
+
+	if _, err := NewJWKSReconciler(kr); nil == err {
+		t.Errorf("NewJWKSReconciler() expected error without targets")
+	}
+	if _, err := NewHTTPTarget("plain", "http://cdn.example.com/jwks.json", "https://bucket.example.com/jwks.json", nil); nil == err {
+		t.Errorf("NewHTTPTarget() expected error for a non-HTTPS URL")
+	}
+}
@@ -0,0 +1,17 @@
+// Package jwt creates and verifies JSON Web Tokens (RFC 7519) signed with
+// the JSON Web Signature algorithms of RFC 7518 and RFC 8037.
+//
+// A JOSESignerVerifier is created for an algorithm and key with
+// NewJOSESignerVerifier, and generates tokens with GenerateToken and
+// verifies them with VerifyToken:
+//
+//	sv, err := jwt.NewJOSESignerVerifier(jwt.ES256, privateKey)
+//	if nil != err {
+//		return err
+//	}
+//
+//	token, err := sv.GenerateToken(jwt.Header{Algorithm: string(jwt.ES256)}, claims)
+//
+// The algorithms and the signers and verifiers implementing them are
+// defined in the jwa and jws subpackages, and are re-exported here.
+package jwt
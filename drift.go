@@ -0,0 +1,163 @@
+package jwt
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// defaultDriftSamples is the number of samples a DriftEstimator keeps.
+const defaultDriftSamples = 8
+
+// maxDriftRate bounds the estimated drift rate to 500 parts per million,
+// the frequency tolerance NTP assumes of a working clock.
+const maxDriftRate = 500e-6
+
+// OffsetProvider supplies the correction to apply to the system time,
+// such that the true time is time.Now().Add(Offset()).
+type OffsetProvider interface {
+	Offset() time.Duration
+}
+
+// FixedOffset is an OffsetProvider with a constant offset, for an offset
+// measured by other means.
+type FixedOffset time.Duration
+
+// Offset returns the fixed offset.
+func (o FixedOffset) Offset() time.Duration {
+	return time.Duration(o)
+}
+
+// ClockSample is one exchange with a reference clock: a request sent at
+// local time Sent, answered with the reference time Reference, and
+// received at local time Received.
+type ClockSample struct {
+	Sent      time.Time
+	Reference time.Time
+	Received  time.Time
+}
+
+// offset returns the offset of the sample, assuming the reference time
+// was read halfway through the round trip.
+func (s ClockSample) offset() time.Duration {
+	return s.Reference.Sub(s.midpoint())
+}
+
+// midpoint returns the local time halfway through the round trip.
+func (s ClockSample) midpoint() time.Time {
+	return s.Sent.Add(s.delay() / 2)
+}
+
+func (s ClockSample) delay() time.Duration {
+	return s.Received.Sub(s.Sent)
+}
+
+// DriftEstimator estimates the offset of the local clock from a reference
+// clock, NTP-style, from samples of exchanges with it. It is an
+// OffsetProvider, for ValidationClaims.ClockOffset.
+//
+// Of the recent samples, the one with the shortest round trip gives the
+// offset, as its midpoint assumption is least wrong. The drift rate of the
+// local clock, estimated across the samples, extrapolates the offset to
+// the current time, so the estimate stays accurate between samples.
+type DriftEstimator struct {
+	mu      sync.Mutex
+	samples []ClockSample
+	size    int
+}
+
+// NewDriftEstimator creates a DriftEstimator keeping the given number of
+// recent samples, or 8 if size is not positive.
+func NewDriftEstimator(size int) *DriftEstimator {
+	if size <= 0 {
+		size = defaultDriftSamples
+	}
+
+	return &DriftEstimator{size: size}
+}
+
+// AddSample records an exchange with the reference clock.
+func (e *DriftEstimator) AddSample(sample ClockSample) error {
+	if sample.Received.Before(sample.Sent) {
+		return errors.New("Clock sample received before it was sent")
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.samples = append(e.samples, sample)
+	if len(e.samples) > e.size {
+		e.samples = e.samples[len(e.samples)-e.size:]
+	}
+	return nil
+}
+
+// Offset returns the estimated offset of the local clock at the current
+// time, or zero without samples.
+func (e *DriftEstimator) Offset() time.Duration {
+	return e.OffsetAt(time.Now())
+}
+
+// OffsetAt returns the estimated offset of the local clock at local time
+// now.
+func (e *DriftEstimator) OffsetAt(now time.Time) time.Duration {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.samples) == 0 {
+		return 0
+	}
+
+	best := e.samples[0]
+	for _, sample := range e.samples[1:] {
+		if sample.delay() < best.delay() {
+			best = sample
+		}
+	}
+
+	elapsed := now.Sub(best.midpoint())
+	return best.offset() + time.Duration(e.driftRate()*float64(elapsed))
+}
+
+// DriftRate returns the estimated drift rate of the local clock, as the
+// change in offset per unit of local time.
+func (e *DriftEstimator) DriftRate() float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.driftRate()
+}
+
+// driftRate fits the sample offsets to their local times by least
+// squares. e.mu must be held.
+func (e *DriftEstimator) driftRate() float64 {
+	if len(e.samples) < 2 {
+		return 0
+	}
+
+	origin := e.samples[0].midpoint()
+	var sumT, sumO, sumTT, sumTO float64
+	for _, sample := range e.samples {
+		t := float64(sample.midpoint().Sub(origin))
+		o := float64(sample.offset())
+		sumT += t
+		sumO += o
+		sumTT += t * t
+		sumTO += t * o
+	}
+
+	n := float64(len(e.samples))
+	denominator := n*sumTT - sumT*sumT
+	if denominator == 0 {
+		return 0
+	}
+
+	rate := (n*sumTO - sumT*sumO) / denominator
+	if rate > maxDriftRate {
+		return maxDriftRate
+	}
+	if rate < -maxDriftRate {
+		return -maxDriftRate
+	}
+	return rate
+}
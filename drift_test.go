@@ -0,0 +1,82 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDriftEstimator(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	sample := func(at time.Duration, delay time.Duration, offset time.Duration) ClockSample {
+		sent := start.Add(at)
+		return ClockSample{
+			Sent:      sent,
+			Reference: sent.Add(delay / 2).Add(offset),
+			Received:  sent.Add(delay),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		samples []ClockSample
+		at      time.Duration
+		want    time.Duration
+	}{
+		{"Must estimate zero without samples", nil, 0, 0},
+		{"Must estimate the offset of a single sample", []ClockSample{sample(0, 20*time.Millisecond, 3*time.Second)}, time.Minute, 3 * time.Second},
+		{"Must prefer the sample with the shortest round trip", []ClockSample{
+			sample(0, 800*time.Millisecond, 3*time.Second+300*time.Millisecond),
+			sample(time.Second, 10*time.Millisecond, 3*time.Second),
+			sample(2*time.Second, 500*time.Millisecond, 2*time.Second+800*time.Millisecond),
+		}, 2 * time.Second, 3 * time.Second},
+		{"Must extrapolate the drift rate", []ClockSample{
+			sample(0, 10*time.Millisecond, time.Second),
+			sample(1000*time.Second, 10*time.Millisecond, time.Second+100*time.Millisecond),
+			sample(2000*time.Second, 10*time.Millisecond, time.Second+200*time.Millisecond),
+		}, 3000 * time.Second, time.Second + 300*time.Millisecond},
+		{"Must bound the drift rate", []ClockSample{
+			sample(0, 10*time.Millisecond, 0),
+			sample(time.Second, 10*time.Millisecond, time.Second),
+		}, 1000 * time.Second, 500 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := NewDriftEstimator(0)
+			for _, s := range tt.samples {
+				if err := e.AddSample(s); nil != err {
+					t.Fatalf("DriftEstimator.AddSample() error = %v", err)
+				}
+			}
+
+			got := e.OffsetAt(start.Add(tt.at))
+			if diff := got - tt.want; diff > time.Millisecond || diff < -time.Millisecond {
+				t.Errorf("DriftEstimator.OffsetAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	e := NewDriftEstimator(2)
+	for i := 0; i < 5; i++ {
+		e.AddSample(sample(time.Duration(i)*time.Second, 10*time.Millisecond, time.Duration(i)*time.Second))
+	}
+	if len(e.samples) != 2 {
+		t.Errorf("DriftEstimator kept %d samples, want 2", len(e.samples))
+	}
+
+	if err := e.AddSample(ClockSample{Sent: start, Received: start.Add(-time.Second)}); nil == err {
+		t.Errorf("DriftEstimator.AddSample() expected error for a sample received before it was sent")
+	}
+}
+
+func TestValidationClaims_ClockOffset(t *testing.T) {
+	// A token that expired 30 seconds ago by the local clock, which runs a
+	// minute slow.
+	claims := Claims{Expiration: NewNumericDate(time.Now().Add(-30*time.Second))}
+
+	if valid, _ := claims.ValidateRegisteredClaims(&ValidationClaims{}); valid {
+		t.Errorf("ValidateRegisteredClaims() = true for an expired token")
+	}
+	if valid, _ := claims.ValidateRegisteredClaims(&ValidationClaims{ClockOffset: FixedOffset(-time.Minute)}); !valid {
+		t.Errorf("ValidateRegisteredClaims() = false for a token valid by the corrected clock")
+	}
+}
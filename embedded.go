@@ -0,0 +1,91 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// EmbeddedTokenPolicy configures how tokens found at a JSON path are verified.
+type EmbeddedTokenPolicy struct {
+	Verifier           *JOSESignerVerifier
+	ValidationCriteria *ValidationClaims
+}
+
+// VerifyEmbeddedTokens walks a JSON document for fields known to contain
+// tokens, e.g. the body of a webhook or SCIM event, and verifies each
+// token with the policy configured for its path.
+//
+// Policy paths are dot separated. A "*" segment matches every element of
+// an array or every member of an object, e.g. "events.*.token". Results
+// are keyed by the concrete path of each token, e.g. "events.0.token".
+// Paths that are absent from the document are not reported.
+func VerifyEmbeddedTokens(document []byte, policies map[string]EmbeddedTokenPolicy) (map[string]VerificationResult, error) {
+	var root interface{}
+	if err := json.Unmarshal(document, &root); nil != err {
+		return nil, err
+	}
+
+	results := make(map[string]VerificationResult)
+
+	for path, policy := range policies {
+		if nil == policy.Verifier {
+			return nil, fmt.Errorf("No verifier configured for path %q", path)
+		}
+
+		for concretePath, value := range matchJSONPath(root, strings.Split(path, "."), nil) {
+			rawToken, ok := value.(string)
+			if !ok {
+				results[concretePath] = VerificationResult{Err: errors.New("Value is not a token string")}
+				continue
+			}
+
+			token, valid, err := policy.Verifier.VerifyToken([]byte(rawToken), policy.ValidationCriteria)
+			results[concretePath] = newVerificationResult(token, valid, err)
+		}
+	}
+
+	return results, nil
+}
+
+// matchJSONPath returns the values matching the path segments, keyed by
+// their concrete dot separated path.
+func matchJSONPath(value interface{}, segments []string, prefix []string) map[string]interface{} {
+	matches := make(map[string]interface{})
+
+	if len(segments) == 0 {
+		matches[strings.Join(prefix, ".")] = value
+		return matches
+	}
+
+	segment, rest := segments[0], segments[1:]
+
+	collect := func(key string, child interface{}) {
+		for path, match := range matchJSONPath(child, rest, append(prefix[:len(prefix):len(prefix)], key)) {
+			matches[path] = match
+		}
+	}
+
+	switch node := value.(type) {
+	case map[string]interface{}:
+		if segment == "*" {
+			for key, child := range node {
+				collect(key, child)
+			}
+		} else if child, ok := node[segment]; ok {
+			collect(segment, child)
+		}
+	case []interface{}:
+		if segment == "*" {
+			for i, child := range node {
+				collect(strconv.Itoa(i), child)
+			}
+		} else if i, err := strconv.Atoi(segment); nil == err && i >= 0 && i < len(node) {
+			collect(segment, node[i])
+		}
+	}
+
+	return matches
+}
@@ -0,0 +1,69 @@
+package jwt
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestVerifyEmbeddedTokens(t *testing.T) {
+	sv, err := NewJOSESignerVerifier(HS256, exampleKey)
+	if nil != err {
+		t.Fatalf("NewJOSESignerVerifier() error = %v", err)
+	}
+
+	valid, _ := sv.GenerateToken(Header{Algorithm: string(HS256)}, Claims{Issuer: "oxenfurt"})
+	wrongIssuer, _ := sv.GenerateToken(Header{Algorithm: string(HS256)}, Claims{Issuer: "novigrad"})
+
+	document := []byte(fmt.Sprintf(`{
+		"actor": {"token": %q},
+		"events": [
+			{"token": %q},
+			{"token": %q},
+			{"token": 42},
+			{"other": "field"}
+		]
+	}`, valid, valid, wrongIssuer))
+
+	policy := EmbeddedTokenPolicy{
+		Verifier:           sv,
+		ValidationCriteria: &ValidationClaims{Issuer: []string{"oxenfurt"}},
+	}
+
+	got, err := VerifyEmbeddedTokens(document, map[string]EmbeddedTokenPolicy{
+		"actor.token":    policy,
+		"events.*.token": policy,
+		"missing.token":  policy,
+	})
+	if nil != err {
+		t.Fatalf("VerifyEmbeddedTokens() error = %v", err)
+	}
+
+	tests := []struct {
+		path      string
+		wantValid bool
+		wantErr   bool
+	}{
+		{"actor.token", true, false},
+		{"events.0.token", true, false},
+		{"events.1.token", false, false},
+		{"events.2.token", false, true},
+	}
+	for _, tt := range tests {
+		t.Run("Must report "+tt.path, func(t *testing.T) {
+			result, ok := got[tt.path]
+			if !ok {
+				t.Fatalf("VerifyEmbeddedTokens() has no result for %v", tt.path)
+			}
+			if (result.Err != nil) != tt.wantErr {
+				t.Errorf("VerifyEmbeddedTokens() error = %v, wantErr %v", result.Err, tt.wantErr)
+			}
+			if result.Valid != tt.wantValid {
+				t.Errorf("VerifyEmbeddedTokens() valid = %v, want %v", result.Valid, tt.wantValid)
+			}
+		})
+	}
+
+	if len(got) != len(tests) {
+		t.Errorf("VerifyEmbeddedTokens() returned %d results, want %d", len(got), len(tests))
+	}
+}
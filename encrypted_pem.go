@@ -0,0 +1,257 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+// maxPBKDF2Iterations bounds the work an encrypted key can demand.
+const maxPBKDF2Iterations = 10000000
+
+var (
+	// ErrPassphraseRequired is returned when parsing an encrypted key
+	// without a passphrase provider.
+	ErrPassphraseRequired = errors.New("Key is encrypted, a passphrase is required")
+
+	// ErrIncorrectPassphrase is returned when an encrypted key cannot be
+	// decrypted with the passphrase.
+	ErrIncorrectPassphrase = errors.New("Incorrect passphrase for encrypted key")
+)
+
+// PassphraseProvider returns the passphrase for an encrypted key. It is
+// only called for encrypted keys, and the returned slice is zeroed after
+// use.
+type PassphraseProvider func() ([]byte, error)
+
+// ParseKeyWithPassphrase parses a key like ParseKey, decrypting encrypted
+// PEM private keys with the passphrase from the provider. Both encrypted
+// PKCS#8 ("ENCRYPTED PRIVATE KEY", PBES2 with PBKDF2 and AES-CBC or
+// 3DES-CBC) and legacy OpenSSL encrypted PEM ("Proc-Type: 4,ENCRYPTED")
+// are supported.
+func ParseKeyWithPassphrase(data []byte, passphrase PassphraseProvider) (interface{}, error) {
+	key, _, err := parseKey(data, passphrase)
+	return key, err
+}
+
+// decryptPEMBlock decrypts an encrypted PEM block, returning the block
+// holding the plaintext key.
+func decryptPEMBlock(block *pem.Block, provider PassphraseProvider) (*pem.Block, error) {
+	if nil == provider {
+		return nil, ErrPassphraseRequired
+	}
+
+	passphrase, err := provider()
+	if nil != err {
+		return nil, err
+	}
+	defer func() {
+		for i := range passphrase {
+			passphrase[i] = 0
+		}
+	}()
+
+	if block.Type == "ENCRYPTED PRIVATE KEY" {
+		der, err := decryptPKCS8(block.Bytes, passphrase)
+		if nil != err {
+			return nil, err
+		}
+		return &pem.Block{Type: "PRIVATE KEY", Bytes: der}, nil
+	}
+
+	der, err := x509.DecryptPEMBlock(block, passphrase)
+	if err == x509.IncorrectPasswordError {
+		return nil, ErrIncorrectPassphrase
+	}
+	if nil != err {
+		return nil, err
+	}
+	return &pem.Block{Type: block.Type, Bytes: der}, nil
+}
+
+// isEncryptedPEMBlock reports whether the block holds an encrypted key.
+func isEncryptedPEMBlock(block *pem.Block) bool {
+	return block.Type == "ENCRYPTED PRIVATE KEY" || x509.IsEncryptedPEMBlock(block)
+}
+
+var (
+	oidPBES2         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHMACWithSHA1  = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidDESEDE3CBC    = asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 7}
+	oidAES128CBC     = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC     = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC     = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidHMACWithSHA2s = map[string]func() hash.Hash{
+		"1.2.840.113549.2.8":  sha256.New224,
+		"1.2.840.113549.2.9":  sha256.New,
+		"1.2.840.113549.2.10": sha512.New384,
+		"1.2.840.113549.2.11": sha512.New,
+	}
+)
+
+// encryptedPrivateKeyInfo is the PKCS#8 encrypted key (RFC 5208 Section 6).
+type encryptedPrivateKeyInfo struct {
+	Algorithm     pkix.AlgorithmIdentifier
+	EncryptedData []byte
+}
+
+// pbes2Params are the PBES2 parameters (RFC 8018 Appendix A.4).
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+// pbkdf2Params are the PBKDF2 parameters (RFC 8018 Appendix A.2).
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// decryptPKCS8 decrypts a PBES2 encrypted PKCS#8 key.
+func decryptPKCS8(der []byte, passphrase []byte) ([]byte, error) {
+	var info encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(der, &info); nil != err {
+		return nil, fmt.Errorf("Malformed encrypted PKCS#8 key: %v", err)
+	}
+	if !info.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, fmt.Errorf("Unsupported PKCS#8 encryption algorithm %v", info.Algorithm.Algorithm)
+	}
+
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params); nil != err {
+		return nil, fmt.Errorf("Malformed PBES2 parameters: %v", err)
+	}
+
+	newCipher, keySize, err := pbes2Cipher(params.EncryptionScheme.Algorithm)
+	if nil != err {
+		return nil, err
+	}
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); nil != err {
+		return nil, fmt.Errorf("Malformed PBES2 encryption parameters: %v", err)
+	}
+
+	key, err := pbes2Key(params.KeyDerivationFunc, passphrase, keySize)
+	if nil != err {
+		return nil, err
+	}
+
+	block, err := newCipher(key)
+	if nil != err {
+		return nil, err
+	}
+	if len(iv) != block.BlockSize() {
+		return nil, errors.New("Malformed PBES2 encryption parameters: invalid IV size")
+	}
+	if len(info.EncryptedData) == 0 || len(info.EncryptedData)%block.BlockSize() != 0 {
+		return nil, errors.New("Malformed encrypted PKCS#8 key: invalid ciphertext size")
+	}
+
+	plaintext := make([]byte, len(info.EncryptedData))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, info.EncryptedData)
+
+	// A wrong passphrase shows as bad padding or a malformed key.
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > block.BlockSize() ||
+		!bytes.Equal(plaintext[len(plaintext)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, ErrIncorrectPassphrase
+	}
+	plaintext = plaintext[:len(plaintext)-padding]
+	if _, err := x509.ParsePKCS8PrivateKey(plaintext); nil != err {
+		return nil, ErrIncorrectPassphrase
+	}
+
+	return plaintext, nil
+}
+
+// pbes2Cipher returns the block cipher and key size of a PBES2 encryption
+// scheme.
+func pbes2Cipher(oid asn1.ObjectIdentifier) (func(key []byte) (cipher.Block, error), int, error) {
+	switch {
+	case oid.Equal(oidAES128CBC):
+		return aes.NewCipher, 16, nil
+	case oid.Equal(oidAES192CBC):
+		return aes.NewCipher, 24, nil
+	case oid.Equal(oidAES256CBC):
+		return aes.NewCipher, 32, nil
+	case oid.Equal(oidDESEDE3CBC):
+		return des.NewTripleDESCipher, 24, nil
+	}
+	return nil, 0, fmt.Errorf("Unsupported PBES2 encryption scheme %v", oid)
+}
+
+// pbes2Key derives the encryption key with PBKDF2.
+func pbes2Key(kdf pkix.AlgorithmIdentifier, passphrase []byte, keySize int) ([]byte, error) {
+	if !kdf.Algorithm.Equal(oidPBKDF2) {
+		return nil, fmt.Errorf("Unsupported PBES2 key derivation function %v", kdf.Algorithm)
+	}
+
+	var params pbkdf2Params
+	if _, err := asn1.Unmarshal(kdf.Parameters.FullBytes, &params); nil != err {
+		return nil, fmt.Errorf("Malformed PBKDF2 parameters: %v", err)
+	}
+	if params.IterationCount < 1 || params.IterationCount > maxPBKDF2Iterations {
+		return nil, fmt.Errorf("PBKDF2 iteration count %d out of range", params.IterationCount)
+	}
+	if params.KeyLength != 0 && params.KeyLength != keySize {
+		return nil, fmt.Errorf("PBKDF2 key length %d does not match the cipher", params.KeyLength)
+	}
+
+	prf := sha1.New
+	if len(params.PRF.Algorithm) > 0 && !params.PRF.Algorithm.Equal(oidHMACWithSHA1) {
+		var ok bool
+		if prf, ok = oidHMACWithSHA2s[params.PRF.Algorithm.String()]; !ok {
+			return nil, fmt.Errorf("Unsupported PBKDF2 pseudorandom function %v", params.PRF.Algorithm)
+		}
+	}
+
+	return pbkdf2(passphrase, params.Salt, params.IterationCount, keySize, prf), nil
+}
+
+// pbkdf2 derives a key with PBKDF2 (RFC 8018 Section 5.2) using HMAC
+// with the hash as the pseudorandom function.
+func pbkdf2(password, salt []byte, iterations, keyLength int, h func() hash.Hash) []byte {
+	prf := hmac.New(h, password)
+	hashLength := prf.Size()
+	blocks := (keyLength + hashLength - 1) / hashLength
+
+	var index [4]byte
+	key := make([]byte, 0, blocks*hashLength)
+	u := make([]byte, hashLength)
+	for block := 1; block <= blocks; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(index[:], uint32(block))
+		prf.Write(index[:])
+		key = prf.Sum(key)
+
+		t := key[len(key)-hashLength:]
+		copy(u, t)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range u {
+				t[j] ^= u[j]
+			}
+		}
+	}
+
+	return key[:keyLength]
+}
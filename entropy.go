@@ -0,0 +1,37 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"io"
+
+	"github.com/georgejenkins/jwt/jws"
+)
+
+// randomizedSigner is implemented by signers whose signatures depend on a
+// random source.
+type randomizedSigner interface {
+	UseRand(rng io.Reader) error
+}
+
+// WithEntropySource signs with rng as the random source, after checking
+// its health with jws.CheckEntropy, so a broken source fails construction
+// rather than producing weak signatures. The check is made for every
+// algorithm, though only ECDSA and RSA signers use the source.
+func WithEntropySource(rng io.Reader) Option {
+	return func(sv *JOSESignerVerifier) error {
+		if err := jws.CheckEntropy(rng); nil != err {
+			return err
+		}
+
+		if signer, ok := sv.signer.(randomizedSigner); ok {
+			return signer.UseRand(rng)
+		}
+		return nil
+	}
+}
+
+// WithEntropySelfTest checks the health of the system random source,
+// crypto/rand.Reader, at construction.
+func WithEntropySelfTest() Option {
+	return WithEntropySource(rand.Reader)
+}
@@ -0,0 +1,25 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+)
+
+func TestWithEntropySource(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	if _, err := NewJOSESignerVerifier(ES256, key, WithEntropySelfTest()); nil != err {
+		t.Errorf("NewJOSESignerVerifier() error = %v with a healthy random source", err)
+	}
+
+	if _, err := NewJOSESignerVerifier(ES256, key, WithEntropySource(bytes.NewReader(make([]byte, 4096)))); nil == err {
+		t.Errorf("NewJOSESignerVerifier() expected error with a stuck random source")
+	}
+
+	if _, err := NewJOSESignerVerifier(HS256, exampleKey, WithEntropySource(bytes.NewReader(nil))); nil == err {
+		t.Errorf("NewJOSESignerVerifier() expected error with an empty random source")
+	}
+}
@@ -0,0 +1,178 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+
+	"github.com/georgejenkins/jwt/jwa"
+	"github.com/georgejenkins/jwt/jwe"
+	"github.com/georgejenkins/jwt/jwk"
+	"github.com/georgejenkins/jwt/jws"
+)
+
+// The algorithms, signers, verifiers, encrypters and keys live in the jwa,
+// jws, jwe and jwk packages. They are re-exported here so users of tokens
+// need only import this package, while users of the lower level primitives
+// can import jwa, jws, jwe and jwk alone.
+
+// Algorithm represents the algorithm used to sign the JWT.
+type Algorithm = jwa.Algorithm
+
+// "alg" (Algorithm) Header Parameter Values for JWS
+const (
+	HS256 = jwa.HS256
+	HS384 = jwa.HS384
+	HS512 = jwa.HS512
+	RS256 = jwa.RS256
+	RS384 = jwa.RS384
+	RS512 = jwa.RS512
+	ES256 = jwa.ES256
+	ES384 = jwa.ES384
+	ES512 = jwa.ES512
+	PS256 = jwa.PS256
+	PS384 = jwa.PS384
+	PS512 = jwa.PS512
+	EdDSA = jwa.EdDSA
+	None  = jwa.None
+)
+
+// KeyManagementAlgorithm represents the algorithm used to determine the
+// content encryption key of a JWE.
+type KeyManagementAlgorithm = jwa.KeyManagementAlgorithm
+
+// ContentEncryptionAlgorithm represents the algorithm used to encrypt the
+// plaintext of a JWE.
+type ContentEncryptionAlgorithm = jwa.ContentEncryptionAlgorithm
+
+// "alg" (Algorithm) Header Parameter Values for JWE
+const (
+	RSAOAEP      = jwa.RSAOAEP
+	RSAOAEP256   = jwa.RSAOAEP256
+	A128KW       = jwa.A128KW
+	A192KW       = jwa.A192KW
+	A256KW       = jwa.A256KW
+	Direct       = jwa.Direct
+	ECDHES       = jwa.ECDHES
+	ECDHESA128KW = jwa.ECDHESA128KW
+	ECDHESA192KW = jwa.ECDHESA192KW
+	ECDHESA256KW = jwa.ECDHESA256KW
+	A128GCMKW    = jwa.A128GCMKW
+	A192GCMKW    = jwa.A192GCMKW
+	A256GCMKW    = jwa.A256GCMKW
+)
+
+// "enc" (Encryption Algorithm) Header Parameter Values for JWE
+const (
+	A128CBCHS256 = jwa.A128CBCHS256
+	A192CBCHS384 = jwa.A192CBCHS384
+	A256CBCHS512 = jwa.A256CBCHS512
+	A128GCM      = jwa.A128GCM
+	A192GCM      = jwa.A192GCM
+	A256GCM      = jwa.A256GCM
+)
+
+// CompressionAlgorithm represents the algorithm used to compress the
+// plaintext of a JWE before encryption.
+type CompressionAlgorithm = jwa.CompressionAlgorithm
+
+// "zip" (Compression Algorithm) Header Parameter Values for JWE
+const (
+	Deflate = jwa.Deflate
+)
+
+// JWEHeader is the JWE Protected Header.
+type JWEHeader = jwe.Header
+
+// EphemeralKey is the 'epk' header of ECDH-ES JWEs.
+type EphemeralKey = jwe.EphemeralKey
+
+// KeyEncrypter determines the content encryption key of a JWE.
+type KeyEncrypter = jwe.KeyEncrypter
+
+// KeyDecrypter recovers the content encryption key of a JWE.
+type KeyDecrypter = jwe.KeyDecrypter
+
+// ContentEncrypter encrypts the plaintext of a JWE.
+type ContentEncrypter = jwe.ContentEncrypter
+
+// TokenSigner signs the JWS signing input.
+type TokenSigner = jws.TokenSigner
+
+// TokenVerifier verifies a signature over the JWS signing input.
+type TokenVerifier = jws.TokenVerifier
+
+// Signers and verifiers for each of the supported algorithm families.
+type (
+	HMACSignerVerifier = jws.HMACSignerVerifier
+	RSASigner          = jws.RSASigner
+	RSAVerifier        = jws.RSAVerifier
+	ECDSASigner        = jws.ECDSASigner
+	ECDSAVerifier      = jws.ECDSAVerifier
+	EdDSASigner        = jws.EdDSASigner
+	EdDSAVerifier      = jws.EdDSAVerifier
+	NoneSignerVerifier = jws.NoneSignerVerifier
+)
+
+// ErrAlgorithmKeyMismatch is returned for tokens whose 'alg' is not of the
+// family of the verification key.
+var ErrAlgorithmKeyMismatch = jws.ErrAlgorithmKeyMismatch
+
+// JWKSet is a JWK Set, the {"keys": [...]} document identity providers
+// publish their keys in.
+type JWKSet = jwk.Set
+
+// InitHMACSignerVerifier initializes a new HMAC signer/verifier.
+func InitHMACSignerVerifier(alg Algorithm, key []byte) (*HMACSignerVerifier, error) {
+	return jws.InitHMACSignerVerifier(alg, key)
+}
+
+// InitRSASigner initializes a new RSA family signer.
+func InitRSASigner(alg Algorithm, key *rsa.PrivateKey) (*RSASigner, error) {
+	return jws.InitRSASigner(alg, key)
+}
+
+// InitRSAVerifier initializes a new RSA family verifier.
+func InitRSAVerifier(alg Algorithm, key *rsa.PublicKey) (*RSAVerifier, error) {
+	return jws.InitRSAVerifier(alg, key)
+}
+
+// InitECDSASigner initializes a new ECDSA family signer.
+func InitECDSASigner(alg Algorithm, key *ecdsa.PrivateKey) (*ECDSASigner, error) {
+	return jws.InitECDSASigner(alg, key)
+}
+
+// InitECDSAVerifier initializes a new ECDSA family verifier.
+func InitECDSAVerifier(alg Algorithm, key *ecdsa.PublicKey) (*ECDSAVerifier, error) {
+	return jws.InitECDSAVerifier(alg, key)
+}
+
+// InitEdDSASigner initializes a new EdDSA signer.
+func InitEdDSASigner(alg Algorithm, key *ed25519.PrivateKey) (*EdDSASigner, error) {
+	return jws.InitEdDSASigner(alg, key)
+}
+
+// InitEdDSAVerifier initializes a new EdDSA verifier.
+func InitEdDSAVerifier(alg Algorithm, key *ed25519.PublicKey) (*EdDSAVerifier, error) {
+	return jws.InitEdDSAVerifier(alg, key)
+}
+
+// InitNoneSignerVerifier initializes a new 'None' signer/verifier.
+func InitNoneSignerVerifier(alg Algorithm) (*NoneSignerVerifier, error) {
+	return jws.InitNoneSignerVerifier(alg)
+}
+
+// Base64URLEncode encodes a byte array into a base64url string.
+func Base64URLEncode(arg []byte) string {
+	return jws.Base64URLEncode(arg)
+}
+
+// Base64URLDecode decodes a base64url string into a byte array.
+func Base64URLDecode(arg string) ([]byte, error) {
+	return jws.Base64URLDecode(arg)
+}
+
+// GetHash returns the hash calculated from the plaintext, as required by the algorithm
+func GetHash(algorithm Algorithm, plaintext []byte) ([]byte, error) {
+	return jws.GetHash(algorithm, plaintext)
+}
@@ -0,0 +1,219 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FailureLimiter throttles the sources of failed verifications, such as
+// client IPs or the subjects tokens claim, to slow down attempts to forge
+// tokens by brute force. See FailureLimitMiddleware.
+type FailureLimiter interface {
+	// Wait returns how long the source must wait before its next attempt,
+	// or zero if it may attempt now.
+	Wait(source string) time.Duration
+
+	// Failure records a failed verification by the source.
+	Failure(source string)
+}
+
+// BackoffLimiter is an in-memory FailureLimiter applying exponential
+// backoff. A source may fail threshold times freely; each further failure
+// blocks it for twice as long as the previous one, starting at base, until
+// blocks reach max, which acts as a temporary ban. A source's failures are
+// forgotten once it hasn't failed for max.
+type BackoffLimiter struct {
+	threshold int
+	base      time.Duration
+	max       time.Duration
+
+	mu        sync.Mutex
+	sources   map[string]*backoffState
+	lastSweep time.Time
+}
+
+type backoffState struct {
+	failures     int
+	lastFailure  time.Time
+	blockedUntil time.Time
+}
+
+// NewBackoffLimiter creates a BackoffLimiter blocking sources after
+// threshold failures, for base doubling up to max.
+func NewBackoffLimiter(threshold int, base time.Duration, max time.Duration) (*BackoffLimiter, error) {
+	if threshold < 0 {
+		return nil, errors.New("Failure threshold cannot be negative")
+	}
+	if base <= 0 || max < base {
+		return nil, errors.New("Backoff must be positive and not exceed the maximum")
+	}
+
+	return &BackoffLimiter{
+		threshold: threshold,
+		base:      base,
+		max:       max,
+		sources:   make(map[string]*backoffState),
+		lastSweep: time.Now(),
+	}, nil
+}
+
+// Wait returns how long the source is blocked for.
+func (l *BackoffLimiter) Wait(source string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	state, ok := l.sources[source]
+	if !ok {
+		return 0
+	}
+
+	if wait := time.Until(state.blockedUntil); wait > 0 {
+		return wait
+	}
+	return 0
+}
+
+// Failure records a failure by the source, blocking it once it has failed
+// more than the threshold.
+func (l *BackoffLimiter) Failure(source string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(l.lastSweep) >= l.max {
+		for s, state := range l.sources {
+			if now.Sub(state.lastFailure) >= l.max {
+				delete(l.sources, s)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	state, ok := l.sources[source]
+	if !ok || now.Sub(state.lastFailure) >= l.max {
+		state = &backoffState{}
+		l.sources[source] = state
+	}
+	state.failures++
+	state.lastFailure = now
+
+	if excess := state.failures - l.threshold; excess > 0 {
+		state.blockedUntil = now.Add(l.backoff(excess))
+	}
+}
+
+// backoff returns the block of the nth failure over the threshold.
+func (l *BackoffLimiter) backoff(n int) time.Duration {
+	backoff := l.base
+	for i := 1; i < n && backoff < l.max; i++ {
+		backoff *= 2
+	}
+	if backoff > l.max {
+		return l.max
+	}
+	return backoff
+}
+
+// FailureSourcesFunc returns the sources a request's failures are counted
+// against. The token is not yet verified.
+type FailureSourcesFunc func(r *http.Request, rawToken []byte) []string
+
+// DefaultFailureSources returns the client IP of the request, as "ip:"
+// followed by the address, and the subject the token claims, if any, as
+// "sub:" followed by the subject. Behind a proxy, the client IP is the
+// proxy's; use a FailureSourcesFunc reading the forwarded address the
+// proxy sets instead.
+func DefaultFailureSources(r *http.Request, rawToken []byte) []string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if nil != err {
+		host = r.RemoteAddr
+	}
+	sources := []string{"ip:" + host}
+
+	if subject := unverifiedSubject(rawToken); subject != "" {
+		sources = append(sources, "sub:"+subject)
+	}
+
+	return sources
+}
+
+// unverifiedSubject returns the 'sub' claim of a token without verifying
+// it, or an empty string.
+func unverifiedSubject(rawToken []byte) string {
+	parts := strings.Split(string(rawToken), ".")
+	if len(parts) != 3 {
+		return ""
+	}
+
+	body, err := Base64URLDecode(parts[1])
+	if nil != err {
+		return ""
+	}
+
+	var claims struct {
+		Subject string `json:"sub"`
+	}
+	if err := json.Unmarshal(body, &claims); nil != err {
+		return ""
+	}
+
+	return claims.Subject
+}
+
+// FailureLimitMiddleware wraps a handler, requiring requests to carry a
+// bearer token that verifies against the criteria, or are rejected with
+// 401 Unauthorized. Failed verifications are counted against each of the
+// sources returned by sources, or by DefaultFailureSources if nil, and
+// requests from a source the limiter blocks are rejected with 429 Too Many
+// Requests and a Retry-After header before their token is verified.
+//
+// Anyone can claim a subject in a forged token, so counting failures by
+// subject lets attackers block a subject's valid tokens for as long as they
+// keep failing. This is the price of throttling attacks on one subject
+// spread across many client IPs.
+func (sv *JOSESignerVerifier) FailureLimitMiddleware(validationCriteria *ValidationClaims, limiter FailureLimiter, sources FailureSourcesFunc, next http.Handler) http.Handler {
+	if nil == sources {
+		sources = DefaultFailureSources
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		const prefix = "Bearer "
+		authorization := r.Header.Get("Authorization")
+		if !strings.HasPrefix(authorization, prefix) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		rawToken := []byte(authorization[len(prefix):])
+
+		requestSources := sources(r, rawToken)
+
+		var wait time.Duration
+		for _, source := range requestSources {
+			if sourceWait := limiter.Wait(source); sourceWait > wait {
+				wait = sourceWait
+			}
+		}
+		if wait > 0 {
+			w.Header().Set("Retry-After", strconv.FormatInt(int64((wait+time.Second-1)/time.Second), 10))
+			http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+			return
+		}
+
+		_, valid, err := sv.VerifyToken(rawToken, validationCriteria)
+		if nil != err || !valid {
+			for _, source := range requestSources {
+				limiter.Failure(source)
+			}
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
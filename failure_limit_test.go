@@ -0,0 +1,102 @@
+package jwt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBackoffLimiter(t *testing.T) {
+	if _, err := NewBackoffLimiter(3, 0, time.Hour); nil == err {
+		t.Errorf("NewBackoffLimiter() expected error without a backoff")
+	}
+	if _, err := NewBackoffLimiter(3, time.Hour, time.Minute); nil == err {
+		t.Errorf("NewBackoffLimiter() expected error with a backoff exceeding the maximum")
+	}
+
+	limiter, _ := NewBackoffLimiter(2, time.Minute, 5*time.Minute)
+
+	tests := []struct {
+		name     string
+		wantWait time.Duration
+	}{
+		{"Must not block the first failure", 0},
+		{"Must not block failures up to the threshold", 0},
+		{"Must block the first failure over the threshold for the base", time.Minute},
+		{"Must double the block", 2 * time.Minute},
+		{"Must double the block again", 4 * time.Minute},
+		{"Must cap the block at the maximum", 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter.Failure("ip:192.0.2.1")
+
+			wait := limiter.Wait("ip:192.0.2.1")
+			if wait > tt.wantWait || wait < tt.wantWait-time.Second {
+				t.Errorf("BackoffLimiter.Wait() = %v, want %v", wait, tt.wantWait)
+			}
+		})
+	}
+
+	if wait := limiter.Wait("ip:192.0.2.2"); wait != 0 {
+		t.Errorf("BackoffLimiter.Wait() = %v for another source, want 0", wait)
+	}
+
+	limiter.sources["ip:192.0.2.1"].lastFailure = time.Now().Add(-5 * time.Minute)
+	limiter.sources["ip:192.0.2.1"].blockedUntil = time.Now()
+	limiter.Failure("ip:192.0.2.1")
+	if wait := limiter.Wait("ip:192.0.2.1"); wait != 0 {
+		t.Errorf("BackoffLimiter.Wait() = %v after failures were forgotten, want 0", wait)
+	}
+}
+
+func TestFailureLimitMiddleware(t *testing.T) {
+	sv, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	forger, _ := NewJOSESignerVerifier(HS256, []byte("not the key shared with the server"))
+	limiter, _ := NewBackoffLimiter(1, time.Minute, time.Hour)
+
+	handler := sv.FailureLimitMiddleware(nil, limiter, nil, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	header := Header{Algorithm: string(HS256)}
+	alice, _ := sv.GenerateToken(header, Claims{Subject: "alice"})
+	bob, _ := sv.GenerateToken(header, Claims{Subject: "bob"})
+	forgedAlice, _ := forger.GenerateToken(header, Claims{Subject: "alice"})
+	forgedCarol, _ := forger.GenerateToken(header, Claims{Subject: "carol"})
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		token      []byte
+		wantStatus int
+	}{
+		{"Must accept a valid token", "192.0.2.1:1234", alice, http.StatusNoContent},
+		{"Must reject a request without a token", "192.0.2.1:1234", nil, http.StatusUnauthorized},
+		{"Must reject a forged token", "192.0.2.1:1234", forgedCarol, http.StatusUnauthorized},
+		{"Must reject a second forged token", "192.0.2.1:1234", forgedCarol, http.StatusUnauthorized},
+		{"Must throttle a client IP after repeated failures", "192.0.2.1:1234", bob, http.StatusTooManyRequests},
+		{"Must not throttle other client IPs", "192.0.2.2:1234", bob, http.StatusNoContent},
+		{"Must throttle a subject after repeated failures across client IPs", "192.0.2.3:1234", forgedCarol, http.StatusTooManyRequests},
+		{"Must not throttle other subjects", "192.0.2.4:1234", forgedAlice, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "https://api.example.com/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if nil != tt.token {
+				r.Header.Set("Authorization", "Bearer "+string(tt.token))
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("FailureLimitMiddleware() status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusTooManyRequests && w.Header().Get("Retry-After") == "" {
+				t.Errorf("FailureLimitMiddleware() has no Retry-After header")
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package jwt
+
+import (
+	"sync"
+	"time"
+)
+
+// Reasons tokens fail verification, as counted by
+// RecentVerificationFailures.
+const (
+	FailureMalformed = "malformed"
+	FailureSignature = "signature"
+	FailureClaims    = "claims"
+	FailureInternal  = "internal"
+)
+
+// recentFailureWindow is the period RecentVerificationFailures covers, in
+// one minute buckets.
+const recentFailureWindow = 15
+
+// failureBucket counts the failures within one minute.
+type failureBucket struct {
+	minute int64
+	counts map[string]uint64
+}
+
+var recentFailures struct {
+	mu      sync.Mutex
+	buckets [recentFailureWindow]failureBucket
+}
+
+// recordVerificationFailure counts a verification failure for the reason.
+func recordVerificationFailure(reason string) {
+	minute := time.Now().Unix() / 60
+
+	recentFailures.mu.Lock()
+	defer recentFailures.mu.Unlock()
+
+	bucket := &recentFailures.buckets[minute%recentFailureWindow]
+	if bucket.minute != minute || nil == bucket.counts {
+		bucket.minute = minute
+		bucket.counts = make(map[string]uint64)
+	}
+	bucket.counts[reason]++
+}
+
+// RecentVerificationFailures returns the number of tokens that failed
+// VerifyToken in the last 15 minutes, by reason.
+func RecentVerificationFailures() map[string]uint64 {
+	oldest := time.Now().Unix()/60 - recentFailureWindow + 1
+
+	recentFailures.mu.Lock()
+	defer recentFailures.mu.Unlock()
+
+	counts := make(map[string]uint64)
+	for _, bucket := range recentFailures.buckets {
+		if bucket.minute < oldest {
+			continue
+		}
+		for reason, count := range bucket.counts {
+			counts[reason] += count
+		}
+	}
+
+	return counts
+}
@@ -0,0 +1,280 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// maxCachedClaimSubjects bounds the number of subjects whose claims a
+// CachingClaimResolver holds at once.
+const maxCachedClaimSubjects = 4096
+
+// ClaimResolver fetches claims about the subject of a verified token from
+// a source other than the token, such as a UserInfo endpoint or a
+// directory.
+type ClaimResolver interface {
+	ResolveClaims(token *Token) (map[string]interface{}, error)
+}
+
+// ClaimResolverFunc adapts a function to a ClaimResolver.
+type ClaimResolverFunc func(token *Token) (map[string]interface{}, error)
+
+// ResolveClaims calls f(token).
+func (f ClaimResolverFunc) ResolveClaims(token *Token) (map[string]interface{}, error) {
+	return f(token)
+}
+
+// Principal is the subject of a verified token, with the token's claims
+// merged with those resolved from other sources.
+type Principal struct {
+	Issuer  string
+	Subject string
+	Claims  map[string]interface{}
+
+	Token *Token
+}
+
+// Claim returns the claim at a dot separated path, e.g. "address.country".
+func (p *Principal) Claim(path string) (interface{}, bool) {
+	return lookupClaimPath(p.Claims, path)
+}
+
+// ClaimFederator verifies thin tokens, carrying little more than a
+// subject, and enriches them with claims fetched from its resolvers, so
+// services can rely on claims the identity provider doesn't issue in
+// tokens. The token's own claims take precedence over resolved claims,
+// and earlier resolvers over later ones.
+type ClaimFederator struct {
+	verifier  *JOSESignerVerifier
+	resolvers []ClaimResolver
+}
+
+// NewClaimFederator creates a ClaimFederator verifying tokens with
+// verifier, and resolving claims with each of the resolvers in turn.
+func NewClaimFederator(verifier *JOSESignerVerifier, resolvers ...ClaimResolver) (*ClaimFederator, error) {
+	if nil == verifier {
+		return nil, errors.New("Claim federation requires a verifier")
+	}
+	if len(resolvers) == 0 {
+		return nil, errors.New("Claim federation requires at least one claim resolver")
+	}
+
+	return &ClaimFederator{verifier: verifier, resolvers: resolvers}, nil
+}
+
+// Principal verifies the token, validates its claims against the criteria
+// and, if valid, resolves its subject's claims. A resolver failing fails
+// the whole resolution, so services never act on a partial claim set.
+func (f *ClaimFederator) Principal(rawToken []byte, validationCriteria *ValidationClaims) (*Principal, bool, error) {
+	token, valid, err := f.verifier.VerifyToken(rawToken, validationCriteria)
+	if !valid || nil != err {
+		return nil, valid, err
+	}
+
+	principal, err := f.Federate(token)
+	if nil != err {
+		return nil, false, err
+	}
+
+	return principal, true, nil
+}
+
+// Federate resolves the claims of the subject of a token already verified.
+// Resolved claims about another subject, according to their 'sub' claim,
+// are rejected.
+func (f *ClaimFederator) Federate(token *Token) (*Principal, error) {
+	if nil == token || !token.signatureValid {
+		return nil, errors.New("Claims can only be federated for a verified token")
+	}
+	if token.RegisteredClaims.Subject == "" {
+		return nil, errors.New("Claims can only be federated for a token with a subject")
+	}
+
+	claims, err := decodeClaimsMap(token.DecodedBody)
+	if nil != err {
+		return nil, err
+	}
+
+	for _, resolver := range f.resolvers {
+		resolved, err := resolver.ResolveClaims(token)
+		if nil != err {
+			return nil, err
+		}
+
+		if subject, ok := resolved["sub"]; ok && subject != token.RegisteredClaims.Subject {
+			return nil, fmt.Errorf("Claim source returned claims about subject %v, expected %q", subject, token.RegisteredClaims.Subject)
+		}
+
+		for name, value := range resolved {
+			if _, ok := claims[name]; !ok {
+				claims[name] = value
+			}
+		}
+	}
+
+	return &Principal{
+		Issuer:  token.RegisteredClaims.Issuer,
+		Subject: token.RegisteredClaims.Subject,
+		Claims:  claims,
+		Token:   token,
+	}, nil
+}
+
+// CachingClaimResolver caches the claims resolved for each subject, keyed
+// by the token's issuer and subject, so sources are not queried for every
+// request.
+type CachingClaimResolver struct {
+	resolver ClaimResolver
+	ttl      time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cachedClaims
+}
+
+// cachedClaims are the cached claims of a subject.
+type cachedClaims struct {
+	claims map[string]interface{}
+	expiry time.Time
+}
+
+// NewCachingClaimResolver creates a CachingClaimResolver caching the claims
+// resolver resolves for ttl.
+func NewCachingClaimResolver(resolver ClaimResolver, ttl time.Duration) (*CachingClaimResolver, error) {
+	if nil == resolver {
+		return nil, errors.New("Claim resolver cannot be nil")
+	}
+	if ttl <= 0 {
+		return nil, errors.New("Claim cache TTL must be positive")
+	}
+
+	return &CachingClaimResolver{
+		resolver: resolver,
+		ttl:      ttl,
+		entries:  make(map[string]cachedClaims),
+	}, nil
+}
+
+// ResolveClaims returns the cached claims of the token's subject, or
+// resolves and caches them. Failures are not cached.
+func (c *CachingClaimResolver) ResolveClaims(token *Token) (map[string]interface{}, error) {
+	key := claimCacheKey(token.RegisteredClaims.Issuer, token.RegisteredClaims.Subject)
+	now := time.Now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && now.Before(entry.expiry) {
+		return entry.claims, nil
+	}
+
+	claims, err := c.resolver.ResolveClaims(token)
+	if nil != err {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= maxCachedClaimSubjects {
+		c.evict(now)
+	}
+	c.entries[key] = cachedClaims{claims: claims, expiry: now.Add(c.ttl)}
+
+	return claims, nil
+}
+
+// Invalidate drops the cached claims of a subject, e.g. after the subject
+// is updated in the directory.
+func (c *CachingClaimResolver) Invalidate(issuer string, subject string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, claimCacheKey(issuer, subject))
+}
+
+// evict drops expired entries, or an arbitrary entry if none has expired.
+// It must be called with the lock held.
+func (c *CachingClaimResolver) evict(now time.Time) {
+	for key, entry := range c.entries {
+		if !now.Before(entry.expiry) {
+			delete(c.entries, key)
+		}
+	}
+
+	if len(c.entries) < maxCachedClaimSubjects {
+		return
+	}
+	for key := range c.entries {
+		delete(c.entries, key)
+		return
+	}
+}
+
+func claimCacheKey(issuer string, subject string) string {
+	return issuer + "\x00" + subject
+}
+
+// UserInfoResolver resolves claims from an OpenID Connect UserInfo
+// endpoint (OpenID Connect Core 1.0, section 5.3), presenting the token
+// being verified as the access token.
+type UserInfoResolver struct {
+	endpoint string
+	client   *http.Client
+}
+
+// NewUserInfoResolver creates a UserInfoResolver for the HTTPS endpoint,
+// requesting claims with client, or a client with a 10 second timeout if
+// nil.
+func NewUserInfoResolver(endpoint string, client *http.Client) (*UserInfoResolver, error) {
+	parsed, err := url.Parse(endpoint)
+	if nil != err {
+		return nil, err
+	}
+	if parsed.Scheme != "https" || parsed.Host == "" {
+		return nil, fmt.Errorf("UserInfo endpoint %q must be an absolute HTTPS URL", endpoint)
+	}
+
+	if nil == client {
+		client = &http.Client{Timeout: defaultRemoteTimeout}
+	}
+
+	return &UserInfoResolver{endpoint: endpoint, client: client}, nil
+}
+
+// ResolveClaims requests the claims of the token's subject from the
+// UserInfo endpoint. Only plain JSON responses are supported, not signed
+// or encrypted ones.
+func (r *UserInfoResolver) ResolveClaims(token *Token) (map[string]interface{}, error) {
+	request, err := http.NewRequest(http.MethodGet, r.endpoint, nil)
+	if nil != err {
+		return nil, err
+	}
+	request.Header.Set("Authorization", "Bearer "+string(token.RawToken))
+	request.Header.Set("Accept", "application/json")
+
+	response, err := r.client.Do(request)
+	if nil != err {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("UserInfo request failed with status %d", response.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(response.Body, defaultRemoteMaxResponseSize+1))
+	if nil != err {
+		return nil, err
+	}
+	if len(body) > defaultRemoteMaxResponseSize {
+		return nil, fmt.Errorf("UserInfo response exceeds %d bytes", defaultRemoteMaxResponseSize)
+	}
+
+	return decodeClaimsMap(body)
+}
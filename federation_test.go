@@ -0,0 +1,101 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClaimFederator(t *testing.T) {
+	issuer, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	header := Header{Algorithm: string(HS256), Type: "JWT"}
+	rawToken, _ := issuer.GenerateToken(header, map[string]interface{}{"sub": "alice", "iss": "issuer", "email": "alice@token.example"})
+	otherToken, _ := issuer.GenerateToken(header, Claims{Issuer: "issuer"})
+
+	var userInfoRequests int
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userInfoRequests++
+		if r.Header.Get("Authorization") != "Bearer "+string(rawToken) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"sub": "alice", "name": "Alice", "email": "alice@userinfo.example"})
+	}))
+	defer server.Close()
+
+	if _, err := NewUserInfoResolver("http://userinfo.example", nil); nil == err {
+		t.Errorf("NewUserInfoResolver() expected error for a HTTP endpoint")
+	}
+	if _, err := NewClaimFederator(issuer); nil == err {
+		t.Errorf("NewClaimFederator() expected error without resolvers")
+	}
+
+	userInfo, _ := NewUserInfoResolver(server.URL, server.Client())
+	cachedUserInfo, _ := NewCachingClaimResolver(userInfo, time.Minute)
+	directory := ClaimResolverFunc(func(token *Token) (map[string]interface{}, error) {
+		return map[string]interface{}{"name": "Alice Directory", "department": "Finance"}, nil
+	})
+	impostor := ClaimResolverFunc(func(token *Token) (map[string]interface{}, error) {
+		return map[string]interface{}{"sub": "mallory"}, nil
+	})
+	failing := ClaimResolverFunc(func(token *Token) (map[string]interface{}, error) {
+		return nil, errors.New("Directory unavailable")
+	})
+
+	tests := []struct {
+		name       string
+		resolvers  []ClaimResolver
+		token      []byte
+		wantValid  bool
+		wantErr    bool
+		wantClaims map[string]interface{}
+	}{
+		{"Must merge claims from the UserInfo endpoint and a directory", []ClaimResolver{cachedUserInfo, directory}, rawToken, true, false, map[string]interface{}{"name": "Alice", "department": "Finance", "email": "alice@token.example"}},
+		{"Must not resolve claims about another subject", []ClaimResolver{impostor}, rawToken, false, true, nil},
+		{"Must fail when a resolver fails", []ClaimResolver{directory, failing}, rawToken, false, true, nil},
+		{"Must not resolve claims for a token without a subject", []ClaimResolver{directory}, otherToken, false, true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			federator, _ := NewClaimFederator(issuer, tt.resolvers...)
+			principal, valid, err := federator.Principal(tt.token, &ValidationClaims{Issuer: []string{"issuer"}, Subject: []string{"alice"}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ClaimFederator.Principal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if valid != tt.wantValid {
+				t.Errorf("ClaimFederator.Principal() valid = %v, want %v", valid, tt.wantValid)
+			}
+			for name, want := range tt.wantClaims {
+				if got, _ := principal.Claim(name); got != want {
+					t.Errorf("ClaimFederator.Principal() claim %s = %v, want %v", name, got, want)
+				}
+			}
+		})
+	}
+
+	federator, _ := NewClaimFederator(issuer, cachedUserInfo)
+	for i := 0; i < 3; i++ {
+		federator.Principal(rawToken, nil)
+	}
+	if userInfoRequests != 1 {
+		t.Errorf("CachingClaimResolver made %d UserInfo requests, want 1", userInfoRequests)
+	}
+
+	cachedUserInfo.Invalidate("issuer", "alice")
+	if principal, _, err := federator.Principal(rawToken, nil); nil != err || principal.Subject != "alice" {
+		t.Errorf("ClaimFederator.Principal() = %v, %v after invalidation", principal, err)
+	}
+	if userInfoRequests != 2 {
+		t.Errorf("CachingClaimResolver made %d UserInfo requests after invalidation, want 2", userInfoRequests)
+	}
+
+	unauthorized, _ := NewUserInfoResolver(server.URL, server.Client())
+	token, _, _ := issuer.VerifyToken(otherToken, nil)
+	if _, err := unauthorized.ResolveClaims(token); nil == err || !strings.Contains(err.Error(), "401") {
+		t.Errorf("UserInfoResolver.ResolveClaims() error = %v, want status 401", err)
+	}
+}
@@ -0,0 +1,190 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// ErrReferenceNotFound is returned when a reference token is unknown,
+// expired or revoked.
+var ErrReferenceNotFound = errors.New("Reference token not found")
+
+// ReferenceStore stores the claims behind opaque reference tokens.
+type ReferenceStore interface {
+	// Put stores the claims for the reference until expiry.
+	Put(reference string, claims []byte, expiry time.Time) error
+	// Get returns the claims for the reference, or ErrReferenceNotFound.
+	Get(reference string) ([]byte, error)
+	// Delete removes the reference.
+	Delete(reference string) error
+}
+
+// MemoryReferenceStore is an in-memory ReferenceStore, suitable for a
+// single gateway instance.
+type MemoryReferenceStore struct {
+	mu      sync.Mutex
+	entries map[string]referenceEntry
+}
+
+type referenceEntry struct {
+	claims []byte
+	expiry time.Time
+}
+
+// NewMemoryReferenceStore creates an empty MemoryReferenceStore.
+func NewMemoryReferenceStore() *MemoryReferenceStore {
+	return &MemoryReferenceStore{
+		entries: make(map[string]referenceEntry),
+	}
+}
+
+// Put stores the claims for the reference until expiry.
+func (s *MemoryReferenceStore) Put(reference string, claims []byte, expiry time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[reference] = referenceEntry{
+		claims: claims,
+		expiry: expiry,
+	}
+	return nil
+}
+
+// Get returns the claims for the reference, or ErrReferenceNotFound.
+func (s *MemoryReferenceStore) Get(reference string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[reference]
+	if !ok {
+		return nil, ErrReferenceNotFound
+	}
+
+	if !time.Now().Before(entry.expiry) {
+		delete(s.entries, reference)
+		return nil, ErrReferenceNotFound
+	}
+
+	return entry.claims, nil
+}
+
+// Delete removes the reference.
+func (s *MemoryReferenceStore) Delete(reference string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, reference)
+	return nil
+}
+
+// referenceLength is the number of random bytes in a reference token.
+const referenceLength = 32
+
+// TokenGateway translates between opaque reference tokens, handed to
+// clients on the public internet, and short-lived JWTs minted for internal
+// hops. The claims behind each reference are kept in a ReferenceStore, so
+// a reference reveals nothing and can be revoked by deleting it.
+type TokenGateway struct {
+	sv    *JOSESignerVerifier
+	store ReferenceStore
+	ttl   time.Duration
+	rng   io.Reader
+}
+
+// NewTokenGateway creates a TokenGateway minting internal JWTs valid for
+// ttl with the JOSESignerVerifier.
+func NewTokenGateway(sv *JOSESignerVerifier, store ReferenceStore, ttl time.Duration) (*TokenGateway, error) {
+	if nil == sv || nil == store {
+		return nil, errors.New("Token gateway requires a JOSESignerVerifier and a ReferenceStore")
+	}
+
+	if ttl <= 0 {
+		return nil, errors.New("Token gateway TTL must be positive")
+	}
+
+	return &TokenGateway{
+		sv:    sv,
+		store: store,
+		ttl:   ttl,
+		rng:   rand.Reader,
+	}, nil
+}
+
+// IssueReference stores the claims and returns an opaque reference token
+// for them, valid until expiry.
+func (g *TokenGateway) IssueReference(claims interface{}, expiry time.Time) (string, error) {
+	encoded, err := json.Marshal(claims)
+	if nil != err {
+		return "", err
+	}
+
+	// Claims must be an object so they can be narrowed when exchanged.
+	if _, err := decodeClaimsMap(encoded); nil != err {
+		return "", err
+	}
+
+	b := make([]byte, referenceLength)
+	if _, err := io.ReadFull(g.rng, b); nil != err {
+		return "", err
+	}
+	reference := Base64URLEncode(b)
+
+	if err := g.store.Put(reference, encoded, expiry); nil != err {
+		return "", err
+	}
+
+	return reference, nil
+}
+
+// Resolve returns the claims stored for a reference token.
+func (g *TokenGateway) Resolve(reference string) (map[string]interface{}, error) {
+	encoded, err := g.store.Get(reference)
+	if nil != err {
+		return nil, err
+	}
+
+	return decodeClaimsMap(encoded)
+}
+
+// Exchange resolves a reference token and mints a narrow JWT for an
+// internal audience. The JWT carries the 'iss' and 'sub' of the stored
+// claims and the named claims only, and expires after the gateway TTL.
+func (g *TokenGateway) Exchange(reference string, audience string, claimNames ...string) ([]byte, error) {
+	if audience == "" {
+		return nil, errors.New("Exchanged tokens must have an audience")
+	}
+
+	stored, err := g.Resolve(reference)
+	if nil != err {
+		return nil, err
+	}
+
+	now := time.Now()
+	narrow := map[string]interface{}{
+		"aud": audience,
+		"iat": NewNumericDate(now),
+		"exp": NewNumericDate(now.Add(g.ttl)),
+	}
+	for _, name := range append([]string{"iss", "sub"}, claimNames...) {
+		if value, ok := stored[name]; ok {
+			narrow[name] = value
+		}
+	}
+
+	return g.sv.GenerateToken(
+		Header{
+			Algorithm: string(g.sv.algorithm),
+			Type:      "JWT",
+		},
+		narrow,
+	)
+}
+
+// Revoke deletes a reference token, so it can no longer be exchanged.
+func (g *TokenGateway) Revoke(reference string) error {
+	return g.store.Delete(reference)
+}
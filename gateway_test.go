@@ -0,0 +1,64 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTokenGateway(t *testing.T) {
+	sv, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	gateway, err := NewTokenGateway(sv, NewMemoryReferenceStore(), time.Minute)
+	if nil != err {
+		t.Fatalf("NewTokenGateway() error = %v", err)
+	}
+
+	reference, err := gateway.IssueReference(map[string]interface{}{
+		"iss":    "https://issuer.example.com",
+		"sub":    "alice",
+		"scope":  "orders:read",
+		"email":  "alice@example.com",
+		"tenant": "acme",
+	}, time.Now().Add(time.Hour))
+	if nil != err {
+		t.Fatalf("TokenGateway.IssueReference() error = %v", err)
+	}
+
+	raw, err := gateway.Exchange(reference, "orders-service", "scope")
+	if nil != err {
+		t.Fatalf("TokenGateway.Exchange() error = %v", err)
+	}
+
+	token, valid, err := sv.VerifyToken(raw, &ValidationClaims{
+		Issuer:   []string{"https://issuer.example.com"},
+		Subject:  []string{"alice"},
+		Audience: []string{"orders-service"},
+	})
+	if !valid || nil != err {
+		t.Fatalf("VerifyToken() = %v, %v, want valid", valid, err)
+	}
+
+	var claims map[string]interface{}
+	GetClaims(token, &claims)
+	if claims["scope"] != "orders:read" {
+		t.Errorf("Exchanged token scope = %v, want orders:read", claims["scope"])
+	}
+	if _, ok := claims["email"]; ok {
+		t.Errorf("Exchanged token carries a claim that was not requested")
+	}
+
+	if err := gateway.Revoke(reference); nil != err {
+		t.Fatalf("TokenGateway.Revoke() error = %v", err)
+	}
+	if _, err := gateway.Exchange(reference, "orders-service"); err != ErrReferenceNotFound {
+		t.Errorf("TokenGateway.Exchange() error = %v, want ErrReferenceNotFound", err)
+	}
+
+	expired, _ := gateway.IssueReference(Claims{Subject: "bob"}, time.Now().Add(-time.Second))
+	if _, err := gateway.Resolve(expired); err != ErrReferenceNotFound {
+		t.Errorf("TokenGateway.Resolve() error = %v, want ErrReferenceNotFound for an expired reference", err)
+	}
+
+	if _, err := gateway.IssueReference("not an object", time.Now().Add(time.Hour)); nil == err {
+		t.Errorf("TokenGateway.IssueReference() expected error for non-object claims")
+	}
+}
@@ -0,0 +1,175 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/georgejenkins/jwt/jwk"
+)
+
+// GoldenToken is the machine-readable description of a signed token kept
+// in a golden file, from which the token can be regenerated and verified.
+//
+// Golden files hold the signing key, so they must only be made with test
+// keys.
+type GoldenToken struct {
+	Algorithm Algorithm       `json:"alg"`
+	Key       json.RawMessage `json:"key"`
+	Header    json.RawMessage `json:"header"`
+	Claims    json.RawMessage `json:"claims"`
+
+	// VerifyAt is the time time-based claims are validated at.
+	VerifyAt time.Time `json:"verify_at"`
+
+	// Deterministic is true when the algorithm produces the same signature
+	// every time, so the token itself can be compared.
+	Deterministic bool `json:"deterministic"`
+
+	Token string `json:"token"`
+}
+
+// WriteGoldenFile signs a token with the key and writes it with its
+// description to a golden file at path. Time-based claims will be
+// validated at verifyAt, which should fall within the token's validity.
+func WriteGoldenFile(path string, alg Algorithm, key interface{}, header interface{}, claims interface{}, verifyAt time.Time) (*GoldenToken, error) {
+	encodedKey, err := jwk.Marshal(key)
+	if nil != err {
+		return nil, err
+	}
+
+	encodedHeader, err := json.Marshal(header)
+	if nil != err {
+		return nil, err
+	}
+
+	encodedClaims, err := json.Marshal(claims)
+	if nil != err {
+		return nil, err
+	}
+
+	golden := &GoldenToken{
+		Algorithm:     alg,
+		Key:           encodedKey,
+		Header:        encodedHeader,
+		Claims:        encodedClaims,
+		VerifyAt:      verifyAt.UTC(),
+		Deterministic: isDeterministicAlgorithm(alg),
+	}
+
+	token, err := golden.sign()
+	if nil != err {
+		return nil, err
+	}
+	golden.Token = string(token)
+
+	if err := golden.verify(); nil != err {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(golden, "", "  ")
+	if nil != err {
+		return nil, err
+	}
+
+	return golden, ioutil.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// VerifyGoldenFile reads a golden file and checks the token in it still
+// verifies, and for deterministic algorithms that the token is still
+// generated identically from its description.
+func VerifyGoldenFile(path string) (*GoldenToken, error) {
+	data, err := ioutil.ReadFile(path)
+	if nil != err {
+		return nil, err
+	}
+
+	var golden GoldenToken
+	if err := json.Unmarshal(data, &golden); nil != err {
+		return nil, err
+	}
+
+	if err := golden.verify(); nil != err {
+		return nil, err
+	}
+
+	if golden.Deterministic {
+		token, err := golden.sign()
+		if nil != err {
+			return nil, err
+		}
+		if !bytes.Equal(token, []byte(golden.Token)) {
+			return nil, fmt.Errorf("Golden token %s no longer matches the generated token %s", golden.Token, token)
+		}
+	}
+
+	return &golden, nil
+}
+
+// signerVerifier returns a JOSESignerVerifier for the golden key.
+func (g *GoldenToken) signerVerifier() (*JOSESignerVerifier, error) {
+	key, err := jwk.Parse(g.Key)
+	if nil != err {
+		return nil, err
+	}
+
+	return NewJOSESignerVerifier(g.Algorithm, key)
+}
+
+// sign generates the token from its description.
+func (g *GoldenToken) sign() ([]byte, error) {
+	sv, err := g.signerVerifier()
+	if nil != err {
+		return nil, err
+	}
+
+	return sv.GenerateToken(g.Header, g.Claims)
+}
+
+// verify verifies the golden token's signature and registered claims.
+func (g *GoldenToken) verify() error {
+	sv, err := g.signerVerifier()
+	if nil != err {
+		return err
+	}
+
+	token, err := GetRawTokenParts([]byte(g.Token))
+	if nil != err {
+		return err
+	}
+
+	var claims Claims
+	if err := GetClaims(token, &claims); nil != err {
+		return err
+	}
+
+	_, valid, err := sv.VerifyToken([]byte(g.Token), &ValidationClaims{
+		Issuer:     []string{claims.Issuer},
+		Subject:    []string{claims.Subject},
+		Audience:   []string{claims.Audience},
+		Expiration: g.VerifyAt,
+		NotBefore:  g.VerifyAt,
+	})
+	if nil != err {
+		return err
+	}
+	if !valid {
+		return errors.New("Golden token does not verify")
+	}
+
+	return nil
+}
+
+// isDeterministicAlgorithm reports whether the algorithm always produces
+// the same signature for the same input and key.
+func isDeterministicAlgorithm(alg Algorithm) bool {
+	switch alg {
+	case HS256, HS384, HS512, RS256, RS384, RS512, EdDSA:
+		return true
+	}
+
+	return false
+}
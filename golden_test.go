@@ -0,0 +1,78 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGoldenFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "golden")
+	if nil != err {
+		t.Fatalf("TempDir() error = %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	issuedAt := time.Unix(1600000000, 0)
+	claims := Claims{
+		Issuer:     "https://issuer.example.com",
+		Subject:    "alice",
+		IssuedAt:   NewNumericDate(issuedAt),
+		Expiration: NewNumericDate(issuedAt.Add(time.Hour)),
+	}
+
+	tests := []struct {
+		name              string
+		alg               Algorithm
+		key               interface{}
+		wantDeterministic bool
+	}{
+		{"Must round trip an HS256 golden file", HS256, exampleKey, true},
+		{"Must round trip an EdDSA golden file", EdDSA, &edKey, true},
+		{"Must round trip an ES256 golden file", ES256, ecKey, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, string(tt.alg)+".json")
+
+			written, err := WriteGoldenFile(path, tt.alg, tt.key, Header{Algorithm: string(tt.alg)}, claims, issuedAt.Add(time.Minute))
+			if nil != err {
+				t.Fatalf("WriteGoldenFile() error = %v", err)
+			}
+			if written.Deterministic != tt.wantDeterministic {
+				t.Errorf("WriteGoldenFile() deterministic = %v, want %v", written.Deterministic, tt.wantDeterministic)
+			}
+
+			read, err := VerifyGoldenFile(path)
+			if nil != err {
+				t.Fatalf("VerifyGoldenFile() error = %v", err)
+			}
+			if read.Token != written.Token {
+				t.Errorf("VerifyGoldenFile() token = %v, want %v", read.Token, written.Token)
+			}
+
+			// A change to the token must be detected.
+			data, _ := ioutil.ReadFile(path)
+			signature := written.Token[strings.LastIndex(written.Token, ".")+1:]
+			tampered := strings.Replace(string(data), signature, strings.Repeat("A", len(signature)), 1)
+			ioutil.WriteFile(path, []byte(tampered), 0644)
+			if _, err := VerifyGoldenFile(path); nil == err {
+				t.Errorf("VerifyGoldenFile() expected error for a tampered token")
+			}
+		})
+	}
+
+	if _, err := WriteGoldenFile(filepath.Join(dir, "expired.json"), HS256, exampleKey, Header{Algorithm: string(HS256)}, claims, issuedAt.Add(2*time.Hour)); nil == err {
+		t.Errorf("WriteGoldenFile() expected error verifying outside of the token's validity")
+	}
+}
@@ -1,4 +1,4 @@
-package main
+package jwt
 
 import "encoding/json"
 
@@ -12,7 +12,7 @@ type Header struct {
 
 	KeyID string `json:"kid,omitempty"`
 
-	// X509URL string `json:"x5u"`
+	X509URL string `json:"x5u,omitempty"`
 
 	// X509CertificateChain string `json:"x5c"`
 
@@ -22,7 +22,15 @@ type Header struct {
 
 	ContentType string `json:"cty,omitempty"`
 
-	// Critical string `json:"crit"`
+	// Nonce is a server-provided nonce, as used by ACME (RFC 8555).
+	Nonce string `json:"nonce,omitempty"`
+
+	// Base64Payload set to false leaves the payload unencoded (RFC 7797),
+	// see UnencodedPayloadHeader.
+	Base64Payload *bool `json:"b64,omitempty"`
+
+	// Critical lists the header parameters recipients must understand.
+	Critical []string `json:"crit,omitempty"`
 }
 
 func GetHeader(token *Token, outputType interface{}) error {
@@ -0,0 +1,18 @@
+// Package josecompat converts between the types of this library and those
+// of gopkg.in/go-jose/go-jose (v2, v3 and v4), so projects using both can
+// share key material and tokens without re-encoding them by hand.
+//
+// The conversions go through the types' standard serializations, JWKs and
+// compact JWSs, so the package does not depend on go-jose: its
+// *JSONWebKey, JSONWebKeySet and *JSONWebSignature types satisfy the
+// interfaces accepted here.
+//
+//	var joseKey jose.JSONWebKey
+//	if err := josecompat.KeyToJSONWebKey(key, &joseKey); nil != err {
+//		return err
+//	}
+//
+//	signature, err := josecompat.TokenToJSONWebSignature(rawToken, func(token string) (josecompat.CompactSerializer, error) {
+//		return jose.ParseSigned(token, []jose.SignatureAlgorithm{jose.ES256})
+//	})
+package josecompat
@@ -0,0 +1,73 @@
+package josecompat
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/georgejenkins/jwt/jwk"
+)
+
+// KeyFromJSONWebKey converts a go-jose *JSONWebKey to a Key. X.509
+// certificate chains ('x5c') are not carried over.
+func KeyFromJSONWebKey(joseKey json.Marshaler) (*jwk.Key, error) {
+	if nil == joseKey {
+		return nil, errors.New("JSON Web Key cannot be nil")
+	}
+
+	data, err := joseKey.MarshalJSON()
+	if nil != err {
+		return nil, err
+	}
+
+	var key jwk.Key
+	if err := json.Unmarshal(data, &key); nil != err {
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+// KeyToJSONWebKey converts a Key into a go-jose *JSONWebKey, joseKey.
+func KeyToJSONWebKey(key *jwk.Key, joseKey json.Unmarshaler) error {
+	if nil == key || nil == joseKey {
+		return errors.New("Keys cannot be nil")
+	}
+
+	data, err := json.Marshal(key)
+	if nil != err {
+		return err
+	}
+
+	return joseKey.UnmarshalJSON(data)
+}
+
+// KeySetFromJSONWebKeySet converts a go-jose JSONWebKeySet, or a pointer
+// to one, to a Set. As jwk.ParseSet does, keys that can't be converted are
+// ignored rather than failing the set.
+func KeySetFromJSONWebKeySet(joseSet interface{}) (*jwk.Set, error) {
+	if nil == joseSet {
+		return nil, errors.New("JSON Web Key Set cannot be nil")
+	}
+
+	data, err := json.Marshal(joseSet)
+	if nil != err {
+		return nil, err
+	}
+
+	return jwk.ParseSet(data)
+}
+
+// KeySetToJSONWebKeySet converts a Set into a go-jose *JSONWebKeySet,
+// joseSet.
+func KeySetToJSONWebKeySet(set *jwk.Set, joseSet interface{}) error {
+	if nil == set || nil == joseSet {
+		return errors.New("Key sets cannot be nil")
+	}
+
+	data, err := json.Marshal(set)
+	if nil != err {
+		return err
+	}
+
+	return json.Unmarshal(data, joseSet)
+}
@@ -0,0 +1,118 @@
+package josecompat
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/georgejenkins/jwt/jwa"
+	"github.com/georgejenkins/jwt/jwk"
+)
+
+// fakeJSONWebKey serializes as go-jose's JSONWebKey does, as a JWK.
+type fakeJSONWebKey struct {
+	raw json.RawMessage
+}
+
+func (k fakeJSONWebKey) MarshalJSON() ([]byte, error) {
+	return k.raw, nil
+}
+
+func (k *fakeJSONWebKey) UnmarshalJSON(data []byte) error {
+	k.raw = append(k.raw[:0], data...)
+	return nil
+}
+
+// fakeJSONWebKeySet serializes as go-jose's JSONWebKeySet does.
+type fakeJSONWebKeySet struct {
+	Keys []fakeJSONWebKey `json:"keys"`
+}
+
+func TestKeyConversion(t *testing.T) {
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	tests := []struct {
+		name string
+		key  *jwk.Key
+	}{
+		{
+			name: "Must convert an ECDSA public key",
+			key:  &jwk.Key{KeyID: "ec", Algorithm: jwa.ES256, Use: jwk.UseSignature, Key: &ecKey.PublicKey},
+		},
+		{
+			name: "Must convert an ECDSA private key",
+			key:  &jwk.Key{KeyID: "ec", Algorithm: jwa.ES256, Key: ecKey},
+		},
+		{
+			name: "Must convert an RSA private key",
+			key:  &jwk.Key{KeyID: "rsa", Algorithm: jwa.RS256, Key: rsaKey},
+		},
+		{
+			name: "Must convert an Ed25519 private key",
+			key:  &jwk.Key{KeyID: "ed", Algorithm: jwa.EdDSA, Key: &edKey},
+		},
+		{
+			name: "Must convert an HMAC secret",
+			key:  &jwk.Key{KeyID: "oct", Algorithm: jwa.HS256, Key: []byte("0123456789abcdef0123456789abcdef")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var joseKey fakeJSONWebKey
+			if err := KeyToJSONWebKey(tt.key, &joseKey); nil != err {
+				t.Fatalf("KeyToJSONWebKey() error = %v", err)
+			}
+
+			key, err := KeyFromJSONWebKey(joseKey)
+			if nil != err {
+				t.Fatalf("KeyFromJSONWebKey() error = %v", err)
+			}
+
+			if key.KeyID != tt.key.KeyID || key.Algorithm != tt.key.Algorithm || key.Use != tt.key.Use {
+				t.Errorf("KeyFromJSONWebKey() = %+v, want %+v", key, tt.key)
+			}
+
+			want, _ := jwk.Thumbprint(tt.key.Key)
+			got, _ := jwk.Thumbprint(key.Key)
+			if got != want || reflect.TypeOf(key.Key) != reflect.TypeOf(tt.key.Key) {
+				t.Errorf("KeyFromJSONWebKey() key = %T %s, want %T %s", key.Key, got, tt.key.Key, want)
+			}
+		})
+	}
+}
+
+func TestKeySetConversion(t *testing.T) {
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+
+	set := &jwk.Set{Keys: []*jwk.Key{
+		{KeyID: "ec", Algorithm: jwa.ES256, Key: &ecKey.PublicKey},
+		{KeyID: "rsa", Algorithm: jwa.RS256, Key: &rsaKey.PublicKey},
+	}}
+
+	var joseSet fakeJSONWebKeySet
+	if err := KeySetToJSONWebKeySet(set, &joseSet); nil != err {
+		t.Fatalf("KeySetToJSONWebKeySet() error = %v", err)
+	}
+	if len(joseSet.Keys) != 2 {
+		t.Fatalf("KeySetToJSONWebKeySet() converted %d keys, want 2", len(joseSet.Keys))
+	}
+
+	joseSet.Keys = append(joseSet.Keys, fakeJSONWebKey{raw: json.RawMessage(`{"kty":"unknown"}`)})
+
+	converted, err := KeySetFromJSONWebKeySet(&joseSet)
+	if nil != err {
+		t.Fatalf("KeySetFromJSONWebKeySet() error = %v", err)
+	}
+	if len(converted.Keys) != 2 || len(converted.Find("rsa", jwa.RS256, "")) != 1 {
+		t.Errorf("KeySetFromJSONWebKeySet() = %+v, want the ec and rsa keys", converted.Keys)
+	}
+}
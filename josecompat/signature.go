@@ -0,0 +1,87 @@
+package josecompat
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/georgejenkins/jwt/jws"
+)
+
+// CompactSerializer is a JWS serializable in the compact serialization,
+// such as a go-jose *JSONWebSignature.
+type CompactSerializer interface {
+	CompactSerialize() (string, error)
+}
+
+// SignatureParser parses a compact JWS, usually by calling go-jose's
+// ParseSigned with the algorithms expected.
+type SignatureParser func(token string) (CompactSerializer, error)
+
+// TokenFromJSONWebSignature converts a go-jose *JSONWebSignature to a raw
+// token, for the VerifySignature and VerifyToken methods of package jwt.
+// Signatures go-jose can't serialize compactly, those with several
+// signatures or an unprotected header, can't be converted.
+func TokenFromJSONWebSignature(signature CompactSerializer) ([]byte, error) {
+	if nil == signature {
+		return nil, errors.New("JSON Web Signature cannot be nil")
+	}
+
+	token, err := signature.CompactSerialize()
+	if nil != err {
+		return nil, err
+	}
+
+	if err := checkCompact(token); nil != err {
+		return nil, err
+	}
+
+	return []byte(token), nil
+}
+
+// TokenToJSONWebSignature converts a raw token, as generated by package
+// jwt, to a go-jose *JSONWebSignature with parse. The signature is not
+// verified.
+func TokenToJSONWebSignature(rawToken []byte, parse SignatureParser) (CompactSerializer, error) {
+	if nil == parse {
+		return nil, errors.New("Signature parser cannot be nil")
+	}
+
+	token := string(rawToken)
+	if err := checkCompact(token); nil != err {
+		return nil, err
+	}
+
+	return parse(token)
+}
+
+// checkCompact checks the token is a compact JWS, with a header naming its
+// algorithm and a signature.
+func checkCompact(token string) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("Compact JWS must have 3 parts, found %d", len(parts))
+	}
+
+	headerBytes, err := jws.Base64URLDecode(parts[0])
+	if nil != err {
+		return err
+	}
+
+	var header struct {
+		Algorithm string `json:"alg"`
+	}
+	if err := json.Unmarshal(headerBytes, &header); nil != err {
+		return err
+	}
+	if header.Algorithm == "" {
+		return errors.New("Compact JWS header has no 'alg'")
+	}
+
+	if parts[2] == "" {
+		return errors.New("Compact JWS has no signature")
+	}
+
+	return nil
+}
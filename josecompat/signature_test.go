@@ -0,0 +1,82 @@
+package josecompat
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/georgejenkins/jwt"
+)
+
+// fakeJSONWebSignature serializes as go-jose's JSONWebSignature does.
+type fakeJSONWebSignature struct {
+	compact string
+	err     error
+}
+
+func (s *fakeJSONWebSignature) CompactSerialize() (string, error) {
+	return s.compact, s.err
+}
+
+func TestTokenConversion(t *testing.T) {
+	sv, err := jwt.NewJOSESignerVerifier(jwt.HS256, []byte("0123456789abcdef0123456789abcdef"))
+	if nil != err {
+		t.Fatal(err)
+	}
+	rawToken, err := sv.GenerateToken(jwt.Header{Algorithm: "HS256", Type: "JWT"}, jwt.Claims{Subject: "alice"})
+	if nil != err {
+		t.Fatal(err)
+	}
+
+	parse := func(token string) (CompactSerializer, error) {
+		return &fakeJSONWebSignature{compact: token}, nil
+	}
+
+	signature, err := TokenToJSONWebSignature(rawToken, parse)
+	if nil != err {
+		t.Fatalf("TokenToJSONWebSignature() error = %v", err)
+	}
+
+	converted, err := TokenFromJSONWebSignature(signature)
+	if nil != err {
+		t.Fatalf("TokenFromJSONWebSignature() error = %v", err)
+	}
+	if _, valid, err := sv.VerifySignature(converted); !valid || nil != err {
+		t.Errorf("VerifySignature() = %v, %v, want a valid token", valid, err)
+	}
+}
+
+func TestTokenConversionErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		signature CompactSerializer
+	}{
+		{
+			name:      "Must reject a nil signature",
+			signature: nil,
+		},
+		{
+			name:      "Must reject a signature go-jose can't serialize compactly",
+			signature: &fakeJSONWebSignature{err: errors.New("square/go-jose: cannot use compact serialization")},
+		},
+		{
+			name:      "Must reject a token with too few parts",
+			signature: &fakeJSONWebSignature{compact: "eyJhbGciOiJIUzI1NiJ9.e30"},
+		},
+		{
+			name:      "Must reject a token without an algorithm",
+			signature: &fakeJSONWebSignature{compact: "e30.e30.c2ln"},
+		},
+		{
+			name:      "Must reject an unsigned token",
+			signature: &fakeJSONWebSignature{compact: "eyJhbGciOiJub25lIn0.e30."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := TokenFromJSONWebSignature(tt.signature); nil == err {
+				t.Errorf("TokenFromJSONWebSignature() error = nil, want an error")
+			}
+		})
+	}
+}
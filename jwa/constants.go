@@ -1,4 +1,4 @@
-package main
+package jwa
 
 // Algorithm represents the algorithm used to sign the JWT.
 type Algorithm string
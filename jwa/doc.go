@@ -0,0 +1,3 @@
+// Package jwa defines the JSON Web Algorithms (RFC 7518) used to sign
+// JSON Web Signatures and encrypt JSON Web Encryptions.
+package jwa
@@ -0,0 +1,66 @@
+package jwa
+
+// KeyManagementAlgorithm represents the algorithm used to determine the
+// content encryption key of a JWE.
+type KeyManagementAlgorithm string
+
+// "alg" (Algorithm) Header Parameter Values for JWE
+const (
+	// RSAOAEP RSAES OAEP using default parameters				Recommended+
+	RSAOAEP KeyManagementAlgorithm = "RSA-OAEP"
+	// RSAOAEP256 RSAES OAEP using SHA-256 and MGF1 with SHA-256		Optional
+	RSAOAEP256 KeyManagementAlgorithm = "RSA-OAEP-256"
+	// A128KW AES Key Wrap with default initial value using 128-bit key	Recommended
+	A128KW KeyManagementAlgorithm = "A128KW"
+	// A192KW AES Key Wrap with default initial value using 192-bit key	Optional
+	A192KW KeyManagementAlgorithm = "A192KW"
+	// A256KW AES Key Wrap with default initial value using 256-bit key	Recommended
+	A256KW KeyManagementAlgorithm = "A256KW"
+	// Direct use of a shared symmetric key as the CEK			Recommended
+	Direct KeyManagementAlgorithm = "dir"
+	// ECDHES Elliptic Curve Diffie-Hellman Ephemeral Static key		Recommended+
+	// agreement using Concat KDF
+	ECDHES KeyManagementAlgorithm = "ECDH-ES"
+	// ECDHESA128KW ECDH-ES using Concat KDF and CEK wrapped with "A128KW"	Recommended
+	ECDHESA128KW KeyManagementAlgorithm = "ECDH-ES+A128KW"
+	// ECDHESA192KW ECDH-ES using Concat KDF and CEK wrapped with "A192KW"	Optional
+	ECDHESA192KW KeyManagementAlgorithm = "ECDH-ES+A192KW"
+	// ECDHESA256KW ECDH-ES using Concat KDF and CEK wrapped with "A256KW"	Recommended
+	ECDHESA256KW KeyManagementAlgorithm = "ECDH-ES+A256KW"
+	// A128GCMKW Key wrapping with AES GCM using 128-bit key		Optional
+	A128GCMKW KeyManagementAlgorithm = "A128GCMKW"
+	// A192GCMKW Key wrapping with AES GCM using 192-bit key		Optional
+	A192GCMKW KeyManagementAlgorithm = "A192GCMKW"
+	// A256GCMKW Key wrapping with AES GCM using 256-bit key		Optional
+	A256GCMKW KeyManagementAlgorithm = "A256GCMKW"
+)
+
+// ContentEncryptionAlgorithm represents the algorithm used to encrypt the
+// plaintext of a JWE.
+type ContentEncryptionAlgorithm string
+
+// "enc" (Encryption Algorithm) Header Parameter Values for JWE
+const (
+	// A128CBCHS256 AES CBC using 128-bit key with HMAC SHA-256		Required
+	A128CBCHS256 ContentEncryptionAlgorithm = "A128CBC-HS256"
+	// A192CBCHS384 AES CBC using 192-bit key with HMAC SHA-384		Optional
+	A192CBCHS384 ContentEncryptionAlgorithm = "A192CBC-HS384"
+	// A256CBCHS512 AES CBC using 256-bit key with HMAC SHA-512		Required
+	A256CBCHS512 ContentEncryptionAlgorithm = "A256CBC-HS512"
+	// A128GCM AES GCM using 128-bit key					Recommended
+	A128GCM ContentEncryptionAlgorithm = "A128GCM"
+	// A192GCM AES GCM using 192-bit key					Optional
+	A192GCM ContentEncryptionAlgorithm = "A192GCM"
+	// A256GCM AES GCM using 256-bit key					Recommended
+	A256GCM ContentEncryptionAlgorithm = "A256GCM"
+)
+
+// CompressionAlgorithm represents the algorithm used to compress the
+// plaintext of a JWE before encryption.
+type CompressionAlgorithm string
+
+// "zip" (Compression Algorithm) Header Parameter Values for JWE
+const (
+	// Deflate compression (RFC 1951)
+	Deflate CompressionAlgorithm = "DEF"
+)
@@ -0,0 +1,272 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/georgejenkins/jwt/jwe"
+)
+
+// JWEEncrypterDecrypter encrypts and decrypts tokens as compact JWEs
+// (RFC 7516), for tokens that must be confidential rather than only
+// integrity protected. It mirrors JOSESignerVerifier: GenerateToken
+// encrypts a claim set, and DecryptToken decrypts a token and validates
+// its registered claims.
+type JWEEncrypterDecrypter struct {
+	algorithm  KeyManagementAlgorithm
+	encryption ContentEncryptionAlgorithm
+	encrypter  KeyEncrypter
+	decrypter  KeyDecrypter
+
+	compression         CompressionAlgorithm
+	maxDecompressedSize int
+}
+
+// JWEOption configures optional behaviour of a JWEEncrypterDecrypter.
+type JWEOption func(ed *JWEEncrypterDecrypter) error
+
+// WithCompression compresses the plaintext of encrypted tokens with
+// DEFLATE ("zip":"DEF") unless their header selects otherwise, shrinking
+// large claim sets. Compression reveals how compressible the plaintext is
+// through its length, so it should not be used when attackers can choose
+// part of a plaintext that also holds secrets.
+func WithCompression() JWEOption {
+	return func(ed *JWEEncrypterDecrypter) error {
+		ed.compression = Deflate
+		return nil
+	}
+}
+
+// WithMaxDecompressedSize limits the size in bytes compressed plaintexts
+// may decompress to, rejecting decompression bombs. It defaults to
+// jwe.DefaultMaxDecompressedSize.
+func WithMaxDecompressedSize(size int) JWEOption {
+	return func(ed *JWEEncrypterDecrypter) error {
+		if size <= 0 {
+			return errors.New("Maximum decompressed size must be positive")
+		}
+
+		ed.maxDecompressedSize = size
+		return nil
+	}
+}
+
+// EncryptedToken is a decrypted JWE token.
+type EncryptedToken struct {
+	Header           JWEHeader
+	RegisteredClaims Claims
+
+	RawToken  []byte
+	Plaintext []byte
+}
+
+// NewJWEEncrypterDecrypter creates a new JWEEncrypterDecrypter encrypting
+// content with enc by default, under a content encryption key determined
+// by the key management algorithm alg with key:
+//
+//   - Direct encryption ("dir") takes a []byte key of the size enc requires.
+//   - A128KW, A192KW and A256KW, and A128GCMKW, A192GCMKW and A256GCMKW
+//     take a []byte key encryption key of the size alg requires.
+//   - RSA-OAEP and RSA-OAEP-256 take an *rsa.PrivateKey.
+//   - ECDH-ES and ECDH-ES+A128KW, +A192KW and +A256KW take a P-256, P-384
+//     or P-521 *ecdsa.PrivateKey or, from Go 1.20, an X25519
+//     *ecdh.PrivateKey.
+//
+// Given the public key of an RSA or ECDH key pair, the JWEEncrypterDecrypter
+// only encrypts.
+func NewJWEEncrypterDecrypter(alg KeyManagementAlgorithm, enc ContentEncryptionAlgorithm, key interface{}, opts ...JWEOption) (*JWEEncrypterDecrypter, error) {
+	size, err := jwe.CEKSize(enc)
+	if nil != err {
+		return nil, err
+	}
+
+	ed := &JWEEncrypterDecrypter{
+		algorithm:           alg,
+		encryption:          enc,
+		maxDecompressedSize: jwe.DefaultMaxDecompressedSize,
+	}
+
+	for _, opt := range opts {
+		if err := opt(ed); nil != err {
+			return nil, err
+		}
+	}
+
+	switch alg {
+	case Direct:
+		secret, ok := key.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("Cannot use key type %T for JWE alg %s", key, alg)
+		}
+		if len(secret) != size {
+			return nil, fmt.Errorf("JWE enc %s requires a %d byte key, received %d bytes", enc, size, len(secret))
+		}
+		km, err := jwe.InitDirectKeyManager(secret)
+		if nil != err {
+			return nil, err
+		}
+		ed.encrypter, ed.decrypter = km, km
+	case A128KW, A192KW, A256KW, A128GCMKW, A192GCMKW, A256GCMKW:
+		kek, ok := key.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("Cannot use key type %T for JWE alg %s", key, alg)
+		}
+		km, err := jwe.InitAESKeyManager(alg, kek)
+		if nil != err {
+			return nil, err
+		}
+		ed.encrypter, ed.decrypter = km, km
+	case RSAOAEP, RSAOAEP256:
+		if err := ed.initRSA(key); nil != err {
+			return nil, err
+		}
+	case ECDHES, ECDHESA128KW, ECDHESA192KW, ECDHESA256KW:
+		if err := ed.initECDH(key); nil != err {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("Unsupported JWE key management algorithm %q", alg)
+	}
+
+	return ed, nil
+}
+
+// initRSA configures RSA-OAEP key management. A private key encrypts and
+// decrypts, a public key only encrypts.
+func (ed *JWEEncrypterDecrypter) initRSA(key interface{}) error {
+	switch rsaKey := key.(type) {
+	case *rsa.PrivateKey:
+		kd, err := jwe.InitRSAKeyDecrypter(ed.algorithm, rsaKey)
+		if nil != err {
+			return err
+		}
+		ke, err := jwe.InitRSAKeyEncrypter(ed.algorithm, &rsaKey.PublicKey)
+		if nil != err {
+			return err
+		}
+		ed.encrypter, ed.decrypter = ke, kd
+	case *rsa.PublicKey:
+		ke, err := jwe.InitRSAKeyEncrypter(ed.algorithm, rsaKey)
+		if nil != err {
+			return err
+		}
+		ed.encrypter = ke
+	default:
+		return fmt.Errorf("Cannot use key type %T for JWE alg %s", key, ed.algorithm)
+	}
+
+	return nil
+}
+
+// initECDH configures ECDH-ES key management. A private key encrypts and
+// decrypts, a public key only encrypts.
+func (ed *JWEEncrypterDecrypter) initECDH(key interface{}) error {
+	public := key
+	if private, ok := key.(interface{ Public() crypto.PublicKey }); ok {
+		kd, err := jwe.InitECDHKeyDecrypter(ed.algorithm, key)
+		if nil != err {
+			return err
+		}
+		ed.decrypter = kd
+		public = private.Public()
+	}
+
+	ke, err := jwe.InitECDHKeyEncrypter(ed.algorithm, public)
+	if nil != err {
+		return err
+	}
+	ed.encrypter = ke
+
+	return nil
+}
+
+// Encrypt encrypts the plaintext, returning the compact JWE. The header's
+// 'alg' is set to that of the JWEEncrypterDecrypter, its 'enc' selects
+// the content encryption algorithm, or the default if empty, and its 'zip'
+// the compression algorithm, or the default if empty.
+func (ed *JWEEncrypterDecrypter) Encrypt(header JWEHeader, plaintext []byte) ([]byte, error) {
+	if nil == ed.encrypter {
+		return nil, errors.New("JWEEncrypterDecrypter not configured for encryption - did you provide the correct key type?")
+	}
+
+	if header.Encryption == "" {
+		header.Encryption = ed.encryption
+	}
+	if header.Compression == "" {
+		header.Compression = ed.compression
+	}
+	return jwe.Encrypt(plaintext, header, ed.encrypter)
+}
+
+// Decrypt decrypts a compact JWE. Its 'alg' must be that of the
+// JWEEncrypterDecrypter, and its 'enc' any supported content encryption
+// algorithm. Compressed plaintexts are decompressed, up to the maximum
+// decompressed size. The plaintext is not interpreted.
+func (ed *JWEEncrypterDecrypter) Decrypt(rawToken []byte) (*EncryptedToken, error) {
+	if nil == ed.decrypter {
+		return nil, errors.New("JWEEncrypterDecrypter not configured for decryption - did you provide the correct key type?")
+	}
+
+	parts, err := jwe.Parse(rawToken)
+	if nil != err {
+		return nil, err
+	}
+
+	plaintext, err := jwe.DecryptLimited(rawToken, ed.decrypter, ed.maxDecompressedSize)
+	if nil != err {
+		return nil, err
+	}
+
+	return &EncryptedToken{
+		Header:    parts.Header,
+		RawToken:  rawToken,
+		Plaintext: plaintext,
+	}, nil
+}
+
+// GenerateToken generates an encrypted token from a JWE header and a claim
+// set body, which is encoded as JSON.
+func (ed *JWEEncrypterDecrypter) GenerateToken(header JWEHeader, body interface{}) ([]byte, error) {
+	plaintext, err := json.Marshal(body)
+	if nil != err {
+		return nil, err
+	}
+
+	return ed.Encrypt(header, plaintext)
+}
+
+// DecryptToken decrypts the token, and validates its registered claims
+// against the criteria. With shared keys, "dir" and the AES key wrapping
+// algorithms, decryption authenticates the token: only holders of the key
+// could have encrypted it. With RSA and ECDH, anyone holding the public
+// key could have, so tokens must also be signed to be authenticated.
+func (ed *JWEEncrypterDecrypter) DecryptToken(rawToken []byte, validationCriteria *ValidationClaims) (*EncryptedToken, bool, error) {
+	token, err := ed.Decrypt(rawToken)
+	if nil != err {
+		return nil, false, err
+	}
+
+	return validateEncryptedToken(token, validationCriteria)
+}
+
+// validateEncryptedToken decodes the registered claims of a decrypted
+// token, and validates them against the criteria.
+func validateEncryptedToken(token *EncryptedToken, validationCriteria *ValidationClaims) (*EncryptedToken, bool, error) {
+	var claims Claims
+	if err := json.Unmarshal(token.Plaintext, &claims); nil != err {
+		return token, false, err
+	}
+	token.RegisteredClaims = claims
+
+	valid, err := claims.ValidateRegisteredClaims(validationCriteria)
+	return token, valid, err
+}
+
+// GetEncryptedClaims decodes the claim set of a decrypted token into
+// outputType.
+func GetEncryptedClaims(token *EncryptedToken, outputType interface{}) error {
+	return json.Unmarshal(token.Plaintext, outputType)
+}
@@ -0,0 +1,199 @@
+package jwe
+
+import (
+	"crypto/aes"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/georgejenkins/jwt/jwa"
+	"github.com/georgejenkins/jwt/jws"
+)
+
+// keyWrapIV is the default initial value of AES key wrap.
+var keyWrapIV = []byte{0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6, 0xa6}
+
+// aesKeyWrap wraps the key with the KEK using AES key wrap (RFC 3394).
+func aesKeyWrap(kek []byte, key []byte) ([]byte, error) {
+	if len(key) < 16 || len(key)%8 != 0 {
+		return nil, errors.New("AES key wrap requires a key of at least 16 bytes, in multiples of 8 bytes")
+	}
+
+	block, err := aes.NewCipher(kek)
+	if nil != err {
+		return nil, err
+	}
+
+	n := len(key) / 8
+	wrapped := make([]byte, 8+len(key))
+	copy(wrapped, keyWrapIV)
+	copy(wrapped[8:], key)
+
+	b := make([]byte, 16)
+	for j := 0; j < 6; j++ {
+		for i := 1; i <= n; i++ {
+			copy(b, wrapped[:8])
+			copy(b[8:], wrapped[8*i:8*i+8])
+			block.Encrypt(b, b)
+
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(wrapped[:8], binary.BigEndian.Uint64(b[:8])^t)
+			copy(wrapped[8*i:8*i+8], b[8:])
+		}
+	}
+
+	return wrapped, nil
+}
+
+// aesKeyUnwrap unwraps a key wrapped with the KEK using AES key wrap
+// (RFC 3394), failing if its integrity check fails.
+func aesKeyUnwrap(kek []byte, wrapped []byte) ([]byte, error) {
+	if len(wrapped) < 24 || len(wrapped)%8 != 0 {
+		return nil, errors.New("AES key wrapped keys must be at least 24 bytes, in multiples of 8 bytes")
+	}
+
+	block, err := aes.NewCipher(kek)
+	if nil != err {
+		return nil, err
+	}
+
+	n := len(wrapped)/8 - 1
+	key := make([]byte, len(wrapped))
+	copy(key, wrapped)
+
+	b := make([]byte, 16)
+	for j := 5; j >= 0; j-- {
+		for i := n; i >= 1; i-- {
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(b[:8], binary.BigEndian.Uint64(key[:8])^t)
+			copy(b[8:], key[8*i:8*i+8])
+			block.Decrypt(b, b)
+
+			copy(key[:8], b[:8])
+			copy(key[8*i:8*i+8], b[8:])
+		}
+	}
+
+	if subtle.ConstantTimeCompare(key[:8], keyWrapIV) != 1 {
+		return nil, errors.New("AES key unwrap failed, the wrapped key or KEK is invalid")
+	}
+
+	return key[8:], nil
+}
+
+// aesKeyManagerSizes are the KEK sizes of the AES key wrapping algorithms.
+var aesKeyManagerSizes = map[jwa.KeyManagementAlgorithm]int{
+	jwa.A128KW:    16,
+	jwa.A192KW:    24,
+	jwa.A256KW:    32,
+	jwa.A128GCMKW: 16,
+	jwa.A192GCMKW: 24,
+	jwa.A256GCMKW: 32,
+}
+
+// AESKeyManager wraps random content encryption keys with a shared key
+// encryption key (KEK), using AES key wrap (A128KW, A192KW, A256KW) or
+// AES GCM (A128GCMKW, A192GCMKW, A256GCMKW). AES GCM key wrapping sets
+// the 'iv' and 'tag' headers.
+type AESKeyManager struct {
+	algorithm jwa.KeyManagementAlgorithm
+	kek       []byte
+}
+
+// InitAESKeyManager initializes a new AES key manager. The KEK must be of
+// the algorithm's size.
+func InitAESKeyManager(alg jwa.KeyManagementAlgorithm, kek []byte) (*AESKeyManager, error) {
+	size, ok := aesKeyManagerSizes[alg]
+	if !ok {
+		return nil, errors.New("Key management algorithm unexpected, must be one of: A128KW, A192KW, A256KW, A128GCMKW, A192GCMKW, A256GCMKW")
+	}
+
+	if len(kek) != size {
+		return nil, fmt.Errorf("JWE alg %s requires a %d byte key, received %d bytes", alg, size, len(kek))
+	}
+
+	return &AESKeyManager{algorithm: alg, kek: kek}, nil
+}
+
+// Algorithm returns the key management algorithm.
+func (km *AESKeyManager) Algorithm() jwa.KeyManagementAlgorithm {
+	return km.algorithm
+}
+
+// EncryptKey generates a random CEK and wraps it with the KEK.
+func (km *AESKeyManager) EncryptKey(header *Header, cekSize int) ([]byte, []byte, error) {
+	cek := make([]byte, cekSize)
+	if _, err := io.ReadFull(rng, cek); nil != err {
+		return nil, nil, err
+	}
+
+	if !km.wrapsWithGCM() {
+		encryptedKey, err := aesKeyWrap(km.kek, cek)
+		if nil != err {
+			return nil, nil, err
+		}
+		return cek, encryptedKey, nil
+	}
+
+	wrapper := gcm{keySize: len(km.kek)}
+	iv := make([]byte, wrapper.IVSize())
+	if _, err := io.ReadFull(rng, iv); nil != err {
+		return nil, nil, err
+	}
+
+	encryptedKey, tag, err := wrapper.Encrypt(km.kek, iv, cek, nil)
+	if nil != err {
+		return nil, nil, err
+	}
+	header.IV = jws.Base64URLEncode(iv)
+	header.Tag = jws.Base64URLEncode(tag)
+
+	return cek, encryptedKey, nil
+}
+
+// DecryptKey unwraps the CEK with the KEK.
+func (km *AESKeyManager) DecryptKey(header Header, encryptedKey []byte, cekSize int) ([]byte, error) {
+	var cek []byte
+	var err error
+	if km.wrapsWithGCM() {
+		cek, err = km.unwrapGCM(header, encryptedKey)
+	} else {
+		cek, err = aesKeyUnwrap(km.kek, encryptedKey)
+	}
+	if nil != err {
+		return nil, err
+	}
+
+	if len(cek) != cekSize {
+		return nil, fmt.Errorf("JWE enc %s requires a %d byte CEK, unwrapped %d bytes", header.Encryption, cekSize, len(cek))
+	}
+
+	return cek, nil
+}
+
+// unwrapGCM decrypts a CEK wrapped with AES GCM, authenticated with the
+// 'iv' and 'tag' headers.
+func (km *AESKeyManager) unwrapGCM(header Header, encryptedKey []byte) ([]byte, error) {
+	iv, err := jws.Base64URLDecode(header.IV)
+	if nil != err {
+		return nil, fmt.Errorf("JWE 'iv' header is not base64url encoded: %v", err)
+	}
+
+	tag, err := jws.Base64URLDecode(header.Tag)
+	if nil != err {
+		return nil, fmt.Errorf("JWE 'tag' header is not base64url encoded: %v", err)
+	}
+
+	return gcm{keySize: len(km.kek)}.Decrypt(km.kek, iv, encryptedKey, tag, nil)
+}
+
+// wrapsWithGCM reports whether the algorithm wraps keys with AES GCM.
+func (km *AESKeyManager) wrapsWithGCM() bool {
+	switch km.algorithm {
+	case jwa.A128GCMKW, jwa.A192GCMKW, jwa.A256GCMKW:
+		return true
+	}
+	return false
+}
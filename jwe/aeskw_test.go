@@ -0,0 +1,135 @@
+package jwe
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/georgejenkins/jwt/jwa"
+	"github.com/georgejenkins/jwt/jws"
+)
+
+// TestAESKeyWrap_RFC3394 checks AES key wrap against the test vectors of
+// RFC 3394, section 4.
+func TestAESKeyWrap_RFC3394(t *testing.T) {
+	tests := []struct {
+		name    string
+		kek     []byte
+		key     []byte
+		wrapped []byte
+	}{
+		{
+			"Must wrap 128 bits of key data with a 128-bit KEK",
+			mustHexDecode(`000102030405060708090A0B0C0D0E0F`),
+			mustHexDecode(`00112233445566778899AABBCCDDEEFF`),
+			mustHexDecode(`1FA68B0A8112B447 AEF34BD8FB5A7B82 9D3E862371D2CFE5`),
+		},
+		{
+			"Must wrap 192 bits of key data with a 256-bit KEK",
+			mustHexDecode(`000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F`),
+			mustHexDecode(`00112233445566778899AABBCCDDEEFF0001020304050607`),
+			mustHexDecode(`A8F9BC1612C68B3F F6E6F4FBE30E71E4 769C8B80A32CB895 8CD5D17D6B254DA1`),
+		},
+		{
+			"Must wrap 256 bits of key data with a 256-bit KEK",
+			mustHexDecode(`000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F`),
+			mustHexDecode(`00112233445566778899AABBCCDDEEFF000102030405060708090A0B0C0D0E0F`),
+			mustHexDecode(`28C9F404C4B810F4 CBCCB35CFB87F826 3F5786E2D80ED326 CBC7F0E71A99F43B FB988B9B7A02DD21`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wrapped, err := aesKeyWrap(tt.kek, tt.key)
+			if nil != err || !bytes.Equal(wrapped, tt.wrapped) {
+				t.Fatalf("aesKeyWrap() = %X, %v, want %X", wrapped, err, tt.wrapped)
+			}
+
+			key, err := aesKeyUnwrap(tt.kek, wrapped)
+			if nil != err || !bytes.Equal(key, tt.key) {
+				t.Errorf("aesKeyUnwrap() = %X, %v, want %X", key, err, tt.key)
+			}
+
+			wrapped[len(wrapped)-1] ^= 1
+			if _, err := aesKeyUnwrap(tt.kek, wrapped); nil == err {
+				t.Errorf("aesKeyUnwrap() expected error for a modified wrapped key")
+			}
+		})
+	}
+}
+
+// TestAESKeyManager_RFC7516 decrypts the A128KW example of RFC 7516,
+// appendix A.3.
+func TestAESKeyManager_RFC7516(t *testing.T) {
+	kek, _ := jws.Base64URLDecode("GawgguFyGrWKav7AX4VKUg")
+	compact := "eyJhbGciOiJBMTI4S1ciLCJlbmMiOiJBMTI4Q0JDLUhTMjU2In0." +
+		"6KB707dM9YTIgHtLvtgWQ8mKwboJW3of9locizkDTHzBC2IlrT1oOQ." +
+		"AxY8DCtDaGlsbGljb3RoZQ." +
+		"KDlTtXchhZTGufMYmOYGS4HffxPSUrfmqCHXaI9wOGY." +
+		"U0m_YmjN04DJvceFICbCVQ"
+
+	km, err := InitAESKeyManager(jwa.A128KW, kek)
+	if nil != err {
+		t.Fatalf("InitAESKeyManager() error = %v", err)
+	}
+
+	got, err := Decrypt([]byte(compact), km)
+	if nil != err || string(got) != "Live long and prosper." {
+		t.Errorf("Decrypt() = %s, %v", got, err)
+	}
+}
+
+func TestAESKeyManager(t *testing.T) {
+	plaintext := []byte("The Blue Stripes will ambush Radovid on the bridge to Temple Isle")
+
+	tests := []struct {
+		name       string
+		alg        jwa.KeyManagementAlgorithm
+		enc        jwa.ContentEncryptionAlgorithm
+		kek        []byte
+		decryptKEK []byte
+		wantErr    bool
+	}{
+		{"Must round trip A128KW", jwa.A128KW, jwa.A128CBCHS256, bytes.Repeat([]byte{1}, 16), bytes.Repeat([]byte{1}, 16), false},
+		{"Must round trip A192KW", jwa.A192KW, jwa.A256GCM, bytes.Repeat([]byte{1}, 24), bytes.Repeat([]byte{1}, 24), false},
+		{"Must round trip A256KW", jwa.A256KW, jwa.A256CBCHS512, bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{1}, 32), false},
+		{"Must round trip A128GCMKW", jwa.A128GCMKW, jwa.A128GCM, bytes.Repeat([]byte{1}, 16), bytes.Repeat([]byte{1}, 16), false},
+		{"Must round trip A256GCMKW", jwa.A256GCMKW, jwa.A128CBCHS256, bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{1}, 32), false},
+		{"Must not decrypt A256KW with another KEK", jwa.A256KW, jwa.A256GCM, bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 32), true},
+		{"Must not decrypt A256GCMKW with another KEK", jwa.A256GCMKW, jwa.A256GCM, bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 32), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ke, err := InitAESKeyManager(tt.alg, tt.kek)
+			if nil != err {
+				t.Fatalf("InitAESKeyManager() error = %v", err)
+			}
+			kd, err := InitAESKeyManager(tt.alg, tt.decryptKEK)
+			if nil != err {
+				t.Fatalf("InitAESKeyManager() error = %v", err)
+			}
+
+			compact, err := Encrypt(plaintext, Header{Encryption: tt.enc}, ke)
+			if nil != err {
+				t.Fatalf("Encrypt() error = %v", err)
+			}
+			parts, _ := Parse(compact)
+			if gcm := ke.wrapsWithGCM(); gcm != (parts.Header.IV != "" && parts.Header.Tag != "") {
+				t.Errorf("Encrypt() header = %+v", parts.Header)
+			}
+
+			got, err := Decrypt(compact, kd)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Decrypt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !bytes.Equal(got, plaintext) {
+				t.Errorf("Decrypt() = %s, want %s", got, plaintext)
+			}
+		})
+	}
+
+	if _, err := InitAESKeyManager(jwa.A256KW, bytes.Repeat([]byte{1}, 16)); nil == err {
+		t.Errorf("InitAESKeyManager() expected error for a 16 byte A256KW key")
+	}
+	if _, err := InitAESKeyManager(jwa.Direct, bytes.Repeat([]byte{1}, 16)); nil == err {
+		t.Errorf("InitAESKeyManager() expected error for dir")
+	}
+}
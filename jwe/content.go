@@ -0,0 +1,223 @@
+package jwe
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+
+	"github.com/georgejenkins/jwt/jwa"
+)
+
+// ContentEncrypter encrypts and decrypts the plaintext of a JWE with a
+// content encryption algorithm, authenticating the additional data.
+type ContentEncrypter interface {
+	// KeySize is the size of the content encryption key, in bytes.
+	KeySize() int
+	// IVSize is the size of the initialization vector, in bytes.
+	IVSize() int
+	Encrypt(cek, iv, plaintext, aad []byte) (ciphertext []byte, tag []byte, err error)
+	Decrypt(cek, iv, ciphertext, tag, aad []byte) ([]byte, error)
+}
+
+// contentEncrypters are the content encryption algorithms of RFC 7518,
+// section 5.
+var contentEncrypters = map[jwa.ContentEncryptionAlgorithm]ContentEncrypter{
+	jwa.A128CBCHS256: &cbcHMAC{keySize: 32, newHash: sha256.New},
+	jwa.A192CBCHS384: &cbcHMAC{keySize: 48, newHash: sha512.New384},
+	jwa.A256CBCHS512: &cbcHMAC{keySize: 64, newHash: sha512.New},
+	jwa.A128GCM:      gcm{keySize: 16},
+	jwa.A192GCM:      gcm{keySize: 24},
+	jwa.A256GCM:      gcm{keySize: 32},
+}
+
+// GetContentEncrypter returns the ContentEncrypter of a content encryption
+// algorithm.
+func GetContentEncrypter(enc jwa.ContentEncryptionAlgorithm) (ContentEncrypter, error) {
+	ce, ok := contentEncrypters[enc]
+	if !ok {
+		return nil, fmt.Errorf("Unsupported JWE content encryption algorithm %q", enc)
+	}
+
+	return ce, nil
+}
+
+// CEKSize returns the content encryption key size, in bytes, of the content
+// encryption algorithm.
+func CEKSize(enc jwa.ContentEncryptionAlgorithm) (int, error) {
+	ce, err := GetContentEncrypter(enc)
+	if nil != err {
+		return 0, err
+	}
+
+	return ce.KeySize(), nil
+}
+
+// gcm is AES GCM (RFC 7518, section 5.3).
+type gcm struct {
+	keySize int
+}
+
+func (c gcm) KeySize() int {
+	return c.keySize
+}
+
+func (c gcm) IVSize() int {
+	return 12
+}
+
+func (c gcm) aead(cek []byte) (cipher.AEAD, error) {
+	if len(cek) != c.keySize {
+		return nil, fmt.Errorf("AES GCM requires a %d byte key, received %d bytes", c.keySize, len(cek))
+	}
+
+	block, err := aes.NewCipher(cek)
+	if nil != err {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func (c gcm) Encrypt(cek, iv, plaintext, aad []byte) ([]byte, []byte, error) {
+	aead, err := c.aead(cek)
+	if nil != err {
+		return nil, nil, err
+	}
+	if len(iv) != aead.NonceSize() {
+		return nil, nil, errors.New("JWE initialization vector has an invalid length")
+	}
+
+	sealed := aead.Seal(nil, iv, plaintext, aad)
+	tagStart := len(sealed) - aead.Overhead()
+
+	return sealed[:tagStart], sealed[tagStart:], nil
+}
+
+func (c gcm) Decrypt(cek, iv, ciphertext, tag, aad []byte) ([]byte, error) {
+	aead, err := c.aead(cek)
+	if nil != err {
+		return nil, err
+	}
+
+	if len(iv) != aead.NonceSize() {
+		return nil, errors.New("JWE initialization vector has an invalid length")
+	}
+
+	if len(tag) != aead.Overhead() {
+		return nil, errors.New("JWE authentication tag has an invalid length")
+	}
+
+	sealed := append(append([]byte{}, ciphertext...), tag...)
+	plaintext, err := aead.Open(nil, iv, sealed, aad)
+	if nil != err {
+		return nil, errors.New("JWE decryption failed")
+	}
+
+	return plaintext, nil
+}
+
+// cbcHMAC is AES CBC with HMAC SHA-2 (RFC 7518, section 5.2). The first
+// half of the key is the MAC key, the second the encryption key.
+type cbcHMAC struct {
+	keySize int
+	newHash func() hash.Hash
+}
+
+func (c *cbcHMAC) KeySize() int {
+	return c.keySize
+}
+
+func (c *cbcHMAC) IVSize() int {
+	return aes.BlockSize
+}
+
+func (c *cbcHMAC) Encrypt(cek, iv, plaintext, aad []byte) ([]byte, []byte, error) {
+	if len(cek) != c.keySize {
+		return nil, nil, fmt.Errorf("AES CBC HMAC requires a %d byte key, received %d bytes", c.keySize, len(cek))
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, nil, errors.New("JWE initialization vector has an invalid length")
+	}
+	macKey, encKey := cek[:c.keySize/2], cek[c.keySize/2:]
+
+	block, err := aes.NewCipher(encKey)
+	if nil != err {
+		return nil, nil, err
+	}
+
+	padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+	ciphertext := make([]byte, len(plaintext)+padding)
+	copy(ciphertext, plaintext)
+	for i := len(plaintext); i < len(ciphertext); i++ {
+		ciphertext[i] = byte(padding)
+	}
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, ciphertext)
+
+	return ciphertext, c.tag(macKey, iv, ciphertext, aad), nil
+}
+
+func (c *cbcHMAC) Decrypt(cek, iv, ciphertext, tag, aad []byte) ([]byte, error) {
+	if len(cek) != c.keySize {
+		return nil, fmt.Errorf("AES CBC HMAC requires a %d byte key, received %d bytes", c.keySize, len(cek))
+	}
+	if len(iv) != aes.BlockSize {
+		return nil, errors.New("JWE initialization vector has an invalid length")
+	}
+	if len(tag) != c.keySize/2 {
+		return nil, errors.New("JWE authentication tag has an invalid length")
+	}
+	macKey, encKey := cek[:c.keySize/2], cek[c.keySize/2:]
+
+	// The tag is checked before decrypting, so padding errors can't be used
+	// as an oracle.
+	if subtle.ConstantTimeCompare(tag, c.tag(macKey, iv, ciphertext, aad)) != 1 {
+		return nil, errors.New("JWE decryption failed")
+	}
+
+	if len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, errors.New("JWE decryption failed")
+	}
+
+	block, err := aes.NewCipher(encKey)
+	if nil != err {
+		return nil, err
+	}
+
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > aes.BlockSize {
+		return nil, errors.New("JWE decryption failed")
+	}
+	for _, b := range plaintext[len(plaintext)-padding:] {
+		if int(b) != padding {
+			return nil, errors.New("JWE decryption failed")
+		}
+	}
+
+	return plaintext[:len(plaintext)-padding], nil
+}
+
+// tag computes the authentication tag, the first half of the HMAC of the
+// additional data, the IV, the ciphertext, and the additional data's
+// length in bits.
+func (c *cbcHMAC) tag(macKey, iv, ciphertext, aad []byte) []byte {
+	al := make([]byte, 8)
+	binary.BigEndian.PutUint64(al, uint64(len(aad))*8)
+
+	mac := hmac.New(c.newHash, macKey)
+	mac.Write(aad)
+	mac.Write(iv)
+	mac.Write(ciphertext)
+	mac.Write(al)
+
+	return mac.Sum(nil)[:c.keySize/2]
+}
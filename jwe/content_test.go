@@ -0,0 +1,98 @@
+package jwe
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/georgejenkins/jwt/jwa"
+)
+
+func mustHexDecode(arg string) []byte {
+	data, _ := hex.DecodeString(strings.Join(strings.Fields(arg), ""))
+	return data
+}
+
+// TestCBCHMAC_RFC7518 checks A128CBC-HS256 against the test case of
+// RFC 7518, appendix B.1.
+func TestCBCHMAC_RFC7518(t *testing.T) {
+	key := mustHexDecode(`00 01 02 03 04 05 06 07 08 09 0a 0b 0c 0d 0e 0f
+		10 11 12 13 14 15 16 17 18 19 1a 1b 1c 1d 1e 1f`)
+	plaintext := mustHexDecode(`41 20 63 69 70 68 65 72 20 73 79 73 74 65 6d 20
+		6d 75 73 74 20 6e 6f 74 20 62 65 20 72 65 71 75
+		69 72 65 64 20 74 6f 20 62 65 20 73 65 63 72 65
+		74 2c 20 61 6e 64 20 69 74 20 6d 75 73 74 20 62
+		65 20 61 62 6c 65 20 74 6f 20 66 61 6c 6c 20 69
+		6e 74 6f 20 74 68 65 20 68 61 6e 64 73 20 6f 66
+		20 74 68 65 20 65 6e 65 6d 79 20 77 69 74 68 6f
+		75 74 20 69 6e 63 6f 6e 76 65 6e 69 65 6e 63 65`)
+	iv := mustHexDecode(`1a f3 8c 2d c2 b9 6f fd d8 66 94 09 23 41 bc 04`)
+	aad := mustHexDecode(`54 68 65 20 73 65 63 6f 6e 64 20 70 72 69 6e 63
+		69 70 6c 65 20 6f 66 20 41 75 67 75 73 74 65 20
+		4b 65 72 63 6b 68 6f 66 66 73`)
+	wantTag := mustHexDecode(`65 2c 3f a3 6b 0a 7c 5b 32 19 fa b3 a3 0b c1 c4`)
+
+	ce, _ := GetContentEncrypter(jwa.A128CBCHS256)
+	ciphertext, tag, err := ce.Encrypt(key, iv, plaintext, aad)
+	if nil != err {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	if !bytes.Equal(tag, wantTag) {
+		t.Errorf("Encrypt() tag = %x, want %x", tag, wantTag)
+	}
+
+	got, err := ce.Decrypt(key, iv, ciphertext, tag, aad)
+	if nil != err || !bytes.Equal(got, plaintext) {
+		t.Errorf("Decrypt() = %s, %v", got, err)
+	}
+}
+
+func TestContentEncrypter(t *testing.T) {
+	plaintext := []byte("The Blue Stripes will ambush Radovid on the bridge to Temple Isle")
+	aad := []byte("eyJhbGciOiJkaXIifQ")
+
+	for _, enc := range []jwa.ContentEncryptionAlgorithm{
+		jwa.A128CBCHS256, jwa.A192CBCHS384, jwa.A256CBCHS512, jwa.A128GCM, jwa.A192GCM, jwa.A256GCM,
+	} {
+		t.Run("Must round trip "+string(enc), func(t *testing.T) {
+			ce, err := GetContentEncrypter(enc)
+			if nil != err {
+				t.Fatalf("GetContentEncrypter() error = %v", err)
+			}
+			key := bytes.Repeat([]byte{0x17}, ce.KeySize())
+			iv := bytes.Repeat([]byte{0x2a}, ce.IVSize())
+
+			for _, p := range [][]byte{plaintext, plaintext[:16], {}} {
+				ciphertext, tag, err := ce.Encrypt(key, iv, p, aad)
+				if nil != err {
+					t.Fatalf("Encrypt() error = %v", err)
+				}
+				got, err := ce.Decrypt(key, iv, ciphertext, tag, aad)
+				if nil != err || !bytes.Equal(got, p) {
+					t.Errorf("Decrypt() = %s, %v, want %s", got, err, p)
+				}
+			}
+
+			ciphertext, tag, _ := ce.Encrypt(key, iv, plaintext, aad)
+			tampered := append([]byte{}, ciphertext...)
+			tampered[0] ^= 1
+			if _, err := ce.Decrypt(key, iv, tampered, tag, aad); nil == err {
+				t.Errorf("Decrypt() expected error for a modified ciphertext")
+			}
+			if _, err := ce.Decrypt(key, iv, ciphertext, tag, []byte("eyJhbGciOiJSU0EtT0FFUCJ9")); nil == err {
+				t.Errorf("Decrypt() expected error for modified additional data")
+			}
+			if _, err := ce.Decrypt(key, iv, ciphertext, tag[:len(tag)-1], aad); nil == err {
+				t.Errorf("Decrypt() expected error for a truncated tag")
+			}
+			if _, _, err := ce.Encrypt(key[1:], iv, plaintext, aad); nil == err {
+				t.Errorf("Encrypt() expected error for a short key")
+			}
+		})
+	}
+
+	if _, err := GetContentEncrypter("A64GCM"); nil == err {
+		t.Errorf("GetContentEncrypter() expected error for an unknown algorithm")
+	}
+}
@@ -0,0 +1,10 @@
+package jwe
+
+import "github.com/georgejenkins/jwt/jwa"
+
+// KeyDecrypter recovers the content encryption key (CEK) of cekSize bytes
+// of a JWE from its header and JWE Encrypted Key.
+type KeyDecrypter interface {
+	Algorithm() jwa.KeyManagementAlgorithm
+	DecryptKey(header Header, encryptedKey []byte, cekSize int) (cek []byte, err error)
+}
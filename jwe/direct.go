@@ -0,0 +1,60 @@
+package jwe
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/georgejenkins/jwt/jwa"
+)
+
+// DirectKeyManager uses a shared symmetric key directly as the content
+// encryption key ("alg":"dir"). It has no JWE Encrypted Key.
+type DirectKeyManager struct {
+	key []byte
+}
+
+// InitDirectKeyManager initializes a new direct key manager. The key is
+// copied, so the caller may clear its own copy.
+func InitDirectKeyManager(key []byte) (*DirectKeyManager, error) {
+	if len(key) == 0 {
+		return nil, errors.New("Cannot initialize DirectKeyManager with an empty key")
+	}
+
+	return &DirectKeyManager{key: append([]byte(nil), key...)}, nil
+}
+
+// Algorithm returns "dir".
+func (km *DirectKeyManager) Algorithm() jwa.KeyManagementAlgorithm {
+	return jwa.Direct
+}
+
+// EncryptKey returns the shared key as the CEK, with an empty encrypted key.
+func (km *DirectKeyManager) EncryptKey(header *Header, cekSize int) ([]byte, []byte, error) {
+	if err := km.checkSize(cekSize); nil != err {
+		return nil, nil, err
+	}
+
+	return km.key, nil, nil
+}
+
+// DecryptKey returns the shared key as the CEK. The encrypted key must be
+// empty.
+func (km *DirectKeyManager) DecryptKey(header Header, encryptedKey []byte, cekSize int) ([]byte, error) {
+	if len(encryptedKey) != 0 {
+		return nil, errors.New("Direct encryption JWEs must have an empty encrypted key")
+	}
+
+	if err := km.checkSize(cekSize); nil != err {
+		return nil, err
+	}
+
+	return km.key, nil
+}
+
+func (km *DirectKeyManager) checkSize(cekSize int) error {
+	if len(km.key) != cekSize {
+		return fmt.Errorf("Direct encryption requires a %d byte key, received %d bytes", cekSize, len(km.key))
+	}
+
+	return nil
+}
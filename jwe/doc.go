@@ -0,0 +1,5 @@
+// Package jwe encrypts and decrypts JSON Web Encryptions (RFC 7516) in the
+// compact serialization. Content encryption keys are determined by a
+// KeyEncrypter and recovered by a KeyDecrypter for the JWE's key
+// management algorithm.
+package jwe
@@ -0,0 +1,307 @@
+package jwe
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/georgejenkins/jwt/jwa"
+	"github.com/georgejenkins/jwt/jws"
+)
+
+// EphemeralKey is the 'epk' header of ECDH-ES: the sender's ephemeral
+// public key, as a JWK.
+type EphemeralKey struct {
+	KeyType string `json:"kty"`
+	Curve   string `json:"crv"`
+	X       string `json:"x"`
+	Y       string `json:"y,omitempty"`
+}
+
+// ecdhKeyWrapSizes are the AES key wrap KEK sizes of the ECDH-ES
+// algorithms, 0 for direct key agreement.
+var ecdhKeyWrapSizes = map[jwa.KeyManagementAlgorithm]int{
+	jwa.ECDHES:       0,
+	jwa.ECDHESA128KW: 16,
+	jwa.ECDHESA192KW: 24,
+	jwa.ECDHESA256KW: 32,
+}
+
+// ecdhPublicKey is a recipient public key for ECDH-ES.
+type ecdhPublicKey interface {
+	// agree generates an ephemeral key on the recipient's curve, returning
+	// it and the shared secret.
+	agree() (*EphemeralKey, []byte, error)
+}
+
+// ecdhPrivateKey is a recipient private key for ECDH-ES.
+type ecdhPrivateKey interface {
+	// agree returns the shared secret with the ephemeral key.
+	agree(epk *EphemeralKey) ([]byte, error)
+}
+
+// ECDHKeyEncrypter agrees a key with a recipient's public key using
+// ephemeral-static ECDH (RFC 7518, section 4.6). With ECDH-ES the agreed
+// key is the CEK; with ECDH-ES+A128KW, +A192KW and +A256KW it wraps a
+// random CEK. The 'apu' and 'apv' headers, if set, are included in the
+// key derivation.
+type ECDHKeyEncrypter struct {
+	algorithm jwa.KeyManagementAlgorithm
+	recipient ecdhPublicKey
+}
+
+// InitECDHKeyEncrypter initializes a new ECDH-ES key encrypter. P-256,
+// P-384 and P-521 keys are *ecdsa.PublicKey; X25519 keys, which require
+// Go 1.20, are *ecdh.PublicKey.
+func InitECDHKeyEncrypter(alg jwa.KeyManagementAlgorithm, key interface{}) (*ECDHKeyEncrypter, error) {
+	if _, ok := ecdhKeyWrapSizes[alg]; !ok {
+		return nil, errors.New("Key management algorithm unexpected, must be one of: ECDH-ES, ECDH-ES+A128KW, ECDH-ES+A192KW, ECDH-ES+A256KW")
+	}
+
+	var recipient ecdhPublicKey
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		if nil == k || !supportedCurve(k.Curve) {
+			return nil, errors.New("ECDH-ES requires a P-256, P-384 or P-521 key")
+		}
+		recipient = nistPublicKey{key: k}
+	default:
+		x25519, ok := newX25519PublicKey(key)
+		if !ok {
+			return nil, fmt.Errorf("Cannot use key type %T for JWE alg %s", key, alg)
+		}
+		recipient = x25519
+	}
+
+	return &ECDHKeyEncrypter{algorithm: alg, recipient: recipient}, nil
+}
+
+// Algorithm returns the key management algorithm.
+func (ke *ECDHKeyEncrypter) Algorithm() jwa.KeyManagementAlgorithm {
+	return ke.algorithm
+}
+
+// EncryptKey agrees a key with the recipient, setting the 'epk' header to
+// the ephemeral public key.
+func (ke *ECDHKeyEncrypter) EncryptKey(header *Header, cekSize int) ([]byte, []byte, error) {
+	apu, apv, err := partyInfo(*header)
+	if nil != err {
+		return nil, nil, err
+	}
+
+	epk, z, err := ke.recipient.agree()
+	if nil != err {
+		return nil, nil, err
+	}
+	header.EphemeralKey = epk
+
+	kekSize := ecdhKeyWrapSizes[ke.algorithm]
+	if kekSize == 0 {
+		return concatKDF(z, string(header.Encryption), apu, apv, cekSize), nil, nil
+	}
+
+	cek := make([]byte, cekSize)
+	if _, err := io.ReadFull(rng, cek); nil != err {
+		return nil, nil, err
+	}
+
+	encryptedKey, err := aesKeyWrap(concatKDF(z, string(ke.algorithm), apu, apv, kekSize), cek)
+	if nil != err {
+		return nil, nil, err
+	}
+
+	return cek, encryptedKey, nil
+}
+
+// ECDHKeyDecrypter recovers the CEK of ECDH-ES JWEs with the recipient's
+// private key, see ECDHKeyEncrypter.
+type ECDHKeyDecrypter struct {
+	algorithm jwa.KeyManagementAlgorithm
+	key       ecdhPrivateKey
+}
+
+// InitECDHKeyDecrypter initializes a new ECDH-ES key decrypter. P-256,
+// P-384 and P-521 keys are *ecdsa.PrivateKey; X25519 keys, which require
+// Go 1.20, are *ecdh.PrivateKey.
+func InitECDHKeyDecrypter(alg jwa.KeyManagementAlgorithm, key interface{}) (*ECDHKeyDecrypter, error) {
+	if _, ok := ecdhKeyWrapSizes[alg]; !ok {
+		return nil, errors.New("Key management algorithm unexpected, must be one of: ECDH-ES, ECDH-ES+A128KW, ECDH-ES+A192KW, ECDH-ES+A256KW")
+	}
+
+	var private ecdhPrivateKey
+	switch k := key.(type) {
+	case *ecdsa.PrivateKey:
+		if nil == k || !supportedCurve(k.Curve) {
+			return nil, errors.New("ECDH-ES requires a P-256, P-384 or P-521 key")
+		}
+		private = nistPrivateKey{key: k}
+	default:
+		x25519, ok := newX25519PrivateKey(key)
+		if !ok {
+			return nil, fmt.Errorf("Cannot use key type %T for JWE alg %s", key, alg)
+		}
+		private = x25519
+	}
+
+	return &ECDHKeyDecrypter{algorithm: alg, key: private}, nil
+}
+
+// Algorithm returns the key management algorithm.
+func (kd *ECDHKeyDecrypter) Algorithm() jwa.KeyManagementAlgorithm {
+	return kd.algorithm
+}
+
+// DecryptKey agrees the key with the 'epk' header's ephemeral key, and
+// unwraps the CEK with it for the key wrapping algorithms.
+func (kd *ECDHKeyDecrypter) DecryptKey(header Header, encryptedKey []byte, cekSize int) ([]byte, error) {
+	if nil == header.EphemeralKey {
+		return nil, errors.New("ECDH-ES JWEs require an 'epk' header")
+	}
+
+	apu, apv, err := partyInfo(header)
+	if nil != err {
+		return nil, err
+	}
+
+	z, err := kd.key.agree(header.EphemeralKey)
+	if nil != err {
+		return nil, err
+	}
+
+	kekSize := ecdhKeyWrapSizes[kd.algorithm]
+	if kekSize == 0 {
+		if len(encryptedKey) != 0 {
+			return nil, errors.New("ECDH-ES JWEs must have an empty encrypted key")
+		}
+		return concatKDF(z, string(header.Encryption), apu, apv, cekSize), nil
+	}
+
+	cek, err := aesKeyUnwrap(concatKDF(z, string(kd.algorithm), apu, apv, kekSize), encryptedKey)
+	if nil != err {
+		return nil, err
+	}
+	if len(cek) != cekSize {
+		return nil, fmt.Errorf("JWE enc %s requires a %d byte CEK, unwrapped %d bytes", header.Encryption, cekSize, len(cek))
+	}
+
+	return cek, nil
+}
+
+// concatKDF derives a key of size bytes from the shared secret z, using
+// the Concat KDF with SHA-256 (RFC 7518, section 4.6.2).
+func concatKDF(z []byte, algorithmID string, apu []byte, apv []byte, size int) []byte {
+	var otherInfo []byte
+	for _, field := range [][]byte{[]byte(algorithmID), apu, apv} {
+		otherInfo = appendUint32(otherInfo, uint32(len(field)))
+		otherInfo = append(otherInfo, field...)
+	}
+	otherInfo = appendUint32(otherInfo, uint32(size*8))
+
+	var key []byte
+	for counter := uint32(1); len(key) < size; counter++ {
+		h := sha256.New()
+		h.Write(appendUint32(nil, counter))
+		h.Write(z)
+		h.Write(otherInfo)
+		key = h.Sum(key)
+	}
+
+	return key[:size]
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var encoded [4]byte
+	binary.BigEndian.PutUint32(encoded[:], v)
+	return append(b, encoded[:]...)
+}
+
+// partyInfo decodes the 'apu' and 'apv' headers.
+func partyInfo(header Header) ([]byte, []byte, error) {
+	apu, err := jws.Base64URLDecode(header.PartyUInfo)
+	if nil != err {
+		return nil, nil, fmt.Errorf("JWE 'apu' header is not base64url encoded: %v", err)
+	}
+
+	apv, err := jws.Base64URLDecode(header.PartyVInfo)
+	if nil != err {
+		return nil, nil, fmt.Errorf("JWE 'apv' header is not base64url encoded: %v", err)
+	}
+
+	return apu, apv, nil
+}
+
+// supportedCurve reports whether the curve is P-256, P-384 or P-521.
+func supportedCurve(curve elliptic.Curve) bool {
+	return curve == elliptic.P256() || curve == elliptic.P384() || curve == elliptic.P521()
+}
+
+// nistPublicKey is a P-256, P-384 or P-521 recipient public key.
+type nistPublicKey struct {
+	key *ecdsa.PublicKey
+}
+
+func (pub nistPublicKey) agree() (*EphemeralKey, []byte, error) {
+	ephemeral, err := ecdsa.GenerateKey(pub.key.Curve, rng)
+	if nil != err {
+		return nil, nil, err
+	}
+
+	size := coordinateSize(pub.key.Curve)
+	x, _ := pub.key.Curve.ScalarMult(pub.key.X, pub.key.Y, ephemeral.D.Bytes())
+
+	return &EphemeralKey{
+		KeyType: "EC",
+		Curve:   pub.key.Curve.Params().Name,
+		X:       jws.Base64URLEncode(fixedBytes(ephemeral.X, size)),
+		Y:       jws.Base64URLEncode(fixedBytes(ephemeral.Y, size)),
+	}, fixedBytes(x, size), nil
+}
+
+// nistPrivateKey is a P-256, P-384 or P-521 recipient private key.
+type nistPrivateKey struct {
+	key *ecdsa.PrivateKey
+}
+
+func (prv nistPrivateKey) agree(epk *EphemeralKey) ([]byte, error) {
+	curve := prv.key.Curve
+	if epk.KeyType != "EC" || epk.Curve != curve.Params().Name {
+		return nil, fmt.Errorf("JWE 'epk' must be an EC key on %s", curve.Params().Name)
+	}
+
+	size := coordinateSize(curve)
+	x, errX := jws.Base64URLDecode(epk.X)
+	y, errY := jws.Base64URLDecode(epk.Y)
+	if nil != errX || nil != errY || len(x) != size || len(y) != size {
+		return nil, errors.New("JWE 'epk' coordinates are invalid")
+	}
+
+	// Points off the curve would leak the private key (invalid curve
+	// attacks), so must be rejected.
+	epkX, epkY := new(big.Int).SetBytes(x), new(big.Int).SetBytes(y)
+	if !curve.IsOnCurve(epkX, epkY) {
+		return nil, errors.New("JWE 'epk' is not on the curve")
+	}
+
+	z, _ := curve.ScalarMult(epkX, epkY, prv.key.D.Bytes())
+	return fixedBytes(z, size), nil
+}
+
+// coordinateSize returns the size of the curve's coordinates in bytes.
+func coordinateSize(curve elliptic.Curve) int {
+	return (curve.Params().BitSize + 7) / 8
+}
+
+// fixedBytes returns the integer as a big-endian byte slice, left padded
+// to size bytes.
+func fixedBytes(i *big.Int, size int) []byte {
+	data := make([]byte, size)
+	b := i.Bytes()
+	copy(data[size-len(b):], b)
+
+	return data
+}
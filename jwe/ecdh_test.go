@@ -0,0 +1,126 @@
+package jwe
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"math/big"
+	"testing"
+
+	"github.com/georgejenkins/jwt/jwa"
+	"github.com/georgejenkins/jwt/jws"
+)
+
+func mustDecodeBigInt(s string) *big.Int {
+	data, _ := jws.Base64URLDecode(s)
+	return new(big.Int).SetBytes(data)
+}
+
+// TestECDHKeyDecrypter_RFC7518 checks ECDH-ES key agreement and the Concat
+// KDF against the example of RFC 7518, appendix C.
+func TestECDHKeyDecrypter_RFC7518(t *testing.T) {
+	bob := &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     mustDecodeBigInt("weNJy2HscCSM6AEDTDg04biOvhFhyyWvOHQfeF_PxMQ"),
+			Y:     mustDecodeBigInt("e8lnCO-AlStT-NJVX-crhB7QRYhiix03illJOVAOyck"),
+		},
+		D: mustDecodeBigInt("VEmDZpDXXK8p8N0Cndsxs924q6nS1RXFASRl6BfUqdw"),
+	}
+	header := Header{
+		Algorithm:  jwa.ECDHES,
+		Encryption: jwa.A128GCM,
+		EphemeralKey: &EphemeralKey{
+			KeyType: "EC",
+			Curve:   "P-256",
+			X:       "gI0GAILBdu7T53akrFmMyGcsF3n5dO7MmwNBHKW5SV0",
+			Y:       "SLW_xSffzlPWrHEVI30DHM_4egVwt3NQqeUD7nMFpps",
+		},
+		PartyUInfo: "QWxpY2U",
+		PartyVInfo: "Qm9i",
+	}
+
+	kd, err := InitECDHKeyDecrypter(jwa.ECDHES, bob)
+	if nil != err {
+		t.Fatalf("InitECDHKeyDecrypter() error = %v", err)
+	}
+
+	cek, err := kd.DecryptKey(header, nil, 16)
+	if got := jws.Base64URLEncode(cek); nil != err || got != "VqqN6vgjbSBcIijNcacQGg" {
+		t.Errorf("ECDHKeyDecrypter.DecryptKey() = %s, %v, want VqqN6vgjbSBcIijNcacQGg", got, err)
+	}
+
+	header.EphemeralKey.Y = "SLW_xSffzlPWrHEVI30DHM_4egVwt3NQqeUD7nMFppw"
+	if _, err := kd.DecryptKey(header, nil, 16); nil == err {
+		t.Errorf("ECDHKeyDecrypter.DecryptKey() expected error for an epk off the curve")
+	}
+}
+
+func TestECDHKeyEncrypter_ECDHKeyDecrypter(t *testing.T) {
+	p256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	p384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	p521, _ := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	otherP256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	plaintext := []byte("The Blue Stripes will ambush Radovid on the bridge to Temple Isle")
+
+	tests := []struct {
+		name       string
+		alg        jwa.KeyManagementAlgorithm
+		enc        jwa.ContentEncryptionAlgorithm
+		public     interface{}
+		private    interface{}
+		header     Header
+		wantErr    bool
+		wantKeyLen int
+	}{
+		{"Must round trip ECDH-ES with P-256", jwa.ECDHES, jwa.A256GCM, &p256.PublicKey, p256, Header{}, false, 0},
+		{"Must round trip ECDH-ES with P-521 and party info", jwa.ECDHES, jwa.A256CBCHS512, &p521.PublicKey, p521, Header{PartyUInfo: "QWxpY2U", PartyVInfo: "Qm9i"}, false, 0},
+		{"Must round trip ECDH-ES+A128KW with P-256", jwa.ECDHESA128KW, jwa.A128GCM, &p256.PublicKey, p256, Header{}, false, 24},
+		{"Must round trip ECDH-ES+A192KW with P-384", jwa.ECDHESA192KW, jwa.A192CBCHS384, &p384.PublicKey, p384, Header{}, false, 56},
+		{"Must round trip ECDH-ES+A256KW with P-521", jwa.ECDHESA256KW, jwa.A256GCM, &p521.PublicKey, p521, Header{}, false, 40},
+		{"Must not decrypt ECDH-ES with another key", jwa.ECDHES, jwa.A256GCM, &p256.PublicKey, otherP256, Header{}, true, 0},
+		{"Must not decrypt ECDH-ES+A256KW with another key", jwa.ECDHESA256KW, jwa.A256GCM, &p256.PublicKey, otherP256, Header{}, true, 40},
+		{"Must not decrypt an epk on another curve", jwa.ECDHES, jwa.A256GCM, &p384.PublicKey, p256, Header{}, true, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ke, err := InitECDHKeyEncrypter(tt.alg, tt.public)
+			if nil != err {
+				t.Fatalf("InitECDHKeyEncrypter() error = %v", err)
+			}
+			kd, err := InitECDHKeyDecrypter(tt.alg, tt.private)
+			if nil != err {
+				t.Fatalf("InitECDHKeyDecrypter() error = %v", err)
+			}
+
+			header := tt.header
+			header.Encryption = tt.enc
+			compact, err := Encrypt(plaintext, header, ke)
+			if nil != err {
+				t.Fatalf("Encrypt() error = %v", err)
+			}
+
+			parts, _ := Parse(compact)
+			if nil == parts.Header.EphemeralKey || parts.Header.PartyUInfo != tt.header.PartyUInfo || len(parts.EncryptedKey) != tt.wantKeyLen {
+				t.Errorf("Encrypt() header = %+v, encrypted key = %x", parts.Header, parts.EncryptedKey)
+			}
+
+			got, err := Decrypt(compact, kd)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Decrypt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !bytes.Equal(got, plaintext) {
+				t.Errorf("Decrypt() = %s, want %s", got, plaintext)
+			}
+		})
+	}
+
+	p224, _ := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	if _, err := InitECDHKeyEncrypter(jwa.ECDHES, &p224.PublicKey); nil == err {
+		t.Errorf("InitECDHKeyEncrypter() expected error for a P-224 key")
+	}
+	if _, err := InitECDHKeyDecrypter(jwa.RSAOAEP, p256); nil == err {
+		t.Errorf("InitECDHKeyDecrypter() expected error for RSA-OAEP")
+	}
+}
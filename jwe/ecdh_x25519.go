@@ -0,0 +1,80 @@
+//go:build go1.20
+// +build go1.20
+
+package jwe
+
+import (
+	"crypto/ecdh"
+	"errors"
+
+	"github.com/georgejenkins/jwt/jws"
+)
+
+// x25519PublicKey is an X25519 recipient public key.
+type x25519PublicKey struct {
+	key *ecdh.PublicKey
+}
+
+// newX25519PublicKey returns the key as an X25519 recipient public key,
+// if it is one.
+func newX25519PublicKey(key interface{}) (ecdhPublicKey, bool) {
+	pub, ok := key.(*ecdh.PublicKey)
+	if !ok || nil == pub || pub.Curve() != ecdh.X25519() {
+		return nil, false
+	}
+
+	return x25519PublicKey{key: pub}, true
+}
+
+func (pub x25519PublicKey) agree() (*EphemeralKey, []byte, error) {
+	ephemeral, err := ecdh.X25519().GenerateKey(rng)
+	if nil != err {
+		return nil, nil, err
+	}
+
+	z, err := ephemeral.ECDH(pub.key)
+	if nil != err {
+		return nil, nil, err
+	}
+
+	return &EphemeralKey{
+		KeyType: "OKP",
+		Curve:   "X25519",
+		X:       jws.Base64URLEncode(ephemeral.PublicKey().Bytes()),
+	}, z, nil
+}
+
+// x25519PrivateKey is an X25519 recipient private key.
+type x25519PrivateKey struct {
+	key *ecdh.PrivateKey
+}
+
+// newX25519PrivateKey returns the key as an X25519 recipient private key,
+// if it is one.
+func newX25519PrivateKey(key interface{}) (ecdhPrivateKey, bool) {
+	prv, ok := key.(*ecdh.PrivateKey)
+	if !ok || nil == prv || prv.Curve() != ecdh.X25519() {
+		return nil, false
+	}
+
+	return x25519PrivateKey{key: prv}, true
+}
+
+func (prv x25519PrivateKey) agree(epk *EphemeralKey) ([]byte, error) {
+	if epk.KeyType != "OKP" || epk.Curve != "X25519" {
+		return nil, errors.New("JWE 'epk' must be an OKP key on X25519")
+	}
+
+	x, err := jws.Base64URLDecode(epk.X)
+	if nil != err {
+		return nil, errors.New("JWE 'epk' coordinate is invalid")
+	}
+
+	pub, err := ecdh.X25519().NewPublicKey(x)
+	if nil != err {
+		return nil, err
+	}
+
+	// ECDH fails for low order points, whose shared secret is all zeros.
+	return prv.key.ECDH(pub)
+}
@@ -0,0 +1,14 @@
+//go:build !go1.20
+// +build !go1.20
+
+package jwe
+
+// X25519 keys are provided by crypto/ecdh, from Go 1.20.
+
+func newX25519PublicKey(key interface{}) (ecdhPublicKey, bool) {
+	return nil, false
+}
+
+func newX25519PrivateKey(key interface{}) (ecdhPrivateKey, bool) {
+	return nil, false
+}
@@ -0,0 +1,63 @@
+//go:build go1.20
+// +build go1.20
+
+package jwe
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"crypto/rand"
+	"testing"
+
+	"github.com/georgejenkins/jwt/jwa"
+)
+
+func TestECDHKeyEncrypter_X25519(t *testing.T) {
+	key, _ := ecdh.X25519().GenerateKey(rand.Reader)
+	otherKey, _ := ecdh.X25519().GenerateKey(rand.Reader)
+	p256, _ := ecdh.P256().GenerateKey(rand.Reader)
+	plaintext := []byte("The Blue Stripes will ambush Radovid on the bridge to Temple Isle")
+
+	if _, err := InitECDHKeyEncrypter(jwa.ECDHES, p256.PublicKey()); nil == err {
+		t.Errorf("InitECDHKeyEncrypter() expected error for an *ecdh.PublicKey on P-256")
+	}
+
+	tests := []struct {
+		name    string
+		alg     jwa.KeyManagementAlgorithm
+		private *ecdh.PrivateKey
+		wantErr bool
+	}{
+		{"Must round trip ECDH-ES", jwa.ECDHES, key, false},
+		{"Must round trip ECDH-ES+A128KW", jwa.ECDHESA128KW, key, false},
+		{"Must not decrypt with another key", jwa.ECDHESA128KW, otherKey, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ke, err := InitECDHKeyEncrypter(tt.alg, key.PublicKey())
+			if nil != err {
+				t.Fatalf("InitECDHKeyEncrypter() error = %v", err)
+			}
+			kd, err := InitECDHKeyDecrypter(tt.alg, tt.private)
+			if nil != err {
+				t.Fatalf("InitECDHKeyDecrypter() error = %v", err)
+			}
+
+			compact, err := Encrypt(plaintext, Header{Encryption: jwa.A256GCM}, ke)
+			if nil != err {
+				t.Fatalf("Encrypt() error = %v", err)
+			}
+			if parts, _ := Parse(compact); parts.Header.EphemeralKey.Curve != "X25519" || parts.Header.EphemeralKey.Y != "" {
+				t.Errorf("Encrypt() epk = %+v", parts.Header.EphemeralKey)
+			}
+
+			got, err := Decrypt(compact, kd)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Decrypt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !bytes.Equal(got, plaintext) {
+				t.Errorf("Decrypt() = %s, want %s", got, plaintext)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package jwe
+
+import "github.com/georgejenkins/jwt/jwa"
+
+// KeyEncrypter determines the content encryption key (CEK) of a JWE with
+// its key management algorithm, returning the CEK of cekSize bytes and the
+// JWE Encrypted Key. Algorithms that need header parameters, such as an
+// ephemeral public key, set them on the header before it is protected.
+type KeyEncrypter interface {
+	Algorithm() jwa.KeyManagementAlgorithm
+	EncryptKey(header *Header, cekSize int) (cek []byte, encryptedKey []byte, err error)
+}
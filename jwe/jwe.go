@@ -0,0 +1,205 @@
+package jwe
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/georgejenkins/jwt/jwa"
+	"github.com/georgejenkins/jwt/jws"
+)
+
+// Header is the JWE Protected Header.
+type Header struct {
+	// MUST be present
+	Algorithm  jwa.KeyManagementAlgorithm     `json:"alg"`
+	Encryption jwa.ContentEncryptionAlgorithm `json:"enc"`
+
+	KeyID string `json:"kid,omitempty"`
+
+	Type string `json:"typ,omitempty"`
+
+	ContentType string `json:"cty,omitempty"`
+
+	// Compression is the algorithm the plaintext is compressed with
+	// before encryption, if any.
+	Compression jwa.CompressionAlgorithm `json:"zip,omitempty"`
+
+	// EphemeralKey, PartyUInfo and PartyVInfo are the parameters of ECDH-ES
+	// key agreement. The base64url encoded party info is optional.
+	EphemeralKey *EphemeralKey `json:"epk,omitempty"`
+	PartyUInfo   string        `json:"apu,omitempty"`
+	PartyVInfo   string        `json:"apv,omitempty"`
+
+	// IV and Tag are the base64url encoded initialization vector and
+	// authentication tag of AES GCM key wrapping.
+	IV  string `json:"iv,omitempty"`
+	Tag string `json:"tag,omitempty"`
+}
+
+// Parts are the decoded parts of a compact JWE.
+type Parts struct {
+	Header Header
+
+	// RawHeader is the base64url encoded protected header, which is the
+	// additional authenticated data of the content encryption.
+	RawHeader []byte
+
+	EncryptedKey         []byte
+	InitializationVector []byte
+	Ciphertext           []byte
+	AuthenticationTag    []byte
+}
+
+// rng is the source of initialization vectors and content encryption keys.
+var rng io.Reader = rand.Reader
+
+// Encrypt encrypts the plaintext with the header's content encryption
+// algorithm, under a content encryption key determined by ke, returning
+// the compact JWE. The header's 'alg' is set to ke's algorithm. If the
+// header's 'zip' is set, the plaintext is compressed first.
+func Encrypt(plaintext []byte, header Header, ke KeyEncrypter) ([]byte, error) {
+	if nil == ke {
+		return nil, errors.New("JWE encryption requires a key encrypter")
+	}
+	header.Algorithm = ke.Algorithm()
+
+	plaintext, err := compress(header.Compression, plaintext)
+	if nil != err {
+		return nil, err
+	}
+
+	ce, err := GetContentEncrypter(header.Encryption)
+	if nil != err {
+		return nil, err
+	}
+
+	cek, encryptedKey, err := ke.EncryptKey(&header, ce.KeySize())
+	if nil != err {
+		return nil, err
+	}
+
+	protected, err := json.Marshal(header)
+	if nil != err {
+		return nil, err
+	}
+	rawHeader := jws.Base64URLEncode(protected)
+
+	iv := make([]byte, ce.IVSize())
+	if _, err := io.ReadFull(rng, iv); nil != err {
+		return nil, err
+	}
+
+	ciphertext, tag, err := ce.Encrypt(cek, iv, plaintext, []byte(rawHeader))
+	if nil != err {
+		return nil, err
+	}
+
+	return []byte(strings.Join([]string{
+		rawHeader,
+		jws.Base64URLEncode(encryptedKey),
+		jws.Base64URLEncode(iv),
+		jws.Base64URLEncode(ciphertext),
+		jws.Base64URLEncode(tag),
+	}, ".")), nil
+}
+
+// Decrypt decrypts a compact JWE, recovering its content encryption key
+// with kd. The JWE's 'alg' must be kd's algorithm. Compressed plaintexts
+// are decompressed up to DefaultMaxDecompressedSize bytes.
+func Decrypt(compact []byte, kd KeyDecrypter) ([]byte, error) {
+	return DecryptLimited(compact, kd, DefaultMaxDecompressedSize)
+}
+
+// DecryptLimited decrypts a compact JWE as Decrypt does, failing if its
+// plaintext decompresses to more than maxDecompressedSize bytes.
+func DecryptLimited(compact []byte, kd KeyDecrypter, maxDecompressedSize int) ([]byte, error) {
+	if nil == kd {
+		return nil, errors.New("JWE decryption requires a key decrypter")
+	}
+
+	parts, err := Parse(compact)
+	if nil != err {
+		return nil, err
+	}
+
+	if parts.Header.Algorithm != kd.Algorithm() {
+		return nil, fmt.Errorf("Expected JWE alg to be %s but received %q", kd.Algorithm(), parts.Header.Algorithm)
+	}
+
+	ce, err := GetContentEncrypter(parts.Header.Encryption)
+	if nil != err {
+		return nil, err
+	}
+
+	cek, err := kd.DecryptKey(parts.Header, parts.EncryptedKey, ce.KeySize())
+	if nil != err {
+		return nil, err
+	}
+
+	plaintext, err := ce.Decrypt(cek, parts.InitializationVector, parts.Ciphertext, parts.AuthenticationTag, parts.RawHeader)
+	if nil != err {
+		return nil, err
+	}
+
+	return decompress(parts.Header.Compression, plaintext, maxDecompressedSize)
+}
+
+// EncryptDirect encrypts the plaintext with A256GCM using key directly as
+// the content encryption key ("alg":"dir"), returning the compact JWE.
+// Header members other than 'alg' and 'enc' are taken from header.
+func EncryptDirect(plaintext []byte, key []byte, header Header) ([]byte, error) {
+	km, err := InitDirectKeyManager(key)
+	if nil != err {
+		return nil, err
+	}
+
+	header.Encryption = jwa.A256GCM
+	return Encrypt(plaintext, header, km)
+}
+
+// DecryptDirect decrypts a compact JWE encrypted with key as the content
+// encryption key ("alg":"dir").
+func DecryptDirect(compact []byte, key []byte) ([]byte, error) {
+	km, err := InitDirectKeyManager(key)
+	if nil != err {
+		return nil, err
+	}
+
+	return Decrypt(compact, km)
+}
+
+// Parse splits and decodes a compact JWE without decrypting it, so its
+// header can be inspected to select a key.
+func Parse(compact []byte) (*Parts, error) {
+	segments := strings.Split(string(compact), ".")
+	if len(segments) != 5 {
+		return nil, errors.New("Compact JWEs MUST have exactly five parts")
+	}
+
+	decoded := make([][]byte, len(segments))
+	for i, segment := range segments {
+		data, err := jws.Base64URLDecode(segment)
+		if nil != err {
+			return nil, err
+		}
+		decoded[i] = data
+	}
+
+	var header Header
+	if err := json.Unmarshal(decoded[0], &header); nil != err {
+		return nil, err
+	}
+
+	return &Parts{
+		Header:               header,
+		RawHeader:            []byte(segments[0]),
+		EncryptedKey:         decoded[1],
+		InitializationVector: decoded[2],
+		Ciphertext:           decoded[3],
+		AuthenticationTag:    decoded[4],
+	}, nil
+}
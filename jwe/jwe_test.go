@@ -0,0 +1,162 @@
+package jwe
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/georgejenkins/jwt/jwa"
+	"github.com/georgejenkins/jwt/jws"
+)
+
+var exampleKey = bytes.Repeat([]byte{0x17}, 32)
+
+func TestEncryptDirect_DecryptDirect(t *testing.T) {
+	plaintext := []byte("The Blue Stripes will ambush Radovid on the bridge to Temple Isle")
+
+	compact, err := EncryptDirect(plaintext, exampleKey, Header{KeyID: "data-key-1"})
+	if nil != err {
+		t.Fatalf("EncryptDirect() error = %v", err)
+	}
+
+	parts, err := Parse(compact)
+	if nil != err {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if parts.Header.Algorithm != "dir" || parts.Header.Encryption != "A256GCM" || parts.Header.KeyID != "data-key-1" {
+		t.Errorf("Parse() header = %+v", parts.Header)
+	}
+
+	got, err := DecryptDirect(compact, exampleKey)
+	if nil != err || !bytes.Equal(got, plaintext) {
+		t.Errorf("DecryptDirect() = %s, %v, want %s", got, err, plaintext)
+	}
+
+	segments := strings.Split(string(compact), ".")
+	tamperedHeader := jws.Base64URLEncode([]byte(`{"alg":"dir","enc":"A256GCM","kid":"data-key-2"}`))
+
+	tests := []struct {
+		name    string
+		compact string
+		key     []byte
+	}{
+		{"Must not decrypt with the wrong key", string(compact), bytes.Repeat([]byte{0x18}, 32)},
+		{"Must not decrypt with a short key", string(compact), exampleKey[:16]},
+		{"Must not decrypt a modified header", strings.Join(append([]string{tamperedHeader}, segments[1:]...), "."), exampleKey},
+		{"Must not decrypt a truncated tag", strings.Join(append(segments[:4:4], segments[4][:10]), "."), exampleKey},
+		{"Must not decrypt four parts", strings.Join(segments[:4], "."), exampleKey},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecryptDirect([]byte(tt.compact), tt.key); nil == err {
+				t.Errorf("DecryptDirect() expected error")
+			}
+		})
+	}
+}
+
+func TestInitDirectKeyManager(t *testing.T) {
+	key := append([]byte(nil), exampleKey...)
+	km, err := InitDirectKeyManager(key)
+	if nil != err {
+		t.Fatalf("InitDirectKeyManager() error = %v", err)
+	}
+
+	compact, err := Encrypt([]byte("session"), Header{Encryption: "A256GCM"}, km)
+	if nil != err {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	for i := range key {
+		key[i] = 0
+	}
+	if _, err := Decrypt(compact, km); nil != err {
+		t.Errorf("Decrypt() error = %v after the caller cleared its key", err)
+	}
+
+	if _, err := InitDirectKeyManager(nil); nil == err {
+		t.Errorf("InitDirectKeyManager() expected error with an empty key")
+	}
+}
+
+// stubKeyManager is a key manager for an algorithm this package doesn't
+// implement, wrapping the CEK by reversing it.
+type stubKeyManager struct{}
+
+func (stubKeyManager) Algorithm() jwa.KeyManagementAlgorithm {
+	return "stub"
+}
+
+func (stubKeyManager) EncryptKey(header *Header, cekSize int) ([]byte, []byte, error) {
+	header.KeyID = "stubbed"
+	cek := bytes.Repeat([]byte{0x2a}, cekSize)
+	return cek, reverse(cek), nil
+}
+
+func (stubKeyManager) DecryptKey(header Header, encryptedKey []byte, cekSize int) ([]byte, error) {
+	return reverse(encryptedKey), nil
+}
+
+func reverse(b []byte) []byte {
+	reversed := make([]byte, len(b))
+	for i := range b {
+		reversed[len(b)-1-i] = b[i]
+	}
+	return reversed
+}
+
+func TestEncrypt_Decrypt(t *testing.T) {
+	plaintext := []byte("Roche's men wait in the sewers under Oxenfurt")
+
+	compact, err := Encrypt(plaintext, Header{Encryption: jwa.A256GCM}, stubKeyManager{})
+	if nil != err {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+
+	parts, _ := Parse(compact)
+	if parts.Header.Algorithm != "stub" || parts.Header.KeyID != "stubbed" || len(parts.EncryptedKey) != 32 {
+		t.Errorf("Encrypt() header = %+v, encrypted key of %d bytes", parts.Header, len(parts.EncryptedKey))
+	}
+
+	got, err := Decrypt(compact, stubKeyManager{})
+	if nil != err || !bytes.Equal(got, plaintext) {
+		t.Errorf("Decrypt() = %s, %v, want %s", got, err, plaintext)
+	}
+
+	direct, _ := InitDirectKeyManager(exampleKey)
+	tests := []struct {
+		name    string
+		encrypt func() ([]byte, error)
+		decrypt func([]byte) ([]byte, error)
+	}{
+		{
+			"Must not decrypt with a key manager for another algorithm",
+			func() ([]byte, error) { return compact, nil },
+			func(c []byte) ([]byte, error) { return Decrypt(c, direct) },
+		},
+		{
+			"Must not encrypt with an unsupported content encryption algorithm",
+			func() ([]byte, error) { return Encrypt(plaintext, Header{Encryption: "A64GCM"}, direct) },
+			nil,
+		},
+		{
+			"Must not encrypt without a key encrypter",
+			func() ([]byte, error) { return Encrypt(plaintext, Header{Encryption: jwa.A256GCM}, nil) },
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := tt.encrypt()
+			if nil == tt.decrypt {
+				if nil == err {
+					t.Errorf("Encrypt() expected error")
+				}
+				return
+			}
+			if _, err := tt.decrypt(c); nil == err {
+				t.Errorf("Decrypt() expected error")
+			}
+		})
+	}
+}
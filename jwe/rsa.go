@@ -0,0 +1,122 @@
+package jwe
+
+import (
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/georgejenkins/jwt/jwa"
+)
+
+// minimumRSAKeySize is the smallest RSA key, in bits, RFC 7518 allows for
+// key encryption.
+const minimumRSAKeySize = 2048
+
+// RSAKeyEncrypter wraps a random content encryption key with an RSA
+// public key, using RSA-OAEP or RSA-OAEP-256.
+type RSAKeyEncrypter struct {
+	algorithm jwa.KeyManagementAlgorithm
+	pubKey    *rsa.PublicKey
+}
+
+// InitRSAKeyEncrypter initializes a new RSA-OAEP key encrypter.
+func InitRSAKeyEncrypter(alg jwa.KeyManagementAlgorithm, key *rsa.PublicKey) (*RSAKeyEncrypter, error) {
+	if nil == key {
+		return nil, errors.New("Cannot init RSAKeyEncrypter with empty key")
+	}
+
+	if err := validateRSAKey(alg, key); nil != err {
+		return nil, err
+	}
+
+	return &RSAKeyEncrypter{algorithm: alg, pubKey: key}, nil
+}
+
+// Algorithm returns the key management algorithm.
+func (ke *RSAKeyEncrypter) Algorithm() jwa.KeyManagementAlgorithm {
+	return ke.algorithm
+}
+
+// EncryptKey generates a random CEK and encrypts it to the public key.
+func (ke *RSAKeyEncrypter) EncryptKey(header *Header, cekSize int) ([]byte, []byte, error) {
+	cek := make([]byte, cekSize)
+	if _, err := io.ReadFull(rng, cek); nil != err {
+		return nil, nil, err
+	}
+
+	encryptedKey, err := rsa.EncryptOAEP(oaepHash(ke.algorithm), rng, ke.pubKey, cek, nil)
+	if nil != err {
+		return nil, nil, err
+	}
+
+	return cek, encryptedKey, nil
+}
+
+// RSAKeyDecrypter unwraps content encryption keys with an RSA private key,
+// using RSA-OAEP or RSA-OAEP-256.
+type RSAKeyDecrypter struct {
+	algorithm jwa.KeyManagementAlgorithm
+	prvKey    *rsa.PrivateKey
+}
+
+// InitRSAKeyDecrypter initializes a new RSA-OAEP key decrypter.
+func InitRSAKeyDecrypter(alg jwa.KeyManagementAlgorithm, key *rsa.PrivateKey) (*RSAKeyDecrypter, error) {
+	if nil == key {
+		return nil, errors.New("Cannot init RSAKeyDecrypter with empty key")
+	}
+
+	if err := validateRSAKey(alg, &key.PublicKey); nil != err {
+		return nil, err
+	}
+
+	return &RSAKeyDecrypter{algorithm: alg, prvKey: key}, nil
+}
+
+// Algorithm returns the key management algorithm.
+func (kd *RSAKeyDecrypter) Algorithm() jwa.KeyManagementAlgorithm {
+	return kd.algorithm
+}
+
+// DecryptKey decrypts the CEK with the private key. If decryption fails, a
+// random CEK is returned instead, so the failure only surfaces when the
+// content fails to decrypt, and can't be told apart from other failures
+// (RFC 7516, section 11.5).
+func (kd *RSAKeyDecrypter) DecryptKey(header Header, encryptedKey []byte, cekSize int) ([]byte, error) {
+	cek, err := rsa.DecryptOAEP(oaepHash(kd.algorithm), rng, kd.prvKey, encryptedKey, nil)
+	if nil != err || len(cek) != cekSize {
+		cek = make([]byte, cekSize)
+		if _, err := io.ReadFull(rng, cek); nil != err {
+			return nil, err
+		}
+	}
+
+	return cek, nil
+}
+
+// validateRSAKey validates the algorithm is RSA-OAEP or RSA-OAEP-256, and
+// the key is large enough.
+func validateRSAKey(alg jwa.KeyManagementAlgorithm, key *rsa.PublicKey) error {
+	if jwa.RSAOAEP != alg && jwa.RSAOAEP256 != alg {
+		return errors.New("Key management algorithm unexpected, must be one of: RSA-OAEP, RSA-OAEP-256")
+	}
+
+	if key.N.BitLen() < minimumRSAKeySize {
+		return fmt.Errorf("RSA key encryption requires a key of at least %d bits, received %d bits", minimumRSAKeySize, key.N.BitLen())
+	}
+
+	return nil
+}
+
+// oaepHash returns the OAEP hash of the algorithm: SHA-1 for RSA-OAEP and
+// SHA-256 for RSA-OAEP-256.
+func oaepHash(alg jwa.KeyManagementAlgorithm) hash.Hash {
+	if alg == jwa.RSAOAEP256 {
+		return sha256.New()
+	}
+
+	return sha1.New()
+}
@@ -0,0 +1,82 @@
+package jwe
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/georgejenkins/jwt/jwa"
+)
+
+func TestRSAKeyEncrypter_RSAKeyDecrypter(t *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	otherKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	shortKey, _ := rsa.GenerateKey(rand.Reader, 1024)
+	plaintext := []byte("The Blue Stripes will ambush Radovid on the bridge to Temple Isle")
+
+	if _, err := InitRSAKeyEncrypter(jwa.RSAOAEP, &shortKey.PublicKey); nil == err {
+		t.Errorf("InitRSAKeyEncrypter() expected error for a 1024 bit key")
+	}
+	if _, err := InitRSAKeyDecrypter(jwa.Direct, key); nil == err {
+		t.Errorf("InitRSAKeyDecrypter() expected error for dir")
+	}
+
+	tests := []struct {
+		name       string
+		alg        jwa.KeyManagementAlgorithm
+		enc        jwa.ContentEncryptionAlgorithm
+		decryptKey *rsa.PrivateKey
+		wantErr    bool
+	}{
+		{"Must round trip RSA-OAEP", jwa.RSAOAEP, jwa.A256GCM, key, false},
+		{"Must round trip RSA-OAEP-256", jwa.RSAOAEP256, jwa.A128CBCHS256, key, false},
+		{"Must not decrypt with another key", jwa.RSAOAEP, jwa.A256GCM, otherKey, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ke, err := InitRSAKeyEncrypter(tt.alg, &key.PublicKey)
+			if nil != err {
+				t.Fatalf("InitRSAKeyEncrypter() error = %v", err)
+			}
+			kd, err := InitRSAKeyDecrypter(tt.alg, tt.decryptKey)
+			if nil != err {
+				t.Fatalf("InitRSAKeyDecrypter() error = %v", err)
+			}
+
+			compact, err := Encrypt(plaintext, Header{Encryption: tt.enc}, ke)
+			if nil != err {
+				t.Fatalf("Encrypt() error = %v", err)
+			}
+
+			got, err := Decrypt(compact, kd)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Decrypt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !bytes.Equal(got, plaintext) {
+				t.Errorf("Decrypt() = %s, want %s", got, plaintext)
+			}
+		})
+	}
+}
+
+func TestRSAKeyDecrypter_DecryptKey(t *testing.T) {
+	key, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ke, _ := InitRSAKeyEncrypter(jwa.RSAOAEP256, &key.PublicKey)
+	kd, _ := InitRSAKeyDecrypter(jwa.RSAOAEP, key)
+
+	cek, encryptedKey, err := ke.EncryptKey(&Header{}, 32)
+	if nil != err {
+		t.Fatalf("RSAKeyEncrypter.EncryptKey() error = %v", err)
+	}
+
+	// A CEK wrapped with another hash must not be reported as an error, but
+	// replaced with a random CEK, failing later as any other bad token.
+	got, err := kd.DecryptKey(Header{}, encryptedKey, 32)
+	if nil != err {
+		t.Fatalf("RSAKeyDecrypter.DecryptKey() error = %v", err)
+	}
+	if len(got) != 32 || bytes.Equal(got, cek) {
+		t.Errorf("RSAKeyDecrypter.DecryptKey() = %x, want a random 32 byte CEK", got)
+	}
+}
@@ -0,0 +1,62 @@
+package jwe
+
+import (
+	"bytes"
+	"compress/flate"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/georgejenkins/jwt/jwa"
+)
+
+// DefaultMaxDecompressedSize is the size in bytes compressed plaintexts
+// may decompress to by default, ample for claim sets while bounding the
+// memory a decompression bomb can claim.
+const DefaultMaxDecompressedSize = 256 << 10
+
+// compress compresses the plaintext with the 'zip' algorithm, if any.
+func compress(zip jwa.CompressionAlgorithm, plaintext []byte) ([]byte, error) {
+	switch zip {
+	case "":
+		return plaintext, nil
+	case jwa.Deflate:
+		var compressed bytes.Buffer
+		w, err := flate.NewWriter(&compressed, flate.BestCompression)
+		if nil != err {
+			return nil, err
+		}
+		if _, err := w.Write(plaintext); nil != err {
+			return nil, err
+		}
+		if err := w.Close(); nil != err {
+			return nil, err
+		}
+		return compressed.Bytes(), nil
+	}
+
+	return nil, fmt.Errorf("Unsupported JWE compression algorithm %q", zip)
+}
+
+// decompress decompresses the plaintext with the 'zip' algorithm, if any,
+// failing if it decompresses to more than maxSize bytes.
+func decompress(zip jwa.CompressionAlgorithm, plaintext []byte, maxSize int) ([]byte, error) {
+	switch zip {
+	case "":
+		return plaintext, nil
+	case jwa.Deflate:
+		r := flate.NewReader(bytes.NewReader(plaintext))
+		defer r.Close()
+
+		decompressed, err := ioutil.ReadAll(io.LimitReader(r, int64(maxSize)+1))
+		if nil != err {
+			return nil, err
+		}
+		if len(decompressed) > maxSize {
+			return nil, fmt.Errorf("JWE plaintext decompresses to more than %d bytes", maxSize)
+		}
+		return decompressed, nil
+	}
+
+	return nil, fmt.Errorf("Unsupported JWE compression algorithm %q", zip)
+}
@@ -0,0 +1,52 @@
+package jwe
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/georgejenkins/jwt/jwa"
+)
+
+func TestEncrypt_Decrypt_Deflate(t *testing.T) {
+	km, _ := InitDirectKeyManager(exampleKey)
+	plaintext := bytes.Repeat([]byte(`{"role":"admin"},`), 100)
+
+	compressed, err := Encrypt(plaintext, Header{Encryption: jwa.A256GCM, Compression: jwa.Deflate}, km)
+	if nil != err {
+		t.Fatalf("Encrypt() error = %v", err)
+	}
+	uncompressed, _ := Encrypt(plaintext, Header{Encryption: jwa.A256GCM}, km)
+	if len(compressed) >= len(uncompressed) {
+		t.Errorf("Encrypt() compressed JWE of %d bytes, uncompressed %d bytes", len(compressed), len(uncompressed))
+	}
+
+	bomb, _ := Encrypt(make([]byte, DefaultMaxDecompressedSize+1), Header{Encryption: jwa.A256GCM, Compression: jwa.Deflate}, km)
+
+	tests := []struct {
+		name    string
+		compact []byte
+		maxSize int
+		want    []byte
+		wantErr bool
+	}{
+		{"Must decompress a compressed plaintext", compressed, DefaultMaxDecompressedSize, plaintext, false},
+		{"Must decompress a plaintext of exactly the maximum size", compressed, len(plaintext), plaintext, false},
+		{"Must not decompress a plaintext over the maximum size", compressed, len(plaintext) - 1, nil, true},
+		{"Must not decompress a decompression bomb", bomb, DefaultMaxDecompressedSize, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecryptLimited(tt.compact, km, tt.maxSize)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecryptLimited() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !bytes.Equal(got, tt.want) {
+				t.Errorf("DecryptLimited() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Encrypt(plaintext, Header{Encryption: jwa.A256GCM, Compression: "GZIP"}, km); nil == err {
+		t.Errorf("Encrypt() expected error with an unsupported compression algorithm")
+	}
+}
@@ -0,0 +1,201 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/georgejenkins/jwt/jwe"
+)
+
+// JWEKeyring holds the key encryption keys (KEKs) of a JWE consumer
+// through their rotation, so KEKs can be rotated without invalidating
+// outstanding encrypted tokens. Tokens are encrypted under the current
+// KEK, the one added last, with its key ID in the 'kid' header. They are
+// decrypted with the KEK of their key ID or, if they have none, with the
+// current then the previous KEKs in turn.
+//
+// KEKs may be symmetric or asymmetric, in any mix, each configured as a
+// JWEEncrypterDecrypter. Previous KEKs only need to decrypt; Reencrypt
+// moves tokens under the current KEK before a previous one is removed.
+type JWEKeyring struct {
+	mu   sync.RWMutex
+	keks []jweKeyringEntry
+}
+
+// jweKeyringEntry is a KEK with its key ID.
+type jweKeyringEntry struct {
+	kid string
+	ed  *JWEEncrypterDecrypter
+}
+
+// NewJWEKeyring creates an empty JWEKeyring.
+func NewJWEKeyring() *JWEKeyring {
+	return &JWEKeyring{}
+}
+
+// Add adds a KEK with a unique key ID, making it the current KEK. The
+// current KEK must be able to encrypt.
+func (kr *JWEKeyring) Add(kid string, ed *JWEEncrypterDecrypter) error {
+	if kid == "" {
+		return errors.New("JWEKeyring keys require a key ID")
+	}
+	if nil == ed || nil == ed.encrypter {
+		return fmt.Errorf("Key %q cannot encrypt, so cannot be the current key", kid)
+	}
+
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+
+	for _, entry := range kr.keks {
+		if entry.kid == kid {
+			return fmt.Errorf("JWEKeyring already holds key %q", kid)
+		}
+	}
+
+	// Entries are read outside the lock, so the slice is replaced, not
+	// modified.
+	keks := make([]jweKeyringEntry, 0, len(kr.keks)+1)
+	keks = append(keks, jweKeyringEntry{kid: kid, ed: ed})
+	kr.keks = append(keks, kr.keks...)
+	return nil
+}
+
+// Remove removes a previous KEK. Tokens encrypted under it can no longer be
+// decrypted. The current KEK can't be removed.
+func (kr *JWEKeyring) Remove(kid string) error {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+
+	for i, entry := range kr.keks {
+		if entry.kid != kid {
+			continue
+		}
+		if i == 0 {
+			return fmt.Errorf("Key %q is the current key", kid)
+		}
+
+		keks := make([]jweKeyringEntry, 0, len(kr.keks)-1)
+		keks = append(keks, kr.keks[:i]...)
+		kr.keks = append(keks, kr.keks[i+1:]...)
+		return nil
+	}
+
+	return ErrUnknownKeyID
+}
+
+// CurrentKeyID returns the key ID of the current KEK, or "" if the
+// JWEKeyring is empty.
+func (kr *JWEKeyring) CurrentKeyID() string {
+	keks := kr.entries()
+	if len(keks) == 0 {
+		return ""
+	}
+
+	return keks[0].kid
+}
+
+// Encrypt encrypts the plaintext under the current KEK, setting the
+// header's key ID, see JWEEncrypterDecrypter.Encrypt.
+func (kr *JWEKeyring) Encrypt(header JWEHeader, plaintext []byte) ([]byte, error) {
+	keks := kr.entries()
+	if len(keks) == 0 {
+		return nil, errors.New("JWEKeyring has no keys")
+	}
+
+	header.KeyID = keks[0].kid
+	return keks[0].ed.Encrypt(header, plaintext)
+}
+
+// GenerateToken generates a token encrypted under the current KEK, see
+// JWEEncrypterDecrypter.GenerateToken.
+func (kr *JWEKeyring) GenerateToken(header JWEHeader, body interface{}) ([]byte, error) {
+	keks := kr.entries()
+	if len(keks) == 0 {
+		return nil, errors.New("JWEKeyring has no keys")
+	}
+
+	header.KeyID = keks[0].kid
+	return keks[0].ed.GenerateToken(header, body)
+}
+
+// Decrypt decrypts a compact JWE with the KEK of its key ID, returning
+// ErrUnknownKeyID if there is none. Tokens without a key ID are decrypted
+// with the current then the previous KEKs of their 'alg' in turn.
+func (kr *JWEKeyring) Decrypt(rawToken []byte) (*EncryptedToken, error) {
+	_, token, err := kr.decrypt(rawToken)
+	return token, err
+}
+
+// DecryptToken decrypts the token as Decrypt does, and validates its
+// registered claims against the criteria, see
+// JWEEncrypterDecrypter.DecryptToken.
+func (kr *JWEKeyring) DecryptToken(rawToken []byte, validationCriteria *ValidationClaims) (*EncryptedToken, bool, error) {
+	token, err := kr.Decrypt(rawToken)
+	if nil != err {
+		return nil, false, err
+	}
+
+	return validateEncryptedToken(token, validationCriteria)
+}
+
+// Reencrypt decrypts a token and encrypts its plaintext under the current
+// KEK, with the current KEK's default content encryption algorithm. The
+// header's other parameters are kept. Tokens already encrypted under the
+// current KEK are returned unchanged.
+func (kr *JWEKeyring) Reencrypt(rawToken []byte) ([]byte, error) {
+	kid, token, err := kr.decrypt(rawToken)
+	if nil != err {
+		return nil, err
+	}
+	if kid == kr.CurrentKeyID() && token.Header.KeyID == kid {
+		return rawToken, nil
+	}
+
+	header := token.Header
+	header.Encryption = ""
+	return kr.Encrypt(header, token.Plaintext)
+}
+
+// decrypt decrypts a compact JWE, returning the key ID of the KEK that
+// decrypted it.
+func (kr *JWEKeyring) decrypt(rawToken []byte) (string, *EncryptedToken, error) {
+	parts, err := jwe.Parse(rawToken)
+	if nil != err {
+		return "", nil, err
+	}
+
+	keks := kr.entries()
+	if parts.Header.KeyID != "" {
+		for _, entry := range keks {
+			if entry.kid == parts.Header.KeyID {
+				token, err := entry.ed.Decrypt(rawToken)
+				return entry.kid, token, err
+			}
+		}
+		return "", nil, ErrUnknownKeyID
+	}
+
+	err = ErrUnknownKeyID
+	for _, entry := range keks {
+		if entry.ed.algorithm != parts.Header.Algorithm || nil == entry.ed.decrypter {
+			continue
+		}
+
+		var token *EncryptedToken
+		token, err = entry.ed.Decrypt(rawToken)
+		if nil == err {
+			return entry.kid, token, nil
+		}
+	}
+
+	return "", nil, err
+}
+
+// entries returns the KEKs, current first.
+func (kr *JWEKeyring) entries() []jweKeyringEntry {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+
+	return kr.keks
+}
@@ -0,0 +1,88 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+)
+
+func TestJWEKeyring(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	previous, _ := NewJWEEncrypterDecrypter(Direct, A256GCM, bytes.Repeat([]byte{1}, 32))
+	current, _ := NewJWEEncrypterDecrypter(RSAOAEP256, A128CBCHS256, rsaKey)
+	unknown, _ := NewJWEEncrypterDecrypter(Direct, A256GCM, bytes.Repeat([]byte{2}, 32))
+
+	kr := NewJWEKeyring()
+	if err := kr.Add("2020", previous); nil != err {
+		t.Fatalf("JWEKeyring.Add() error = %v", err)
+	}
+	previousToken, _ := kr.GenerateToken(JWEHeader{Type: "JWT"}, Claims{Subject: "alice", Issuer: "issuer"})
+	legacyToken, _ := previous.GenerateToken(JWEHeader{}, Claims{Subject: "alice", Issuer: "issuer"})
+
+	if err := kr.Add("2021", current); nil != err {
+		t.Fatalf("JWEKeyring.Add() error = %v", err)
+	}
+	currentToken, _ := kr.GenerateToken(JWEHeader{Type: "JWT"}, Claims{Subject: "alice", Issuer: "issuer"})
+	unknownKeyToken, _ := unknown.GenerateToken(JWEHeader{KeyID: "2019"}, Claims{Subject: "alice", Issuer: "issuer"})
+	unknownToken, _ := unknown.GenerateToken(JWEHeader{}, Claims{Subject: "alice", Issuer: "issuer"})
+
+	if kid := kr.CurrentKeyID(); kid != "2021" {
+		t.Errorf("JWEKeyring.CurrentKeyID() = %q, want 2021", kid)
+	}
+	if err := kr.Add("2021", current); nil == err {
+		t.Errorf("JWEKeyring.Add() expected error for a duplicate key ID")
+	}
+	if err := kr.Remove("2021"); nil == err {
+		t.Errorf("JWEKeyring.Remove() expected error for the current key")
+	}
+
+	criteria := &ValidationClaims{Issuer: []string{"issuer"}, Subject: []string{"alice"}}
+	tests := []struct {
+		name    string
+		token   []byte
+		wantKID string
+		wantErr bool
+	}{
+		{"Must decrypt a token of the current key", currentToken, "2021", false},
+		{"Must decrypt a token of a previous key", previousToken, "2020", false},
+		{"Must decrypt a token without a key ID", legacyToken, "", false},
+		{"Must not decrypt a token of an unknown key ID", unknownKeyToken, "", true},
+		{"Must not decrypt a token without a key ID of no key", unknownToken, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, valid, err := kr.DecryptToken(tt.token, criteria)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("JWEKeyring.DecryptToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !valid || got.Header.KeyID != tt.wantKID {
+				t.Errorf("JWEKeyring.DecryptToken() = %+v, %v", got.Header, valid)
+			}
+
+			reencrypted, err := kr.Reencrypt(tt.token)
+			if nil != err {
+				t.Fatalf("JWEKeyring.Reencrypt() error = %v", err)
+			}
+			if tt.wantKID == "2021" && !bytes.Equal(reencrypted, tt.token) {
+				t.Errorf("JWEKeyring.Reencrypt() re-encrypted a token of the current key")
+			}
+			if _, _, err := current.DecryptToken(reencrypted, criteria); nil != err {
+				t.Errorf("JWEKeyring.Reencrypt() token not decrypted by the current key, error = %v", err)
+			}
+			if got, _ := kr.Decrypt(reencrypted); nil == got || got.Header.KeyID != "2021" || got.Header.Encryption != A128CBCHS256 {
+				t.Errorf("JWEKeyring.Reencrypt() header = %+v", got)
+			}
+		})
+	}
+
+	if err := kr.Remove("2020"); nil != err {
+		t.Fatalf("JWEKeyring.Remove() error = %v", err)
+	}
+	if _, err := kr.Decrypt(previousToken); nil == err {
+		t.Errorf("JWEKeyring.Decrypt() expected error for a token of a removed key")
+	}
+}
@@ -0,0 +1,165 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewJWEEncrypterDecrypter(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	shortRSAKey, _ := rsa.GenerateKey(rand.Reader, 1024)
+
+	tests := []struct {
+		name    string
+		alg     KeyManagementAlgorithm
+		enc     ContentEncryptionAlgorithm
+		key     interface{}
+		wantErr bool
+	}{
+		{"Must create a direct encrypter", Direct, A256GCM, bytes.Repeat([]byte{1}, 32), false},
+		{"Must create a direct encrypter for CBC HMAC", Direct, A256CBCHS512, bytes.Repeat([]byte{1}, 64), false},
+		{"Must fail a direct key of the wrong size", Direct, A256GCM, bytes.Repeat([]byte{1}, 16), true},
+		{"Must fail a direct key of the wrong type", Direct, A256GCM, mustGenerateP256(), true},
+		{"Must create an RSA-OAEP encrypter decrypter", RSAOAEP, A256GCM, rsaKey, false},
+		{"Must create an RSA-OAEP-256 encrypter", RSAOAEP256, A128CBCHS256, &rsaKey.PublicKey, false},
+		{"Must create an ECDH-ES encrypter decrypter", ECDHES, A256GCM, mustGenerateP256(), false},
+		{"Must create an ECDH-ES+A256KW encrypter", ECDHESA256KW, A256GCM, &mustGenerateP256().PublicKey, false},
+		{"Must fail an ECDH-ES key of the wrong type", ECDHES, A256GCM, rsaKey, true},
+		{"Must fail an RSA key smaller than 2048 bits", RSAOAEP, A256GCM, shortRSAKey, true},
+		{"Must fail an RSA-OAEP key of the wrong type", RSAOAEP, A256GCM, bytes.Repeat([]byte{1}, 32), true},
+		{"Must fail an unsupported content encryption algorithm", Direct, "A64GCM", bytes.Repeat([]byte{1}, 32), true},
+		{"Must create an A256KW encrypter decrypter", A256KW, A128GCM, bytes.Repeat([]byte{1}, 32), false},
+		{"Must create an A128GCMKW encrypter decrypter", A128GCMKW, A256CBCHS512, bytes.Repeat([]byte{1}, 16), false},
+		{"Must fail an A192KW key of the wrong size", A192KW, A256GCM, bytes.Repeat([]byte{1}, 32), true},
+		{"Must fail an A256GCMKW key of the wrong type", A256GCMKW, A256GCM, rsaKey, true},
+		{"Must fail an unsupported key management algorithm", "A64KW", A256GCM, bytes.Repeat([]byte{1}, 32), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewJWEEncrypterDecrypter(tt.alg, tt.enc, tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewJWEEncrypterDecrypter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestJWEEncrypterDecrypter_DecryptToken(t *testing.T) {
+	ed, _ := NewJWEEncrypterDecrypter(Direct, A256GCM, bytes.Repeat([]byte{1}, 32))
+	other, _ := NewJWEEncrypterDecrypter(Direct, A256GCM, bytes.Repeat([]byte{2}, 32))
+
+	token, err := ed.GenerateToken(JWEHeader{KeyID: "session", Type: "JWT"}, Claims{Subject: "alice", Issuer: "issuer"})
+	if nil != err {
+		t.Fatalf("JWEEncrypterDecrypter.GenerateToken() error = %v", err)
+	}
+	if parts := strings.Split(string(token), "."); len(parts) != 5 || strings.Contains(string(token), "alice") {
+		t.Fatalf("JWEEncrypterDecrypter.GenerateToken() = %s, want a five part JWE", token)
+	}
+	cbc, _ := NewJWEEncrypterDecrypter(Direct, A128CBCHS256, bytes.Repeat([]byte{1}, 32))
+	perToken, err := cbc.GenerateToken(JWEHeader{KeyID: "session", Encryption: A256GCM}, Claims{Subject: "alice", Issuer: "issuer"})
+	if nil != err {
+		t.Fatalf("JWEEncrypterDecrypter.GenerateToken() error = %v with a per token enc", err)
+	}
+	if _, err := cbc.GenerateToken(JWEHeader{Encryption: A128GCM}, Claims{}); nil == err {
+		t.Errorf("JWEEncrypterDecrypter.GenerateToken() expected error with an enc the direct key doesn't fit")
+	}
+	expired, _ := ed.GenerateToken(JWEHeader{}, Claims{Expiration: NewNumericDate(time.Now().Add(-time.Hour))})
+
+	tests := []struct {
+		name      string
+		ed        *JWEEncrypterDecrypter
+		token     []byte
+		criteria  *ValidationClaims
+		wantValid bool
+		wantErr   bool
+	}{
+		{"Must decrypt and validate a token", ed, token, &ValidationClaims{Issuer: []string{"issuer"}, Subject: []string{"alice"}}, true, false},
+		{"Must not validate a token for another subject", ed, token, &ValidationClaims{Issuer: []string{"issuer"}, Subject: []string{"bob"}}, false, false},
+		{"Must not validate an expired token", ed, expired, nil, false, true},
+		{"Must not decrypt with another key", other, token, nil, false, true},
+		{"Must decrypt a token with a per token enc", cbc, perToken, &ValidationClaims{Issuer: []string{"issuer"}, Subject: []string{"alice"}}, true, false},
+		{"Must not decrypt a JWS", ed, []byte("eyJhbGciOiJIUzI1NiJ9.e30.c2ln"), nil, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, valid, err := tt.ed.DecryptToken(tt.token, tt.criteria)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("JWEEncrypterDecrypter.DecryptToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if valid != tt.wantValid {
+				t.Errorf("JWEEncrypterDecrypter.DecryptToken() valid = %v, want %v", valid, tt.wantValid)
+			}
+			if valid && (got.Header.KeyID != "session" || got.RegisteredClaims.Issuer != "issuer") {
+				t.Errorf("JWEEncrypterDecrypter.DecryptToken() = %+v", got)
+			}
+		})
+	}
+}
+
+func TestJWEEncrypterDecrypter_Compression(t *testing.T) {
+	if _, err := NewJWEEncrypterDecrypter(Direct, A256GCM, bytes.Repeat([]byte{1}, 32), WithMaxDecompressedSize(0)); nil == err {
+		t.Errorf("NewJWEEncrypterDecrypter() expected error with a maximum decompressed size of 0")
+	}
+
+	compressing, _ := NewJWEEncrypterDecrypter(Direct, A256GCM, bytes.Repeat([]byte{1}, 32), WithCompression())
+	limited, _ := NewJWEEncrypterDecrypter(Direct, A256GCM, bytes.Repeat([]byte{1}, 32), WithMaxDecompressedSize(64))
+
+	claims := Claims{Subject: "alice", Issuer: "issuer", Audience: strings.Repeat("audience", 10)}
+	token, err := compressing.GenerateToken(JWEHeader{}, claims)
+	if nil != err {
+		t.Fatalf("JWEEncrypterDecrypter.GenerateToken() error = %v", err)
+	}
+
+	got, valid, err := compressing.DecryptToken(token, &ValidationClaims{Issuer: []string{"issuer"}, Subject: []string{"alice"}, Audience: []string{claims.Audience}})
+	if nil != err || !valid || got.Header.Compression != Deflate {
+		t.Errorf("JWEEncrypterDecrypter.DecryptToken() = %+v, %v, %v", got, valid, err)
+	}
+
+	if _, err := limited.Decrypt(token); nil == err {
+		t.Errorf("JWEEncrypterDecrypter.Decrypt() expected error decompressing over the maximum size")
+	}
+
+	if _, err := compressing.GenerateToken(JWEHeader{Compression: "none"}, claims); nil == err {
+		t.Errorf("JWEEncrypterDecrypter.GenerateToken() expected error with an unsupported compression algorithm")
+	}
+}
+
+func TestJWEEncrypterDecrypter_RSAOAEP(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	recipient, _ := NewJWEEncrypterDecrypter(RSAOAEP256, A256GCM, rsaKey)
+	sender, _ := NewJWEEncrypterDecrypter(RSAOAEP256, A256GCM, &rsaKey.PublicKey)
+
+	token, err := sender.GenerateToken(JWEHeader{Type: "JWT"}, Claims{Subject: "alice", Issuer: "issuer"})
+	if nil != err {
+		t.Fatalf("JWEEncrypterDecrypter.GenerateToken() error = %v", err)
+	}
+
+	got, valid, err := recipient.DecryptToken(token, &ValidationClaims{Issuer: []string{"issuer"}, Subject: []string{"alice"}})
+	if nil != err || !valid || got.Header.Algorithm != RSAOAEP256 {
+		t.Errorf("JWEEncrypterDecrypter.DecryptToken() = %+v, %v, %v", got, valid, err)
+	}
+
+	if _, _, err := sender.DecryptToken(token, nil); nil == err {
+		t.Errorf("JWEEncrypterDecrypter.DecryptToken() expected error with only a public key")
+	}
+}
+
+func TestJWEEncrypterDecrypter_ECDHES(t *testing.T) {
+	key := mustGenerateP256()
+	recipient, _ := NewJWEEncrypterDecrypter(ECDHESA128KW, A128GCM, key)
+	sender, _ := NewJWEEncrypterDecrypter(ECDHESA128KW, A128GCM, &key.PublicKey)
+
+	token, err := sender.GenerateToken(JWEHeader{PartyVInfo: Base64URLEncode([]byte("recipient"))}, Claims{Subject: "alice", Issuer: "issuer"})
+	if nil != err {
+		t.Fatalf("JWEEncrypterDecrypter.GenerateToken() error = %v", err)
+	}
+
+	got, valid, err := recipient.DecryptToken(token, &ValidationClaims{Issuer: []string{"issuer"}, Subject: []string{"alice"}})
+	if nil != err || !valid || nil == got.Header.EphemeralKey || got.Header.EphemeralKey.Curve != "P-256" {
+		t.Errorf("JWEEncrypterDecrypter.DecryptToken() = %+v, %v, %v", got, valid, err)
+	}
+}
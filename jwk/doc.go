@@ -0,0 +1,3 @@
+// Package jwk converts JSON Web Keys (RFC 7517) to and from the native
+// crypto keys used by packages jws and jwt.
+package jwk
@@ -0,0 +1,247 @@
+package jwk
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/georgejenkins/jwt/jwa"
+	"github.com/georgejenkins/jwt/jws"
+)
+
+// Key is a native key with its JWK metadata.
+type Key struct {
+	KeyID     string
+	Algorithm jwa.Algorithm
+	Use       string
+
+	// Key is the native key, of any type returned by Parse. Ed25519 keys
+	// may also be provided as values rather than pointers.
+	Key interface{}
+}
+
+// New wraps a native key as a Key, populating the algorithm from the key
+// type and the key ID from its RFC 7638 thumbprint. RSA keys default to
+// RS256 and HMAC secrets to HS256.
+func New(key interface{}) (*Key, error) {
+	jwk, err := newJSONWebKey(key)
+	if nil != err {
+		return nil, err
+	}
+
+	thumbprint, err := jwk.thumbprint()
+	if nil != err {
+		return nil, err
+	}
+
+	return &Key{
+		KeyID:     thumbprint,
+		Algorithm: jwk.defaultAlgorithm(),
+		Key:       key,
+	}, nil
+}
+
+// Marshal serializes a native key as a JWK with its algorithm and key ID
+// populated, as by New. Private keys are serialized with their private
+// members, so only public keys should be published.
+func Marshal(key interface{}) ([]byte, error) {
+	k, err := New(key)
+	if nil != err {
+		return nil, err
+	}
+
+	return json.Marshal(k)
+}
+
+// Thumbprint returns the base64url encoded SHA-256 JWK thumbprint
+// (RFC 7638) of a native key. Private keys have the same thumbprint as
+// their public keys.
+func Thumbprint(key interface{}) (string, error) {
+	jwk, err := newJSONWebKey(key)
+	if nil != err {
+		return "", err
+	}
+
+	return jwk.thumbprint()
+}
+
+// MarshalJSON serializes the Key as a JWK.
+func (k *Key) MarshalJSON() ([]byte, error) {
+	jwk, err := newJSONWebKey(k.Key)
+	if nil != err {
+		return nil, err
+	}
+
+	jwk.KeyID = k.KeyID
+	jwk.Algorithm = string(k.Algorithm)
+	jwk.Use = k.Use
+
+	return json.Marshal(jwk)
+}
+
+// newJSONWebKey encodes the key material of a native key.
+func newJSONWebKey(key interface{}) (*jsonWebKey, error) {
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsaJSONWebKey(k, nil)
+	case *ecdsa.PrivateKey:
+		return ecdsaJSONWebKey(&k.PublicKey, k.D)
+	case *rsa.PublicKey:
+		return rsaJSONWebKey(k, nil)
+	case *rsa.PrivateKey:
+		return rsaJSONWebKey(&k.PublicKey, k)
+	case ed25519.PublicKey:
+		return ed25519JSONWebKey(k, nil)
+	case *ed25519.PublicKey:
+		return ed25519JSONWebKey(*k, nil)
+	case ed25519.PrivateKey:
+		return ed25519JSONWebKey(k.Public().(ed25519.PublicKey), k)
+	case *ed25519.PrivateKey:
+		return ed25519JSONWebKey(k.Public().(ed25519.PublicKey), *k)
+	case []byte:
+		if len(k) == 0 {
+			return nil, errors.New("Cannot encode an empty HMAC secret as a JWK")
+		}
+		return &jsonWebKey{
+			KeyType: KeyTypeOct,
+			K:       jws.Base64URLEncode(k),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("Cannot encode key type %T as a JWK", key)
+}
+
+func ecdsaJSONWebKey(public *ecdsa.PublicKey, d *big.Int) (*jsonWebKey, error) {
+	if nil == public.Curve {
+		return nil, errors.New("Cannot encode an EC key without a curve as a JWK")
+	}
+
+	var curve string
+	for name, c := range curves {
+		if c.Params().Name == public.Curve.Params().Name {
+			curve = name
+		}
+	}
+	if curve == "" {
+		return nil, fmt.Errorf("Cannot encode EC curve %s as a JWK", public.Curve.Params().Name)
+	}
+
+	// Coordinates and private keys are encoded at the full size of the
+	// curve (RFC 7518 Section 6.2.1).
+	size := (public.Curve.Params().BitSize + 7) / 8
+	jwk := &jsonWebKey{
+		KeyType: KeyTypeEC,
+		Curve:   curve,
+		X:       encodeFixed(public.X, size),
+		Y:       encodeFixed(public.Y, size),
+	}
+	if nil != d {
+		jwk.D = encodeFixed(d, size)
+	}
+
+	return jwk, nil
+}
+
+func rsaJSONWebKey(public *rsa.PublicKey, private *rsa.PrivateKey) (*jsonWebKey, error) {
+	if nil == public.N || public.E <= 0 {
+		return nil, errors.New("Cannot encode an incomplete RSA key as a JWK")
+	}
+
+	jwk := &jsonWebKey{
+		KeyType: KeyTypeRSA,
+		N:       jws.Base64URLEncode(public.N.Bytes()),
+		E:       jws.Base64URLEncode(big.NewInt(int64(public.E)).Bytes()),
+	}
+	if nil == private {
+		return jwk, nil
+	}
+
+	if len(private.Primes) != 2 {
+		return nil, errors.New("Cannot encode a multi-prime RSA key as a JWK")
+	}
+
+	private.Precompute()
+	jwk.D = jws.Base64URLEncode(private.D.Bytes())
+	jwk.P = jws.Base64URLEncode(private.Primes[0].Bytes())
+	jwk.Q = jws.Base64URLEncode(private.Primes[1].Bytes())
+	jwk.DP = jws.Base64URLEncode(private.Precomputed.Dp.Bytes())
+	jwk.DQ = jws.Base64URLEncode(private.Precomputed.Dq.Bytes())
+	jwk.QI = jws.Base64URLEncode(private.Precomputed.Qinv.Bytes())
+
+	return jwk, nil
+}
+
+func ed25519JSONWebKey(public ed25519.PublicKey, private ed25519.PrivateKey) (*jsonWebKey, error) {
+	if len(public) != ed25519.PublicKeySize {
+		return nil, errors.New("Cannot encode an invalid Ed25519 key as a JWK")
+	}
+
+	jwk := &jsonWebKey{
+		KeyType: KeyTypeOKP,
+		Curve:   "Ed25519",
+		X:       jws.Base64URLEncode(public),
+	}
+	if nil != private {
+		jwk.D = jws.Base64URLEncode(private.Seed())
+	}
+
+	return jwk, nil
+}
+
+// encodeFixed base64url encodes an integer, left padded to size bytes.
+func encodeFixed(i *big.Int, size int) string {
+	data := make([]byte, size)
+	b := i.Bytes()
+	copy(data[size-len(b):], b)
+
+	return jws.Base64URLEncode(data)
+}
+
+// defaultAlgorithm returns the algorithm a key is used with by default.
+func (key *jsonWebKey) defaultAlgorithm() jwa.Algorithm {
+	switch key.KeyType {
+	case KeyTypeEC:
+		switch key.Curve {
+		case "P-256":
+			return jwa.ES256
+		case "P-384":
+			return jwa.ES384
+		case "P-521":
+			return jwa.ES512
+		}
+	case KeyTypeRSA:
+		return jwa.RS256
+	case KeyTypeOKP:
+		return jwa.EdDSA
+	case KeyTypeOct:
+		return jwa.HS256
+	}
+
+	return ""
+}
+
+// thumbprint computes the RFC 7638 thumbprint from the required public
+// members of the key, in lexicographic order and without whitespace.
+func (key *jsonWebKey) thumbprint() (string, error) {
+	var members string
+	switch key.KeyType {
+	case KeyTypeEC:
+		members = fmt.Sprintf(`{"crv":%q,"kty":%q,"x":%q,"y":%q}`, key.Curve, key.KeyType, key.X, key.Y)
+	case KeyTypeRSA:
+		members = fmt.Sprintf(`{"e":%q,"kty":%q,"n":%q}`, key.E, key.KeyType, key.N)
+	case KeyTypeOKP:
+		members = fmt.Sprintf(`{"crv":%q,"kty":%q,"x":%q}`, key.Curve, key.KeyType, key.X)
+	case KeyTypeOct:
+		members = fmt.Sprintf(`{"k":%q,"kty":%q}`, key.K, key.KeyType)
+	default:
+		return "", fmt.Errorf("Cannot compute the thumbprint of JWK key type %q", key.KeyType)
+	}
+
+	digest := sha256.Sum256([]byte(members))
+	return jws.Base64URLEncode(digest[:]), nil
+}
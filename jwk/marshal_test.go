@@ -0,0 +1,103 @@
+package jwk
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/georgejenkins/jwt/jwa"
+)
+
+func TestMarshal(t *testing.T) {
+	tests := []struct {
+		name    string
+		jwk     string
+		wantAlg jwa.Algorithm
+	}{
+		{"Must marshal an EC public key", exampleECPublicJWK, jwa.ES256},
+		{"Must marshal an EC private key", exampleECPrivateJWK, jwa.ES256},
+		{"Must marshal an RSA public key", exampleRSAPublicJWK, jwa.RS256},
+		{"Must marshal an RSA private key", exampleRSAPrivateJWK, jwa.RS256},
+		{"Must marshal an OKP public key", exampleOKPPublicJWK, jwa.EdDSA},
+		{"Must marshal an OKP private key", exampleOKPPrivateJWK, jwa.EdDSA},
+		{"Must marshal an oct key", exampleOctJWK, jwa.HS256},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := Parse([]byte(tt.jwk))
+			if nil != err {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			data, err := Marshal(key)
+			if nil != err {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+
+			var members map[string]interface{}
+			json.Unmarshal(data, &members)
+			if members["alg"] != string(tt.wantAlg) {
+				t.Errorf("Marshal() alg = %v, want %v", members["alg"], tt.wantAlg)
+			}
+			if thumbprint, _ := Thumbprint(key); members["kid"] != thumbprint {
+				t.Errorf("Marshal() kid = %v, want thumbprint %v", members["kid"], thumbprint)
+			}
+
+			roundTrip, err := Parse(data)
+			if nil != err {
+				t.Fatalf("Parse() of marshalled key error = %v", err)
+			}
+			if !reflect.DeepEqual(roundTrip, key) {
+				t.Errorf("Parse(Marshal()) = %v, want %v", roundTrip, key)
+			}
+		})
+	}
+}
+
+// TestThumbprint checks thumbprints against RFC 7638 Section 3.1 and
+// RFC 8037 Appendix A.3.
+func TestThumbprint(t *testing.T) {
+	rfc7638Key, err := Parse([]byte(`{"kty":"RSA",
+		"n":"0vx7agoebGcQSuuPiLJXZptN9nndrQmbXEps2aiAFbWhM78LhWx4cbbfAAtVT86zwu1RK7aPFFxuhDR1L6tSoc_BJECPebWKRXjBZCiFV4n3oknjhMstn64tZ_2W-5JsGY4Hc5n9yBXArwl93lqt7_RN5w6Cf0h4QyQ5v-65YGjQR0_FDW2QvzqY368QQMicAtaSqzs8KJZgnYb9c7d0zgdAZHzu6qMQvRL5hajrn1n91CbOpbISD08qNLyrdkt-bFTWhAI4vMQFh6WeZu0fM4lFd2NcRwr3XPksINHaQ-G_xBniIqbw0Ls1jF44-csFCur-kEgU8awapJzKnqDKgw",
+		"e":"AQAB"}`))
+	if nil != err {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	rfc8037Key, _ := Parse([]byte(exampleOKPPrivateJWK))
+
+	tests := []struct {
+		name string
+		key  interface{}
+		want string
+	}{
+		{"Must compute the RFC 7638 RSA thumbprint", rfc7638Key, "NzbLsXh8uDCcd-6MNwXF4W_7noWXFZAfHkxZsRGC9Xs"},
+		{"Must compute the RFC 8037 Ed25519 thumbprint from a private key", rfc8037Key, "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := Thumbprint(tt.key); nil != err || got != tt.want {
+				t.Errorf("Thumbprint() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarshal_PadsECCoordinates(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		key, _ := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+		data, err := Marshal(key)
+		if nil != err {
+			t.Fatalf("Marshal() error = %v", err)
+		}
+		if _, err := Parse(data); nil != err {
+			t.Fatalf("Parse() of marshalled P-521 key error = %v", err)
+		}
+	}
+
+	if _, err := Marshal("not a key"); nil == err {
+		t.Errorf("Marshal() expected error for an unsupported key type")
+	}
+}
@@ -0,0 +1,269 @@
+package jwk
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/georgejenkins/jwt/jws"
+)
+
+// Key types ("kty" parameter values) from RFC 7518 Section 6.1 and
+// RFC 8037 Section 2.
+const (
+	KeyTypeEC  = "EC"
+	KeyTypeRSA = "RSA"
+	KeyTypeOKP = "OKP"
+	KeyTypeOct = "oct"
+)
+
+// jsonWebKey holds the members of a JSON Web Key for the supported key
+// types. Key material members are base64url encoded.
+type jsonWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+	Use       string `json:"use,omitempty"`
+
+	// EC and OKP
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+	Y     string `json:"y,omitempty"`
+
+	// RSA
+	N  string `json:"n,omitempty"`
+	E  string `json:"e,omitempty"`
+	P  string `json:"p,omitempty"`
+	Q  string `json:"q,omitempty"`
+	DP string `json:"dp,omitempty"`
+	DQ string `json:"dq,omitempty"`
+	QI string `json:"qi,omitempty"`
+
+	// Private key for EC, OKP and RSA
+	D string `json:"d,omitempty"`
+
+	// oct
+	K string `json:"k,omitempty"`
+}
+
+// Parse parses a JSON Web Key into the native key it represents, which may
+// be passed directly to NewJOSESignerVerifier:
+//
+//	EC:  *ecdsa.PublicKey or *ecdsa.PrivateKey
+//	RSA: *rsa.PublicKey or *rsa.PrivateKey
+//	OKP: *ed25519.PublicKey or *ed25519.PrivateKey
+//	oct: []byte
+//
+// A private key is returned when the JWK holds private key members.
+func Parse(data []byte) (interface{}, error) {
+	var key jsonWebKey
+	if err := json.Unmarshal(data, &key); nil != err {
+		return nil, err
+	}
+
+	return key.nativeKey()
+}
+
+// nativeKey returns the native key for the JWK.
+func (key *jsonWebKey) nativeKey() (interface{}, error) {
+	switch key.KeyType {
+	case KeyTypeEC:
+		return key.ecdsaKey()
+	case KeyTypeRSA:
+		return key.rsaKey()
+	case KeyTypeOKP:
+		return key.ed25519Key()
+	case KeyTypeOct:
+		return key.octKey()
+	case "":
+		return nil, errors.New("JWK has no key type")
+	}
+
+	return nil, fmt.Errorf("Unsupported JWK key type %q", key.KeyType)
+}
+
+// curves maps "crv" parameter values to elliptic curves.
+var curves = map[string]elliptic.Curve{
+	"P-256": elliptic.P256(),
+	"P-384": elliptic.P384(),
+	"P-521": elliptic.P521(),
+}
+
+func (key *jsonWebKey) ecdsaKey() (interface{}, error) {
+	curve, ok := curves[key.Curve]
+	if !ok {
+		return nil, fmt.Errorf("Unsupported EC JWK curve %q", key.Curve)
+	}
+	size := (curve.Params().BitSize + 7) / 8
+
+	x, err := decodeFixed("x", key.X, size)
+	if nil != err {
+		return nil, err
+	}
+
+	y, err := decodeFixed("y", key.Y, size)
+	if nil != err {
+		return nil, err
+	}
+
+	public := &ecdsa.PublicKey{
+		Curve: curve,
+		X:     x,
+		Y:     y,
+	}
+	if !curve.IsOnCurve(x, y) {
+		return nil, errors.New("EC JWK point is not on the curve")
+	}
+
+	if key.D == "" {
+		return public, nil
+	}
+
+	d, err := decodeFixed("d", key.D, size)
+	if nil != err {
+		return nil, err
+	}
+
+	// The private key must belong to the public key it is published with.
+	if px, py := curve.ScalarBaseMult(d.Bytes()); px.Cmp(x) != 0 || py.Cmp(y) != 0 {
+		return nil, errors.New("EC JWK private key does not match its public key")
+	}
+
+	return &ecdsa.PrivateKey{
+		PublicKey: *public,
+		D:         d,
+	}, nil
+}
+
+func (key *jsonWebKey) rsaKey() (interface{}, error) {
+	n, err := decodeBigInt("n", key.N)
+	if nil != err {
+		return nil, err
+	}
+
+	e, err := decodeBigInt("e", key.E)
+	if nil != err {
+		return nil, err
+	}
+	if !e.IsInt64() || e.Int64() > 1<<31-1 {
+		return nil, errors.New("RSA JWK exponent is too large")
+	}
+
+	public := &rsa.PublicKey{
+		N: n,
+		E: int(e.Int64()),
+	}
+
+	if key.D == "" {
+		return public, nil
+	}
+
+	d, err := decodeBigInt("d", key.D)
+	if nil != err {
+		return nil, err
+	}
+
+	p, err := decodeBigInt("p", key.P)
+	if nil != err {
+		return nil, err
+	}
+
+	q, err := decodeBigInt("q", key.Q)
+	if nil != err {
+		return nil, err
+	}
+
+	private := &rsa.PrivateKey{
+		PublicKey: *public,
+		D:         d,
+		Primes:    []*big.Int{p, q},
+	}
+	if err := private.Validate(); nil != err {
+		return nil, fmt.Errorf("Invalid RSA JWK private key: %v", err)
+	}
+	private.Precompute()
+
+	return private, nil
+}
+
+func (key *jsonWebKey) ed25519Key() (interface{}, error) {
+	if key.Curve != "Ed25519" {
+		return nil, fmt.Errorf("Unsupported OKP JWK curve %q", key.Curve)
+	}
+
+	x, err := decodeBytes("x", key.X, ed25519.PublicKeySize)
+	if nil != err {
+		return nil, err
+	}
+	public := ed25519.PublicKey(x)
+
+	if key.D == "" {
+		return &public, nil
+	}
+
+	d, err := decodeBytes("d", key.D, ed25519.SeedSize)
+	if nil != err {
+		return nil, err
+	}
+
+	private := ed25519.NewKeyFromSeed(d)
+	if !bytes.Equal(public, private.Public().(ed25519.PublicKey)) {
+		return nil, errors.New("OKP JWK private key does not match its public key")
+	}
+
+	return &private, nil
+}
+
+func (key *jsonWebKey) octKey() (interface{}, error) {
+	k, err := decodeBytes("k", key.K, 0)
+	if nil != err {
+		return nil, err
+	}
+
+	return k, nil
+}
+
+// decodeBytes decodes a base64url key member, which must have the given
+// size in bytes, or be non-empty when size is 0.
+func decodeBytes(member string, value string, size int) ([]byte, error) {
+	if value == "" {
+		return nil, fmt.Errorf("JWK is missing the %q member", member)
+	}
+
+	data, err := jws.Base64URLDecode(value)
+	if nil != err {
+		return nil, fmt.Errorf("JWK member %q is not base64url encoded: %v", member, err)
+	}
+
+	if size > 0 && len(data) != size {
+		return nil, fmt.Errorf("JWK member %q must be %d bytes, received %d", member, size, len(data))
+	}
+
+	return data, nil
+}
+
+// decodeFixed decodes a base64url key member holding a fixed size integer.
+func decodeFixed(member string, value string, size int) (*big.Int, error) {
+	data, err := decodeBytes(member, value, size)
+	if nil != err {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(data), nil
+}
+
+// decodeBigInt decodes a base64url key member holding an integer.
+func decodeBigInt(member string, value string) (*big.Int, error) {
+	data, err := decodeBytes(member, value, 0)
+	if nil != err {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,140 @@
+package jwk
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"reflect"
+	"testing"
+
+	"github.com/georgejenkins/jwt/jwa"
+	"github.com/georgejenkins/jwt/jws"
+)
+
+// Example keys from RFC 7515 Appendix A and RFC 8037 Appendix A
+const (
+	exampleECPublicJWK = `{"kty":"EC","crv":"P-256",
+		"x":"f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU",
+		"y":"x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0"}`
+	exampleECPrivateJWK = `{"kty":"EC","crv":"P-256",
+		"x":"f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU",
+		"y":"x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0",
+		"d":"jpsQnnGQmL-YBIffH1136cspYG6-0iY7X1fCE9-E9LI"}`
+	exampleRSAPublicJWK = `{"kty":"RSA",
+		"n":"ofgWCuLjybRlzo0tZWJjNiuSfb4p4fAkd_wWJcyQoTbji9k0l8W26mPddxHmfHQp-Vaw-4qPCJrcS2mJPMEzP1Pt0Bm4d4QlL-yRT-SFd2lZS-pCgNMsD1W_YpRPEwOWvG6b32690r2jZ47soMZo9wGzjb_7OMg0LOL-bSf63kpaSHSXndS5z5rexMdbBYUsLA9e-KXBdQOS-UTo7WTBEMa2R2CapHg665xsmtdVMTBQY4uDZlxvb3qCo5ZwKh9kG4LT6_I5IhlJH7aGhyxXFvUK-DWNmoudF8NAco9_h9iaGNj8q2ethFkMLs91kzk2PAcDTW9gb54h4FRWyuXpoQ",
+		"e":"AQAB"}`
+	exampleRSAPrivateJWK = `{"kty":"RSA",
+		"n":"ofgWCuLjybRlzo0tZWJjNiuSfb4p4fAkd_wWJcyQoTbji9k0l8W26mPddxHmfHQp-Vaw-4qPCJrcS2mJPMEzP1Pt0Bm4d4QlL-yRT-SFd2lZS-pCgNMsD1W_YpRPEwOWvG6b32690r2jZ47soMZo9wGzjb_7OMg0LOL-bSf63kpaSHSXndS5z5rexMdbBYUsLA9e-KXBdQOS-UTo7WTBEMa2R2CapHg665xsmtdVMTBQY4uDZlxvb3qCo5ZwKh9kG4LT6_I5IhlJH7aGhyxXFvUK-DWNmoudF8NAco9_h9iaGNj8q2ethFkMLs91kzk2PAcDTW9gb54h4FRWyuXpoQ",
+		"e":"AQAB",
+		"d":"Eq5xpGnNCivDflJsRQBXHx1hdR1k6Ulwe2JZD50LpXyWPEAeP88vLNO97IjlA7_GQ5sLKMgvfTeXZx9SE-7YwVol2NXOoAJe46sui395IW_GO-pWJ1O0BkTGoVEn2bKVRUCgu-GjBVaYLU6f3l9kJfFNS3E0QbVdxzubSu3Mkqzjkn439X0M_V51gfpRLI9JYanrC4D4qAdGcopV_0ZHHzQlBjudU2QvXt4ehNYTCBr6XCLQUShb1juUO1ZdiYoFaFQT5Tw8bGUl_x_jTj3ccPDVZFD9pIuhLhBOneufuBiB4cS98l2SR_RQyGWSeWjnczT0QU91p1DhOVRuOopznQ",
+		"p":"4BzEEOtIpmVdVEZNCqS7baC4crd0pqnRH_5IB3jw3bcxGn6QLvnEtfdUdiYrqBdss1l58BQ3KhooKeQTa9AB0Hw_Py5PJdTJNPY8cQn7ouZ2KKDcmnPGBY5t7yLc1QlQ5xHdwW1VhvKn-nXqhJTBgIPgtldC-KDV5z-y2XDwGUc",
+		"q":"uQPEfgmVtjL0Uyyx88GZFF1fOunH3-7cepKmtH4pxhtCoHqpWmT8YAmZxaewHgHAjLYsp1ZSe7zFYHj7C6ul7TjeLQeZD_YwD66t62wDmpe_HlB-TnBA-njbglfIsRLtXlnDzQkv5dTltRJ11BKBBypeeF6689rjcJIDEz9RWdc"}`
+	exampleOKPPublicJWK = `{"kty":"OKP","crv":"Ed25519",
+		"x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`
+	exampleOKPPrivateJWK = `{"kty":"OKP","crv":"Ed25519",
+		"d":"nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A",
+		"x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`
+	exampleOctJWK = `{"kty":"oct",
+		"k":"AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS0gZH75aKtMN3Yj0iPS4hcgUuTwjAzZr1Z9CAow"}`
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		wantType interface{}
+		wantErr  bool
+	}{
+		{"Must parse an EC public key", exampleECPublicJWK, &ecdsa.PublicKey{}, false},
+		{"Must parse an EC private key", exampleECPrivateJWK, &ecdsa.PrivateKey{}, false},
+		{"Must parse an RSA public key", exampleRSAPublicJWK, &rsa.PublicKey{}, false},
+		{"Must parse an RSA private key", exampleRSAPrivateJWK, &rsa.PrivateKey{}, false},
+		{"Must parse an OKP public key", exampleOKPPublicJWK, &ed25519.PublicKey{}, false},
+		{"Must parse an OKP private key", exampleOKPPrivateJWK, &ed25519.PrivateKey{}, false},
+		{"Must parse an oct key", exampleOctJWK, []byte{}, false},
+		{"Must fail without a key type", `{"k":"AyM1"}`, nil, true},
+		{"Must fail with an unsupported key type", `{"kty":"XYZ"}`, nil, true},
+		{"Must fail with an unsupported curve", `{"kty":"EC","crv":"P-192","x":"AA","y":"AA"}`, nil, true},
+		{"Must fail with a point off the curve", `{"kty":"EC","crv":"P-256","x":"f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU","y":"f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU"}`, nil, true},
+		{"Must fail with a mismatched EC private key", `{"kty":"EC","crv":"P-256","x":"f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU","y":"x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0","d":"nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A"}`, nil, true},
+		{"Must fail with a short Ed25519 key", `{"kty":"OKP","crv":"Ed25519","x":"11qYAYKx"}`, nil, true},
+		{"Must fail with an empty oct key", `{"kty":"oct","k":""}`, nil, true},
+		{"Must fail with invalid base64url", `{"kty":"oct","k":"A"}`, nil, true},
+		{"Must fail with invalid JSON", `{"kty":`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if reflect.TypeOf(got) != reflect.TypeOf(tt.wantType) {
+				t.Errorf("Parse() = %T, want %T", got, tt.wantType)
+			}
+		})
+	}
+}
+
+// TestParse_SignVerify ensures parsed private keys sign tokens that
+// verify with the parsed public keys.
+func TestParse_SignVerify(t *testing.T) {
+	tests := []struct {
+		name    string
+		private string
+		public  string
+		alg     jwa.Algorithm
+	}{
+		{"Must sign and verify with parsed EC keys", exampleECPrivateJWK, exampleECPublicJWK, jwa.ES256},
+		{"Must sign and verify with parsed RSA keys", exampleRSAPrivateJWK, exampleRSAPublicJWK, jwa.RS256},
+		{"Must sign and verify with parsed OKP keys", exampleOKPPrivateJWK, exampleOKPPublicJWK, jwa.EdDSA},
+		{"Must sign and verify with a parsed oct key", exampleOctJWK, exampleOctJWK, jwa.HS256},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			private, err := Parse([]byte(tt.private))
+			if nil != err {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			public, err := Parse([]byte(tt.public))
+			if nil != err {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			signer, verifier := signerVerifier(t, tt.alg, private, public)
+			signature, err := signer.Sign([]byte("payload"))
+			if nil != err {
+				t.Fatalf("Sign() error = %v", err)
+			}
+			if valid, err := verifier.Verify([]byte("payload"), signature); !valid || nil != err {
+				t.Errorf("Verify() = %v, %v, want valid", valid, err)
+			}
+		})
+	}
+}
+
+func signerVerifier(t *testing.T, alg jwa.Algorithm, private interface{}, public interface{}) (jws.TokenSigner, jws.TokenVerifier) {
+	var signer jws.TokenSigner
+	var verifier jws.TokenVerifier
+	var err, verr error
+
+	switch key := private.(type) {
+	case *ecdsa.PrivateKey:
+		signer, err = jws.InitECDSASigner(alg, key)
+		verifier, verr = jws.InitECDSAVerifier(alg, public.(*ecdsa.PublicKey))
+	case *rsa.PrivateKey:
+		signer, err = jws.InitRSASigner(alg, key)
+		verifier, verr = jws.InitRSAVerifier(alg, public.(*rsa.PublicKey))
+	case *ed25519.PrivateKey:
+		signer, err = jws.InitEdDSASigner(alg, key)
+		verifier, verr = jws.InitEdDSAVerifier(alg, public.(*ed25519.PublicKey))
+	case []byte:
+		var sv *jws.HMACSignerVerifier
+		sv, err = jws.InitHMACSignerVerifier(alg, key)
+		signer, verifier = sv, sv
+	}
+	if nil != err || nil != verr {
+		t.Fatalf("Failed to initialize signer and verifier: %v, %v", err, verr)
+	}
+
+	return signer, verifier
+}
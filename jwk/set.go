@@ -0,0 +1,191 @@
+package jwk
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/georgejenkins/jwt/jwa"
+	"github.com/georgejenkins/jwt/jws"
+)
+
+// Public key uses ("use" parameter values, RFC 7517 Section 4.2).
+const (
+	UseSignature  = "sig"
+	UseEncryption = "enc"
+)
+
+// Set is a JWK Set (RFC 7517 Section 5), the {"keys": [...]} document
+// identity providers publish their keys in.
+type Set struct {
+	Keys []*Key `json:"keys"`
+}
+
+// ParseSet parses a JWK Set. As RFC 7517 requires, keys of unsupported
+// types or with invalid members are ignored rather than failing the set.
+func ParseSet(data []byte) (*Set, error) {
+	var set Set
+	if err := json.Unmarshal(data, &set); nil != err {
+		return nil, err
+	}
+
+	return &set, nil
+}
+
+// UnmarshalJSON parses a JWK Set, ignoring keys that can't be parsed.
+func (s *Set) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Keys []json.RawMessage `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &raw); nil != err {
+		return err
+	}
+	if nil == raw.Keys {
+		return errors.New("JWK Set has no 'keys' member")
+	}
+
+	s.Keys = make([]*Key, 0, len(raw.Keys))
+	for _, rawKey := range raw.Keys {
+		var key Key
+		if err := json.Unmarshal(rawKey, &key); nil != err {
+			continue
+		}
+		s.Keys = append(s.Keys, &key)
+	}
+
+	return nil
+}
+
+// UnmarshalJSON parses a JWK into the Key.
+func (k *Key) UnmarshalJSON(data []byte) error {
+	var jwk jsonWebKey
+	if err := json.Unmarshal(data, &jwk); nil != err {
+		return err
+	}
+
+	native, err := jwk.nativeKey()
+	if nil != err {
+		return err
+	}
+
+	k.KeyID = jwk.KeyID
+	k.Algorithm = jwa.Algorithm(jwk.Algorithm)
+	k.Use = jwk.Use
+	k.Key = native
+	return nil
+}
+
+// Find returns the keys matching the key ID, algorithm and use. An empty
+// value matches any key, and keys with no algorithm or use match any
+// algorithm or use.
+func (s *Set) Find(kid string, alg jwa.Algorithm, use string) []*Key {
+	var keys []*Key
+	for _, key := range s.Keys {
+		if kid != "" && key.KeyID != kid {
+			continue
+		}
+		if alg != "" && key.Algorithm != "" && key.Algorithm != alg {
+			continue
+		}
+		if use != "" && key.Use != "" && key.Use != use {
+			continue
+		}
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// Verifier returns a TokenVerifier for the first signature key matching
+// the key ID that can verify the algorithm.
+func (s *Set) Verifier(kid string, alg jwa.Algorithm) (jws.TokenVerifier, error) {
+	if alg == "" {
+		return nil, errors.New("An algorithm is required to select a verification key")
+	}
+
+	for _, key := range s.Find(kid, alg, UseSignature) {
+		if verifier, err := key.Verifier(alg); nil == err {
+			return verifier, nil
+		}
+	}
+
+	return nil, fmt.Errorf("No key in the JWK Set matches kid %q and alg %q", kid, alg)
+}
+
+// Verifier returns a TokenVerifier for the key and algorithm. Private keys
+// verify with their public key.
+func (k *Key) Verifier(alg jwa.Algorithm) (jws.TokenVerifier, error) {
+	if k.Algorithm != "" && k.Algorithm != alg {
+		return nil, fmt.Errorf("Key is for algorithm %q, not %q", k.Algorithm, alg)
+	}
+
+	native := k.Key
+	if private, ok := native.(*ecdsa.PrivateKey); ok {
+		native = &private.PublicKey
+	}
+
+	switch key := native.(type) {
+	case *ecdsa.PublicKey:
+		// The curve must match the algorithm, which the verifier doesn't check.
+		if jwk, err := newJSONWebKey(key); nil != err || jwk.defaultAlgorithm() != alg {
+			return nil, fmt.Errorf("EC key cannot verify algorithm %q", alg)
+		}
+		return jws.InitECDSAVerifier(alg, key)
+	case *rsa.PrivateKey:
+		return jws.InitRSAVerifier(alg, &key.PublicKey)
+	case *rsa.PublicKey:
+		return jws.InitRSAVerifier(alg, key)
+	case *ed25519.PrivateKey:
+		public := key.Public().(ed25519.PublicKey)
+		return jws.InitEdDSAVerifier(alg, &public)
+	case *ed25519.PublicKey:
+		return jws.InitEdDSAVerifier(alg, key)
+	case []byte:
+		return jws.InitHMACSignerVerifier(alg, key)
+	}
+
+	return nil, fmt.Errorf("Cannot create a verifier for key type %T", k.Key)
+}
+
+// Public returns the set with private keys replaced by their public keys,
+// suitable for publishing. Symmetric keys have no public half and are
+// omitted.
+func (s *Set) Public() *Set {
+	public := &Set{Keys: []*Key{}}
+	for _, key := range s.Keys {
+		native, ok := publicKey(key.Key)
+		if !ok {
+			continue
+		}
+
+		public.Keys = append(public.Keys, &Key{
+			KeyID:     key.KeyID,
+			Algorithm: key.Algorithm,
+			Use:       key.Use,
+			Key:       native,
+		})
+	}
+
+	return public
+}
+
+// publicKey returns the public key of an asymmetric native key.
+func publicKey(key interface{}) (interface{}, bool) {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return &k.PublicKey, true
+	case *ecdsa.PrivateKey:
+		return &k.PublicKey, true
+	case ed25519.PrivateKey:
+		return k.Public().(ed25519.PublicKey), true
+	case *ed25519.PrivateKey:
+		return k.Public().(ed25519.PublicKey), true
+	case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey, *ed25519.PublicKey:
+		return k, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,126 @@
+package jwk
+
+import (
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/georgejenkins/jwt/jwa"
+	"github.com/georgejenkins/jwt/jws"
+)
+
+// exampleSet is RFC 7517 Appendix A.1 with the RFC 8037 Ed25519 key, an
+// unsupported key type and an encryption key added.
+const exampleSet = `{"keys":[
+	{"kty":"EC","crv":"P-256",
+		"x":"MKBCTNIcKUSDii11ySs3526iDZ8AiTo7Tu6KPAqv7D4",
+		"y":"4Etl6SRW2YiLUrN5vfvVHuhp7x8PxltmWWlbbM4IFyM",
+		"use":"enc","kid":"1"},
+	{"kty":"EC","crv":"P-256",
+		"x":"f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU",
+		"y":"x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0",
+		"use":"sig","alg":"ES256","kid":"ec-sig"},
+	{"kty":"OKP","crv":"Ed25519",
+		"x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo",
+		"kid":"ed"},
+	{"kty":"XYZ","kid":"unknown"}
+]}`
+
+func TestParseSet(t *testing.T) {
+	set, err := ParseSet([]byte(exampleSet))
+	if nil != err {
+		t.Fatalf("ParseSet() error = %v", err)
+	}
+	if len(set.Keys) != 3 {
+		t.Fatalf("ParseSet() parsed %d keys, want 3", len(set.Keys))
+	}
+
+	if _, err := ParseSet([]byte(`{"kty":"oct"}`)); nil == err {
+		t.Errorf("ParseSet() expected error without 'keys'")
+	}
+}
+
+func TestSet_Find(t *testing.T) {
+	set, _ := ParseSet([]byte(exampleSet))
+
+	tests := []struct {
+		name    string
+		kid     string
+		alg     jwa.Algorithm
+		use     string
+		wantIDs []string
+	}{
+		{"Must find a key by kid", "ed", "", "", []string{"ed"}},
+		{"Must find keys by alg, including keys with no alg", "", jwa.ES256, "", []string{"1", "ec-sig", "ed"}},
+		{"Must find keys by use, including keys with no use", "", "", UseSignature, []string{"ec-sig", "ed"}},
+		{"Must not find an unknown kid", "missing", "", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids []string
+			for _, key := range set.Find(tt.kid, tt.alg, tt.use) {
+				ids = append(ids, key.KeyID)
+			}
+			if len(ids) != len(tt.wantIDs) {
+				t.Fatalf("Set.Find() = %v, want %v", ids, tt.wantIDs)
+			}
+			for i := range ids {
+				if ids[i] != tt.wantIDs[i] {
+					t.Errorf("Set.Find() = %v, want %v", ids, tt.wantIDs)
+				}
+			}
+		})
+	}
+}
+
+func TestSet_Verifier(t *testing.T) {
+	set, _ := ParseSet([]byte(exampleSet))
+
+	private, _ := Parse([]byte(exampleOKPPrivateJWK))
+	signer, _ := jws.InitEdDSASigner(jwa.EdDSA, private.(*ed25519.PrivateKey))
+	signature, _ := signer.Sign([]byte("payload"))
+
+	verifier, err := set.Verifier("ed", jwa.EdDSA)
+	if nil != err {
+		t.Fatalf("Set.Verifier() error = %v", err)
+	}
+	if valid, err := verifier.Verify([]byte("payload"), signature); !valid || nil != err {
+		t.Errorf("Verify() = %v, %v, want valid", valid, err)
+	}
+
+	tests := []struct {
+		name string
+		kid  string
+		alg  jwa.Algorithm
+	}{
+		{"Must not verify with an encryption key", "1", jwa.ES256},
+		{"Must not verify an algorithm the key is not for", "ec-sig", jwa.ES384},
+		{"Must not verify an algorithm of another key type", "ed", jwa.HS256},
+		{"Must not verify without an algorithm", "ed", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := set.Verifier(tt.kid, tt.alg); nil == err {
+				t.Errorf("Set.Verifier() expected error")
+			}
+		})
+	}
+}
+
+func TestSet_Public(t *testing.T) {
+	_, private, _ := ed25519.GenerateKey(nil)
+	set := &Set{Keys: []*Key{
+		{KeyID: "ed", Use: UseSignature, Key: &private},
+		{KeyID: "hmac", Key: []byte("secret")},
+	}}
+
+	public := set.Public()
+	if len(public.Keys) != 1 || public.Keys[0].KeyID != "ed" || public.Keys[0].Use != UseSignature {
+		t.Fatalf("Set.Public() = %+v, want only the Ed25519 key", public.Keys)
+	}
+	if _, ok := public.Keys[0].Key.(ed25519.PublicKey); !ok {
+		t.Errorf("Set.Public() key type = %T, want ed25519.PublicKey", public.Keys[0].Key)
+	}
+	if _, ok := set.Keys[0].Key.(*ed25519.PrivateKey); !ok {
+		t.Errorf("Set.Public() modified the original set")
+	}
+}
@@ -0,0 +1,376 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/georgejenkins/jwt/jwk"
+)
+
+// maxJWKSSize is the default bound on the size of a fetched JWKS
+// document, see WithMaxJWKSSize.
+const maxJWKSSize = 1 << 20
+
+// jwksClientTimeout bounds requests made by the default JWKS client, so a
+// hung endpoint can't stall verification indefinitely.
+const jwksClientTimeout = 10 * time.Second
+
+// minJWKSCacheDuration is the shortest time a fetched JWK Set is cached,
+// whatever its Cache-Control says, so responses forbidding caching don't
+// cause a fetch per verification. A shorter TTL takes precedence.
+const minJWKSCacheDuration = time.Minute
+
+// jwksRetryBackoff is the delay before a failed fetch is retried, doubled
+// after each consecutive failure up to maxJWKSRetryBackoff.
+const (
+	jwksRetryBackoff    = time.Second
+	maxJWKSRetryBackoff = 5 * time.Minute
+)
+
+// ErrUnknownKeyID is returned when no key matches a key ID.
+var ErrUnknownKeyID = errors.New("No key found for key ID")
+
+// JWKSFetcher retrieves a JWK Set over HTTPS and caches it, so tokens from
+// an identity provider can be verified with its published keys.
+//
+// The set is cached for the TTL, unless the response's Cache-Control
+// max-age says otherwise, and is revalidated with its ETag once expired.
+// Only one fetch is made at a time, without blocking callers while there
+// are keys to serve. If a refresh fails, the previously fetched keys
+// continue to be served, and fetches back off until the endpoint recovers.
+type JWKSFetcher struct {
+	url     string
+	ttl     time.Duration
+	client  *http.Client
+	maxSize int64
+
+	mu        sync.Mutex
+	set       *jwk.Set
+	etag      string
+	fetchedAt time.Time
+	expiry    time.Time
+
+	// The fetch in flight, closed once it completes, and the backoff
+	// after failed fetches.
+	fetching chan struct{}
+	lastErr  error
+	backoff  time.Duration
+	retryAt  time.Time
+
+	// Duration of the last successful fetch, see Provenance.
+	fetchLatency time.Duration
+
+	// Background refresh and refetching on unknown key IDs, configured
+	// with JWKSOptions.
+	refreshInterval time.Duration
+	refreshJitter   time.Duration
+	missCooldown    time.Duration
+	lastMissFetch   time.Time
+	done            chan struct{}
+	closeOnce       sync.Once
+
+	// Cache statistics, see Stats.
+	hits        uint64
+	fetches     uint64
+	notModified uint64
+	failures    uint64
+}
+
+// JWKSStats describes the state of a JWKSFetcher's cache.
+type JWKSStats struct {
+	URL       string    `json:"url"`
+	KeyIDs    []string  `json:"kids"`
+	FetchedAt time.Time `json:"fetched_at"`
+	Expiry    time.Time `json:"expiry"`
+	Fresh     bool      `json:"fresh"`
+
+	Hits        uint64 `json:"hits"`
+	Fetches     uint64 `json:"fetches"`
+	NotModified uint64 `json:"not_modified"`
+	Failures    uint64 `json:"failures"`
+}
+
+// NewJWKSFetcher creates a JWKSFetcher for the HTTPS URL of a JWK Set. If
+// client is nil, a client with a 10 second timeout is used. If background
+// refresh is configured, Close must be called to stop it.
+func NewJWKSFetcher(jwksURL string, ttl time.Duration, client *http.Client, opts ...JWKSOption) (*JWKSFetcher, error) {
+	parsed, err := url.Parse(jwksURL)
+	if nil != err {
+		return nil, err
+	}
+	if parsed.Scheme != "https" || parsed.Host == "" {
+		return nil, fmt.Errorf("JWKS URL %q must be an absolute HTTPS URL", jwksURL)
+	}
+
+	if ttl <= 0 {
+		return nil, errors.New("JWKS cache TTL must be positive")
+	}
+
+	if nil == client {
+		client = &http.Client{Timeout: jwksClientTimeout}
+	}
+
+	f := &JWKSFetcher{
+		url:     jwksURL,
+		ttl:     ttl,
+		client:  client,
+		maxSize: maxJWKSSize,
+		done:    make(chan struct{}),
+	}
+	for _, opt := range opts {
+		if err := opt(f); nil != err {
+			return nil, err
+		}
+	}
+
+	if f.refreshInterval > 0 {
+		go f.refreshLoop()
+	}
+
+	return f, nil
+}
+
+// Keys returns the JWK Set, fetching it if it isn't cached or has expired.
+// Expired keys are served without waiting while another caller fetches
+// the set, or while fetches are backing off after a failure.
+func (f *JWKSFetcher) Keys() (*jwk.Set, error) {
+	f.mu.Lock()
+	now := time.Now()
+	if nil != f.set && now.Before(f.expiry) {
+		defer f.mu.Unlock()
+		f.hits++
+		return f.set, nil
+	}
+	if nil != f.set && (nil != f.fetching || now.Before(f.retryAt)) {
+		defer f.mu.Unlock()
+		return f.set, nil
+	}
+	f.mu.Unlock()
+
+	err := f.fetch(false)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if nil != f.set {
+		return f.set, nil
+	}
+	return nil, err
+}
+
+// KeyForKid returns the key with the key ID, or ErrUnknownKeyID.
+func (f *JWKSFetcher) KeyForKid(kid string) (*jwk.Key, error) {
+	set, err := f.Keys()
+	if nil != err {
+		return nil, err
+	}
+
+	if kid == "" {
+		return nil, ErrUnknownKeyID
+	}
+
+	keys := set.Find(kid, "", "")
+	if len(keys) == 0 && f.refetchOnMiss() {
+		set, err = f.Keys()
+		if nil != err {
+			return nil, err
+		}
+		keys = set.Find(kid, "", "")
+	}
+
+	if len(keys) == 0 {
+		return nil, ErrUnknownKeyID
+	}
+
+	return keys[0], nil
+}
+
+// Refresh fetches the JWK Set, regardless of whether it has expired or
+// fetches are backing off. If a fetch is already in flight, Refresh waits
+// for it instead.
+func (f *JWKSFetcher) Refresh() error {
+	return f.fetch(true)
+}
+
+// Stats returns the key IDs, freshness and cache statistics of the
+// fetcher.
+func (f *JWKSFetcher) Stats() JWKSStats {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	stats := JWKSStats{
+		URL:         f.url,
+		KeyIDs:      []string{},
+		FetchedAt:   f.fetchedAt,
+		Expiry:      f.expiry,
+		Fresh:       nil != f.set && time.Now().Before(f.expiry),
+		Hits:        f.hits,
+		Fetches:     f.fetches,
+		NotModified: f.notModified,
+		Failures:    f.failures,
+	}
+	if nil != f.set {
+		for _, key := range f.set.Keys {
+			stats.KeyIDs = append(stats.KeyIDs, key.KeyID)
+		}
+	}
+
+	return stats
+}
+
+// jwksResponse is the outcome of a successful JWKS request.
+type jwksResponse struct {
+	set         *jwk.Set
+	etag        string
+	notModified bool
+	fetchedAt   time.Time
+	latency     time.Duration
+	header      http.Header
+}
+
+// fetch retrieves the JWK Set, without holding f.mu over the request, and
+// records the outcome. If a fetch is already in flight, it waits for that
+// fetch and returns its error instead. Unless forced, it returns the last
+// error without fetching while backing off after a failure.
+func (f *JWKSFetcher) fetch(force bool) error {
+	f.mu.Lock()
+	if fetching := f.fetching; nil != fetching {
+		f.mu.Unlock()
+		<-fetching
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		return f.lastErr
+	}
+	if !force && time.Now().Before(f.retryAt) {
+		defer f.mu.Unlock()
+		return f.lastErr
+	}
+
+	fetching := make(chan struct{})
+	f.fetching = fetching
+	f.fetches++
+	cached, etag := f.set, f.etag
+	f.mu.Unlock()
+
+	response, err := f.fetchSet(cached, etag)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	defer close(fetching)
+	f.fetching = nil
+
+	f.lastErr = err
+	if nil != err {
+		f.failures++
+		if f.backoff < jwksRetryBackoff {
+			f.backoff = jwksRetryBackoff
+		} else if f.backoff *= 2; f.backoff > maxJWKSRetryBackoff {
+			f.backoff = maxJWKSRetryBackoff
+		}
+		f.retryAt = time.Now().Add(f.backoff)
+		return err
+	}
+
+	f.backoff = 0
+	f.retryAt = time.Time{}
+	if response.notModified {
+		f.notModified++
+	} else {
+		f.set = response.set
+		f.etag = response.etag
+	}
+	f.fetchedAt = response.fetchedAt
+	f.fetchLatency = response.latency
+	f.expiry = response.fetchedAt.Add(f.cacheDuration(response.header))
+	return nil
+}
+
+// fetchSet requests the JWK Set, revalidating the cached set with its
+// ETag, if any.
+func (f *JWKSFetcher) fetchSet(cached *jwk.Set, etag string) (*jwksResponse, error) {
+	request, err := http.NewRequest(http.MethodGet, f.url, nil)
+	if nil != err {
+		return nil, err
+	}
+	request.Header.Set("Accept", "application/jwk-set+json, application/json")
+	if nil != cached && etag != "" {
+		request.Header.Set("If-None-Match", etag)
+	}
+
+	start := time.Now()
+	response, err := f.client.Do(request)
+	if nil != err {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	now := time.Now()
+	switch response.StatusCode {
+	case http.StatusNotModified:
+		if nil == cached {
+			return nil, errors.New("JWKS endpoint returned 304 Not Modified with no cached keys")
+		}
+		return &jwksResponse{notModified: true, fetchedAt: now, latency: now.Sub(start), header: response.Header}, nil
+	case http.StatusOK:
+	default:
+		return nil, fmt.Errorf("JWKS endpoint returned status %d", response.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(response.Body, f.maxSize+1))
+	if nil != err {
+		return nil, err
+	}
+	if int64(len(body)) > f.maxSize {
+		return nil, fmt.Errorf("JWKS document exceeds %d bytes", f.maxSize)
+	}
+
+	set, err := jwk.ParseSet(body)
+	if nil != err {
+		return nil, err
+	}
+
+	return &jwksResponse{
+		set:       set,
+		etag:      response.Header.Get("ETag"),
+		fetchedAt: now,
+		latency:   time.Since(start),
+		header:    response.Header,
+	}, nil
+}
+
+// cacheDuration returns how long a response may be cached for, honoring
+// the Cache-Control max-age, no-cache and no-store directives, but never
+// less than minJWKSCacheDuration or the TTL, whichever is shorter.
+func (f *JWKSFetcher) cacheDuration(header http.Header) time.Duration {
+	floor := minJWKSCacheDuration
+	if f.ttl < floor {
+		floor = f.ttl
+	}
+
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		switch {
+		case directive == "no-cache" || directive == "no-store":
+			return floor
+		case strings.HasPrefix(directive, "max-age="):
+			seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+			if nil == err && seconds >= 0 {
+				if duration := time.Duration(seconds) * time.Second; duration > floor {
+					return duration
+				}
+				return floor
+			}
+		}
+	}
+
+	return f.ttl
+}
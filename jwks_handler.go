@@ -0,0 +1,90 @@
+package jwt
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/georgejenkins/jwt/jwk"
+)
+
+// WellKnownJWKSPath is the conventional path of a JWKS document.
+const WellKnownJWKSPath = "/.well-known/jwks.json"
+
+// KeySetSource supplies the keys a JWKS handler publishes.
+type KeySetSource interface {
+	KeySet() (*jwk.Set, error)
+}
+
+// KeySetFunc adapts a function to a KeySetSource.
+type KeySetFunc func() (*jwk.Set, error)
+
+// KeySet calls f().
+func (f KeySetFunc) KeySet() (*jwk.Set, error) {
+	return f()
+}
+
+// NewJWKSHandler returns an http.Handler publishing the public halves of
+// the source's keys as a JWK Set, so clients can verify tokens without
+// manual key distribution:
+//
+//	mux.Handle(jwt.WellKnownJWKSPath, handler)
+//
+// Private keys are published as their public keys, and symmetric keys are
+// never published. Responses may be cached for maxAge, and carry an ETag
+// for revalidation. The source is read on every request, so rotated keys
+// are published once maxAge has passed.
+func NewJWKSHandler(source KeySetSource, maxAge time.Duration) (http.Handler, error) {
+	if nil == source {
+		return nil, errors.New("JWKS handler requires a key set source")
+	}
+	if maxAge < 0 {
+		return nil, errors.New("JWKS max age cannot be negative")
+	}
+
+	cacheControl := fmt.Sprintf("public, max-age=%d", int64(maxAge/time.Second))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		body, err := publicJWKS(source)
+		if nil != err {
+			w.Header().Set("Cache-Control", "no-store")
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		digest := sha256.Sum256(body)
+		etag := `"` + Base64URLEncode(digest[:]) + `"`
+
+		w.Header().Set("Cache-Control", cacheControl)
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/jwk-set+json")
+		if r.Method == http.MethodGet {
+			w.Write(body)
+		}
+	}), nil
+}
+
+// publicJWKS returns the JWK Set document of the public halves of the
+// source's keys.
+func publicJWKS(source KeySetSource) ([]byte, error) {
+	set, err := source.KeySet()
+	if nil != err {
+		return nil, err
+	}
+
+	return json.Marshal(set.Public())
+}
@@ -0,0 +1,82 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/georgejenkins/jwt/jwk"
+)
+
+func TestNewJWKSHandler(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	set := &jwk.Set{Keys: []*jwk.Key{
+		{KeyID: "ec-1", Algorithm: ES256, Use: jwk.UseSignature, Key: key},
+		{KeyID: "hmac", Algorithm: HS256, Key: exampleKey},
+	}}
+	handler, err := NewJWKSHandler(KeySetFunc(func() (*jwk.Set, error) { return set, nil }), 5*time.Minute)
+	if nil != err {
+		t.Fatalf("NewJWKSHandler() error = %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(WellKnownJWKSPath, handler)
+	server := httptest.NewTLSServer(mux)
+	defer server.Close()
+
+	response, err := server.Client().Get(server.URL + WellKnownJWKSPath)
+	if nil != err {
+		t.Fatalf("GET error = %v", err)
+	}
+	body, _ := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+
+	if got := response.Header.Get("Cache-Control"); got != "public, max-age=300" {
+		t.Errorf("Cache-Control = %q, want %q", got, "public, max-age=300")
+	}
+	if got := response.Header.Get("Content-Type"); got != "application/jwk-set+json" {
+		t.Errorf("Content-Type = %q, want application/jwk-set+json", got)
+	}
+	if strings.Contains(string(body), `"d"`) || strings.Contains(string(body), "hmac") {
+		t.Errorf("JWKS handler published private or symmetric key material: %s", body)
+	}
+
+	// Tokens must verify against the published keys.
+	fetcher, _ := NewJWKSFetcher(server.URL+WellKnownJWKSPath, time.Hour, server.Client())
+	verifier, _ := NewKeyResolvingVerifier(fetcher)
+	signer, _ := NewJOSESignerVerifier(ES256, key)
+	token, _ := signer.GenerateToken(Header{Algorithm: string(ES256), KeyID: "ec-1"}, Claims{Subject: "alice"})
+	if _, valid, err := verifier.VerifySignature(token); !valid || nil != err {
+		t.Errorf("VerifySignature() = %v, %v with the published keys", valid, err)
+	}
+
+	request, _ := http.NewRequest(http.MethodGet, server.URL+WellKnownJWKSPath, nil)
+	request.Header.Set("If-None-Match", response.Header.Get("ETag"))
+	revalidated, err := server.Client().Do(request)
+	if nil != err || revalidated.StatusCode != http.StatusNotModified {
+		t.Errorf("Revalidation = %v, %v, want 304 Not Modified", revalidated.StatusCode, err)
+	}
+
+	posted, _ := server.Client().Post(server.URL+WellKnownJWKSPath, "application/json", nil)
+	if posted.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("POST status = %d, want 405", posted.StatusCode)
+	}
+
+	failing, _ := NewJWKSHandler(KeySetFunc(func() (*jwk.Set, error) { return nil, errors.New("Keyring unavailable") }), time.Minute)
+	recorder := httptest.NewRecorder()
+	failing.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, WellKnownJWKSPath, nil))
+	if recorder.Code != http.StatusInternalServerError || recorder.Header().Get("Cache-Control") != "no-store" {
+		t.Errorf("Failing source status = %d, Cache-Control %q", recorder.Code, recorder.Header().Get("Cache-Control"))
+	}
+
+	if _, err := NewJWKSHandler(nil, time.Minute); nil == err {
+		t.Errorf("NewJWKSHandler() expected error without a source")
+	}
+}
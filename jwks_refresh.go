@@ -0,0 +1,96 @@
+package jwt
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// JWKSOption configures optional behaviour of a JWKSFetcher.
+type JWKSOption func(f *JWKSFetcher) error
+
+// WithBackgroundRefresh refreshes the JWK Set in the background every
+// interval plus a random jitter of up to jitter, so key rotations are
+// picked up before they are needed, and many instances don't refresh in
+// lockstep.
+func WithBackgroundRefresh(interval time.Duration, jitter time.Duration) JWKSOption {
+	return func(f *JWKSFetcher) error {
+		if interval <= 0 || jitter < 0 {
+			return errors.New("JWKS refresh interval must be positive and jitter non-negative")
+		}
+
+		f.refreshInterval = interval
+		f.refreshJitter = jitter
+		return nil
+	}
+}
+
+// WithRefetchOnUnknownKid refetches the JWK Set when KeyForKid is asked for
+// a key ID it doesn't hold, as happens just after the identity provider
+// rotates its keys. Refetches are at least cooldown apart, so tokens with
+// made up key IDs can't be used to hammer the JWKS endpoint.
+func WithRefetchOnUnknownKid(cooldown time.Duration) JWKSOption {
+	return func(f *JWKSFetcher) error {
+		if cooldown <= 0 {
+			return errors.New("JWKS refetch cooldown must be positive")
+		}
+
+		f.missCooldown = cooldown
+		return nil
+	}
+}
+
+// WithMaxJWKSSize rejects JWKS documents larger than maxSize bytes,
+// instead of the default of 1 MiB.
+func WithMaxJWKSSize(maxSize int64) JWKSOption {
+	return func(f *JWKSFetcher) error {
+		if maxSize <= 0 {
+			return errors.New("JWKS maximum size must be positive")
+		}
+
+		f.maxSize = maxSize
+		return nil
+	}
+}
+
+// Close stops background refresh.
+func (f *JWKSFetcher) Close() {
+	f.closeOnce.Do(func() {
+		close(f.done)
+	})
+}
+
+// refreshLoop refreshes the JWK Set until the fetcher is closed. Failed
+// refreshes keep serving the previous keys, and are retried at the next
+// interval.
+func (f *JWKSFetcher) refreshLoop() {
+	for {
+		delay := f.refreshInterval
+		if f.refreshJitter > 0 {
+			delay += time.Duration(rand.Int63n(int64(f.refreshJitter)))
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-f.done:
+			timer.Stop()
+			return
+		case <-timer.C:
+			f.Refresh()
+		}
+	}
+}
+
+// refetchOnMiss refetches the JWK Set after an unknown key ID, if enabled
+// and the cooldown has passed. It reports whether the set was refetched.
+func (f *JWKSFetcher) refetchOnMiss() bool {
+	f.mu.Lock()
+	if f.missCooldown <= 0 || time.Since(f.lastMissFetch) < f.missCooldown {
+		f.mu.Unlock()
+		return false
+	}
+	f.lastMissFetch = time.Now()
+	f.mu.Unlock()
+
+	return nil == f.fetch(false)
+}
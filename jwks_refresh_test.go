@@ -0,0 +1,79 @@
+package jwt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestJWKSFetcher_RefetchOnUnknownKid(t *testing.T) {
+	var requests int32
+	var rotated int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		jwks := exampleJWKS
+		if atomic.LoadInt32(&rotated) == 1 {
+			jwks = strings.Replace(jwks, `"ec-1"`, `"ec-2"`, 1)
+		}
+		w.Write([]byte(jwks))
+	}))
+	defer server.Close()
+
+	fetcher, err := NewJWKSFetcher(server.URL, time.Hour, server.Client(), WithRefetchOnUnknownKid(time.Hour))
+	if nil != err {
+		t.Fatalf("NewJWKSFetcher() error = %v", err)
+	}
+	if _, err := fetcher.KeyForKid("ec-1"); nil != err {
+		t.Fatalf("JWKSFetcher.KeyForKid() error = %v", err)
+	}
+
+	atomic.StoreInt32(&rotated, 1)
+	if key, err := fetcher.KeyForKid("ec-2"); nil != err || key.KeyID != "ec-2" {
+		t.Fatalf("JWKSFetcher.KeyForKid() = %v, %v, want the rotated key", key, err)
+	}
+
+	if _, err := fetcher.KeyForKid("made-up"); err != ErrUnknownKeyID {
+		t.Errorf("JWKSFetcher.KeyForKid() error = %v, want ErrUnknownKeyID", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("JWKSFetcher fetched %d times, want 2 with the cooldown in effect", got)
+	}
+}
+
+func TestJWKSFetcher_BackgroundRefresh(t *testing.T) {
+	var requests int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(exampleJWKS))
+	}))
+	defer server.Close()
+
+	fetcher, err := NewJWKSFetcher(server.URL, time.Hour, server.Client(), WithBackgroundRefresh(10*time.Millisecond, 5*time.Millisecond))
+	if nil != err {
+		t.Fatalf("NewJWKSFetcher() error = %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for atomic.LoadInt32(&requests) < 3 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if atomic.LoadInt32(&requests) < 3 {
+		t.Fatalf("JWKSFetcher refreshed %d times in the background, want at least 3", atomic.LoadInt32(&requests))
+	}
+
+	fetcher.Close()
+	fetcher.Close()
+	time.Sleep(50 * time.Millisecond)
+	stopped := atomic.LoadInt32(&requests)
+	time.Sleep(50 * time.Millisecond)
+	if got := atomic.LoadInt32(&requests); got != stopped {
+		t.Errorf("JWKSFetcher refreshed %d times after Close", got-stopped)
+	}
+
+	if _, err := NewJWKSFetcher(server.URL, time.Hour, nil, WithBackgroundRefresh(0, 0)); nil == err {
+		t.Errorf("NewJWKSFetcher() expected error for a zero refresh interval")
+	}
+}
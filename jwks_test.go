@@ -0,0 +1,203 @@
+package jwt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const exampleJWKS = `{"keys":[
+	{"kty":"EC","crv":"P-256","kid":"ec-1","alg":"ES256",
+		"x":"f83OJ3D2xF1Bg8vub9tLe1gHMzV76e8Tus9uPHvRVEU",
+		"y":"x_FEzRu9m36HLN_tue659LNpXW6pCyStikYjKIWI5a0"}
+]}`
+
+func TestJWKSFetcher(t *testing.T) {
+	var requests, notModified int32
+	var cacheControl atomic.Value
+	cacheControl.Store("")
+	failing := int32(0)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if atomic.LoadInt32(&failing) == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Cache-Control", cacheControl.Load().(string))
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(exampleJWKS))
+	}))
+	defer server.Close()
+
+	fetcher, err := NewJWKSFetcher(server.URL, time.Hour, server.Client())
+	if nil != err {
+		t.Fatalf("NewJWKSFetcher() error = %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		key, err := fetcher.KeyForKid("ec-1")
+		if nil != err || key.KeyID != "ec-1" {
+			t.Fatalf("JWKSFetcher.KeyForKid() = %v, %v", key, err)
+		}
+	}
+	if atomic.LoadInt32(&requests) != 1 {
+		t.Errorf("JWKSFetcher fetched %d times within the TTL, want 1", requests)
+	}
+
+	if _, err := fetcher.KeyForKid("unknown"); err != ErrUnknownKeyID {
+		t.Errorf("JWKSFetcher.KeyForKid() error = %v, want ErrUnknownKeyID", err)
+	}
+
+	// no-cache responses are revalidated with the ETag, but are still
+	// cached for the minimum cache duration.
+	cacheControl.Store("no-cache")
+	fetcher.Refresh()
+	fetcher.Keys()
+	if atomic.LoadInt32(&notModified) != 1 {
+		t.Errorf("JWKSFetcher revalidated %d times, want 1", notModified)
+	}
+
+	// Cached keys continue to be served while the endpoint is failing.
+	atomic.StoreInt32(&failing, 1)
+	if _, err := fetcher.KeyForKid("ec-1"); nil != err {
+		t.Errorf("JWKSFetcher.KeyForKid() error = %v, want stale keys served", err)
+	}
+	if err := fetcher.Refresh(); nil == err {
+		t.Errorf("JWKSFetcher.Refresh() expected error from a failing endpoint")
+	}
+
+	if _, err := NewJWKSFetcher("http://issuer.example.com/jwks", time.Hour, nil); nil == err {
+		t.Errorf("NewJWKSFetcher() expected error for a non-HTTPS URL")
+	}
+}
+
+func TestJWKSFetcher_CacheDuration(t *testing.T) {
+	fetcher, _ := NewJWKSFetcher("https://issuer.example.com/jwks", time.Hour, nil)
+	shortFetcher, _ := NewJWKSFetcher("https://issuer.example.com/jwks", time.Second, nil)
+
+	tests := []struct {
+		name         string
+		fetcher      *JWKSFetcher
+		cacheControl string
+		want         time.Duration
+	}{
+		{"Must cache for the TTL without Cache-Control", fetcher, "", time.Hour},
+		{"Must cache for the max-age", fetcher, "max-age=300", 5 * time.Minute},
+		{"Must cache no-store responses for the minimum", fetcher, "no-store", minJWKSCacheDuration},
+		{"Must cache no-cache responses for the minimum", fetcher, "no-cache", minJWKSCacheDuration},
+		{"Must cache a zero max-age for the minimum", fetcher, "max-age=0", minJWKSCacheDuration},
+		{"Must not cache beyond a shorter TTL for no-store responses", shortFetcher, "no-store", time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			header.Set("Cache-Control", tt.cacheControl)
+			if got := tt.fetcher.cacheDuration(header); got != tt.want {
+				t.Errorf("JWKSFetcher.cacheDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if fetcher.client.Timeout <= 0 {
+		t.Errorf("NewJWKSFetcher() default client has no timeout")
+	}
+}
+
+func TestJWKSFetcher_Backoff(t *testing.T) {
+	var requests, failing int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if atomic.LoadInt32(&failing) == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(exampleJWKS))
+	}))
+	defer server.Close()
+
+	atomic.StoreInt32(&failing, 1)
+	fetcher, _ := NewJWKSFetcher(server.URL, time.Millisecond, server.Client())
+	for i := 0; i < 3; i++ {
+		if _, err := fetcher.Keys(); nil == err {
+			t.Fatalf("JWKSFetcher.Keys() expected error from a failing endpoint")
+		}
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("JWKSFetcher fetched %d times while backing off, want 1", got)
+	}
+
+	atomic.StoreInt32(&failing, 0)
+	if err := fetcher.Refresh(); nil != err {
+		t.Fatalf("JWKSFetcher.Refresh() error = %v", err)
+	}
+
+	// Once the set has expired, a failing endpoint is retried after the
+	// backoff, and the stale keys are served meanwhile.
+	atomic.StoreInt32(&failing, 1)
+	time.Sleep(5 * time.Millisecond)
+	before := atomic.LoadInt32(&requests)
+	for i := 0; i < 3; i++ {
+		if _, err := fetcher.KeyForKid("ec-1"); nil != err {
+			t.Fatalf("JWKSFetcher.KeyForKid() error = %v, want stale keys served", err)
+		}
+	}
+	if got := atomic.LoadInt32(&requests) - before; got != 1 {
+		t.Errorf("JWKSFetcher fetched %d times while backing off, want 1", got)
+	}
+}
+
+func TestJWKSFetcher_SlowEndpoint(t *testing.T) {
+	var requests int32
+	entered := make(chan struct{}, 1)
+	release := make(chan struct{})
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) > 1 {
+			entered <- struct{}{}
+			<-release
+		}
+		w.Write([]byte(exampleJWKS))
+	}))
+	defer server.Close()
+
+	fetcher, _ := NewJWKSFetcher(server.URL, time.Millisecond, server.Client())
+	if _, err := fetcher.Keys(); nil != err {
+		t.Fatalf("JWKSFetcher.Keys() error = %v", err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	refreshed := make(chan error)
+	go func() { refreshed <- fetcher.Refresh() }()
+	<-entered
+
+	// While the endpoint hangs, the stale keys are served without waiting
+	// and without another request.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fetcher.Stats()
+		if _, err := fetcher.KeyForKid("ec-1"); nil != err {
+			t.Errorf("JWKSFetcher.KeyForKid() error = %v, want stale keys served", err)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("JWKSFetcher blocked on a fetch in flight")
+	}
+
+	close(release)
+	if err := <-refreshed; nil != err {
+		t.Errorf("JWKSFetcher.Refresh() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != 2 {
+		t.Errorf("JWKSFetcher fetched %d times, want 2", got)
+	}
+}
@@ -0,0 +1,4 @@
+// Package jws signs and verifies JSON Web Signatures (RFC 7515) with the
+// algorithms defined in package jwa. Package jwt builds tokens on top of
+// these signers and verifiers.
+package jws
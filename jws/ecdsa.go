@@ -1,4 +1,4 @@
-package main
+package jws
 
 import (
 	"crypto/ecdsa"
@@ -6,27 +6,30 @@ import (
 	"crypto/rand"
 	"errors"
 	"fmt"
+	"hash"
 	"io"
 	"math/big"
+
+	"github.com/georgejenkins/jwt/jwa"
 )
 
 // ECDSASigner contains configuration for signing JWSs using the
 // ECDSA 256/384/512 family.
 type ECDSASigner struct {
-	algorithm Algorithm
+	algorithm jwa.Algorithm
 	prvKey    *ecdsa.PrivateKey
 	rng       io.Reader
 }
 
 // getExpectedKeyParameters returns the key parameters needed to validate
 // against user key input when initializing the signer/verifier.
-func getExpectedKeyParameters(alg Algorithm) (int, elliptic.Curve, error) {
+func getExpectedKeyParameters(alg jwa.Algorithm) (int, elliptic.Curve, error) {
 	switch alg {
-	case ES256:
+	case jwa.ES256:
 		return 32, elliptic.P256(), nil
-	case ES384:
+	case jwa.ES384:
 		return 48, elliptic.P384(), nil
-	case ES512:
+	case jwa.ES512:
 		return 66, elliptic.P521(), nil
 	}
 
@@ -38,7 +41,7 @@ func getExpectedKeyParameters(alg Algorithm) (int, elliptic.Curve, error) {
 
 // validateKeyMatchesAlgorithm validates the key provided matches
 // the parameters of the algorithm it is to be used with.
-func validateKeyMatchesAlgorithm(alg Algorithm, key *ecdsa.PrivateKey) error {
+func validateKeyMatchesAlgorithm(alg jwa.Algorithm, key *ecdsa.PrivateKey) error {
 	expectedBitSize, expectedCurve, err := getExpectedKeyParameters(alg)
 	if nil != err {
 		return err
@@ -60,7 +63,7 @@ func validateKeyMatchesAlgorithm(alg Algorithm, key *ecdsa.PrivateKey) error {
 }
 
 // InitECDSASigner initializes a new ECDSA family signer.
-func InitECDSASigner(alg Algorithm, key *ecdsa.PrivateKey) (*ECDSASigner, error) {
+func InitECDSASigner(alg jwa.Algorithm, key *ecdsa.PrivateKey) (*ECDSASigner, error) {
 	if nil == key {
 		return nil, errors.New("Cannot init ECDSASigner with empty key")
 	}
@@ -88,7 +91,22 @@ func (sv *ECDSASigner) Sign(plaintext []byte) ([]byte, error) {
 		return nil, err
 	}
 
-	r, s, err := ecdsa.Sign(rand.Reader, sv.prvKey, hash)
+	return sv.signDigest(hash)
+}
+
+// NewHash returns a hash for the signing input, see HashSigner.
+func (sv *ECDSASigner) NewHash() (hash.Hash, error) {
+	return newHash(sv.algorithm)
+}
+
+// SignHash signs the signing input written to a hash returned by NewHash.
+func (sv *ECDSASigner) SignHash(h hash.Hash) ([]byte, error) {
+	return sv.signDigest(h.Sum(nil))
+}
+
+// signDigest signs the hash of a payload.
+func (sv *ECDSASigner) signDigest(hash []byte) ([]byte, error) {
+	r, s, err := ecdsa.Sign(sv.rng, sv.prvKey, hash)
 	if nil != err {
 		return nil, err
 	}
@@ -96,6 +114,17 @@ func (sv *ECDSASigner) Sign(plaintext []byte) ([]byte, error) {
 	return sv.padAndJoin(r, s), nil
 }
 
+// UseRand sets the random source used for signing, after checking its
+// health with CheckEntropy.
+func (sv *ECDSASigner) UseRand(rng io.Reader) error {
+	if err := CheckEntropy(rng); nil != err {
+		return err
+	}
+
+	sv.rng = rng
+	return nil
+}
+
 // getSignatureLength returns the length of the expected
 // r/s signature portions, useful for padding and splitting
 func getSignatureLength(curve elliptic.Curve) int {
@@ -130,12 +159,12 @@ func (sv *ECDSASigner) padAndJoin(r *big.Int, s *big.Int) []byte {
 
 // ECDSAVerifier contains configuration for verifying JWSs using the ECDSA 256/384/512 family.
 type ECDSAVerifier struct {
-	algorithm Algorithm
+	algorithm jwa.Algorithm
 	pubKey    *ecdsa.PublicKey
 }
 
 // InitECDSAVerifier initializes a new ECDSA family signer.
-func InitECDSAVerifier(alg Algorithm, key *ecdsa.PublicKey) (*ECDSAVerifier, error) {
+func InitECDSAVerifier(alg jwa.Algorithm, key *ecdsa.PublicKey) (*ECDSAVerifier, error) {
 	if nil == key {
 		return nil, errors.New("Cannot init ECDSAVerifier with empty key")
 	}
@@ -144,10 +173,14 @@ func InitECDSAVerifier(alg Algorithm, key *ecdsa.PublicKey) (*ECDSAVerifier, err
 		return nil, errors.New("Cannot init ECDSAVerifier with no algorithm")
 	}
 
-	if ES256 != alg && ES384 != alg && ES512 != alg {
+	if jwa.ES256 != alg && jwa.ES384 != alg && jwa.ES512 != alg {
 		return nil, errors.New("Signing algorithm unexpected, must be one of: ES256, ES384, ES512")
 	}
 
+	if err := CheckKeyAlgorithm(alg, key); nil != err {
+		return nil, err
+	}
+
 	return &ECDSAVerifier{
 		algorithm: alg,
 		pubKey:    key,
@@ -162,6 +195,22 @@ func (sv *ECDSAVerifier) Verify(plaintext []byte, signature []byte) (bool, error
 		return false, err
 	}
 
+	return sv.verifyDigest(hash, signature)
+}
+
+// NewHash returns a hash for the signing input, see HashVerifier.
+func (sv *ECDSAVerifier) NewHash() (hash.Hash, error) {
+	return newHash(sv.algorithm)
+}
+
+// VerifyHash verifies the signature over the signing input written to a
+// hash returned by NewHash.
+func (sv *ECDSAVerifier) VerifyHash(h hash.Hash, signature []byte) (bool, error) {
+	return sv.verifyDigest(h.Sum(nil), signature)
+}
+
+// verifyDigest verifies the signature over the hash of a payload.
+func (sv *ECDSAVerifier) verifyDigest(hash []byte, signature []byte) (bool, error) {
 	rsSplitLen := getSignatureLength(sv.pubKey.Curve)
 
 	// conjecture - do we need to validate the signature length of
@@ -1,4 +1,4 @@
-package main
+package jws
 
 import (
 	"crypto/ecdsa"
@@ -6,6 +6,8 @@ import (
 	"crypto/rand"
 	"reflect"
 	"testing"
+
+	"github.com/georgejenkins/jwt/jwa"
 )
 
 // Example values from https://tools.ietf.org/html/rfc7515#appendix-A.3
@@ -58,7 +60,7 @@ func getECDSA512PrivateTestKey() *ecdsa.PrivateKey {
 
 func TestInitECDSASigner(t *testing.T) {
 	type args struct {
-		alg Algorithm
+		alg jwa.Algorithm
 		key *ecdsa.PrivateKey
 	}
 	tests := []struct {
@@ -70,11 +72,11 @@ func TestInitECDSASigner(t *testing.T) {
 		{
 			"Must initialize ECDSASigner given valid key and ES256",
 			args{
-				ES256,
+				jwa.ES256,
 				getECDSA256PrivateTestKey(),
 			},
 			&ECDSASigner{
-				algorithm: ES256,
+				algorithm: jwa.ES256,
 				prvKey:    getECDSA256PrivateTestKey(),
 				rng:       rand.Reader,
 			},
@@ -83,11 +85,11 @@ func TestInitECDSASigner(t *testing.T) {
 		{
 			"Must initialize ECDSASigner given valid key and ES384",
 			args{
-				ES384,
+				jwa.ES384,
 				getECDSA384PrivateTestKey(),
 			},
 			&ECDSASigner{
-				algorithm: ES384,
+				algorithm: jwa.ES384,
 				prvKey:    getECDSA384PrivateTestKey(),
 				rng:       rand.Reader,
 			},
@@ -96,11 +98,11 @@ func TestInitECDSASigner(t *testing.T) {
 		{
 			"Must initialize ECDSASigner given valid key and ES512",
 			args{
-				ES512,
+				jwa.ES512,
 				getECDSA512PrivateTestKey(),
 			},
 			&ECDSASigner{
-				algorithm: ES512,
+				algorithm: jwa.ES512,
 				prvKey:    getECDSA512PrivateTestKey(),
 				rng:       rand.Reader,
 			},
@@ -109,7 +111,7 @@ func TestInitECDSASigner(t *testing.T) {
 		{
 			"Must fail to initialize ECDSASigner given a nil key",
 			args{
-				ES256,
+				jwa.ES256,
 				nil,
 			},
 			nil,
@@ -118,7 +120,7 @@ func TestInitECDSASigner(t *testing.T) {
 		{
 			"Must fail to initialize ECDSASigner given an unexpected algorithm",
 			args{
-				RS256,
+				jwa.RS256,
 				getECDSA256PrivateTestKey(),
 			},
 			nil,
@@ -141,7 +143,7 @@ func TestInitECDSASigner(t *testing.T) {
 
 func TestInitECDSAVerifier(t *testing.T) {
 	type args struct {
-		alg Algorithm
+		alg jwa.Algorithm
 		key *ecdsa.PublicKey
 	}
 	tests := []struct {
@@ -153,43 +155,37 @@ func TestInitECDSAVerifier(t *testing.T) {
 		{
 			"Must initialize ECDSAVerifier given valid key and ES256",
 			args{
-				ES256,
+				jwa.ES256,
 				getECDSA256PublicTestKey(),
 			},
 			&ECDSAVerifier{
-				algorithm: ES256,
+				algorithm: jwa.ES256,
 				pubKey:    getECDSA256PublicTestKey(),
 			},
 			false,
 		},
 		{
-			"Must initialize ECDSAVerifier given valid key and ES384",
+			"Must not initialize ECDSAVerifier given a P-256 key and ES384",
 			args{
-				ES384,
+				jwa.ES384,
 				getECDSA256PublicTestKey(),
 			},
-			&ECDSAVerifier{
-				algorithm: ES384,
-				pubKey:    getECDSA256PublicTestKey(),
-			},
-			false,
+			nil,
+			true,
 		},
 		{
-			"Must initialize ECDSAVerifier given valid key and ES512",
+			"Must not initialize ECDSAVerifier given a P-256 key and ES512",
 			args{
-				ES512,
+				jwa.ES512,
 				getECDSA256PublicTestKey(),
 			},
-			&ECDSAVerifier{
-				algorithm: ES512,
-				pubKey:    getECDSA256PublicTestKey(),
-			},
-			false,
+			nil,
+			true,
 		},
 		{
 			"Must fail to initialize ECDSAVerifier given a nil key",
 			args{
-				ES256,
+				jwa.ES256,
 				nil,
 			},
 			nil,
@@ -198,7 +194,7 @@ func TestInitECDSAVerifier(t *testing.T) {
 		{
 			"Must fail to initialize ECDSAVerifier given an unexpected algorithm",
 			args{
-				RS256,
+				jwa.RS256,
 				getECDSA256PublicTestKey(),
 			},
 			nil,
@@ -235,7 +231,7 @@ func TestECDSASigner_Sign(t *testing.T) {
 		{
 			"Must sign payload using ES256 successfully",
 			&ECDSASigner{
-				algorithm: ES256,
+				algorithm: jwa.ES256,
 				prvKey:    getECDSA256PrivateTestKey(),
 				rng:       rand.Reader,
 			},
@@ -247,7 +243,7 @@ func TestECDSASigner_Sign(t *testing.T) {
 		{
 			"Must sign payload using ES384 successfully",
 			&ECDSASigner{
-				algorithm: ES384,
+				algorithm: jwa.ES384,
 				prvKey:    getECDSA256PrivateTestKey(),
 				rng:       rand.Reader,
 			},
@@ -259,7 +255,7 @@ func TestECDSASigner_Sign(t *testing.T) {
 		{
 			"Must sign payload using ES512 successfully",
 			&ECDSASigner{
-				algorithm: ES512,
+				algorithm: jwa.ES512,
 				prvKey:    getECDSA256PrivateTestKey(),
 				rng:       rand.Reader,
 			},
@@ -296,7 +292,7 @@ func TestECDSAVerifier_Verify(t *testing.T) {
 		{
 			"Must verify ES256 signature",
 			&ECDSAVerifier{
-				algorithm: ES256,
+				algorithm: jwa.ES256,
 				pubKey:    getECDSA256PublicTestKey(),
 			},
 			args{
@@ -310,7 +306,7 @@ func TestECDSAVerifier_Verify(t *testing.T) {
 		{
 			"Must verify ES512 signature",
 			&ECDSAVerifier{
-				algorithm: ES512,
+				algorithm: jwa.ES512,
 				pubKey:    getECDSA512PublicTestKey(),
 			},
 			args{
@@ -345,13 +341,13 @@ func TestECDSASigner_ECDSAVerifier_EndToEnd(t *testing.T) {
 	}
 	tests := []struct {
 		name      string
-		algorithm Algorithm
+		algorithm jwa.Algorithm
 		curve     elliptic.Curve
 		args      args
 	}{
 		{
 			"Must sign and verify using ES256",
-			ES256,
+			jwa.ES256,
 			elliptic.P256(),
 			args{
 				plaintext: plaintext,
@@ -359,7 +355,7 @@ func TestECDSASigner_ECDSAVerifier_EndToEnd(t *testing.T) {
 		},
 		{
 			"Must sign and verify using ES384",
-			ES384,
+			jwa.ES384,
 			elliptic.P384(),
 			args{
 				plaintext: plaintext,
@@ -367,7 +363,7 @@ func TestECDSASigner_ECDSAVerifier_EndToEnd(t *testing.T) {
 		},
 		{
 			"Must sign and verify using ES512",
-			ES512,
+			jwa.ES512,
 			elliptic.P521(),
 			args{
 				plaintext: plaintext,
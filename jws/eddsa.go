@@ -1,21 +1,23 @@
-package main
+package jws
 
 import (
 	"crypto/ed25519"
 	"crypto/rand"
 	"errors"
 	"io"
+
+	"github.com/georgejenkins/jwt/jwa"
 )
 
 // EdDSASigner contains configuration for signing JWSs using EdDSA + Edwards25519
 type EdDSASigner struct {
-	algorithm Algorithm
+	algorithm jwa.Algorithm
 	prvKey    *ed25519.PrivateKey
 	rng       io.Reader
 }
 
 // InitEdDSASigner initializes a new ECDSA family signer.
-func InitEdDSASigner(alg Algorithm, key *ed25519.PrivateKey) (*EdDSASigner, error) {
+func InitEdDSASigner(alg jwa.Algorithm, key *ed25519.PrivateKey) (*EdDSASigner, error) {
 	if nil == key {
 		return nil, errors.New("Cannot init EdDSASigner with empty key")
 	}
@@ -24,6 +26,10 @@ func InitEdDSASigner(alg Algorithm, key *ed25519.PrivateKey) (*EdDSASigner, erro
 		return nil, errors.New("Cannot init EdDSASigner with no algorithm")
 	}
 
+	if jwa.EdDSA != alg {
+		return nil, errors.New("Signing algorithm unexpected, must be: EdDSA")
+	}
+
 	return &EdDSASigner{
 		algorithm: alg,
 		prvKey:    key,
@@ -38,12 +44,12 @@ func (sv *EdDSASigner) Sign(plaintext []byte) ([]byte, error) {
 
 // EdDSAVerifier contains configuration for verifying JWSs using the ECDSA 256/384/512 family.
 type EdDSAVerifier struct {
-	algorithm Algorithm
+	algorithm jwa.Algorithm
 	pubKey    *ed25519.PublicKey
 }
 
 // InitEdDSAVerifier initializes a new ECDSA family signer.
-func InitEdDSAVerifier(alg Algorithm, key *ed25519.PublicKey) (*EdDSAVerifier, error) {
+func InitEdDSAVerifier(alg jwa.Algorithm, key *ed25519.PublicKey) (*EdDSAVerifier, error) {
 	if nil == key {
 		return nil, errors.New("Cannot init EdDSAVerifier with empty key")
 	}
@@ -52,7 +58,7 @@ func InitEdDSAVerifier(alg Algorithm, key *ed25519.PublicKey) (*EdDSAVerifier, e
 		return nil, errors.New("Cannot init EdDSAVerifier with no algorithm")
 	}
 
-	if EdDSA != alg {
+	if jwa.EdDSA != alg {
 		return nil, errors.New("Signing algorithm unexpected, must be: EdDSA")
 	}
 
@@ -1,10 +1,12 @@
-package main
+package jws
 
 import (
 	"crypto/ed25519"
 	"crypto/rand"
 	"reflect"
 	"testing"
+
+	"github.com/georgejenkins/jwt/jwa"
 )
 
 func getEdDSAPrivateTestKey(private string, public string) *ed25519.PrivateKey {
@@ -32,7 +34,7 @@ func TestEdDSAVerifier_Verify(t *testing.T) {
 		{
 			"EdDSAVerifier must verify TEST 1 - MESSAGE (length 0 bytes)",
 			&EdDSAVerifier{
-				algorithm: EdDSA,
+				algorithm: jwa.EdDSA,
 				pubKey:    getEdDSAPublicTestKey("d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a"),
 			},
 			args{
@@ -45,7 +47,7 @@ func TestEdDSAVerifier_Verify(t *testing.T) {
 		{
 			"EdDSAVerifier must verify TEST 2 - MESSAGE (length 1 byte)",
 			&EdDSAVerifier{
-				algorithm: EdDSA,
+				algorithm: jwa.EdDSA,
 				pubKey:    getEdDSAPublicTestKey("3d4017c3e843895a92b70aa74d1b7ebc9c982ccf2ec4968cc0cd55f12af4660c"),
 			},
 			args{
@@ -58,7 +60,7 @@ func TestEdDSAVerifier_Verify(t *testing.T) {
 		{
 			"EdDSAVerifier must verify TEST 3 - MESSAGE (length 2 bytes)",
 			&EdDSAVerifier{
-				algorithm: EdDSA,
+				algorithm: jwa.EdDSA,
 				pubKey:    getEdDSAPublicTestKey("fc51cd8e6218a1a38da47ed00230f0580816ed13ba3303ac5deb911548908025"),
 			},
 			args{
@@ -71,7 +73,7 @@ func TestEdDSAVerifier_Verify(t *testing.T) {
 		{
 			"EdDSAVerifier must verify TEST 4 - MESSAGE (length 1023 bytes)",
 			&EdDSAVerifier{
-				algorithm: EdDSA,
+				algorithm: jwa.EdDSA,
 				pubKey:    getEdDSAPublicTestKey("278117fc144c72340f67d0f2316e8386ceffbf2b2428c9c51fef7c597f1d426e"),
 			},
 			args{
@@ -110,7 +112,7 @@ func TestEdDSASigner_Sign(t *testing.T) {
 		{
 			"EdDSAVerifier must sign TEST 1 - MESSAGE (length 0 bytes)",
 			&EdDSASigner{
-				algorithm: EdDSA,
+				algorithm: jwa.EdDSA,
 				prvKey:    getEdDSAPrivateTestKey("9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60", "d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a"),
 			},
 			args{
@@ -122,7 +124,7 @@ func TestEdDSASigner_Sign(t *testing.T) {
 		{
 			"EdDSAVerifier must sign TEST 2 - MESSAGE (length 1 byte)",
 			&EdDSASigner{
-				algorithm: EdDSA,
+				algorithm: jwa.EdDSA,
 				prvKey:    getEdDSAPrivateTestKey("4ccd089b28ff96da9db6c346ec114e0f5b8a319f35aba624da8cf6ed4fb8a6fb", "3d4017c3e843895a92b70aa74d1b7ebc9c982ccf2ec4968cc0cd55f12af4660c"),
 			},
 			args{
@@ -134,7 +136,7 @@ func TestEdDSASigner_Sign(t *testing.T) {
 		{
 			"EdDSAVerifier must verify TEST 3 - MESSAGE (length 2 bytes)",
 			&EdDSASigner{
-				algorithm: EdDSA,
+				algorithm: jwa.EdDSA,
 				prvKey:    getEdDSAPrivateTestKey("c5aa8df43f9f837bedb7442f31dcb7b166d38535076f094b85ce3a2e0b4458f7", "fc51cd8e6218a1a38da47ed00230f0580816ed13ba3303ac5deb911548908025"),
 			},
 			args{
@@ -146,7 +148,7 @@ func TestEdDSASigner_Sign(t *testing.T) {
 		{
 			"EdDSAVerifier must verify TEST 4 - MESSAGE (length 1023 bytes)",
 			&EdDSASigner{
-				algorithm: EdDSA,
+				algorithm: jwa.EdDSA,
 				prvKey:    getEdDSAPrivateTestKey("f5e5767cf153319517630f226876b86c8160cc583bc013744c6bf255f5cc0ee5", "278117fc144c72340f67d0f2316e8386ceffbf2b2428c9c51fef7c597f1d426e"),
 			},
 			args{
@@ -180,12 +182,12 @@ func TestEdDSASigner_EdDSAVerifier_EndToEnd(t *testing.T) {
 	}
 	tests := []struct {
 		name      string
-		algorithm Algorithm
+		algorithm jwa.Algorithm
 		args      args
 	}{
 		{
 			"Must sign and verify using EdDSA",
-			EdDSA,
+			jwa.EdDSA,
 			args{
 				plaintext: plaintext,
 			},
@@ -232,7 +234,7 @@ func TestEdDSASigner_EdDSAVerifier_EndToEnd(t *testing.T) {
 
 func TestInitEdDSASigner(t *testing.T) {
 	type args struct {
-		alg Algorithm
+		alg jwa.Algorithm
 		key *ed25519.PrivateKey
 	}
 	tests := []struct {
@@ -0,0 +1,55 @@
+package jws
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// EntropyHealthChecker is implemented by random sources that can report
+// their own health, such as hardware generators with a status register.
+type EntropyHealthChecker interface {
+	CheckHealth() error
+}
+
+// entropySampleSize is the size of each sample read by CheckEntropy.
+const entropySampleSize = 64
+
+// CheckEntropy performs a startup self-test of a random source, as used by
+// signers whose signatures are only secure with good randomness (ECDSA and
+// RSA-PSS). It fails if the source errors, returns short reads, or is
+// stuck: returning a constant byte, or the same sample twice. Sources
+// implementing EntropyHealthChecker are also asked for their own health.
+//
+// The test catches broken sources, such as a stuck reader from a bad
+// /dev/urandom mount in a container. It can't prove a source is random.
+func CheckEntropy(rng io.Reader) error {
+	if nil == rng {
+		return errors.New("Random source cannot be nil")
+	}
+
+	if checker, ok := rng.(EntropyHealthChecker); ok {
+		if err := checker.CheckHealth(); nil != err {
+			return fmt.Errorf("Random source is unhealthy: %v", err)
+		}
+	}
+
+	first := make([]byte, entropySampleSize)
+	second := make([]byte, entropySampleSize)
+	for _, sample := range [][]byte{first, second} {
+		if _, err := io.ReadFull(rng, sample); nil != err {
+			return fmt.Errorf("Random source failed to read: %v", err)
+		}
+
+		if bytes.Count(sample, sample[:1]) == len(sample) {
+			return errors.New("Random source is stuck on a constant value")
+		}
+	}
+
+	if bytes.Equal(first, second) {
+		return errors.New("Random source is repeating its output")
+	}
+
+	return nil
+}
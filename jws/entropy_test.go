@@ -0,0 +1,84 @@
+package jws
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"testing"
+)
+
+// stuckReader returns the same bytes on every read.
+type stuckReader struct {
+	sample []byte
+}
+
+func (r *stuckReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = r.sample[i%len(r.sample)]
+	}
+	return len(p), nil
+}
+
+// unhealthyReader reports itself as unhealthy.
+type unhealthyReader struct {
+	io.Reader
+}
+
+func (r unhealthyReader) CheckHealth() error {
+	return errors.New("Hardware generator failure")
+}
+
+func TestCheckEntropy(t *testing.T) {
+	tests := []struct {
+		name    string
+		rng     io.Reader
+		wantErr bool
+	}{
+		{"Must pass crypto/rand", rand.Reader, false},
+		{"Must fail a nil source", nil, true},
+		{"Must fail a source of zeros", bytes.NewReader(make([]byte, 1024)), true},
+		{"Must fail a source repeating its output", &stuckReader{sample: []byte{1, 2, 3, 4, 5, 6, 7, 8}}, true},
+		{"Must fail a source that runs dry", bytes.NewReader([]byte{1, 2, 3}), true},
+		{"Must fail a source reporting itself unhealthy", unhealthyReader{rand.Reader}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckEntropy(tt.rng); (err != nil) != tt.wantErr {
+				t.Errorf("CheckEntropy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestECDSASigner_UseRand(t *testing.T) {
+	signer, _ := InitECDSASigner("ES256", getECDSA256PrivateTestKey())
+
+	if err := signer.UseRand(bytes.NewReader(make([]byte, 1024))); nil == err {
+		t.Errorf("ECDSASigner.UseRand() expected error for a stuck source")
+	}
+
+	// The signer must draw from the configured source.
+	counting := &countingReader{Reader: rand.Reader}
+	if err := signer.UseRand(counting); nil != err {
+		t.Fatalf("ECDSASigner.UseRand() error = %v", err)
+	}
+	before := counting.n
+	if _, err := signer.Sign(plaintext); nil != err {
+		t.Fatalf("ECDSASigner.Sign() error = %v", err)
+	}
+	if counting.n == before {
+		t.Errorf("ECDSASigner.Sign() did not use the configured random source")
+	}
+}
+
+type countingReader struct {
+	io.Reader
+	n int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.n += n
+	return n, err
+}
@@ -1,4 +1,4 @@
-package main
+package jws
 
 import (
 	"crypto/hmac"
@@ -8,17 +8,19 @@ import (
 	"errors"
 	"fmt"
 	"hash"
+
+	"github.com/georgejenkins/jwt/jwa"
 )
 
 // HMACSignerVerifier contains configuration for signing
 // and verifying JWSs using the HS256/384/512 family.
 type HMACSignerVerifier struct {
-	algorithm Algorithm
+	algorithm jwa.Algorithm
 	key       []byte
 }
 
 // InitHMACSignerVerifier initializes a new HMAC signer/verifier.
-func InitHMACSignerVerifier(alg Algorithm, key []byte) (*HMACSignerVerifier, error) {
+func InitHMACSignerVerifier(alg jwa.Algorithm, key []byte) (*HMACSignerVerifier, error) {
 	if len(key) == 0 {
 		return nil, errors.New("Cannot initialize HMACSignerVerifier with an empty key")
 	}
@@ -27,7 +29,7 @@ func InitHMACSignerVerifier(alg Algorithm, key []byte) (*HMACSignerVerifier, err
 		return nil, errors.New("Cannot initialize HMACSignerVerifier with no algorithm")
 	}
 
-	if HS256 != alg && HS384 != alg && HS512 != alg {
+	if jwa.HS256 != alg && jwa.HS384 != alg && jwa.HS512 != alg {
 		return nil, errors.New("Signing algorithm unexpected, must be one of: HS256, HS384, HS512")
 	}
 
@@ -72,13 +74,34 @@ func (sv *HMACSignerVerifier) Verify(plaintext []byte, signature []byte) (bool,
 	return (subtle.ConstantTimeCompare(signature, output) == 1), nil
 }
 
+// NewHash returns a keyed hash for the signing input, see HashSigner.
+func (sv *HMACSignerVerifier) NewHash() (hash.Hash, error) {
+	return sv.initHash()
+}
+
+// SignHash returns the MAC of the signing input written to a hash
+// returned by NewHash.
+func (sv *HMACSignerVerifier) SignHash(h hash.Hash) ([]byte, error) {
+	return h.Sum(nil), nil
+}
+
+// VerifyHash verifies the MAC of the signing input written to a hash
+// returned by NewHash.
+func (sv *HMACSignerVerifier) VerifyHash(h hash.Hash, signature []byte) (bool, error) {
+	if len(signature) == 0 {
+		return false, errors.New("Signature cannot be empty")
+	}
+
+	return (subtle.ConstantTimeCompare(signature, h.Sum(nil)) == 1), nil
+}
+
 func (sv *HMACSignerVerifier) initHash() (hash.Hash, error) {
 	switch sv.algorithm {
-	case HS256:
+	case jwa.HS256:
 		return hmac.New(sha256.New, sv.key), nil
-	case HS384:
+	case jwa.HS384:
 		return hmac.New(sha512.New384, sv.key), nil
-	case HS512:
+	case jwa.HS512:
 		return hmac.New(sha512.New, sv.key), nil
 	}
 
@@ -1,4 +1,4 @@
-package main
+package jws
 
 import (
 	"crypto/hmac"
@@ -7,6 +7,8 @@ import (
 	"hash"
 	"reflect"
 	"testing"
+
+	"github.com/georgejenkins/jwt/jwa"
 )
 
 // Key and Payload from RFC 7515 - Example JWS Using HMAC SHA-256
@@ -20,7 +22,7 @@ var examplePayload = []byte("eyJ0eXAiOiJKV1QiLA0KICJhbGciOiJIUzI1NiJ9.eyJpc3MiOi
 // properly and handles misconfigured input an initialization time.
 func TestInitHMACSignerVerifier(t *testing.T) {
 	type args struct {
-		alg Algorithm
+		alg jwa.Algorithm
 		key []byte
 	}
 	tests := []struct {
@@ -32,11 +34,11 @@ func TestInitHMACSignerVerifier(t *testing.T) {
 		{
 			"Must initialize HMACSignerVerifier given valid key and HS256",
 			args{
-				HS256,
+				jwa.HS256,
 				exampleKey,
 			},
 			&HMACSignerVerifier{
-				algorithm: HS256,
+				algorithm: jwa.HS256,
 				key:       exampleKey,
 			},
 			false,
@@ -44,11 +46,11 @@ func TestInitHMACSignerVerifier(t *testing.T) {
 		{
 			"Must initialize HMACSignerVerifier given valid key and HS386",
 			args{
-				HS384,
+				jwa.HS384,
 				exampleKey,
 			},
 			&HMACSignerVerifier{
-				algorithm: HS384,
+				algorithm: jwa.HS384,
 				key:       exampleKey,
 			},
 			false,
@@ -56,11 +58,11 @@ func TestInitHMACSignerVerifier(t *testing.T) {
 		{
 			"Must initialize HMACSignerVerifier given valid key and HS512",
 			args{
-				HS512,
+				jwa.HS512,
 				exampleKey,
 			},
 			&HMACSignerVerifier{
-				algorithm: HS512,
+				algorithm: jwa.HS512,
 				key:       exampleKey,
 			},
 			false,
@@ -68,7 +70,7 @@ func TestInitHMACSignerVerifier(t *testing.T) {
 		{
 			"Must fail to initialize HMACSignerVerifier given empty valid key",
 			args{
-				HS512,
+				jwa.HS512,
 				nil,
 			},
 			nil,
@@ -77,7 +79,7 @@ func TestInitHMACSignerVerifier(t *testing.T) {
 		{
 			"Must fail to initialize HMACSignerVerifier given an unexpected algorithm",
 			args{
-				RS256,
+				jwa.RS256,
 				exampleKey,
 			},
 			nil,
@@ -110,7 +112,7 @@ func TestHMACSignerVerifier_initHash(t *testing.T) {
 		{
 			"Must initialize hash with HS256",
 			&HMACSignerVerifier{
-				algorithm: HS256,
+				algorithm: jwa.HS256,
 				key:       exampleKey,
 			},
 			hmac.New(sha256.New, exampleKey),
@@ -119,7 +121,7 @@ func TestHMACSignerVerifier_initHash(t *testing.T) {
 		{
 			"Must initialize hash with HS384",
 			&HMACSignerVerifier{
-				algorithm: HS384,
+				algorithm: jwa.HS384,
 				key:       exampleKey,
 			},
 			hmac.New(sha512.New384, exampleKey),
@@ -128,7 +130,7 @@ func TestHMACSignerVerifier_initHash(t *testing.T) {
 		{
 			"Must initialize hash with HS512",
 			&HMACSignerVerifier{
-				algorithm: HS512,
+				algorithm: jwa.HS512,
 				key:       exampleKey,
 			},
 			hmac.New(sha512.New, exampleKey),
@@ -137,7 +139,7 @@ func TestHMACSignerVerifier_initHash(t *testing.T) {
 		{
 			"Must fail to initialize hash with unknown algorithm",
 			&HMACSignerVerifier{
-				algorithm: RS256,
+				algorithm: jwa.RS256,
 				key:       exampleKey,
 			},
 			nil,
@@ -176,7 +178,7 @@ func TestHMACSignerVerifier_Sign(t *testing.T) {
 			// https://tools.ietf.org/html/rfc7515#appendix-A.1.1
 			"Must sign payload using HS256",
 			&HMACSignerVerifier{
-				algorithm: HS256,
+				algorithm: jwa.HS256,
 				key:       exampleKey,
 			},
 			args{
@@ -188,7 +190,7 @@ func TestHMACSignerVerifier_Sign(t *testing.T) {
 		{
 			"Must sign payload using HS384",
 			&HMACSignerVerifier{
-				algorithm: HS384,
+				algorithm: jwa.HS384,
 				key:       exampleKey,
 			},
 			args{
@@ -200,7 +202,7 @@ func TestHMACSignerVerifier_Sign(t *testing.T) {
 		{
 			"Must sign payload using HS512",
 			&HMACSignerVerifier{
-				algorithm: HS512,
+				algorithm: jwa.HS512,
 				key:       exampleKey,
 			},
 			args{
@@ -212,7 +214,7 @@ func TestHMACSignerVerifier_Sign(t *testing.T) {
 		{
 			"Must throw on signing an empty payload",
 			&HMACSignerVerifier{
-				algorithm: HS512,
+				algorithm: jwa.HS512,
 				key:       exampleKey,
 			},
 			args{
@@ -253,7 +255,7 @@ func TestHMACSignerVerifier_Verify(t *testing.T) {
 		{
 			"Must validate HS256 signature",
 			&HMACSignerVerifier{
-				algorithm: HS256,
+				algorithm: jwa.HS256,
 				key:       exampleKey,
 			},
 			args{
@@ -266,7 +268,7 @@ func TestHMACSignerVerifier_Verify(t *testing.T) {
 		{
 			"Must validate HS384 signature",
 			&HMACSignerVerifier{
-				algorithm: HS384,
+				algorithm: jwa.HS384,
 				key:       exampleKey,
 			},
 			args{
@@ -279,7 +281,7 @@ func TestHMACSignerVerifier_Verify(t *testing.T) {
 		{
 			"Must validate HS512 signature",
 			&HMACSignerVerifier{
-				algorithm: HS512,
+				algorithm: jwa.HS512,
 				key:       exampleKey,
 			},
 			args{
@@ -292,7 +294,7 @@ func TestHMACSignerVerifier_Verify(t *testing.T) {
 		{
 			"Must validate fail to validate incorrect HS256 signature",
 			&HMACSignerVerifier{
-				algorithm: HS256,
+				algorithm: jwa.HS256,
 				key:       exampleKey,
 			},
 			args{
@@ -305,7 +307,7 @@ func TestHMACSignerVerifier_Verify(t *testing.T) {
 		{
 			"Must validate fail to validate incorrect  HS384 signature",
 			&HMACSignerVerifier{
-				algorithm: HS384,
+				algorithm: jwa.HS384,
 				key:       exampleKey,
 			},
 			args{
@@ -318,7 +320,7 @@ func TestHMACSignerVerifier_Verify(t *testing.T) {
 		{
 			"Must validate fail to validate incorrect  HS512 signature",
 			&HMACSignerVerifier{
-				algorithm: HS512,
+				algorithm: jwa.HS512,
 				key:       exampleKey,
 			},
 			args{
@@ -331,7 +333,7 @@ func TestHMACSignerVerifier_Verify(t *testing.T) {
 		{
 			"Must validate fail to validate empty plaintext",
 			&HMACSignerVerifier{
-				algorithm: HS256,
+				algorithm: jwa.HS256,
 				key:       exampleKey,
 			},
 			args{
@@ -344,7 +346,7 @@ func TestHMACSignerVerifier_Verify(t *testing.T) {
 		{
 			"Must validate fail to validate empty signature",
 			&HMACSignerVerifier{
-				algorithm: HS256,
+				algorithm: jwa.HS256,
 				key:       exampleKey,
 			},
 			args{
@@ -378,7 +380,7 @@ func TestHMACSignerVerifier_EndToEnd(t *testing.T) {
 	testPayload := plaintext
 
 	// Initialize the signer/verifier with a test key
-	sv, err := InitHMACSignerVerifier(HS256, testKeyBytes)
+	sv, err := InitHMACSignerVerifier(jwa.HS256, testKeyBytes)
 	if nil != err {
 		t.Errorf("HMACSignerVerifier End To End failed to initialize: %v", err)
 	}
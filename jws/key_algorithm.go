@@ -0,0 +1,82 @@
+package jws
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+
+	"github.com/georgejenkins/jwt/jwa"
+)
+
+// ErrAlgorithmKeyMismatch is returned when an algorithm is used with a key
+// of a type belonging to another algorithm family.
+var ErrAlgorithmKeyMismatch = errors.New("Algorithm does not match the key type")
+
+// AlgorithmChecker is implemented by verifiers that can check the 'alg' of
+// a token is of the family of their key, independent of the algorithm
+// they were initialized with.
+type AlgorithmChecker interface {
+	CheckAlgorithm(alg jwa.Algorithm) error
+}
+
+// CheckKeyAlgorithm returns an error wrapping ErrAlgorithmKeyMismatch
+// unless the algorithm belongs to the family of the key: RS and PS for
+// RSA keys, ES on the key's curve for ECDSA keys, EdDSA for Ed25519 keys,
+// and HS for []byte secrets.
+func CheckKeyAlgorithm(alg jwa.Algorithm, key interface{}) error {
+	var match bool
+	switch k := key.(type) {
+	case *rsa.PublicKey, *rsa.PrivateKey:
+		_, err := getHashAlgorithm(alg)
+		match = nil == err
+	case *ecdsa.PublicKey:
+		_, curve, err := getExpectedKeyParameters(alg)
+		match = nil == err && curve.Params().Name == k.Params().Name
+	case *ecdsa.PrivateKey:
+		_, curve, err := getExpectedKeyParameters(alg)
+		match = nil == err && curve.Params().Name == k.Params().Name
+	case ed25519.PublicKey, *ed25519.PublicKey, ed25519.PrivateKey, *ed25519.PrivateKey:
+		match = jwa.EdDSA == alg
+	case []byte:
+		match = jwa.HS256 == alg || jwa.HS384 == alg || jwa.HS512 == alg
+	default:
+		return fmt.Errorf("Unsupported key type %T", key)
+	}
+
+	if !match {
+		return fmt.Errorf("%w: cannot use %s with key type %T", ErrAlgorithmKeyMismatch, alg, key)
+	}
+
+	return nil
+}
+
+// CheckAlgorithm checks the algorithm is RS or PS.
+func (v *RSAVerifier) CheckAlgorithm(alg jwa.Algorithm) error {
+	return CheckKeyAlgorithm(alg, v.pubKey)
+}
+
+// CheckAlgorithm checks the algorithm is ES on the curve of the key.
+func (v *ECDSAVerifier) CheckAlgorithm(alg jwa.Algorithm) error {
+	return CheckKeyAlgorithm(alg, v.pubKey)
+}
+
+// CheckAlgorithm checks the algorithm is EdDSA.
+func (v *EdDSAVerifier) CheckAlgorithm(alg jwa.Algorithm) error {
+	return CheckKeyAlgorithm(alg, v.pubKey)
+}
+
+// CheckAlgorithm checks the algorithm is HS.
+func (sv *HMACSignerVerifier) CheckAlgorithm(alg jwa.Algorithm) error {
+	return CheckKeyAlgorithm(alg, sv.key)
+}
+
+// CheckAlgorithm checks the algorithm is none.
+func (sv *NoneSignerVerifier) CheckAlgorithm(alg jwa.Algorithm) error {
+	if jwa.None != alg {
+		return fmt.Errorf("%w: cannot use %s without a key", ErrAlgorithmKeyMismatch, alg)
+	}
+
+	return nil
+}
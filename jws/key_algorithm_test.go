@@ -0,0 +1,73 @@
+package jws
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/georgejenkins/jwt/jwa"
+)
+
+func TestCheckKeyAlgorithm(t *testing.T) {
+	edPublic, _, _ := ed25519.GenerateKey(rand.Reader)
+
+	tests := []struct {
+		name    string
+		alg     jwa.Algorithm
+		key     interface{}
+		wantErr bool
+	}{
+		{"Must allow RS256 with an RSA key", jwa.RS256, getRSAPublicTestKey(), false},
+		{"Must allow PS512 with an RSA key", jwa.PS512, getRSAPrivateTestKey(), false},
+		{"Must not allow HS256 with an RSA key", jwa.HS256, getRSAPublicTestKey(), true},
+		{"Must not allow ES256 with an RSA key", jwa.ES256, getRSAPublicTestKey(), true},
+		{"Must allow ES384 with a P-384 key", jwa.ES384, getECDSA384PublicTestKey(), false},
+		{"Must not allow ES256 with a P-384 key", jwa.ES256, getECDSA384PrivateTestKey(), true},
+		{"Must not allow RS256 with an ECDSA key", jwa.RS256, getECDSA256PublicTestKey(), true},
+		{"Must allow EdDSA with an Ed25519 key", jwa.EdDSA, &edPublic, false},
+		{"Must not allow ES256 with an Ed25519 key", jwa.ES256, edPublic, true},
+		{"Must allow HS512 with a secret", jwa.HS512, exampleKey, false},
+		{"Must not allow RS256 with a secret", jwa.RS256, exampleKey, true},
+		{"Must not allow none with a secret", jwa.None, exampleKey, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckKeyAlgorithm(tt.alg, tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckKeyAlgorithm() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if nil != err && !errors.Is(err, ErrAlgorithmKeyMismatch) {
+				t.Errorf("CheckKeyAlgorithm() error = %v, want ErrAlgorithmKeyMismatch", err)
+			}
+		})
+	}
+}
+
+func TestAlgorithmChecker(t *testing.T) {
+	hmacSV, _ := InitHMACSignerVerifier(jwa.HS256, exampleKey)
+	rsaVerifier, _ := InitRSAVerifier(jwa.RS256, getRSAPublicTestKey())
+	ecVerifier, _ := InitECDSAVerifier(jwa.ES256, getECDSA256PublicTestKey())
+	noneSV, _ := InitNoneSignerVerifier(jwa.None)
+
+	tests := []struct {
+		name     string
+		verifier AlgorithmChecker
+		alg      jwa.Algorithm
+		wantErr  bool
+	}{
+		{"Must check HS384 against an HS256 verifier", hmacSV, jwa.HS384, false},
+		{"Must reject RS256 against an HMAC verifier", hmacSV, jwa.RS256, true},
+		{"Must check PS256 against an RS256 verifier", rsaVerifier, jwa.PS256, false},
+		{"Must reject HS256 against an RSA verifier", rsaVerifier, jwa.HS256, true},
+		{"Must reject ES512 against a P-256 verifier", ecVerifier, jwa.ES512, true},
+		{"Must reject HS256 against a none verifier", noneSV, jwa.HS256, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.verifier.CheckAlgorithm(tt.alg); (err != nil) != tt.wantErr {
+				t.Errorf("CheckAlgorithm() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,20 +1,22 @@
-package main
+package jws
 
 import (
 	"fmt"
+
+	"github.com/georgejenkins/jwt/jwa"
 )
 
 // NoneSignerVerifier provides support for the 'None' alg type.
 // NoneSignerVerifier really does nothing, but due to the insecure
 // nature of this 'algorithm', there is a benefit in being explicit.
 type NoneSignerVerifier struct {
-	algorithm Algorithm
+	algorithm jwa.Algorithm
 	key       []byte
 }
 
 // InitNoneSignerVerifier initializes a new 'None' signer/verifier.
-func InitNoneSignerVerifier(alg Algorithm) (*NoneSignerVerifier, error) {
-	if None != alg {
+func InitNoneSignerVerifier(alg jwa.Algorithm) (*NoneSignerVerifier, error) {
+	if jwa.None != alg {
 		return nil, fmt.Errorf("Expected alg to be None but received %v", alg)
 	}
 	return &NoneSignerVerifier{}, nil
@@ -1,13 +1,15 @@
-package main
+package jws
 
 import (
 	"reflect"
 	"testing"
+
+	"github.com/georgejenkins/jwt/jwa"
 )
 
 func TestInitNoneSignerVerifier(t *testing.T) {
 	type args struct {
-		alg Algorithm
+		alg jwa.Algorithm
 	}
 	tests := []struct {
 		name    string
@@ -18,7 +20,7 @@ func TestInitNoneSignerVerifier(t *testing.T) {
 		{
 			"Must InitNoneSignerVerifier given None",
 			args{
-				alg: None,
+				alg: jwa.None,
 			},
 			&NoneSignerVerifier{},
 			false,
@@ -26,7 +28,7 @@ func TestInitNoneSignerVerifier(t *testing.T) {
 		{
 			"Must fail to InitNoneSignerVerifier given invalid algorithm",
 			args{
-				alg: RS256,
+				alg: jwa.RS256,
 			},
 			nil,
 			true,
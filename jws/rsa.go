@@ -1,4 +1,4 @@
-package main
+package jws
 
 import (
 	"crypto"
@@ -6,20 +6,23 @@ import (
 	"crypto/rsa"
 	"errors"
 	"fmt"
+	"hash"
 	"io"
+
+	"github.com/georgejenkins/jwt/jwa"
 )
 
 // RSASigner contains configuration for signing JWSs using the
 // RS/PS 256/384/512 family.
 type RSASigner struct {
-	algorithm Algorithm
+	algorithm jwa.Algorithm
 	hash      crypto.Hash
 	prvKey    *rsa.PrivateKey
 	rng       io.Reader
 }
 
 // InitRSASigner initializes a new RSA family signer.
-func InitRSASigner(alg Algorithm, key *rsa.PrivateKey) (*RSASigner, error) {
+func InitRSASigner(alg jwa.Algorithm, key *rsa.PrivateKey) (*RSASigner, error) {
 	if nil == key {
 		return nil, errors.New("Cannot init RSASigner with empty key")
 	}
@@ -28,8 +31,8 @@ func InitRSASigner(alg Algorithm, key *rsa.PrivateKey) (*RSASigner, error) {
 		return nil, errors.New("Cannot init RSASigner with no algorithm")
 	}
 
-	if RS256 != alg && RS384 != alg && RS512 != alg &&
-		PS256 != alg && PS384 != alg && PS512 != alg {
+	if jwa.RS256 != alg && jwa.RS384 != alg && jwa.RS512 != alg &&
+		jwa.PS256 != alg && jwa.PS384 != alg && jwa.PS512 != alg {
 		return nil, errors.New("Signing algorithm unexpected, must be one of: RS256, RS384, RS512, PS256, PS384, PS512")
 	}
 
@@ -53,12 +56,28 @@ func (sv *RSASigner) Sign(plaintext []byte) ([]byte, error) {
 		return nil, err
 	}
 
+	return sv.signDigest(hash)
+}
+
+// NewHash returns a hash for the signing input, see HashSigner.
+func (sv *RSASigner) NewHash() (hash.Hash, error) {
+	return newHash(sv.algorithm)
+}
+
+// SignHash signs the signing input written to a hash returned by NewHash.
+func (sv *RSASigner) SignHash(h hash.Hash) ([]byte, error) {
+	return sv.signDigest(h.Sum(nil))
+}
+
+// signDigest signs the hash of a payload.
+func (sv *RSASigner) signDigest(hash []byte) ([]byte, error) {
 	var signature []byte
+	var err error
 
 	switch sv.algorithm {
-	case RS256, RS384, RS512:
+	case jwa.RS256, jwa.RS384, jwa.RS512:
 		signature, err = rsa.SignPKCS1v15(sv.rng, sv.prvKey, sv.hash, hash)
-	case PS256, PS384, PS512:
+	case jwa.PS256, jwa.PS384, jwa.PS512:
 		signature, err = rsa.SignPSS(sv.rng, sv.prvKey, sv.hash, hash, nil)
 	}
 
@@ -69,15 +88,26 @@ func (sv *RSASigner) Sign(plaintext []byte) ([]byte, error) {
 	return signature, nil
 }
 
+// UseRand sets the random source used for signing, after checking its
+// health with CheckEntropy.
+func (sv *RSASigner) UseRand(rng io.Reader) error {
+	if err := CheckEntropy(rng); nil != err {
+		return err
+	}
+
+	sv.rng = rng
+	return nil
+}
+
 // RSAVerifier contains configuration for verifying JWSs using the RS/PS 256/384/512 family.
 type RSAVerifier struct {
-	algorithm Algorithm
+	algorithm jwa.Algorithm
 	hash      crypto.Hash
 	pubKey    *rsa.PublicKey
 }
 
 // InitRSAVerifier initializes a new RS-family signer.
-func InitRSAVerifier(alg Algorithm, key *rsa.PublicKey) (*RSAVerifier, error) {
+func InitRSAVerifier(alg jwa.Algorithm, key *rsa.PublicKey) (*RSAVerifier, error) {
 	if nil == key {
 		return nil, errors.New("Cannot init RSAVerifier with empty key")
 	}
@@ -106,11 +136,29 @@ func (sv *RSAVerifier) Verify(plaintext []byte, signature []byte) (bool, error)
 		return false, err
 	}
 
+	return sv.verifyDigest(hash, signature)
+}
+
+// NewHash returns a hash for the signing input, see HashVerifier.
+func (sv *RSAVerifier) NewHash() (hash.Hash, error) {
+	return newHash(sv.algorithm)
+}
+
+// VerifyHash verifies the signature over the signing input written to a
+// hash returned by NewHash.
+func (sv *RSAVerifier) VerifyHash(h hash.Hash, signature []byte) (bool, error) {
+	return sv.verifyDigest(h.Sum(nil), signature)
+}
+
+// verifyDigest verifies the signature over the hash of a payload.
+func (sv *RSAVerifier) verifyDigest(hash []byte, signature []byte) (bool, error) {
+	var err error
+
 	// Verification functions return an error on validation failure.
 	switch sv.algorithm {
-	case RS256, RS384, RS512:
+	case jwa.RS256, jwa.RS384, jwa.RS512:
 		err = rsa.VerifyPKCS1v15(sv.pubKey, sv.hash, hash, signature)
-	case PS256, PS384, PS512:
+	case jwa.PS256, jwa.PS384, jwa.PS512:
 		err = rsa.VerifyPSS(sv.pubKey, sv.hash, hash, signature, nil)
 	}
 
@@ -122,13 +170,13 @@ func (sv *RSAVerifier) Verify(plaintext []byte, signature []byte) (bool, error)
 }
 
 // getHashAlgorithm returns the crypto hash algorithm suitable for the JWS type
-func getHashAlgorithm(alg Algorithm) (crypto.Hash, error) {
+func getHashAlgorithm(alg jwa.Algorithm) (crypto.Hash, error) {
 	switch alg {
-	case RS256, PS256:
+	case jwa.RS256, jwa.PS256:
 		return crypto.SHA256, nil
-	case RS384, PS384:
+	case jwa.RS384, jwa.PS384:
 		return crypto.SHA384, nil
-	case RS512, PS512:
+	case jwa.RS512, jwa.PS512:
 		return crypto.SHA512, nil
 	}
 	return 0, fmt.Errorf("No compatible hash function found for algorithm type %s", alg)
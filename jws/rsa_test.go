@@ -1,4 +1,4 @@
-package main
+package jws
 
 import (
 	"crypto"
@@ -7,6 +7,8 @@ import (
 	"math/big"
 	"reflect"
 	"testing"
+
+	"github.com/georgejenkins/jwt/jwa"
 )
 
 func getRSAPublicTestKey() *rsa.PublicKey {
@@ -31,7 +33,7 @@ func TestInitRSASigner(t *testing.T) {
 	testKey2048, _ := rsa.GenerateKey(rand.Reader, 2048)
 
 	type args struct {
-		alg Algorithm
+		alg jwa.Algorithm
 		key *rsa.PrivateKey
 	}
 	tests := []struct {
@@ -43,11 +45,11 @@ func TestInitRSASigner(t *testing.T) {
 		{
 			"Must initialize RSASigner with valid RSA key + RS256",
 			args{
-				RS256,
+				jwa.RS256,
 				testKey2048,
 			},
 			&RSASigner{
-				algorithm: RS256,
+				algorithm: jwa.RS256,
 				hash:      crypto.SHA256,
 				prvKey:    testKey2048,
 				rng:       rand.Reader,
@@ -57,11 +59,11 @@ func TestInitRSASigner(t *testing.T) {
 		{
 			"Must initialize RSASigner with valid RSA key + RS384",
 			args{
-				RS384,
+				jwa.RS384,
 				testKey2048,
 			},
 			&RSASigner{
-				algorithm: RS384,
+				algorithm: jwa.RS384,
 				hash:      crypto.SHA384,
 				prvKey:    testKey2048,
 				rng:       rand.Reader,
@@ -71,11 +73,11 @@ func TestInitRSASigner(t *testing.T) {
 		{
 			"Must initialize RSASigner with valid RSA key + RS512",
 			args{
-				RS512,
+				jwa.RS512,
 				testKey2048,
 			},
 			&RSASigner{
-				algorithm: RS512,
+				algorithm: jwa.RS512,
 				hash:      crypto.SHA512,
 				prvKey:    testKey2048,
 				rng:       rand.Reader,
@@ -85,11 +87,11 @@ func TestInitRSASigner(t *testing.T) {
 		{
 			"Must initialize RSASigner with valid RSA key + PS256",
 			args{
-				PS256,
+				jwa.PS256,
 				testKey2048,
 			},
 			&RSASigner{
-				algorithm: PS256,
+				algorithm: jwa.PS256,
 				hash:      crypto.SHA256,
 				prvKey:    testKey2048,
 				rng:       rand.Reader,
@@ -99,11 +101,11 @@ func TestInitRSASigner(t *testing.T) {
 		{
 			"Must initialize RSASigner with valid RSA key + PS384",
 			args{
-				PS384,
+				jwa.PS384,
 				testKey2048,
 			},
 			&RSASigner{
-				algorithm: PS384,
+				algorithm: jwa.PS384,
 				hash:      crypto.SHA384,
 				prvKey:    testKey2048,
 				rng:       rand.Reader,
@@ -113,11 +115,11 @@ func TestInitRSASigner(t *testing.T) {
 		{
 			"Must initialize RSASigner with valid RSA key + PS512",
 			args{
-				PS512,
+				jwa.PS512,
 				testKey2048,
 			},
 			&RSASigner{
-				algorithm: PS512,
+				algorithm: jwa.PS512,
 				hash:      crypto.SHA512,
 				prvKey:    testKey2048,
 				rng:       rand.Reader,
@@ -127,7 +129,7 @@ func TestInitRSASigner(t *testing.T) {
 		{
 			"Must fail to initialize RSASigner with nil RSA key",
 			args{
-				RS256,
+				jwa.RS256,
 				nil,
 			},
 			nil,
@@ -136,7 +138,7 @@ func TestInitRSASigner(t *testing.T) {
 		{
 			"Must fail to initialize RSASigner with unexpected algorithm",
 			args{
-				HS256,
+				jwa.HS256,
 				nil,
 			},
 			nil,
@@ -171,7 +173,7 @@ func TestRSASigner_Sign(t *testing.T) {
 		{
 			"Must sign payload successfully with RS256",
 			&RSASigner{
-				algorithm: RS256,
+				algorithm: jwa.RS256,
 				hash:      crypto.SHA256,
 				prvKey:    getRSAPrivateTestKey(),
 				rng:       rand.Reader,
@@ -185,7 +187,7 @@ func TestRSASigner_Sign(t *testing.T) {
 		{
 			"Must sign payload successfully with RS384",
 			&RSASigner{
-				algorithm: RS384,
+				algorithm: jwa.RS384,
 				hash:      crypto.SHA384,
 				prvKey:    getRSAPrivateTestKey(),
 				rng:       rand.Reader,
@@ -199,7 +201,7 @@ func TestRSASigner_Sign(t *testing.T) {
 		{
 			"Must sign payload successfully with RS512",
 			&RSASigner{
-				algorithm: RS512,
+				algorithm: jwa.RS512,
 				hash:      crypto.SHA512,
 				prvKey:    getRSAPrivateTestKey(),
 				rng:       rand.Reader,
@@ -227,7 +229,7 @@ func TestRSASigner_Sign(t *testing.T) {
 
 func TestInitRSAVerifier(t *testing.T) {
 	type args struct {
-		alg Algorithm
+		alg jwa.Algorithm
 		key *rsa.PublicKey
 	}
 	tests := []struct {
@@ -239,11 +241,11 @@ func TestInitRSAVerifier(t *testing.T) {
 		{
 			"Must initialize RSAVerifier with valid RSA public key + RS256",
 			args{
-				RS256,
+				jwa.RS256,
 				getRSAPublicTestKey(),
 			},
 			&RSAVerifier{
-				algorithm: RS256,
+				algorithm: jwa.RS256,
 				hash:      crypto.SHA256,
 				pubKey:    getRSAPublicTestKey(),
 			},
@@ -252,11 +254,11 @@ func TestInitRSAVerifier(t *testing.T) {
 		{
 			"Must initialize RSAVerifier with valid RSA public key + RS384",
 			args{
-				RS384,
+				jwa.RS384,
 				getRSAPublicTestKey(),
 			},
 			&RSAVerifier{
-				algorithm: RS384,
+				algorithm: jwa.RS384,
 				hash:      crypto.SHA384,
 				pubKey:    getRSAPublicTestKey(),
 			},
@@ -265,11 +267,11 @@ func TestInitRSAVerifier(t *testing.T) {
 		{
 			"Must initialize RSAVerifier with valid RSA public key + RS512",
 			args{
-				RS512,
+				jwa.RS512,
 				getRSAPublicTestKey(),
 			},
 			&RSAVerifier{
-				algorithm: RS512,
+				algorithm: jwa.RS512,
 				hash:      crypto.SHA512,
 				pubKey:    getRSAPublicTestKey(),
 			},
@@ -278,11 +280,11 @@ func TestInitRSAVerifier(t *testing.T) {
 		{
 			"Must initialize RSAVerifier with valid RSA public key + PS256",
 			args{
-				PS256,
+				jwa.PS256,
 				getRSAPublicTestKey(),
 			},
 			&RSAVerifier{
-				algorithm: PS256,
+				algorithm: jwa.PS256,
 				hash:      crypto.SHA256,
 				pubKey:    getRSAPublicTestKey(),
 			},
@@ -291,11 +293,11 @@ func TestInitRSAVerifier(t *testing.T) {
 		{
 			"Must initialize RSAVerifier with valid RSA public key + PS384",
 			args{
-				PS384,
+				jwa.PS384,
 				getRSAPublicTestKey(),
 			},
 			&RSAVerifier{
-				algorithm: PS384,
+				algorithm: jwa.PS384,
 				hash:      crypto.SHA384,
 				pubKey:    getRSAPublicTestKey(),
 			},
@@ -304,11 +306,11 @@ func TestInitRSAVerifier(t *testing.T) {
 		{
 			"Must initialize RSAVerifier with valid RSA public key + PS512",
 			args{
-				PS512,
+				jwa.PS512,
 				getRSAPublicTestKey(),
 			},
 			&RSAVerifier{
-				algorithm: PS512,
+				algorithm: jwa.PS512,
 				hash:      crypto.SHA512,
 				pubKey:    getRSAPublicTestKey(),
 			},
@@ -317,7 +319,7 @@ func TestInitRSAVerifier(t *testing.T) {
 		{
 			"Must fail to initialize RSAVerifier with nil RSA public key",
 			args{
-				RS256,
+				jwa.RS256,
 				nil,
 			},
 			nil,
@@ -326,7 +328,7 @@ func TestInitRSAVerifier(t *testing.T) {
 		{
 			"Must fail to initialize RSAVerifier with unexpected algorithm",
 			args{
-				HS256,
+				jwa.HS256,
 				nil,
 			},
 			nil,
@@ -362,7 +364,7 @@ func TestRSAVerifier_Verify(t *testing.T) {
 		{
 			"Must verify RS256 payload successfully",
 			&RSAVerifier{
-				algorithm: RS256,
+				algorithm: jwa.RS256,
 				hash:      crypto.SHA256,
 				pubKey:    getRSAPublicTestKey(),
 			},
@@ -376,7 +378,7 @@ func TestRSAVerifier_Verify(t *testing.T) {
 		{
 			"Must verify RS384 payload successfully",
 			&RSAVerifier{
-				algorithm: RS384,
+				algorithm: jwa.RS384,
 				hash:      crypto.SHA384,
 				pubKey:    getRSAPublicTestKey(),
 			},
@@ -390,7 +392,7 @@ func TestRSAVerifier_Verify(t *testing.T) {
 		{
 			"Must verify RS512 payload successfully",
 			&RSAVerifier{
-				algorithm: RS512,
+				algorithm: jwa.RS512,
 				hash:      crypto.SHA512,
 				pubKey:    getRSAPublicTestKey(),
 			},
@@ -426,47 +428,47 @@ func TestRSASigner_RSAVerifier_EndToEnd(t *testing.T) {
 	}
 	tests := []struct {
 		name      string
-		algorithm Algorithm
+		algorithm jwa.Algorithm
 		args      args
 	}{
 		{
 			"Must sign and verify using RS256",
-			RS256,
+			jwa.RS256,
 			args{
 				plaintext: plaintext,
 			},
 		},
 		{
 			"Must sign and verify using RS384",
-			RS384,
+			jwa.RS384,
 			args{
 				plaintext: plaintext,
 			},
 		},
 		{
 			"Must sign and verify using RS512",
-			RS512,
+			jwa.RS512,
 			args{
 				plaintext: plaintext,
 			},
 		},
 		{
 			"Must sign and verify using PS256",
-			PS256,
+			jwa.PS256,
 			args{
 				plaintext: plaintext,
 			},
 		},
 		{
 			"Must sign and verify using PS384",
-			PS384,
+			jwa.PS384,
 			args{
 				plaintext: plaintext,
 			},
 		},
 		{
 			"Must sign and verify using PS512",
-			PS512,
+			jwa.PS512,
 			args{
 				plaintext: plaintext,
 			},
@@ -1,4 +1,4 @@
-package main
+package jws
 
 type TokenSigner interface {
 	Sign(plaintext []byte) ([]byte, error)
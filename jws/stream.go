@@ -0,0 +1,18 @@
+package jws
+
+import "hash"
+
+// HashSigner is implemented by signers that can sign a signing input
+// written incrementally to a hash, so payloads too large to hold in memory
+// can be signed. EdDSA signs the whole message, and can't.
+type HashSigner interface {
+	NewHash() (hash.Hash, error)
+	SignHash(h hash.Hash) ([]byte, error)
+}
+
+// HashVerifier is implemented by verifiers that can verify a signature
+// over a signing input written incrementally to a hash.
+type HashVerifier interface {
+	NewHash() (hash.Hash, error)
+	VerifyHash(h hash.Hash, signature []byte) (bool, error)
+}
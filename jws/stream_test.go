@@ -0,0 +1,69 @@
+package jws
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/georgejenkins/jwt/jwa"
+)
+
+// TestHashSigner_HashVerifier ensures signatures over a signing input
+// written to a hash interoperate with signatures over the whole input.
+func TestHashSigner_HashVerifier(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+
+	hmacSV, _ := InitHMACSignerVerifier(jwa.HS256, exampleKey)
+	rsaSigner, _ := InitRSASigner(jwa.PS256, rsaKey)
+	rsaVerifier, _ := InitRSAVerifier(jwa.PS256, &rsaKey.PublicKey)
+	ecSigner, _ := InitECDSASigner(jwa.ES384, ecKey)
+	ecVerifier, _ := InitECDSAVerifier(jwa.ES384, &ecKey.PublicKey)
+
+	type signerVerifier interface {
+		TokenSigner
+		HashSigner
+	}
+	type verifier interface {
+		TokenVerifier
+		HashVerifier
+	}
+	tests := []struct {
+		name     string
+		signer   signerVerifier
+		verifier verifier
+	}{
+		{"Must interoperate for HMAC", hmacSV, hmacSV},
+		{"Must interoperate for RSA", rsaSigner, rsaVerifier},
+		{"Must interoperate for ECDSA", ecSigner, ecVerifier},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := tt.signer.NewHash()
+			h.Write(plaintext[:10])
+			h.Write(plaintext[10:])
+			hashed, err := tt.signer.SignHash(h)
+			if nil != err {
+				t.Fatalf("SignHash() error = %v", err)
+			}
+			if valid, err := tt.verifier.Verify(plaintext, hashed); !valid || nil != err {
+				t.Errorf("Verify() = %v, %v for a signature from SignHash", valid, err)
+			}
+
+			signed, _ := tt.signer.Sign(plaintext)
+			h, _ = tt.verifier.NewHash()
+			h.Write(plaintext)
+			if valid, err := tt.verifier.VerifyHash(h, signed); !valid || nil != err {
+				t.Errorf("VerifyHash() = %v, %v for a signature from Sign", valid, err)
+			}
+
+			h, _ = tt.verifier.NewHash()
+			h.Write(incorrectPlaintext)
+			if valid, _ := tt.verifier.VerifyHash(h, signed); valid {
+				t.Errorf("VerifyHash() = true for another input")
+			}
+		})
+	}
+}
@@ -0,0 +1,7 @@
+package jws
+
+// Example payload to be used for signature testing
+var plaintext = []byte("The Blue Stripes will ambush Radovid on the bridge to Temple Isle")
+
+// Example payload to be used for invalid signature testing
+var incorrectPlaintext = []byte("Long live Radovid the Stern!")
@@ -1,4 +1,4 @@
-package main
+package jws
 
 import (
 	"encoding/hex"
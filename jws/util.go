@@ -0,0 +1,87 @@
+package jws
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+
+	"github.com/georgejenkins/jwt/jwa"
+)
+
+// Base64URLEncode encodes a byte array into a base64url string
+// Adapted to Go from RFC 7515 JSON Web Signature (JWS)
+// Appendix C. Notes on Implementing base64url Encoding without Padding
+func Base64URLEncode(arg []byte) string {
+	s := base64.StdEncoding.EncodeToString(arg)
+
+	// Remove any trailing '='s
+	s = strings.Split(s, "=")[0]
+	// 62nd char of encoding
+	s = strings.Replace(s, "+", "-", -1)
+	// 63rd char of encoding
+	s = strings.Replace(s, "/", "_", -1)
+
+	return s
+}
+
+// Base64URLDecode decodes a base64url string into a byte array
+// Adapted to Go from RFC 7515 JSON Web Signature (JWS)
+// Appendix C. Notes on Implementing base64url Encoding without Padding
+func Base64URLDecode(arg string) ([]byte, error) {
+
+	arg = strings.Replace(arg, "-", "+", -1)
+	arg = strings.Replace(arg, "_", "/", -1)
+
+	// Pad with trailing '='s
+	switch len(arg) % 4 {
+	case 0:
+		// No pad chars in this case
+		break
+	case 2:
+		// Two pad chars
+		arg += "=="
+		break
+	case 3:
+		// One pad char
+		arg += "="
+		break
+	default:
+		return nil, errors.New("Illegal base64url string")
+	}
+
+	data, err := base64.StdEncoding.DecodeString(arg)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// GetHash returns the hash calculated from the plaintext, as required by the algorithm
+func GetHash(algorithm jwa.Algorithm, plaintext []byte) ([]byte, error) {
+	hash, err := newHash(algorithm)
+	if nil != err {
+		return nil, err
+	}
+
+	hash.Write(plaintext)
+	return hash.Sum(nil), nil
+}
+
+// newHash returns a new hash of the kind required by the algorithm
+func newHash(algorithm jwa.Algorithm) (hash.Hash, error) {
+	switch algorithm {
+	case jwa.RS256, jwa.PS256, jwa.ES256:
+		return sha256.New(), nil
+	case jwa.RS384, jwa.PS384, jwa.ES384:
+		return sha512.New384(), nil
+	case jwa.RS512, jwa.PS512, jwa.ES512, jwa.EdDSA:
+		return sha512.New(), nil
+	}
+
+	return nil, fmt.Errorf("Cannot generate hash with the configured algorithm %s", algorithm)
+}
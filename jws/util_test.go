@@ -1,8 +1,10 @@
-package main
+package jws
 
 import (
 	"reflect"
 	"testing"
+
+	"github.com/georgejenkins/jwt/jwa"
 )
 
 func TestBase64URLEncode(t *testing.T) {
@@ -89,7 +91,7 @@ func TestBase64URLDecode(t *testing.T) {
 
 func TestGetHash(t *testing.T) {
 	type args struct {
-		algorithms []Algorithm
+		algorithms []jwa.Algorithm
 		plaintext  []byte
 	}
 	tests := []struct {
@@ -101,10 +103,10 @@ func TestGetHash(t *testing.T) {
 		{
 			"GetHash hashes correctly for *256 families",
 			args{
-				[]Algorithm{
-					RS256,
-					PS256,
-					ES256,
+				[]jwa.Algorithm{
+					jwa.RS256,
+					jwa.PS256,
+					jwa.ES256,
 				},
 				plaintext,
 			},
@@ -114,10 +116,10 @@ func TestGetHash(t *testing.T) {
 		{
 			"GetHash hashes correctly for *384 families",
 			args{
-				[]Algorithm{
-					RS384,
-					PS384,
-					ES384,
+				[]jwa.Algorithm{
+					jwa.RS384,
+					jwa.PS384,
+					jwa.ES384,
 				},
 				plaintext,
 			},
@@ -127,10 +129,10 @@ func TestGetHash(t *testing.T) {
 		{
 			"GetHash hashes correctly for *512 families",
 			args{
-				[]Algorithm{
-					RS512,
-					PS512,
-					ES512,
+				[]jwa.Algorithm{
+					jwa.RS512,
+					jwa.PS512,
+					jwa.ES512,
 				},
 				plaintext,
 			},
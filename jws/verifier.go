@@ -1,4 +1,4 @@
-package main
+package jws
 
 type TokenVerifier interface {
 	Verify(plaintext []byte, hash []byte) (bool, error)
@@ -0,0 +1,182 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// JSONSignature is a signature of a JWS in the JSON serialization, with
+// its base64url encoded protected header and its unprotected header.
+type JSONSignature struct {
+	Protected string                 `json:"protected,omitempty"`
+	Header    map[string]interface{} `json:"header,omitempty"`
+	Signature string                 `json:"signature"`
+}
+
+// JSONWebSignature is a JWS in the general JSON serialization (RFC 7515,
+// section 7.2.1), for interop with systems exchanging JWSs as JSON. It
+// marshals as the general serialization; MarshalFlattened marshals a JWS
+// with a single signature as the flattened serialization.
+type JSONWebSignature struct {
+	Payload    string          `json:"payload"`
+	Signatures []JSONSignature `json:"signatures"`
+}
+
+// flattenedJSONWebSignature is a JWS in the flattened JSON serialization
+// (RFC 7515, section 7.2.2).
+type flattenedJSONWebSignature struct {
+	Payload string `json:"payload"`
+	JSONSignature
+}
+
+// GenerateJSON generates a JWS in the JSON serialization from a JOSE
+// header, protected by the signature, an unprotected header, which may be
+// nil, and a JWS claim set body. The headers must not share members.
+func (sv *JOSESignerVerifier) GenerateJSON(header interface{}, unprotected map[string]interface{}, body interface{}) (*JSONWebSignature, error) {
+	token, err := sv.GenerateToken(header, body)
+	if nil != err {
+		return nil, err
+	}
+
+	parts := strings.Split(string(token), ".")
+	if len(parts) != 3 {
+		return nil, errors.New("Unsigned tokens have no JSON serialization")
+	}
+
+	signature := JSONSignature{Protected: parts[0], Header: unprotected, Signature: parts[2]}
+	if err := signature.checkDisjoint(); nil != err {
+		return nil, err
+	}
+
+	return &JSONWebSignature{
+		Payload:    parts[1],
+		Signatures: []JSONSignature{signature},
+	}, nil
+}
+
+// MarshalFlattened marshals a JWS with a single signature as the
+// flattened JSON serialization.
+func (s *JSONWebSignature) MarshalFlattened() ([]byte, error) {
+	if len(s.Signatures) != 1 {
+		return nil, fmt.Errorf("Flattened JWS must have exactly one signature, found %d", len(s.Signatures))
+	}
+
+	return json.Marshal(flattenedJSONWebSignature{
+		Payload:       s.Payload,
+		JSONSignature: s.Signatures[0],
+	})
+}
+
+// ParseJSONWebSignature parses a JWS in the general or the flattened JSON
+// serialization, without verifying it.
+func ParseJSONWebSignature(data []byte) (*JSONWebSignature, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); nil != err {
+		return nil, err
+	}
+	if _, ok := members["payload"]; !ok {
+		return nil, errors.New("JSON JWS has no 'payload' member")
+	}
+
+	var s JSONWebSignature
+	if _, general := members["signatures"]; general {
+		if _, ok := members["signature"]; ok {
+			return nil, errors.New("JSON JWS cannot be both general and flattened")
+		}
+		if err := json.Unmarshal(data, &s); nil != err {
+			return nil, err
+		}
+	} else {
+		var flattened flattenedJSONWebSignature
+		if err := json.Unmarshal(data, &flattened); nil != err {
+			return nil, err
+		}
+		s = JSONWebSignature{
+			Payload:    flattened.Payload,
+			Signatures: []JSONSignature{flattened.JSONSignature},
+		}
+	}
+
+	if len(s.Signatures) == 0 {
+		return nil, errors.New("JSON JWS has no signatures")
+	}
+	for _, signature := range s.Signatures {
+		if signature.Signature == "" {
+			return nil, errors.New("JSON JWS signatures must have a 'signature' member")
+		}
+		if err := signature.checkDisjoint(); nil != err {
+			return nil, err
+		}
+	}
+
+	return &s, nil
+}
+
+// VerifyJSON verifies a JWS in the general or the flattened JSON
+// serialization, and validates its claims against the criteria, as
+// VerifyToken does. The token of the first signature that verifies is
+// returned. Only the protected header is verified, so 'alg' must be
+// protected, and members of the unprotected header are not used.
+// Signatures with an 'alg' the verifier's key can't be used with are
+// skipped, unless no signature can be verified.
+func (sv *JOSESignerVerifier) VerifyJSON(data []byte, validationCriteria *ValidationClaims) (*Token, bool, error) {
+	s, err := ParseJSONWebSignature(data)
+	if nil != err {
+		return nil, false, err
+	}
+
+	var token *Token
+	var mismatch error
+	verified := false
+	for _, signature := range s.Signatures {
+		signatureToken, valid, signatureErr := sv.VerifyToken(signature.compact(s.Payload), validationCriteria)
+		if valid {
+			return signatureToken, true, nil
+		}
+		if errors.Is(signatureErr, ErrAlgorithmKeyMismatch) {
+			mismatch = signatureErr
+			continue
+		}
+
+		token, err, verified = signatureToken, signatureErr, true
+	}
+
+	if !verified {
+		return nil, false, mismatch
+	}
+	return token, false, err
+}
+
+// compact returns the signature as a compact JWS of the payload.
+func (signature JSONSignature) compact(payload string) []byte {
+	return []byte(signature.Protected + "." + payload + "." + signature.Signature)
+}
+
+// checkDisjoint checks the protected and unprotected headers share no
+// members, as RFC 7515, section 7.2.1 requires.
+func (signature JSONSignature) checkDisjoint() error {
+	if len(signature.Header) == 0 {
+		return nil
+	}
+
+	protected := make(map[string]json.RawMessage)
+	if signature.Protected != "" {
+		decoded, err := Base64URLDecode(signature.Protected)
+		if nil != err {
+			return err
+		}
+		if err := json.Unmarshal(decoded, &protected); nil != err {
+			return err
+		}
+	}
+
+	for name := range signature.Header {
+		if _, ok := protected[name]; ok {
+			return fmt.Errorf("Header parameter %q cannot be both protected and unprotected", name)
+		}
+	}
+
+	return nil
+}
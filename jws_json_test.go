@@ -0,0 +1,73 @@
+package jwt
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestJSONWebSignature(t *testing.T) {
+	sv, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	other, _ := NewJOSESignerVerifier(HS256, []byte("not the key shared with the verifier"))
+
+	header := Header{Algorithm: string(HS256), Type: "JWT"}
+	claims := Claims{Subject: "alice", Issuer: "issuer"}
+
+	s, err := sv.GenerateJSON(header, map[string]interface{}{"kid": "key-1"}, claims)
+	if nil != err {
+		t.Fatalf("JOSESignerVerifier.GenerateJSON() error = %v", err)
+	}
+	if _, err := sv.GenerateJSON(header, map[string]interface{}{"typ": "JWT"}, claims); nil == err {
+		t.Errorf("JOSESignerVerifier.GenerateJSON() expected error with a member both protected and unprotected")
+	}
+
+	general, _ := json.Marshal(s)
+	flattened, err := s.MarshalFlattened()
+	if nil != err {
+		t.Fatalf("JSONWebSignature.MarshalFlattened() error = %v", err)
+	}
+
+	otherSignature, _ := other.GenerateJSON(header, nil, claims)
+	otherSignature.Payload = s.Payload
+	multiple, _ := json.Marshal(JSONWebSignature{
+		Payload:    s.Payload,
+		Signatures: append(otherSignature.Signatures, s.Signatures...),
+	})
+	if _, err := (&JSONWebSignature{Signatures: otherSignature.Signatures[:0]}).MarshalFlattened(); nil == err {
+		t.Errorf("JSONWebSignature.MarshalFlattened() expected error without a signature")
+	}
+
+	tampered := strings.Replace(string(flattened), s.Payload, Base64URLEncode([]byte(`{"sub":"mallory","iss":"issuer"}`)), 1)
+	unprotectedAlg := `{"payload":"` + s.Payload + `","header":{"alg":"HS256"},"signature":"` + s.Signatures[0].Signature + `"}`
+
+	tests := []struct {
+		name      string
+		data      string
+		wantValid bool
+		wantErr   bool
+	}{
+		{"Must verify the general serialization", string(general), true, false},
+		{"Must verify the flattened serialization", string(flattened), true, false},
+		{"Must verify any of several signatures", string(multiple), true, false},
+		{"Must not verify a tampered payload", tampered, false, false},
+		{"Must not verify an unprotected 'alg'", unprotectedAlg, false, true},
+		{"Must not parse a JWS without a payload", `{"signature":"c2ln"}`, false, true},
+		{"Must not parse a JWS without signatures", `{"payload":"e30","signatures":[]}`, false, true},
+		{"Must not parse a JWS both general and flattened", `{"payload":"e30","signature":"c2ln","signatures":[{"signature":"c2ln"}]}`, false, true},
+		{"Must not parse a compact JWS", `"` + string(s.Signatures[0].compact(s.Payload)) + `"`, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, valid, err := sv.VerifyJSON([]byte(tt.data), &ValidationClaims{Issuer: []string{"issuer"}, Subject: []string{"alice"}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("JOSESignerVerifier.VerifyJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if valid != tt.wantValid {
+				t.Errorf("JOSESignerVerifier.VerifyJSON() valid = %v, want %v", valid, tt.wantValid)
+			}
+			if valid && token.RegisteredClaims.Subject != "alice" {
+				t.Errorf("JOSESignerVerifier.VerifyJSON() subject = %q, want alice", token.RegisteredClaims.Subject)
+			}
+		})
+	}
+}
@@ -1,4 +1,4 @@
-package main
+package jwt
 
 import (
 	"crypto/ecdsa"
@@ -7,20 +7,51 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
 	"strings"
+	"time"
 )
 
+// JOSESignerVerifieriface is the interface of JOSESignerVerifier, so
+// callers can substitute it in tests.
 type JOSESignerVerifieriface interface {
 	GenerateToken(header interface{}, body interface{}) ([]byte, error)
-	VerifySignature(token []byte) (bool, error)
-	VerifyToken(token []byte) (bool, error)
+	VerifySignature(rawToken []byte) (*Token, bool, error)
+	VerifyToken(rawToken []byte, validationCriteria *ValidationClaims) (*Token, bool, error)
 }
 
+var _ JOSESignerVerifieriface = (*JOSESignerVerifier)(nil)
+
 type JOSESignerVerifier struct {
 	algorithm Algorithm
 	signer    TokenSigner
 	verifier  TokenVerifier
+
+	// Optional behaviour, configured with Options
+	canonicalClaims bool
+	ttlBudgets      map[string]TTLBudget
+	clampTTL        bool
+	claimLimits     ClaimLimits
+	subjectMapper   SubjectMapper
+	subjectSector   string
+	keyResolver     KeyResolver
+	pinnedKeys      map[string]bool
+	provenance      *Provenance
+	algorithmStatus map[Algorithm]AlgorithmStatus
+	allowedAlgs     map[Algorithm]bool
+	allowNone       bool
+	requiredTypes   []string
+	nonceValidator  NonceValidator
+	memoryBudget    *MemoryBudget
+	criticalHeaders map[string]CriticalHeaderHandler
+	tokenSizeLimits TokenSizeLimits
+	strictJSON      int
+	knownHeaders    map[string]bool
+	keyID           string
+	defaultType     string
+	expiresIn       time.Duration
+	notBeforeSkew   time.Duration
+	setNotBefore    bool
+	signingClock    func() time.Time
 }
 
 //	NewJOSESignerVerifier creates a new JOSESignerVerifier, given a valid
@@ -34,7 +65,28 @@ type JOSESignerVerifier struct {
 //	The JOSE standard also sets aside the option of 'None' for unsigned
 //	and unverifiable tokens. Since this is inherently insecure, a separate
 //	constructor is provided - 'NewJOSESignerVerifierInsecure'
-func NewJOSESignerVerifier(alg Algorithm, key interface{}) (*JOSESignerVerifier, error) {
+//	Options may be provided to configure optional behaviour.
+func NewJOSESignerVerifier(alg Algorithm, key interface{}, opts ...Option) (*JOSESignerVerifier, error) {
+	sv, err := newFromKey(alg, key)
+	if nil != err {
+		return nil, err
+	}
+
+	sv, err = sv.applyOptions(opts)
+	if nil != err {
+		return nil, err
+	}
+
+	if err := sv.checkPinnedKey(key); nil != err {
+		return nil, err
+	}
+
+	sv.provenance = staticProvenance(key)
+	return sv, nil
+}
+
+// newFromKey configures a new JOSESignerVerifier from any supported key type.
+func newFromKey(alg Algorithm, key interface{}) (*JOSESignerVerifier, error) {
 	switch keyType := key.(type) {
 	// RSA
 	case *rsa.PrivateKey:
@@ -179,46 +231,107 @@ func newFromHMACBytes(alg Algorithm, key []byte) (*JOSESignerVerifier, error) {
 
 // NewInsecureJOSESignerVerifier returns a JOSESignerVerifier configured with the
 // 'None' algorithm type. This is NOT RECOMMENDED but is nevertheless provided
-// to conform with the JOSE specification.
-func NewInsecureJOSESignerVerifier(alg Algorithm) (*JOSESignerVerifier, error) {
+// to conform with the JOSE specification. It only verifies unsigned tokens
+// if the AllowNone option is given.
+func NewInsecureJOSESignerVerifier(alg Algorithm, opts ...Option) (*JOSESignerVerifier, error) {
 	if alg != None {
 		return nil, errors.New(`cannot initialize an insecure JOSESignerVerifier without the algorithm 'None'.
 If you want to use a key, use NewJOSESignerVerifier with the key and algorithm type`)
 	}
 
-	return &JOSESignerVerifier{
+	v, err := InitNoneSignerVerifier(alg)
+	if nil != err {
+		return nil, err
+	}
+
+	sv := &JOSESignerVerifier{
 		algorithm: alg,
-	}, nil
+		verifier:  v,
+	}
+
+	return sv.applyOptions(opts)
 }
 
 // GenerateToken generates a complete JWS token as a byte array from a JOSE
-// header and JWS claim set body.
-func (sv *JOSESignerVerifier) GenerateToken(header interface{}, body interface{}) ([]byte, error) {
-	// Must be configured for token signing to be able to sign a token.
-	if sv.verifier == nil {
-		return nil, errors.New("JOSESignerVerifier not configured for signing - did you provide the correct key type?")
-	}
+// header and JWS claim set body. The header's 'alg' is set to the signer's
+// algorithm, and its 'typ' and 'kid' if configured with WithDefaultType
+// and WithKeyID; a header with another 'alg' or 'kid' is an error. A panic
+// while generating the token is returned as an InternalError.
+func (sv *JOSESignerVerifier) GenerateToken(header interface{}, body interface{}) (token []byte, err error) {
+	defer recoverInternal("GenerateToken", &err)
 
 	// Header and body must be json string-ified
 	joseHeader, err := json.Marshal(header)
 	if nil != err {
 		return nil, err
 	}
+	joseHeader, err = sv.populateHeader(joseHeader)
+	if nil != err {
+		return nil, err
+	}
+
+	return sv.generateToken(joseHeader, Base64URLEncode(joseHeader), body)
+}
+
+// generateToken generates a token from a JSON encoded JOSE header, its
+// base64url encoding, and a JWS claim set body. A panic is returned as an
+// InternalError, as batches generate tokens on their own goroutines.
+func (sv *JOSESignerVerifier) generateToken(joseHeader []byte, encodedHeader string, body interface{}) (signed []byte, err error) {
+	defer recoverInternal("GenerateToken", &err)
+
+	// Must be configured for token signing to be able to sign a token.
+	if sv.signer == nil && sv.algorithm != None {
+		return nil, errors.New("JOSESignerVerifier not configured for signing - did you provide the correct key type?")
+	}
+
+	if err := sv.checkAlgorithm(sv.algorithm, true); nil != err {
+		return nil, err
+	}
 
 	jwsPayload, err := json.Marshal(body)
 	if nil != err {
 		return nil, err
 	}
 
-	// Header and body are appended together with a '.'
-	headerAndClaims := appendWithDot(Base64URLEncode(joseHeader), Base64URLEncode(jwsPayload))
+	if sv.expiresIn > 0 || sv.setNotBefore {
+		jwsPayload, err = sv.applyValidity(jwsPayload)
+		if nil != err {
+			return nil, err
+		}
+	}
+
+	if len(sv.ttlBudgets) > 0 {
+		jwsPayload, err = sv.enforceTTLBudget(joseHeader, jwsPayload)
+		if nil != err {
+			return nil, err
+		}
+	}
+
+	if nil != sv.subjectMapper {
+		jwsPayload, err = sv.pseudonymizeSubject(jwsPayload)
+		if nil != err {
+			return nil, err
+		}
+	}
 
-	log.Printf(string(headerAndClaims))
+	if sv.canonicalClaims {
+		jwsPayload, err = CanonicalizeJSON(jwsPayload)
+		if nil != err {
+			return nil, err
+		}
+	}
+
+	if err := sv.checkClaimLimits(jwsPayload); nil != err {
+		return nil, err
+	}
+
+	// Header and body are appended together with a '.'
+	headerAndClaims := appendWithDot(encodedHeader, Base64URLEncode(jwsPayload))
 
 	// If the configured algorithm is 'None', we don't generate
 	// or append a signature. This token is unsigned.
 	if sv.algorithm == None {
-		return headerAndClaims, nil
+		return headerAndClaims, sv.checkTokenSize(headerAndClaims)
 	}
 
 	// Generate the signature of the header.body string
@@ -227,7 +340,12 @@ func (sv *JOSESignerVerifier) GenerateToken(header interface{}, body interface{}
 		return nil, err
 	}
 
-	return appendWithDot(headerAndClaims, Base64URLEncode(jwSignature)), nil
+	token := appendWithDot(headerAndClaims, Base64URLEncode(jwSignature))
+	if err := sv.checkTokenSize(token); nil != err {
+		return nil, err
+	}
+
+	return token, nil
 }
 
 // VerifySignature verifies the signature on the token is valid. It does
@@ -235,13 +353,50 @@ func (sv *JOSESignerVerifier) GenerateToken(header interface{}, body interface{}
 // use, but is made public for advanced use cases or when you have a need
 // to use additional/custom validation logic against the header and claims.
 //
+// A panic while verifying the token, for example in a KeyResolver, is
+// returned as an InternalError. Tokens not admitted by the memory budget
+// are rejected with a MemoryBudgetError.
+//
 // Header and claim validation is MANDATORY. Use the VerifyToken function
 // to validate against any registered claims in addition to signature validation.
 func (sv *JOSESignerVerifier) VerifySignature(rawToken []byte) (*Token, bool, error) {
-	token, err := GetRawTokenParts(rawToken)
+	release, err := sv.admit(rawToken)
+	if nil != err {
+		return nil, false, err
+	}
+	defer release()
+
+	return sv.recoverVerifySignature(rawToken)
+}
+
+// recoverVerifySignature verifies the signature on the token, returning a
+// panic as an InternalError.
+func (sv *JOSESignerVerifier) recoverVerifySignature(rawToken []byte) (token *Token, valid bool, err error) {
+	defer func() {
+		if r := recover(); nil != r {
+			token, valid, err = nil, false, newInternalError("VerifySignature", r)
+		}
+	}()
+
+	return sv.verifySignature(rawToken)
+}
+
+// verifySignature verifies the signature on the token, see VerifySignature.
+func (sv *JOSESignerVerifier) verifySignature(rawToken []byte) (*Token, bool, error) {
+	if err := chaosVerificationFailure(); nil != err {
+		return nil, false, err
+	}
+
+	token, err := getRawTokenParts(rawToken, sv.tokenSizeLimits.withDefaults())
 	if nil != err {
 		return nil, false, err
 	}
+	if err := sv.checkStrictHeader(token.DecodedHeader); nil != err {
+		return nil, false, err
+	}
+	if err := sv.checkStrictPayload(token.DecodedBody); nil != err {
+		return nil, false, err
+	}
 
 	// Base64url decode the JOSE header, validate the contents are well-formed.
 	// Header validation should come after signature validation, since at this
@@ -255,7 +410,34 @@ func (sv *JOSESignerVerifier) VerifySignature(rawToken []byte) (*Token, bool, er
 	}
 	token.RegisteredHeader = header
 
-	signatureValid, err := sv.verifier.Verify(
+	if nil != header.Base64Payload && !*header.Base64Payload {
+		return nil, false, errors.New("Unencoded payloads are only supported for detached JWSs, see VerifyDetached")
+	}
+	critical, err := sv.checkCritical(token.DecodedHeader, "b64")
+	if nil != err {
+		return nil, false, err
+	}
+	if err := sv.checkType(header); nil != err {
+		return nil, false, err
+	}
+
+	if err := sv.checkAlgorithm(Algorithm(header.Algorithm), false); nil != err {
+		return nil, false, err
+	}
+
+	verifier, provenance := sv.verifier, sv.provenance
+	if nil != sv.keyResolver {
+		verifier, provenance, err = sv.resolveVerifier(header)
+		if nil != err {
+			return nil, false, err
+		}
+	}
+
+	if err := checkVerifierAlgorithm(verifier, header); nil != err {
+		return nil, false, err
+	}
+
+	signatureValid, err := verifier.Verify(
 		appendWithDot(
 			token.RawHeader,
 			token.RawBody,
@@ -263,40 +445,98 @@ func (sv *JOSESignerVerifier) VerifySignature(rawToken []byte) (*Token, bool, er
 		token.DecodedSignature,
 	)
 	token.signatureValid = signatureValid
+	token.provenance = provenance.verifiedAt(time.Now())
+
+	// Nonces are only consumed by authentic tokens, so forged tokens can't
+	// be used to exhaust them.
+	if signatureValid && nil == err && nil != sv.nonceValidator {
+		if err := sv.nonceValidator.ValidateNonce(header.Nonce); nil != err {
+			return token, false, err
+		}
+	}
+
+	if signatureValid && nil == err {
+		if err := sv.processCritical(token, critical); nil != err {
+			return token, false, err
+		}
+	}
 
 	return token, signatureValid, err
 }
 
 // VerifyToken verifies the signature on the token is valid, and
-// performs validation on any registered header or claim values.
-func (sv *JOSESignerVerifier) VerifyToken(rawToken []byte, validationCriteria *ValidationClaims) (*Token, bool, error) {
-	token, signatureValid, err := sv.VerifySignature(rawToken)
+// performs validation on any registered header or claim values. A panic
+// while verifying the token is returned as an InternalError, and tokens
+// not admitted by the memory budget are rejected with a MemoryBudgetError.
+func (sv *JOSESignerVerifier) VerifyToken(rawToken []byte, validationCriteria *ValidationClaims) (token *Token, valid bool, err error) {
+	release, err := sv.admit(rawToken)
+	if nil != err {
+		return nil, false, err
+	}
+	defer release()
+
+	defer func() {
+		if r := recover(); nil != r {
+			token, valid, err = nil, false, newInternalError("VerifyToken", r)
+		}
+	}()
+
+	return sv.verifyToken(rawToken, validationCriteria)
+}
+
+// verifyToken verifies the token's signature and claims, see VerifyToken.
+func (sv *JOSESignerVerifier) verifyToken(rawToken []byte, validationCriteria *ValidationClaims) (*Token, bool, error) {
+	token, signatureValid, err := sv.recoverVerifySignature(rawToken)
 	if nil != err || !signatureValid {
+		if errors.Is(err, ErrInternal) {
+			recordVerificationFailure(FailureInternal)
+		} else if nil == token {
+			recordVerificationFailure(FailureMalformed)
+		} else {
+			recordVerificationFailure(FailureSignature)
+		}
 		return nil, false, err
 	}
 
 	var claims Claims
 	err = GetClaims(token, &claims)
 	if nil != err {
+		recordVerificationFailure(FailureMalformed)
 		return token, false, err
 	}
 	token.RegisteredClaims = claims
 
 	claimsValid, err := claims.ValidateRegisteredClaims(validationCriteria)
+	if nil != err || !claimsValid {
+		recordVerificationFailure(FailureClaims)
+	}
 
 	return token, (signatureValid && claimsValid), err
 }
 
 // GetRawTokenParts splits and returns the raw token parts as a Token.
-// The raw values are Base64URLDecoded.
+// The raw values are Base64URLDecoded. Tokens exceeding the default
+// TokenSizeLimits are rejected with a TokenTooLargeError before decoding.
 func GetRawTokenParts(rawToken []byte) (*Token, error) {
+	return getRawTokenParts(rawToken, TokenSizeLimits{}.withDefaults())
+}
+
+// getRawTokenParts splits the token as GetRawTokenParts, within limits.
+func getRawTokenParts(rawToken []byte, limits TokenSizeLimits) (*Token, error) {
+	if err := limits.checkTokenSize(rawToken); nil != err {
+		return nil, err
+	}
 
 	// Validate there is at least one period ('.') and not more than two periods ('.')
 	parts := strings.Split(string(rawToken), ".")
-	if len(parts) < 2 && len(parts) > 3 {
+	if len(parts) < 2 || len(parts) > 3 {
 		return nil, errors.New("Valid tokens MUST have at least one '.' character and MUST NOT have at more than two '.' characters")
 	}
 
+	if err := limits.checkPartSizes(parts); nil != err {
+		return nil, err
+	}
+
 	decodedHeader, err := Base64URLDecode(parts[0])
 	if nil != err {
 		return nil, err
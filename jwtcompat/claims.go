@@ -0,0 +1,292 @@
+package jwtcompat
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+)
+
+// TimeFunc returns the time claims are validated at.
+var TimeFunc = time.Now
+
+// Claims are a claim set, which validates its registered claims.
+type Claims interface {
+	Valid() error
+}
+
+// NumericDate is a JSON numeric date: seconds since the epoch.
+type NumericDate struct {
+	time.Time
+}
+
+// NewNumericDate returns the time, truncated to seconds, as a NumericDate.
+func NewNumericDate(t time.Time) *NumericDate {
+	return &NumericDate{t.Truncate(time.Second)}
+}
+
+// MarshalJSON encodes the date as seconds since the epoch.
+func (date NumericDate) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d", date.Unix())), nil
+}
+
+// UnmarshalJSON decodes a date of seconds since the epoch, which may have
+// a fraction.
+func (date *NumericDate) UnmarshalJSON(b []byte) error {
+	var number json.Number
+	if err := json.Unmarshal(b, &number); nil != err {
+		return fmt.Errorf("Could not parse NumericDate: %v", err)
+	}
+
+	f, err := number.Float64()
+	if nil != err {
+		return fmt.Errorf("Could not convert NumericDate to float: %v", err)
+	}
+
+	seconds, fraction := math.Modf(f)
+	date.Time = time.Unix(int64(seconds), int64(fraction*1e9))
+	return nil
+}
+
+// ClaimStrings is a claim that is a string or an array of strings, such
+// as 'aud'.
+type ClaimStrings []string
+
+// UnmarshalJSON decodes a string or an array of strings.
+func (s *ClaimStrings) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); nil == err {
+		*s = ClaimStrings{single}
+		return nil
+	}
+
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); nil != err {
+		return fmt.Errorf("Claim must be a string or an array of strings: %v", err)
+	}
+	*s = multiple
+	return nil
+}
+
+// RegisteredClaims are the registered claims of RFC 7519, section 4.1.
+// Embed it in custom claim types.
+type RegisteredClaims struct {
+	Issuer    string       `json:"iss,omitempty"`
+	Subject   string       `json:"sub,omitempty"`
+	Audience  ClaimStrings `json:"aud,omitempty"`
+	ExpiresAt *NumericDate `json:"exp,omitempty"`
+	NotBefore *NumericDate `json:"nbf,omitempty"`
+	IssuedAt  *NumericDate `json:"iat,omitempty"`
+	ID        string       `json:"jti,omitempty"`
+}
+
+// Valid validates the time based claims, which are optional.
+func (c RegisteredClaims) Valid() error {
+	now := TimeFunc()
+	return validateTimes(c.VerifyExpiresAt(now, false), c.VerifyIssuedAt(now, false), c.VerifyNotBefore(now, false))
+}
+
+// VerifyAudience reports whether the audience includes cmp.
+func (c *RegisteredClaims) VerifyAudience(cmp string, req bool) bool {
+	return verifyAudience(c.Audience, cmp, req)
+}
+
+// VerifyExpiresAt reports whether the token has not expired at cmp.
+func (c *RegisteredClaims) VerifyExpiresAt(cmp time.Time, req bool) bool {
+	if nil == c.ExpiresAt {
+		return !req
+	}
+	return cmp.Before(c.ExpiresAt.Time)
+}
+
+// VerifyIssuedAt reports whether the token was issued by cmp.
+func (c *RegisteredClaims) VerifyIssuedAt(cmp time.Time, req bool) bool {
+	if nil == c.IssuedAt {
+		return !req
+	}
+	return !cmp.Before(c.IssuedAt.Time)
+}
+
+// VerifyNotBefore reports whether the token is valid at cmp.
+func (c *RegisteredClaims) VerifyNotBefore(cmp time.Time, req bool) bool {
+	if nil == c.NotBefore {
+		return !req
+	}
+	return !cmp.Before(c.NotBefore.Time)
+}
+
+// VerifyIssuer reports whether the issuer is cmp.
+func (c *RegisteredClaims) VerifyIssuer(cmp string, req bool) bool {
+	return verifyString(c.Issuer, cmp, req)
+}
+
+// StandardClaims are the registered claims with times as seconds since the
+// epoch. Use RegisteredClaims in new code.
+type StandardClaims struct {
+	Audience  string `json:"aud,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"`
+	Id        string `json:"jti,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	Issuer    string `json:"iss,omitempty"`
+	NotBefore int64  `json:"nbf,omitempty"`
+	Subject   string `json:"sub,omitempty"`
+}
+
+// Valid validates the time based claims, which are optional.
+func (c StandardClaims) Valid() error {
+	now := TimeFunc().Unix()
+	return validateTimes(c.VerifyExpiresAt(now, false), c.VerifyIssuedAt(now, false), c.VerifyNotBefore(now, false))
+}
+
+// VerifyAudience reports whether the audience is cmp.
+func (c *StandardClaims) VerifyAudience(cmp string, req bool) bool {
+	return verifyString(c.Audience, cmp, req)
+}
+
+// VerifyExpiresAt reports whether the token has not expired at cmp.
+func (c *StandardClaims) VerifyExpiresAt(cmp int64, req bool) bool {
+	if c.ExpiresAt == 0 {
+		return !req
+	}
+	return cmp < c.ExpiresAt
+}
+
+// VerifyIssuedAt reports whether the token was issued by cmp.
+func (c *StandardClaims) VerifyIssuedAt(cmp int64, req bool) bool {
+	if c.IssuedAt == 0 {
+		return !req
+	}
+	return cmp >= c.IssuedAt
+}
+
+// VerifyNotBefore reports whether the token is valid at cmp.
+func (c *StandardClaims) VerifyNotBefore(cmp int64, req bool) bool {
+	if c.NotBefore == 0 {
+		return !req
+	}
+	return cmp >= c.NotBefore
+}
+
+// VerifyIssuer reports whether the issuer is cmp.
+func (c *StandardClaims) VerifyIssuer(cmp string, req bool) bool {
+	return verifyString(c.Issuer, cmp, req)
+}
+
+// MapClaims is a claim set of any claims, decoded as by encoding/json.
+type MapClaims map[string]interface{}
+
+// Valid validates the time based claims, which are optional.
+func (m MapClaims) Valid() error {
+	now := TimeFunc().Unix()
+	return validateTimes(m.VerifyExpiresAt(now, false), m.VerifyIssuedAt(now, false), m.VerifyNotBefore(now, false))
+}
+
+// VerifyAudience reports whether the audience includes cmp.
+func (m MapClaims) VerifyAudience(cmp string, req bool) bool {
+	var audience []string
+	switch aud := m["aud"].(type) {
+	case string:
+		audience = []string{aud}
+	case []string:
+		audience = aud
+	case []interface{}:
+		for _, a := range aud {
+			s, ok := a.(string)
+			if !ok {
+				return false
+			}
+			audience = append(audience, s)
+		}
+	}
+	return verifyAudience(audience, cmp, req)
+}
+
+// VerifyExpiresAt reports whether the token has not expired at cmp.
+func (m MapClaims) VerifyExpiresAt(cmp int64, req bool) bool {
+	exp, ok := m.numericDate("exp")
+	if !ok {
+		return !req && nil == m["exp"]
+	}
+	return float64(cmp) < exp
+}
+
+// VerifyIssuedAt reports whether the token was issued by cmp.
+func (m MapClaims) VerifyIssuedAt(cmp int64, req bool) bool {
+	iat, ok := m.numericDate("iat")
+	if !ok {
+		return !req && nil == m["iat"]
+	}
+	return float64(cmp) >= iat
+}
+
+// VerifyNotBefore reports whether the token is valid at cmp.
+func (m MapClaims) VerifyNotBefore(cmp int64, req bool) bool {
+	nbf, ok := m.numericDate("nbf")
+	if !ok {
+		return !req && nil == m["nbf"]
+	}
+	return float64(cmp) >= nbf
+}
+
+// VerifyIssuer reports whether the issuer is cmp.
+func (m MapClaims) VerifyIssuer(cmp string, req bool) bool {
+	iss, _ := m["iss"].(string)
+	return verifyString(iss, cmp, req)
+}
+
+// numericDate returns a numeric date claim, decoded as a float64 or a
+// json.Number.
+func (m MapClaims) numericDate(name string) (float64, bool) {
+	switch v := m[name].(type) {
+	case float64:
+		return v, true
+	case json.Number:
+		f, err := v.Float64()
+		return f, nil == err
+	}
+	return 0, false
+}
+
+// validateTimes returns a ValidationError for the time based claims that
+// failed, or nil.
+func validateTimes(expiresAt bool, issuedAt bool, notBefore bool) error {
+	vErr := new(ValidationError)
+	if !expiresAt {
+		vErr.Inner = ErrTokenExpired
+		vErr.Errors |= ValidationErrorExpired
+	}
+	if !issuedAt {
+		vErr.Inner = ErrTokenUsedBeforeIssued
+		vErr.Errors |= ValidationErrorIssuedAt
+	}
+	if !notBefore {
+		vErr.Inner = ErrTokenNotValidYet
+		vErr.Errors |= ValidationErrorNotValidYet
+	}
+
+	if vErr.valid() {
+		return nil
+	}
+	return vErr
+}
+
+func verifyAudience(audience []string, cmp string, req bool) bool {
+	if len(audience) == 0 {
+		return !req
+	}
+
+	for _, aud := range audience {
+		if subtle.ConstantTimeCompare([]byte(aud), []byte(cmp)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+func verifyString(value string, cmp string, req bool) bool {
+	if value == "" {
+		return !req
+	}
+	return subtle.ConstantTimeCompare([]byte(value), []byte(cmp)) == 1
+}
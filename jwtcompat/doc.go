@@ -0,0 +1,17 @@
+// Package jwtcompat mirrors the API of github.com/golang-jwt/jwt (v4),
+// backed by the signers and verifiers of package jws, so code can migrate
+// from golang-jwt a call site at a time: Parse, ParseWithClaims and
+// Token.SignedString, the SigningMethod types and the MapClaims,
+// RegisteredClaims and StandardClaims claim types behave as they do there.
+//
+//	token, err := jwtcompat.Parse(tokenString, func(token *jwtcompat.Token) (interface{}, error) {
+//		if _, ok := token.Method.(*jwtcompat.SigningMethodHMAC); !ok {
+//			return nil, fmt.Errorf("Unexpected signing method %v", token.Header["alg"])
+//		}
+//		return secret, nil
+//	})
+//
+// Unlike golang-jwt, the 'none' algorithm is never accepted, and error
+// messages follow the conventions of this library. New code should use
+// package jwt directly.
+package jwtcompat
@@ -0,0 +1,92 @@
+package jwtcompat
+
+import "errors"
+
+// Errors matched by ValidationError.Is, for errors.Is.
+var (
+	ErrInvalidKey            = errors.New("Key is invalid")
+	ErrInvalidKeyType        = errors.New("Key is of invalid type")
+	ErrTokenMalformed        = errors.New("Token is malformed")
+	ErrTokenUnverifiable     = errors.New("Token is unverifiable")
+	ErrTokenSignatureInvalid = errors.New("Token signature is invalid")
+	ErrTokenExpired          = errors.New("Token is expired")
+	ErrTokenUsedBeforeIssued = errors.New("Token used before issued")
+	ErrTokenNotValidYet      = errors.New("Token is not valid yet")
+	ErrTokenInvalidAudience  = errors.New("Token has invalid audience")
+	ErrTokenInvalidIssuer    = errors.New("Token has invalid issuer")
+)
+
+// The errors that may occur while parsing and validating a token, as bits
+// of ValidationError.Errors.
+const (
+	ValidationErrorMalformed        uint32 = 1 << iota // Token is malformed
+	ValidationErrorUnverifiable                        // Token could not be verified because of signing problems
+	ValidationErrorSignatureInvalid                    // Signature validation failed
+	ValidationErrorAudience                            // AUD validation failed
+	ValidationErrorExpired                             // EXP validation failed
+	ValidationErrorIssuedAt                            // IAT validation failed
+	ValidationErrorIssuer                              // ISS validation failed
+	ValidationErrorNotValidYet                         // NBF validation failed
+	ValidationErrorId                                  // JTI validation failed
+	ValidationErrorClaimsInvalid                       // Generic claims validation error
+)
+
+// ValidationError is returned by Parse and ParseWithClaims, and by the
+// Valid method of the claim types.
+type ValidationError struct {
+	// Inner is the error from the Keyfunc or a custom claim type, if any.
+	Inner error
+
+	// Errors is a bit field of the ValidationError constants.
+	Errors uint32
+
+	text string
+}
+
+// NewValidationError creates a ValidationError with a message and the
+// error bits.
+func NewValidationError(errorText string, errorFlags uint32) *ValidationError {
+	return &ValidationError{
+		text:   errorText,
+		Errors: errorFlags,
+	}
+}
+
+func (e ValidationError) Error() string {
+	if nil != e.Inner {
+		return e.Inner.Error()
+	} else if e.text != "" {
+		return e.text
+	}
+	return "Token is invalid"
+}
+
+// Unwrap returns the inner error.
+func (e *ValidationError) Unwrap() error {
+	return e.Inner
+}
+
+// Is reports whether the error is one of the Err constants set in Errors.
+func (e *ValidationError) Is(err error) bool {
+	if errors.Is(errors.Unwrap(e), err) {
+		return true
+	}
+
+	flags := map[error]uint32{
+		ErrTokenMalformed:        ValidationErrorMalformed,
+		ErrTokenUnverifiable:     ValidationErrorUnverifiable,
+		ErrTokenSignatureInvalid: ValidationErrorSignatureInvalid,
+		ErrTokenInvalidAudience:  ValidationErrorAudience,
+		ErrTokenUsedBeforeIssued: ValidationErrorIssuedAt,
+		ErrTokenInvalidIssuer:    ValidationErrorIssuer,
+		ErrTokenExpired:          ValidationErrorExpired,
+		ErrTokenNotValidYet:      ValidationErrorNotValidYet,
+	}
+	flag, ok := flags[err]
+	return ok && e.Errors&flag != 0
+}
+
+// valid reports whether no errors are set.
+func (e *ValidationError) valid() bool {
+	return e.Errors == 0
+}
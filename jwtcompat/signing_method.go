@@ -0,0 +1,298 @@
+package jwtcompat
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"fmt"
+	"sync"
+
+	"github.com/georgejenkins/jwt/jwa"
+	"github.com/georgejenkins/jwt/jws"
+)
+
+// SigningMethod signs and verifies tokens for an algorithm. Signatures are
+// base64url encoded.
+type SigningMethod interface {
+	Verify(signingString, signature string, key interface{}) error
+	Sign(signingString string, key interface{}) (string, error)
+	Alg() string
+}
+
+var signingMethods = struct {
+	sync.RWMutex
+	methods map[string]func() SigningMethod
+}{methods: make(map[string]func() SigningMethod)}
+
+// RegisterSigningMethod registers the SigningMethod of an algorithm.
+func RegisterSigningMethod(alg string, f func() SigningMethod) {
+	signingMethods.Lock()
+	defer signingMethods.Unlock()
+
+	signingMethods.methods[alg] = f
+}
+
+// GetSigningMethod returns the SigningMethod of an algorithm, or nil.
+func GetSigningMethod(alg string) SigningMethod {
+	signingMethods.RLock()
+	defer signingMethods.RUnlock()
+
+	if f, ok := signingMethods.methods[alg]; ok {
+		return f()
+	}
+	return nil
+}
+
+// GetAlgorithms returns the algorithms of the registered signing methods.
+func GetAlgorithms() []string {
+	signingMethods.RLock()
+	defer signingMethods.RUnlock()
+
+	algs := make([]string, 0, len(signingMethods.methods))
+	for alg := range signingMethods.methods {
+		algs = append(algs, alg)
+	}
+	return algs
+}
+
+// SigningMethodHMAC signs with HS256, HS384 and HS512. Keys are []byte.
+type SigningMethodHMAC struct {
+	Name string
+	Hash crypto.Hash
+}
+
+// SigningMethodRSA signs with RS256, RS384 and RS512. Keys are
+// *rsa.PrivateKey to sign and *rsa.PublicKey to verify.
+type SigningMethodRSA struct {
+	Name string
+	Hash crypto.Hash
+}
+
+// SigningMethodRSAPSS signs with PS256, PS384 and PS512. Keys are as for
+// SigningMethodRSA.
+type SigningMethodRSAPSS struct {
+	*SigningMethodRSA
+}
+
+// SigningMethodECDSA signs with ES256, ES384 and ES512. Keys are
+// *ecdsa.PrivateKey to sign and *ecdsa.PublicKey to verify.
+type SigningMethodECDSA struct {
+	Name      string
+	Hash      crypto.Hash
+	KeySize   int
+	CurveBits int
+}
+
+// SigningMethodEd25519 signs with EdDSA. Keys are ed25519.PrivateKey to
+// sign and ed25519.PublicKey to verify, or pointers to them.
+type SigningMethodEd25519 struct{}
+
+// The signing methods of the algorithms of RFC 7518 and RFC 8037.
+var (
+	SigningMethodHS256 = &SigningMethodHMAC{"HS256", crypto.SHA256}
+	SigningMethodHS384 = &SigningMethodHMAC{"HS384", crypto.SHA384}
+	SigningMethodHS512 = &SigningMethodHMAC{"HS512", crypto.SHA512}
+	SigningMethodRS256 = &SigningMethodRSA{"RS256", crypto.SHA256}
+	SigningMethodRS384 = &SigningMethodRSA{"RS384", crypto.SHA384}
+	SigningMethodRS512 = &SigningMethodRSA{"RS512", crypto.SHA512}
+	SigningMethodPS256 = &SigningMethodRSAPSS{&SigningMethodRSA{"PS256", crypto.SHA256}}
+	SigningMethodPS384 = &SigningMethodRSAPSS{&SigningMethodRSA{"PS384", crypto.SHA384}}
+	SigningMethodPS512 = &SigningMethodRSAPSS{&SigningMethodRSA{"PS512", crypto.SHA512}}
+	SigningMethodES256 = &SigningMethodECDSA{"ES256", crypto.SHA256, 32, 256}
+	SigningMethodES384 = &SigningMethodECDSA{"ES384", crypto.SHA384, 48, 384}
+	SigningMethodES512 = &SigningMethodECDSA{"ES512", crypto.SHA512, 66, 521}
+	SigningMethodEdDSA = &SigningMethodEd25519{}
+)
+
+func init() {
+	for _, method := range []SigningMethod{
+		SigningMethodHS256, SigningMethodHS384, SigningMethodHS512,
+		SigningMethodRS256, SigningMethodRS384, SigningMethodRS512,
+		SigningMethodPS256, SigningMethodPS384, SigningMethodPS512,
+		SigningMethodES256, SigningMethodES384, SigningMethodES512,
+		SigningMethodEdDSA,
+	} {
+		method := method
+		RegisterSigningMethod(method.Alg(), func() SigningMethod { return method })
+	}
+}
+
+// Alg returns the algorithm.
+func (m *SigningMethodHMAC) Alg() string {
+	return m.Name
+}
+
+// Sign signs the signing string with a []byte key.
+func (m *SigningMethodHMAC) Sign(signingString string, key interface{}) (string, error) {
+	secret, ok := key.([]byte)
+	if !ok {
+		return "", ErrInvalidKeyType
+	}
+
+	signer, err := jws.InitHMACSignerVerifier(jwa.Algorithm(m.Name), secret)
+	if nil != err {
+		return "", invalidKey(err)
+	}
+	return sign(signer, signingString)
+}
+
+// Verify verifies the signature of the signing string with a []byte key.
+func (m *SigningMethodHMAC) Verify(signingString, signature string, key interface{}) error {
+	secret, ok := key.([]byte)
+	if !ok {
+		return ErrInvalidKeyType
+	}
+
+	verifier, err := jws.InitHMACSignerVerifier(jwa.Algorithm(m.Name), secret)
+	if nil != err {
+		return invalidKey(err)
+	}
+	return verify(verifier, signingString, signature)
+}
+
+// Alg returns the algorithm.
+func (m *SigningMethodRSA) Alg() string {
+	return m.Name
+}
+
+// Sign signs the signing string with an *rsa.PrivateKey.
+func (m *SigningMethodRSA) Sign(signingString string, key interface{}) (string, error) {
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return "", ErrInvalidKeyType
+	}
+
+	signer, err := jws.InitRSASigner(jwa.Algorithm(m.Name), rsaKey)
+	if nil != err {
+		return "", invalidKey(err)
+	}
+	return sign(signer, signingString)
+}
+
+// Verify verifies the signature of the signing string with an
+// *rsa.PublicKey.
+func (m *SigningMethodRSA) Verify(signingString, signature string, key interface{}) error {
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return ErrInvalidKeyType
+	}
+
+	verifier, err := jws.InitRSAVerifier(jwa.Algorithm(m.Name), rsaKey)
+	if nil != err {
+		return invalidKey(err)
+	}
+	return verify(verifier, signingString, signature)
+}
+
+// Alg returns the algorithm.
+func (m *SigningMethodECDSA) Alg() string {
+	return m.Name
+}
+
+// Sign signs the signing string with an *ecdsa.PrivateKey.
+func (m *SigningMethodECDSA) Sign(signingString string, key interface{}) (string, error) {
+	ecdsaKey, ok := key.(*ecdsa.PrivateKey)
+	if !ok {
+		return "", ErrInvalidKeyType
+	}
+
+	signer, err := jws.InitECDSASigner(jwa.Algorithm(m.Name), ecdsaKey)
+	if nil != err {
+		return "", invalidKey(err)
+	}
+	return sign(signer, signingString)
+}
+
+// Verify verifies the signature of the signing string with an
+// *ecdsa.PublicKey.
+func (m *SigningMethodECDSA) Verify(signingString, signature string, key interface{}) error {
+	ecdsaKey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return ErrInvalidKeyType
+	}
+
+	verifier, err := jws.InitECDSAVerifier(jwa.Algorithm(m.Name), ecdsaKey)
+	if nil != err {
+		return invalidKey(err)
+	}
+	return verify(verifier, signingString, signature)
+}
+
+// Alg returns "EdDSA".
+func (m *SigningMethodEd25519) Alg() string {
+	return string(jwa.EdDSA)
+}
+
+// Sign signs the signing string with an ed25519.PrivateKey.
+func (m *SigningMethodEd25519) Sign(signingString string, key interface{}) (string, error) {
+	var edKey ed25519.PrivateKey
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		edKey = k
+	case *ed25519.PrivateKey:
+		edKey = *k
+	default:
+		return "", ErrInvalidKeyType
+	}
+
+	signer, err := jws.InitEdDSASigner(jwa.EdDSA, &edKey)
+	if nil != err {
+		return "", invalidKey(err)
+	}
+	return sign(signer, signingString)
+}
+
+// Verify verifies the signature of the signing string with an
+// ed25519.PublicKey.
+func (m *SigningMethodEd25519) Verify(signingString, signature string, key interface{}) error {
+	var edKey ed25519.PublicKey
+	switch k := key.(type) {
+	case ed25519.PublicKey:
+		edKey = k
+	case *ed25519.PublicKey:
+		edKey = *k
+	default:
+		return ErrInvalidKeyType
+	}
+
+	verifier, err := jws.InitEdDSAVerifier(jwa.EdDSA, &edKey)
+	if nil != err {
+		return invalidKey(err)
+	}
+	return verify(verifier, signingString, signature)
+}
+
+// invalidKey returns an ErrInvalidKey for the reason a key was refused.
+func invalidKey(err error) error {
+	return fmt.Errorf("%w: %v", ErrInvalidKey, err)
+}
+
+// sign signs the signing string, returning the base64url encoded
+// signature.
+func sign(signer jws.TokenSigner, signingString string) (string, error) {
+	signature, err := signer.Sign([]byte(signingString))
+	if nil != err {
+		return "", err
+	}
+
+	return jws.Base64URLEncode(signature), nil
+}
+
+// verify verifies the base64url encoded signature of the signing string.
+func verify(verifier jws.TokenVerifier, signingString string, signature string) error {
+	decoded, err := jws.Base64URLDecode(signature)
+	if nil != err {
+		return err
+	}
+
+	valid, err := verifier.Verify([]byte(signingString), decoded)
+	if nil != err {
+		return err
+	}
+	if !valid {
+		return ErrTokenSignatureInvalid
+	}
+
+	return nil
+}
@@ -0,0 +1,148 @@
+package jwtcompat
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/georgejenkins/jwt/jws"
+)
+
+// Keyfunc returns the key to verify a parsed, but not yet verified, token
+// with. It should check the token's Method is the one expected.
+type Keyfunc func(*Token) (interface{}, error)
+
+// Token is a JWT being created or parsed.
+type Token struct {
+	// Raw is the token, when parsed.
+	Raw       string
+	Method    SigningMethod
+	Header    map[string]interface{}
+	Claims    Claims
+	Signature string
+
+	// Valid is set when a parsed token's signature and claims are valid.
+	Valid bool
+}
+
+// New creates a token for the signing method, with empty MapClaims.
+func New(method SigningMethod) *Token {
+	return NewWithClaims(method, MapClaims{})
+}
+
+// NewWithClaims creates a token for the signing method and claims.
+func NewWithClaims(method SigningMethod, claims Claims) *Token {
+	return &Token{
+		Header: map[string]interface{}{
+			"typ": "JWT",
+			"alg": method.Alg(),
+		},
+		Claims: claims,
+		Method: method,
+	}
+}
+
+// SignedString signs the token with the key, returning the compact JWS.
+func (t *Token) SignedString(key interface{}) (string, error) {
+	signingString, err := t.SigningString()
+	if nil != err {
+		return "", err
+	}
+
+	signature, err := t.Method.Sign(signingString, key)
+	if nil != err {
+		return "", err
+	}
+
+	return signingString + "." + signature, nil
+}
+
+// SigningString returns the encoded header and claims joined by a '.',
+// which is signed.
+func (t *Token) SigningString() (string, error) {
+	header, err := json.Marshal(t.Header)
+	if nil != err {
+		return "", err
+	}
+
+	claims, err := json.Marshal(t.Claims)
+	if nil != err {
+		return "", err
+	}
+
+	return jws.Base64URLEncode(header) + "." + jws.Base64URLEncode(claims), nil
+}
+
+// Parse parses and verifies a token with MapClaims, see ParseWithClaims.
+func Parse(tokenString string, keyFunc Keyfunc) (*Token, error) {
+	return ParseWithClaims(tokenString, MapClaims{}, keyFunc)
+}
+
+// ParseWithClaims parses a token, decoding its claims into claims, and
+// verifies its signature with the key returned by keyFunc and its claims
+// with their Valid method. The token is returned with a *ValidationError
+// if it is invalid, and is Valid otherwise.
+func ParseWithClaims(tokenString string, claims Claims, keyFunc Keyfunc) (*Token, error) {
+	parts := strings.Split(tokenString, ".")
+	if len(parts) != 3 {
+		return nil, NewValidationError("Token contains an invalid number of segments", ValidationErrorMalformed)
+	}
+
+	token := &Token{Raw: tokenString, Signature: parts[2]}
+
+	headerBytes, err := jws.Base64URLDecode(parts[0])
+	if nil != err {
+		return token, &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
+	}
+	if err := json.Unmarshal(headerBytes, &token.Header); nil != err {
+		return token, &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
+	}
+
+	claimBytes, err := jws.Base64URLDecode(parts[1])
+	if nil != err {
+		return token, &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
+	}
+	decoder := json.NewDecoder(bytes.NewReader(claimBytes))
+	if mapClaims, ok := claims.(MapClaims); ok {
+		err = decoder.Decode(&mapClaims)
+	} else {
+		err = decoder.Decode(&claims)
+	}
+	if nil != err {
+		return token, &ValidationError{Inner: err, Errors: ValidationErrorMalformed}
+	}
+	token.Claims = claims
+
+	alg, _ := token.Header["alg"].(string)
+	if token.Method = GetSigningMethod(alg); nil == token.Method {
+		return token, NewValidationError("Signing method (alg) is unavailable", ValidationErrorUnverifiable)
+	}
+
+	if nil == keyFunc {
+		return token, NewValidationError("No Keyfunc was provided", ValidationErrorUnverifiable)
+	}
+	key, err := keyFunc(token)
+	if nil != err {
+		var vErr *ValidationError
+		if errors.As(err, &vErr) {
+			return token, vErr
+		}
+		return token, &ValidationError{Inner: err, Errors: ValidationErrorUnverifiable}
+	}
+
+	if err := token.Method.Verify(parts[0]+"."+parts[1], token.Signature, key); nil != err {
+		return token, &ValidationError{Inner: err, Errors: ValidationErrorSignatureInvalid}
+	}
+
+	if err := claims.Valid(); nil != err {
+		var vErr *ValidationError
+		if errors.As(err, &vErr) {
+			return token, vErr
+		}
+		return token, &ValidationError{Inner: err, Errors: ValidationErrorClaimsInvalid}
+	}
+
+	token.Valid = true
+	return token, nil
+}
@@ -0,0 +1,257 @@
+package jwtcompat
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSignedStringParse(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	otherRSAKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	otherECKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	edPublic, edPrivate, _ := ed25519.GenerateKey(rand.Reader)
+	otherEdPublic, _, _ := ed25519.GenerateKey(rand.Reader)
+	secret := []byte("0123456789abcdef0123456789abcdef")
+
+	tests := []struct {
+		name       string
+		method     SigningMethod
+		signKey    interface{}
+		verifyKey  interface{}
+		wantErrors uint32
+	}{
+		{
+			name:      "Must round trip HS256",
+			method:    SigningMethodHS256,
+			signKey:   secret,
+			verifyKey: secret,
+		},
+		{
+			name:      "Must round trip RS256",
+			method:    SigningMethodRS256,
+			signKey:   rsaKey,
+			verifyKey: &rsaKey.PublicKey,
+		},
+		{
+			name:      "Must round trip PS256",
+			method:    SigningMethodPS256,
+			signKey:   rsaKey,
+			verifyKey: &rsaKey.PublicKey,
+		},
+		{
+			name:      "Must round trip ES256",
+			method:    SigningMethodES256,
+			signKey:   ecKey,
+			verifyKey: &ecKey.PublicKey,
+		},
+		{
+			name:      "Must round trip EdDSA",
+			method:    SigningMethodEdDSA,
+			signKey:   edPrivate,
+			verifyKey: edPublic,
+		},
+		{
+			name:       "Must reject HS256 signed with another secret",
+			method:     SigningMethodHS256,
+			signKey:    secret,
+			verifyKey:  []byte("fedcba9876543210fedcba9876543210"),
+			wantErrors: ValidationErrorSignatureInvalid,
+		},
+		{
+			name:       "Must reject RS256 signed with another key",
+			method:     SigningMethodRS256,
+			signKey:    rsaKey,
+			verifyKey:  &otherRSAKey.PublicKey,
+			wantErrors: ValidationErrorSignatureInvalid,
+		},
+		{
+			name:       "Must reject ES256 signed with another key",
+			method:     SigningMethodES256,
+			signKey:    ecKey,
+			verifyKey:  &otherECKey.PublicKey,
+			wantErrors: ValidationErrorSignatureInvalid,
+		},
+		{
+			name:       "Must reject EdDSA signed with another key",
+			method:     SigningMethodEdDSA,
+			signKey:    edPrivate,
+			verifyKey:  otherEdPublic,
+			wantErrors: ValidationErrorSignatureInvalid,
+		},
+		{
+			name:       "Must reject a verification key of the wrong type",
+			method:     SigningMethodRS256,
+			signKey:    rsaKey,
+			verifyKey:  secret,
+			wantErrors: ValidationErrorSignatureInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signed, err := NewWithClaims(tt.method, MapClaims{"sub": "alice"}).SignedString(tt.signKey)
+			if nil != err {
+				t.Fatalf("SignedString() error = %v", err)
+			}
+
+			token, err := Parse(signed, func(token *Token) (interface{}, error) {
+				if token.Method.Alg() != tt.method.Alg() {
+					return nil, errors.New("Unexpected signing method")
+				}
+				return tt.verifyKey, nil
+			})
+
+			if tt.wantErrors != 0 {
+				var vErr *ValidationError
+				if !errors.As(err, &vErr) || vErr.Errors&tt.wantErrors == 0 {
+					t.Fatalf("Parse() error = %v, want errors %b", err, tt.wantErrors)
+				}
+				if token.Valid {
+					t.Errorf("Parse() token is valid")
+				}
+				return
+			}
+
+			if nil != err {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !token.Valid {
+				t.Errorf("Parse() token is not valid")
+			}
+			if sub := token.Claims.(MapClaims)["sub"]; sub != "alice" {
+				t.Errorf("Parse() sub = %v, want alice", sub)
+			}
+		})
+	}
+}
+
+func TestParseWithClaims(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	keyFunc := func(token *Token) (interface{}, error) {
+		if _, ok := token.Method.(*SigningMethodHMAC); !ok {
+			return nil, errors.New("Unexpected signing method")
+		}
+		return secret, nil
+	}
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		token   func() string
+		claims  Claims
+		wantErr error
+	}{
+		{
+			name: "Must accept valid registered claims",
+			token: func() string {
+				signed, _ := NewWithClaims(SigningMethodHS256, RegisteredClaims{
+					Issuer:    "issuer",
+					Audience:  ClaimStrings{"audience"},
+					IssuedAt:  NewNumericDate(now),
+					ExpiresAt: NewNumericDate(now.Add(time.Hour)),
+				}).SignedString(secret)
+				return signed
+			},
+			claims: &RegisteredClaims{},
+		},
+		{
+			name: "Must reject expired registered claims",
+			token: func() string {
+				signed, _ := NewWithClaims(SigningMethodHS256, RegisteredClaims{
+					ExpiresAt: NewNumericDate(now.Add(-time.Hour)),
+				}).SignedString(secret)
+				return signed
+			},
+			claims:  &RegisteredClaims{},
+			wantErr: ErrTokenExpired,
+		},
+		{
+			name: "Must reject standard claims that are not valid yet",
+			token: func() string {
+				signed, _ := NewWithClaims(SigningMethodHS256, StandardClaims{
+					NotBefore: now.Add(time.Hour).Unix(),
+				}).SignedString(secret)
+				return signed
+			},
+			claims:  &StandardClaims{},
+			wantErr: ErrTokenNotValidYet,
+		},
+		{
+			name: "Must reject expired map claims",
+			token: func() string {
+				signed, _ := NewWithClaims(SigningMethodHS256, MapClaims{
+					"exp": now.Add(-time.Hour).Unix(),
+				}).SignedString(secret)
+				return signed
+			},
+			claims:  MapClaims{},
+			wantErr: ErrTokenExpired,
+		},
+		{
+			name: "Must reject a signing method refused by the keyfunc",
+			token: func() string {
+				key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+				signed, _ := New(SigningMethodES256).SignedString(key)
+				return signed
+			},
+			claims:  MapClaims{},
+			wantErr: ErrTokenUnverifiable,
+		},
+		{
+			name: "Must reject the none algorithm",
+			token: func() string {
+				return "eyJhbGciOiJub25lIiwidHlwIjoiSldUIn0.eyJzdWIiOiJhbGljZSJ9."
+			},
+			claims:  MapClaims{},
+			wantErr: ErrTokenUnverifiable,
+		},
+		{
+			name: "Must reject a token with too few segments",
+			token: func() string {
+				return "eyJhbGciOiJIUzI1NiJ9.eyJzdWIiOiJhbGljZSJ9"
+			},
+			claims:  MapClaims{},
+			wantErr: ErrTokenMalformed,
+		},
+		{
+			name: "Must reject a tampered token",
+			token: func() string {
+				signed, _ := New(SigningMethodHS256).SignedString(secret)
+				return signed[:len(signed)-2] + "AA"
+			},
+			claims:  MapClaims{},
+			wantErr: ErrTokenSignatureInvalid,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := ParseWithClaims(tt.token(), tt.claims, keyFunc)
+			if nil != tt.wantErr {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseWithClaims() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+
+			if nil != err {
+				t.Fatalf("ParseWithClaims() error = %v", err)
+			}
+			if !token.Valid {
+				t.Errorf("ParseWithClaims() token is not valid")
+			}
+
+			claims := token.Claims.(*RegisteredClaims)
+			if !claims.VerifyIssuer("issuer", true) || !claims.VerifyAudience("audience", true) {
+				t.Errorf("ParseWithClaims() claims = %+v", claims)
+			}
+		})
+	}
+}
@@ -0,0 +1,217 @@
+package jwt
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// KeyFileEvent describes a reload of a key file, or its failure.
+type KeyFileEvent struct {
+	Time    time.Time
+	Path    string
+	ModTime time.Time
+	Err     error
+}
+
+// KeyFileSignerVerifier signs and verifies with a key loaded from a file,
+// in any format accepted by ParseKey. Once watched, the file is polled for
+// changes to its modification time or size, and a changed key replaces the
+// previous one in a single atomic step, so deployments rotating keys
+// through mounted secrets don't need restarts. Failed reloads, such as of
+// a partially written file, keep the previous key and are retried at the
+// next interval.
+//
+// The previous key stops verifying as soon as it is replaced. To verify
+// tokens signed before a rotation, use a Keyring.
+type KeyFileSignerVerifier struct {
+	path string
+	alg  Algorithm
+	opts []Option
+
+	current atomic.Value
+
+	mu        sync.Mutex
+	modTime   time.Time
+	size      int64
+	callbacks []func(KeyFileEvent)
+
+	done      chan struct{}
+	closeOnce sync.Once
+	watchOnce sync.Once
+}
+
+// NewKeyFileSignerVerifier creates a KeyFileSignerVerifier for the
+// algorithm with the key in the file at path. The options are applied to
+// every key loaded.
+func NewKeyFileSignerVerifier(path string, alg Algorithm, opts ...Option) (*KeyFileSignerVerifier, error) {
+	kf := &KeyFileSignerVerifier{
+		path: path,
+		alg:  alg,
+		opts: opts,
+		done: make(chan struct{}),
+	}
+
+	if _, err := kf.reload(); nil != err {
+		return nil, err
+	}
+
+	return kf, nil
+}
+
+// OnReload registers a callback invoked after every reload of a changed
+// file, including failed reloads.
+func (kf *KeyFileSignerVerifier) OnReload(callback func(KeyFileEvent)) {
+	kf.mu.Lock()
+	defer kf.mu.Unlock()
+
+	kf.callbacks = append(kf.callbacks, callback)
+}
+
+// Reload loads the key file, replacing the current key if it parses and
+// is accepted for the algorithm.
+func (kf *KeyFileSignerVerifier) Reload() error {
+	event, err := kf.reload()
+	kf.notify(event)
+	return err
+}
+
+func (kf *KeyFileSignerVerifier) reload() (KeyFileEvent, error) {
+	kf.mu.Lock()
+	defer kf.mu.Unlock()
+
+	event := KeyFileEvent{Time: time.Now(), Path: kf.path}
+
+	info, err := os.Stat(kf.path)
+	if nil != err {
+		event.Err = err
+		return event, err
+	}
+	event.ModTime = info.ModTime()
+
+	data, err := ioutil.ReadFile(kf.path)
+	if nil != err {
+		event.Err = err
+		return event, err
+	}
+
+	sv, err := kf.load(data)
+	if nil != err {
+		event.Err = err
+		return event, err
+	}
+
+	kf.current.Store(sv)
+	kf.modTime = info.ModTime()
+	kf.size = info.Size()
+
+	return event, nil
+}
+
+// load creates a signer verifier for the key data, zeroing the data once
+// parsed.
+func (kf *KeyFileSignerVerifier) load(data []byte) (*JOSESignerVerifier, error) {
+	defer func() {
+		for i := range data {
+			data[i] = 0
+		}
+	}()
+
+	key, err := ParseKey(data)
+	if nil != err {
+		return nil, err
+	}
+
+	return NewJOSESignerVerifier(kf.alg, key, kf.opts...)
+}
+
+// changed reports whether the key file has changed since the last
+// successful load.
+func (kf *KeyFileSignerVerifier) changed() bool {
+	info, err := os.Stat(kf.path)
+	if nil != err {
+		return true
+	}
+
+	kf.mu.Lock()
+	defer kf.mu.Unlock()
+
+	return !info.ModTime().Equal(kf.modTime) || info.Size() != kf.size
+}
+
+func (kf *KeyFileSignerVerifier) notify(event KeyFileEvent) {
+	kf.mu.Lock()
+	callbacks := append([]func(KeyFileEvent){}, kf.callbacks...)
+	kf.mu.Unlock()
+
+	for _, callback := range callbacks {
+		callback(event)
+	}
+}
+
+// Watch polls the key file every interval until Close is called,
+// reloading it when it changes.
+func (kf *KeyFileSignerVerifier) Watch(interval time.Duration) error {
+	if interval <= 0 {
+		return errors.New("Key file watch interval must be positive")
+	}
+
+	started := false
+	kf.watchOnce.Do(func() {
+		started = true
+		go kf.watchLoop(interval)
+	})
+	if !started {
+		return errors.New("Key file is already watched")
+	}
+
+	return nil
+}
+
+func (kf *KeyFileSignerVerifier) watchLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-kf.done:
+			return
+		case <-ticker.C:
+			if kf.changed() {
+				kf.Reload()
+			}
+		}
+	}
+}
+
+// Close stops watching the key file.
+func (kf *KeyFileSignerVerifier) Close() {
+	kf.closeOnce.Do(func() {
+		close(kf.done)
+	})
+}
+
+// SignerVerifier returns the signer verifier for the current key, for
+// operations beyond signing and verifying tokens.
+func (kf *KeyFileSignerVerifier) SignerVerifier() *JOSESignerVerifier {
+	return kf.current.Load().(*JOSESignerVerifier)
+}
+
+// GenerateToken signs a token with the current key.
+func (kf *KeyFileSignerVerifier) GenerateToken(header interface{}, body interface{}) ([]byte, error) {
+	return kf.SignerVerifier().GenerateToken(header, body)
+}
+
+// VerifySignature verifies the signature of a token with the current key.
+func (kf *KeyFileSignerVerifier) VerifySignature(rawToken []byte) (*Token, bool, error) {
+	return kf.SignerVerifier().VerifySignature(rawToken)
+}
+
+// VerifyToken verifies a token with the current key, and validates its
+// claims against the criteria.
+func (kf *KeyFileSignerVerifier) VerifyToken(rawToken []byte, validationCriteria *ValidationClaims) (*Token, bool, error) {
+	return kf.SignerVerifier().VerifyToken(rawToken, validationCriteria)
+}
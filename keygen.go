@@ -0,0 +1,124 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"io"
+
+	"github.com/georgejenkins/jwt/jwe"
+)
+
+// GeneratedRSAKeySize is the size in bits of RSA keys generated by
+// GenerateKey, the minimum RFC 7518 requires for the RS and PS algorithms.
+const GeneratedRSAKeySize = 2048
+
+// GenerateKey generates a key suited to the algorithm, and returns it with
+// a JOSESignerVerifier using it, configured with the options:
+//
+//	RS*, PS*: a GeneratedRSAKeySize bit *rsa.PrivateKey
+//	ES256, ES384, ES512: an *ecdsa.PrivateKey on P-256, P-384 or P-521
+//	EdDSA: an *ed25519.PrivateKey
+//	HS256, HS384, HS512: a random []byte secret the size of the hash
+func GenerateKey(alg Algorithm, opts ...Option) (interface{}, *JOSESignerVerifier, error) {
+	key, err := generateKey(alg)
+	if nil != err {
+		return nil, nil, err
+	}
+
+	sv, err := NewJOSESignerVerifier(alg, key, opts...)
+	if nil != err {
+		return nil, nil, err
+	}
+
+	return key, sv, nil
+}
+
+// generateKey generates a key suited to the algorithm, see GenerateKey.
+func generateKey(alg Algorithm) (interface{}, error) {
+	switch alg {
+	case RS256, RS384, RS512, PS256, PS384, PS512:
+		return rsa.GenerateKey(rand.Reader, GeneratedRSAKeySize)
+	case ES256:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	case ES384:
+		return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	case ES512:
+		return ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	case EdDSA:
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		if nil != err {
+			return nil, err
+		}
+		return &key, nil
+	case HS256:
+		return generateSecret(32)
+	case HS384:
+		return generateSecret(48)
+	case HS512:
+		return generateSecret(64)
+	}
+
+	return nil, fmt.Errorf("Cannot generate a key for algorithm %q", alg)
+}
+
+// GenerateJWEKey generates a key suited to the key management algorithm
+// and content encryption algorithm, and returns it with a
+// JWEEncrypterDecrypter using it, configured with the options:
+//
+//	dir: a random []byte content encryption key of the size enc requires,
+//	  the pre-shared key of first-party encrypted session tokens
+//	A*KW, A*GCMKW: a random []byte key encryption key of the size alg requires
+//	RSA-OAEP, RSA-OAEP-256: a GeneratedRSAKeySize bit *rsa.PrivateKey
+//	ECDH-ES, ECDH-ES+A*KW: a P-256 *ecdsa.PrivateKey
+func GenerateJWEKey(alg KeyManagementAlgorithm, enc ContentEncryptionAlgorithm, opts ...JWEOption) (interface{}, *JWEEncrypterDecrypter, error) {
+	key, err := generateJWEKey(alg, enc)
+	if nil != err {
+		return nil, nil, err
+	}
+
+	ed, err := NewJWEEncrypterDecrypter(alg, enc, key, opts...)
+	if nil != err {
+		return nil, nil, err
+	}
+
+	return key, ed, nil
+}
+
+// generateJWEKey generates a key suited to the algorithms, see
+// GenerateJWEKey.
+func generateJWEKey(alg KeyManagementAlgorithm, enc ContentEncryptionAlgorithm) (interface{}, error) {
+	switch alg {
+	case Direct:
+		size, err := jwe.CEKSize(enc)
+		if nil != err {
+			return nil, err
+		}
+		return generateSecret(size)
+	case A128KW, A128GCMKW:
+		return generateSecret(16)
+	case A192KW, A192GCMKW:
+		return generateSecret(24)
+	case A256KW, A256GCMKW:
+		return generateSecret(32)
+	case RSAOAEP, RSAOAEP256:
+		return rsa.GenerateKey(rand.Reader, GeneratedRSAKeySize)
+	case ECDHES, ECDHESA128KW, ECDHESA192KW, ECDHESA256KW:
+		return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	}
+
+	return nil, fmt.Errorf("Cannot generate a key for JWE alg %q", alg)
+}
+
+// generateSecret returns a random secret of size bytes.
+func generateSecret(size int) ([]byte, error) {
+	secret := make([]byte, size)
+	if _, err := io.ReadFull(rand.Reader, secret); nil != err {
+		return nil, fmt.Errorf("Cannot read random bytes for secret: %s", err)
+	}
+
+	return secret, nil
+}
@@ -0,0 +1,113 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"testing"
+)
+
+func TestGenerateKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		alg      Algorithm
+		wantSize int
+		wantErr  bool
+	}{
+		{"Must generate an RSA key for RS256", RS256, GeneratedRSAKeySize, false},
+		{"Must generate an RSA key for PS512", PS512, GeneratedRSAKeySize, false},
+		{"Must generate a P-256 key for ES256", ES256, 256, false},
+		{"Must generate a P-384 key for ES384", ES384, 384, false},
+		{"Must generate a P-521 key for ES512", ES512, 521, false},
+		{"Must generate an Ed25519 key for EdDSA", EdDSA, ed25519.PrivateKeySize * 8, false},
+		{"Must generate a 256 bit secret for HS256", HS256, 256, false},
+		{"Must generate a 512 bit secret for HS512", HS512, 512, false},
+		{"Must fail for none", None, 0, true},
+		{"Must fail for an unknown algorithm", Algorithm("XS256"), 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, sv, err := GenerateKey(tt.alg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GenerateKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var size int
+			switch key := key.(type) {
+			case *rsa.PrivateKey:
+				size = key.N.BitLen()
+			case *ecdsa.PrivateKey:
+				size = key.Curve.Params().BitSize
+			case *ed25519.PrivateKey:
+				size = len(*key) * 8
+			case []byte:
+				size = len(key) * 8
+			}
+			if size != tt.wantSize {
+				t.Errorf("GenerateKey() key %T of %d bits, want %d", key, size, tt.wantSize)
+			}
+
+			token, err := sv.GenerateToken(Header{Algorithm: string(tt.alg)}, Claims{Subject: "alice"})
+			if nil != err {
+				t.Fatalf("GenerateToken() error = %v", err)
+			}
+			if _, valid, err := sv.VerifyToken(token, nil); !valid || nil != err {
+				t.Errorf("VerifyToken() = %v, %v with a generated key", valid, err)
+			}
+		})
+	}
+}
+
+func TestGenerateJWEKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		alg      KeyManagementAlgorithm
+		enc      ContentEncryptionAlgorithm
+		wantSize int
+		wantErr  bool
+	}{
+		{"Must generate a 256 bit key for dir with A256GCM", Direct, A256GCM, 256, false},
+		{"Must generate a 512 bit key for dir with A256CBC-HS512", Direct, A256CBCHS512, 512, false},
+		{"Must generate a 128 bit key for A128KW", A128KW, A256GCM, 128, false},
+		{"Must generate a 256 bit key for A256GCMKW", A256GCMKW, A128CBCHS256, 256, false},
+		{"Must generate an RSA key for RSA-OAEP-256", RSAOAEP256, A256GCM, GeneratedRSAKeySize, false},
+		{"Must generate a P-256 key for ECDH-ES", ECDHES, A256GCM, 256, false},
+		{"Must fail for dir with an unknown enc", Direct, "A64GCM", 0, true},
+		{"Must fail for an unknown algorithm", KeyManagementAlgorithm("A64KW"), A256GCM, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, ed, err := GenerateJWEKey(tt.alg, tt.enc)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GenerateJWEKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var size int
+			switch key := key.(type) {
+			case *rsa.PrivateKey:
+				size = key.N.BitLen()
+			case *ecdsa.PrivateKey:
+				size = key.Curve.Params().BitSize
+			case []byte:
+				size = len(key) * 8
+			}
+			if size != tt.wantSize {
+				t.Errorf("GenerateJWEKey() key %T of %d bits, want %d", key, size, tt.wantSize)
+			}
+
+			token, err := ed.GenerateToken(JWEHeader{}, Claims{Subject: "alice"})
+			if nil != err {
+				t.Fatalf("GenerateToken() error = %v", err)
+			}
+			if _, valid, err := ed.DecryptToken(token, nil); !valid || nil != err {
+				t.Errorf("DecryptToken() = %v, %v with a generated key", valid, err)
+			}
+		})
+	}
+}
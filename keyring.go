@@ -0,0 +1,263 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/georgejenkins/jwt/jwk"
+)
+
+// ErrNoSigningKey is returned when a Keyring has no key that may sign.
+var ErrNoSigningKey = errors.New("Keyring has no current signing key")
+
+// KeyState is the rotation state of a key in a Keyring.
+type KeyState string
+
+const (
+	// KeyPending keys are published and verify tokens, but don't sign
+	// until their NotBefore time, so verifiers learn of them in advance.
+	KeyPending KeyState = "pending"
+	// KeyActive keys sign and verify tokens. The active key with the
+	// latest NotBefore is the current signing key.
+	KeyActive KeyState = "active"
+	// KeyRetired keys only verify tokens, for the grace window after
+	// their RetireAfter time.
+	KeyRetired KeyState = "retired"
+	// KeyExpired keys are past the grace window and are no longer used.
+	KeyExpired KeyState = "expired"
+)
+
+// KeyringKey is a key in a Keyring, with its rotation metadata.
+type KeyringKey struct {
+	// KeyID and Algorithm are required.
+	KeyID     string
+	Algorithm Algorithm
+
+	// Key is a private key or HMAC secret to sign with, or a public key
+	// that can only verify.
+	Key interface{}
+
+	// NotBefore is when the key may start signing, and RetireAfter when
+	// it stops. A zero RetireAfter never retires the key.
+	NotBefore   time.Time
+	RetireAfter time.Time
+}
+
+// stateAt returns the key's rotation state at now, given the grace window.
+func (k *KeyringKey) stateAt(now time.Time, grace time.Duration) KeyState {
+	switch {
+	case !k.RetireAfter.IsZero() && !now.Before(k.RetireAfter.Add(grace)):
+		return KeyExpired
+	case !k.RetireAfter.IsZero() && !now.Before(k.RetireAfter):
+		return KeyRetired
+	case now.Before(k.NotBefore):
+		return KeyPending
+	}
+	return KeyActive
+}
+
+// keyringEntry is a key with its signer/verifier.
+type keyringEntry struct {
+	key KeyringKey
+	sv  *JOSESignerVerifier
+}
+
+// Keyring holds the keys of an issuer through their rotation: it signs
+// with the current key, and verifies with any key that is pending, active
+// or retired within the grace window, so keys can be rotated without
+// invalidating tokens in flight.
+//
+// A Keyring is a KeyResolver, to verify tokens with NewKeyResolvingVerifier,
+// and a KeySetSource, to publish its keys with NewJWKSHandler.
+type Keyring struct {
+	grace time.Duration
+	opts  []Option
+
+	mu      sync.RWMutex
+	entries map[string]*keyringEntry
+}
+
+// NewKeyring creates an empty Keyring keeping retired keys for verification
+// for the grace window, which should be at least the lifetime of the
+// tokens signed. The options configure signing and verification with
+// every key.
+func NewKeyring(grace time.Duration, opts ...Option) (*Keyring, error) {
+	if grace < 0 {
+		return nil, errors.New("Keyring grace window cannot be negative")
+	}
+
+	return &Keyring{
+		grace:   grace,
+		opts:    opts,
+		entries: make(map[string]*keyringEntry),
+	}, nil
+}
+
+// Add adds a key to the Keyring. The key ID must be unique, and the key
+// suited to the algorithm.
+func (kr *Keyring) Add(key KeyringKey) error {
+	if key.KeyID == "" {
+		return errors.New("Keyring keys require a key ID")
+	}
+	if !key.RetireAfter.IsZero() && key.RetireAfter.Before(key.NotBefore) {
+		return fmt.Errorf("Key %q retires before it becomes active", key.KeyID)
+	}
+
+	key.Key = nativeKey(key.Key)
+	sv, err := NewJOSESignerVerifier(key.Algorithm, key.Key, kr.opts...)
+	if nil != err {
+		return err
+	}
+
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+
+	if _, ok := kr.entries[key.KeyID]; ok {
+		return fmt.Errorf("Keyring already holds key %q", key.KeyID)
+	}
+	kr.entries[key.KeyID] = &keyringEntry{key: key, sv: sv}
+	return nil
+}
+
+// Retire sets the time the key stops signing, after which it verifies
+// tokens for the grace window. A retirement already set is never
+// postponed.
+func (kr *Keyring) Retire(kid string, at time.Time) error {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+
+	entry, ok := kr.entries[kid]
+	if !ok {
+		return ErrUnknownKeyID
+	}
+	if at.Before(entry.key.NotBefore) {
+		at = entry.key.NotBefore
+	}
+
+	// Entries are read outside the lock, so are replaced, not modified.
+	if entry.key.RetireAfter.IsZero() || at.Before(entry.key.RetireAfter) {
+		retired := *entry
+		retired.key.RetireAfter = at
+		kr.entries[kid] = &retired
+	}
+	return nil
+}
+
+// Remove removes a key from the Keyring.
+func (kr *Keyring) Remove(kid string) {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+
+	delete(kr.entries, kid)
+}
+
+// SigningKey returns the current signing key: the active key able to sign
+// with the latest NotBefore.
+func (kr *Keyring) SigningKey() (KeyringKey, error) {
+	entry, err := kr.signingEntry(time.Now())
+	if nil != err {
+		return KeyringKey{}, err
+	}
+
+	return entry.key, nil
+}
+
+// signingEntry returns the current signing key at now.
+func (kr *Keyring) signingEntry(now time.Time) (*keyringEntry, error) {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+
+	var current *keyringEntry
+	for _, entry := range kr.entries {
+		if nil == entry.sv.signer || entry.key.stateAt(now, kr.grace) != KeyActive {
+			continue
+		}
+		if nil == current || entry.key.NotBefore.After(current.key.NotBefore) ||
+			(entry.key.NotBefore.Equal(current.key.NotBefore) && entry.key.KeyID > current.key.KeyID) {
+			current = entry
+		}
+	}
+
+	if nil == current {
+		return nil, ErrNoSigningKey
+	}
+	return current, nil
+}
+
+// GenerateToken signs a token with the current signing key, setting the
+// header's algorithm and key ID.
+func (kr *Keyring) GenerateToken(header Header, body interface{}) ([]byte, error) {
+	entry, err := kr.signingEntry(time.Now())
+	if nil != err {
+		return nil, err
+	}
+
+	header.Algorithm = string(entry.key.Algorithm)
+	header.KeyID = entry.key.KeyID
+	return entry.sv.GenerateToken(header, body)
+}
+
+// ResolveKey returns the key with the header's key ID, if it may verify
+// tokens: it must not have expired, and must be for the header's
+// algorithm.
+func (kr *Keyring) ResolveKey(header Header) (*jwk.Key, error) {
+	kr.mu.RLock()
+	entry, ok := kr.entries[header.KeyID]
+	kr.mu.RUnlock()
+
+	if !ok || entry.key.stateAt(time.Now(), kr.grace) == KeyExpired {
+		return nil, ErrUnknownKeyID
+	}
+	if Algorithm(header.Algorithm) != entry.key.Algorithm {
+		return nil, fmt.Errorf("Key %q is for algorithm %q, not %q", entry.key.KeyID, entry.key.Algorithm, header.Algorithm)
+	}
+
+	return entry.jwk(), nil
+}
+
+// KeySet returns the keys that may verify tokens, sorted by key ID. Use
+// it with NewJWKSHandler, which publishes only their public halves.
+func (kr *Keyring) KeySet() (*jwk.Set, error) {
+	now := time.Now()
+
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+
+	set := &jwk.Set{Keys: []*jwk.Key{}}
+	for _, entry := range kr.entries {
+		if entry.key.stateAt(now, kr.grace) != KeyExpired {
+			set.Keys = append(set.Keys, entry.jwk())
+		}
+	}
+
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].KeyID < set.Keys[j].KeyID })
+	return set, nil
+}
+
+// KeyStates returns the rotation state of each key, by key ID.
+func (kr *Keyring) KeyStates() map[string]KeyState {
+	now := time.Now()
+
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+
+	states := make(map[string]KeyState, len(kr.entries))
+	for kid, entry := range kr.entries {
+		states[kid] = entry.key.stateAt(now, kr.grace)
+	}
+
+	return states
+}
+
+// jwk returns the key as a signature JWK.
+func (entry *keyringEntry) jwk() *jwk.Key {
+	return &jwk.Key{
+		KeyID:     entry.key.KeyID,
+		Algorithm: entry.key.Algorithm,
+		Use:       jwk.UseSignature,
+		Key:       entry.key.Key,
+	}
+}
@@ -0,0 +1,118 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+	"time"
+)
+
+func TestKeyring(t *testing.T) {
+	now := time.Now()
+	newKey := func() *ecdsa.PrivateKey {
+		key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		return key
+	}
+	expiredKey, retiredKey, activeKey, pendingKey := newKey(), newKey(), newKey(), newKey()
+	_, olderKey, _ := ed25519.GenerateKey(rand.Reader)
+
+	kr, err := NewKeyring(2 * time.Hour)
+	if nil != err {
+		t.Fatalf("NewKeyring() error = %v", err)
+	}
+	keys := []KeyringKey{
+		{KeyID: "expired", Algorithm: ES256, Key: expiredKey, NotBefore: now.Add(-48 * time.Hour), RetireAfter: now.Add(-24 * time.Hour)},
+		{KeyID: "retired", Algorithm: ES256, Key: retiredKey, NotBefore: now.Add(-24 * time.Hour), RetireAfter: now.Add(-time.Hour)},
+		{KeyID: "older", Algorithm: EdDSA, Key: olderKey, NotBefore: now.Add(-12 * time.Hour)},
+		{KeyID: "active", Algorithm: ES256, Key: activeKey, NotBefore: now.Add(-time.Hour)},
+		{KeyID: "pending", Algorithm: ES256, Key: pendingKey, NotBefore: now.Add(time.Hour)},
+		{KeyID: "public", Algorithm: ES256, Key: &newKey().PublicKey, NotBefore: now},
+	}
+	for _, key := range keys {
+		if err := kr.Add(key); nil != err {
+			t.Fatalf("Keyring.Add(%q) error = %v", key.KeyID, err)
+		}
+	}
+
+	signing, err := kr.SigningKey()
+	if nil != err || signing.KeyID != "active" {
+		t.Errorf("Keyring.SigningKey() = %q, %v, want the active key", signing.KeyID, err)
+	}
+
+	wantStates := map[string]KeyState{
+		"expired": KeyExpired, "retired": KeyRetired, "older": KeyActive,
+		"active": KeyActive, "pending": KeyPending, "public": KeyActive,
+	}
+	for kid, state := range kr.KeyStates() {
+		if wantStates[kid] != state {
+			t.Errorf("Keyring.KeyStates()[%q] = %v, want %v", kid, state, wantStates[kid])
+		}
+	}
+
+	verifier, _ := NewKeyResolvingVerifier(kr)
+	claims := Claims{Subject: "alice"}
+
+	token, err := kr.GenerateToken(Header{}, claims)
+	if nil != err {
+		t.Fatalf("Keyring.GenerateToken() error = %v", err)
+	}
+	signed, valid, err := verifier.VerifySignature(token)
+	if !valid || nil != err || signed.RegisteredHeader.KeyID != "active" {
+		t.Errorf("VerifySignature() = %v, %v for a token signed by the keyring", valid, err)
+	}
+
+	signWith := func(kid string, alg Algorithm, key interface{}) []byte {
+		sv, _ := NewJOSESignerVerifier(alg, key)
+		token, _ := sv.GenerateToken(Header{Algorithm: string(alg), KeyID: kid}, claims)
+		return token
+	}
+	tests := []struct {
+		name      string
+		token     []byte
+		wantValid bool
+	}{
+		{"Must verify a token of a retired key within the grace window", signWith("retired", ES256, retiredKey), true},
+		{"Must verify a token of a pending key", signWith("pending", ES256, pendingKey), true},
+		{"Must verify a token of an Ed25519 key", signWith("older", EdDSA, &olderKey), true},
+		{"Must not verify a token of an expired key", signWith("expired", ES256, expiredKey), false},
+		{"Must not verify a token with another algorithm than the key's", signWith("active", ES384, newKeyP384()), false},
+		{"Must not verify a token of an unknown key", signWith("unknown", ES256, activeKey), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, valid, err := verifier.VerifySignature(tt.token)
+			if valid != tt.wantValid || (nil == err) != tt.wantValid {
+				t.Errorf("VerifySignature() = %v, %v, want %v", valid, err, tt.wantValid)
+			}
+		})
+	}
+
+	set, _ := kr.KeySet()
+	if len(set.Keys) != 5 || set.Keys[0].KeyID != "active" {
+		t.Errorf("Keyring.KeySet() holds %d keys, want the 5 unexpired keys sorted by key ID", len(set.Keys))
+	}
+
+	if err := kr.Add(KeyringKey{KeyID: "active", Algorithm: ES256, Key: newKey()}); nil == err {
+		t.Errorf("Keyring.Add() expected error for a duplicate key ID")
+	}
+	if err := kr.Add(KeyringKey{Algorithm: ES256, Key: newKey()}); nil == err {
+		t.Errorf("Keyring.Add() expected error without a key ID")
+	}
+	if err := kr.Add(KeyringKey{KeyID: "mismatch", Algorithm: RS256, Key: newKey()}); nil == err {
+		t.Errorf("Keyring.Add() expected error for a key unsuited to the algorithm")
+	}
+
+	kr.Remove("active")
+	kr.Remove("older")
+	kr.Remove("public")
+	if _, err := kr.SigningKey(); err != ErrNoSigningKey {
+		t.Errorf("Keyring.SigningKey() error = %v, want %v", err, ErrNoSigningKey)
+	}
+}
+
+func newKeyP384() *ecdsa.PrivateKey {
+	key, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	return key
+}
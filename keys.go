@@ -0,0 +1,192 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/georgejenkins/jwt/jwk"
+)
+
+// KeyFormat identifies an encoding of a key.
+type KeyFormat string
+
+// Key formats recognized by ParseKey.
+const (
+	KeyFormatPKCS1       KeyFormat = "PKCS#1"
+	KeyFormatPKCS8       KeyFormat = "PKCS#8"
+	KeyFormatSEC1        KeyFormat = "SEC1"
+	KeyFormatPKIX        KeyFormat = "PKIX"
+	KeyFormatCertificate KeyFormat = "X.509 certificate"
+	KeyFormatEd25519Seed KeyFormat = "Ed25519 seed"
+	KeyFormatJWK         KeyFormat = "JWK"
+)
+
+// derParser parses a DER encoded key in one format.
+type derParser struct {
+	format KeyFormat
+	parse  func(der []byte) (interface{}, error)
+}
+
+// derParsers are tried in turn on DER input. PKCS#1 is tried for both
+// private and public keys.
+var derParsers = []derParser{
+	{KeyFormatPKCS8, func(der []byte) (interface{}, error) { return x509.ParsePKCS8PrivateKey(der) }},
+	{KeyFormatPKCS1, func(der []byte) (interface{}, error) { return x509.ParsePKCS1PrivateKey(der) }},
+	{KeyFormatPKCS1, func(der []byte) (interface{}, error) { return x509.ParsePKCS1PublicKey(der) }},
+	{KeyFormatSEC1, func(der []byte) (interface{}, error) { return x509.ParseECPrivateKey(der) }},
+	{KeyFormatPKIX, func(der []byte) (interface{}, error) { return x509.ParsePKIXPublicKey(der) }},
+	{KeyFormatCertificate, parseCertificateKey},
+}
+
+// pemFormats maps PEM block types to the format of their contents.
+var pemFormats = map[string]KeyFormat{
+	"RSA PRIVATE KEY": KeyFormatPKCS1,
+	"RSA PUBLIC KEY":  KeyFormatPKCS1,
+	"EC PRIVATE KEY":  KeyFormatSEC1,
+	"PRIVATE KEY":     KeyFormatPKCS8,
+	"PUBLIC KEY":      KeyFormatPKIX,
+	"CERTIFICATE":     KeyFormatCertificate,
+}
+
+// ParseKey parses a key in any supported format into the native key type
+// accepted by NewJOSESignerVerifier. The format is detected from the
+// input: JSON is parsed as a JWK, PEM by its block type, and DER by trying
+// PKCS#8, PKCS#1, SEC1 and PKIX in turn, along with X.509 certificates.
+// 32 bytes that are not DER are taken as a raw Ed25519 seed. Ed25519 keys
+// are returned as pointers. Encrypted PEM keys return
+// ErrPassphraseRequired, see ParseKeyWithPassphrase.
+func ParseKey(data []byte) (interface{}, error) {
+	key, _, err := parseKey(data, nil)
+	return key, err
+}
+
+// parseKey parses a key, returning the detected format. Encrypted PEM keys
+// are decrypted with the passphrase from the provider.
+func parseKey(data []byte, passphrase PassphraseProvider) (interface{}, KeyFormat, error) {
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case len(trimmed) == 0:
+		return nil, "", errors.New("Key is empty")
+	case trimmed[0] == '{' && json.Valid(trimmed):
+		key, err := jwk.Parse(trimmed)
+		if nil != err {
+			return nil, "", err
+		}
+		return key, KeyFormatJWK, nil
+	case bytes.HasPrefix(trimmed, []byte("-----BEGIN ")):
+		return parsePEMKey(trimmed, passphrase)
+	}
+
+	return parseDERKey(data)
+}
+
+// parsePEMKey parses the single key block of PEM input, skipping any EC
+// parameters block.
+func parsePEMKey(data []byte, passphrase PassphraseProvider) (interface{}, KeyFormat, error) {
+	var block *pem.Block
+	for rest := data; ; {
+		var next *pem.Block
+		next, rest = pem.Decode(rest)
+		if nil == next {
+			break
+		}
+		if next.Type == "EC PARAMETERS" {
+			continue
+		}
+		if nil != block {
+			return nil, "", fmt.Errorf("Ambiguous key: PEM input holds both a %q and a %q block", block.Type, next.Type)
+		}
+		block = next
+	}
+	if nil == block {
+		return nil, "", errors.New("Malformed PEM key")
+	}
+
+	encrypted := isEncryptedPEMBlock(block)
+	if encrypted {
+		decrypted, err := decryptPEMBlock(block, passphrase)
+		if nil != err {
+			return nil, "", err
+		}
+		block = decrypted
+	}
+
+	format, ok := pemFormats[block.Type]
+	if !ok {
+		return nil, "", fmt.Errorf("Unsupported PEM block type %q", block.Type)
+	}
+
+	for _, parser := range derParsers {
+		if parser.format != format {
+			continue
+		}
+		if key, err := parser.parse(block.Bytes); nil == err {
+			return nativeKey(key), format, nil
+		}
+	}
+
+	// Decryption with the wrong passphrase can yield valid padding.
+	if encrypted {
+		return nil, "", ErrIncorrectPassphrase
+	}
+	return nil, "", fmt.Errorf("Malformed %s key in PEM block %q", format, block.Type)
+}
+
+// parseDERKey parses a DER key in any format, or a raw Ed25519 seed.
+func parseDERKey(der []byte) (interface{}, KeyFormat, error) {
+	var key interface{}
+	var formats []string
+	var format KeyFormat
+	for _, parser := range derParsers {
+		if parsed, err := parser.parse(der); nil == err {
+			key, format = parsed, parser.format
+			formats = append(formats, string(parser.format))
+		}
+	}
+
+	switch {
+	case len(formats) == 1:
+		return nativeKey(key), format, nil
+	case len(formats) > 1:
+		return nil, "", fmt.Errorf("Ambiguous key: parses as %s", strings.Join(formats, ", "))
+	case len(der) == ed25519.SeedSize:
+		private := ed25519.NewKeyFromSeed(der)
+		return &private, KeyFormatEd25519Seed, nil
+	}
+
+	return nil, "", fmt.Errorf("Unrecognized key format: not a JWK, PEM, DER or %d byte Ed25519 seed", ed25519.SeedSize)
+}
+
+// parseCertificateKey returns the public key of a DER X.509 certificate.
+func parseCertificateKey(der []byte) (interface{}, error) {
+	cert, err := x509.ParseCertificate(der)
+	if nil != err {
+		return nil, err
+	}
+
+	switch cert.PublicKey.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey:
+		return cert.PublicKey, nil
+	}
+	return nil, fmt.Errorf("Unsupported certificate key type %T", cert.PublicKey)
+}
+
+// nativeKey returns Ed25519 keys as pointers, as NewJOSESignerVerifier
+// expects.
+func nativeKey(key interface{}) interface{} {
+	switch k := key.(type) {
+	case ed25519.PrivateKey:
+		return &k
+	case ed25519.PublicKey:
+		return &k
+	}
+	return key
+}
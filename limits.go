@@ -0,0 +1,98 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ClaimLimits bounds the claim sets and tokens a JOSESignerVerifier will
+// issue, so that tokens stay within the header size limits of proxies and
+// load balancers. A zero limit is not enforced.
+type ClaimLimits struct {
+	// MaxClaims is the maximum number of top-level claims.
+	MaxClaims int
+	// MaxClaimSize is the maximum size, in bytes, of a single claim value
+	// once encoded as JSON.
+	MaxClaimSize int
+	// MaxTokenSize is the maximum size, in bytes, of the signed token.
+	MaxTokenSize int
+}
+
+// ClaimLimitError is returned when a claim set exceeds its ClaimLimits.
+// Claim is empty when the number of claims exceeds the limit.
+type ClaimLimitError struct {
+	Claim string
+	Size  int
+	Limit int
+}
+
+func (e *ClaimLimitError) Error() string {
+	if e.Claim == "" {
+		return fmt.Sprintf("Claim count %d exceeds the limit of %d", e.Size, e.Limit)
+	}
+
+	return fmt.Sprintf("Claim %q is %d bytes, exceeding the limit of %d bytes", e.Claim, e.Size, e.Limit)
+}
+
+// WithClaimLimits rejects generated tokens exceeding limits. Claim sets
+// over the claim count or claim size limits are rejected with a
+// ClaimLimitError before signing, and tokens over the token size limit
+// with a TokenSizeError.
+func WithClaimLimits(limits ClaimLimits) Option {
+	return func(sv *JOSESignerVerifier) error {
+		if limits.MaxClaims < 0 || limits.MaxClaimSize < 0 || limits.MaxTokenSize < 0 {
+			return errors.New("Claim limits cannot be negative")
+		}
+
+		sv.claimLimits = limits
+		return nil
+	}
+}
+
+// checkClaimLimits checks the encoded claim set against the claim count and
+// claim size limits.
+func (sv *JOSESignerVerifier) checkClaimLimits(jwsPayload []byte) error {
+	limits := sv.claimLimits
+	if limits.MaxClaims == 0 && limits.MaxClaimSize == 0 {
+		return nil
+	}
+
+	var claims map[string]json.RawMessage
+	if err := json.Unmarshal(jwsPayload, &claims); nil != err {
+		return err
+	}
+
+	if limits.MaxClaims > 0 && len(claims) > limits.MaxClaims {
+		return &ClaimLimitError{
+			Size:  len(claims),
+			Limit: limits.MaxClaims,
+		}
+	}
+
+	if limits.MaxClaimSize > 0 {
+		for name, value := range claims {
+			if len(value) > limits.MaxClaimSize {
+				return &ClaimLimitError{
+					Claim: name,
+					Size:  len(value),
+					Limit: limits.MaxClaimSize,
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkTokenSize checks a generated token against the token size limit.
+func (sv *JOSESignerVerifier) checkTokenSize(token []byte) error {
+	if sv.claimLimits.MaxTokenSize > 0 && len(token) > sv.claimLimits.MaxTokenSize {
+		return &TokenSizeError{
+			Size:       len(token),
+			SizeBudget: sv.claimLimits.MaxTokenSize,
+		}
+	}
+
+	return nil
+}
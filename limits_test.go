@@ -0,0 +1,89 @@
+package jwt
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithClaimLimits(t *testing.T) {
+	type args struct {
+		limits ClaimLimits
+		claims interface{}
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantClaim string
+		wantSize  bool
+		wantErr   bool
+	}{
+		{
+			name: "Must generate a token within limits",
+			args: args{
+				limits: ClaimLimits{MaxClaims: 3, MaxClaimSize: 32, MaxTokenSize: 512},
+				claims: map[string]interface{}{"sub": "alice", "iss": "issuer"},
+			},
+		},
+		{
+			name: "Must reject too many claims",
+			args: args{
+				limits: ClaimLimits{MaxClaims: 1},
+				claims: map[string]interface{}{"sub": "alice", "iss": "issuer"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Must reject an oversized claim",
+			args: args{
+				limits: ClaimLimits{MaxClaimSize: 32},
+				claims: map[string]interface{}{"sub": "alice", "groups": strings.Repeat("g", 64)},
+			},
+			wantClaim: "groups",
+			wantErr:   true,
+		},
+		{
+			name: "Must reject an oversized token",
+			args: args{
+				limits: ClaimLimits{MaxTokenSize: 64},
+				claims: map[string]interface{}{"sub": strings.Repeat("a", 64)},
+			},
+			wantSize: true,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sv, err := NewJOSESignerVerifier(HS256, exampleKey, WithClaimLimits(tt.args.limits))
+			if nil != err {
+				t.Fatalf("NewJOSESignerVerifier() error = %v", err)
+			}
+
+			_, err = sv.GenerateToken(Header{Algorithm: string(HS256)}, tt.args.claims)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GenerateToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr {
+				return
+			}
+
+			if tt.wantSize {
+				if _, ok := err.(*TokenSizeError); !ok {
+					t.Errorf("GenerateToken() error = %T, want *TokenSizeError", err)
+				}
+				return
+			}
+
+			limitErr, ok := err.(*ClaimLimitError)
+			if !ok {
+				t.Fatalf("GenerateToken() error = %T, want *ClaimLimitError", err)
+			}
+			if limitErr.Claim != tt.wantClaim {
+				t.Errorf("GenerateToken() error claim = %q, want %q", limitErr.Claim, tt.wantClaim)
+			}
+		})
+	}
+
+	if _, err := NewJOSESignerVerifier(HS256, exampleKey, WithClaimLimits(ClaimLimits{MaxClaims: -1})); nil == err {
+		t.Errorf("WithClaimLimits() expected error for a negative limit")
+	}
+}
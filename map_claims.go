@@ -0,0 +1,135 @@
+package jwt
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// MapClaims is a claim set of any claims, for services handling claims
+// not known in advance. It is signed with GenerateToken like any claim
+// set, and decoded with GetClaims, keeping numbers as json.Number so large
+// integers are exact. The typed accessors smooth over the forms claims
+// take in the wild, such as numeric subjects and dates held as strings.
+type MapClaims map[string]interface{}
+
+// UnmarshalJSON decodes a claim set, keeping numbers as json.Number.
+func (m *MapClaims) UnmarshalJSON(b []byte) error {
+	claims, err := decodeClaimsMap(b)
+	if nil != err {
+		return err
+	}
+
+	*m = claims
+	return nil
+}
+
+// GetString returns a string claim. Numeric claims, such as the numeric
+// subjects of some identity providers, are returned in decimal.
+func (m MapClaims) GetString(name string) (string, bool) {
+	switch v := m[name].(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	}
+
+	return "", false
+}
+
+// GetTime returns a NumericDate claim, such as 'exp', as a time. Dates may
+// be numbers, with or without a fraction, or strings holding one.
+func (m MapClaims) GetTime(name string) (time.Time, bool) {
+	var seconds int64
+	switch v := m[name].(type) {
+	case json.Number:
+		parsed, err := parseNumericDate(v.String())
+		if nil != err {
+			return time.Time{}, false
+		}
+		seconds = parsed
+	case string:
+		parsed, err := parseNumericDate(v)
+		if nil != err {
+			return time.Time{}, false
+		}
+		seconds = parsed
+	case float64:
+		parsed, err := numericDateSeconds(v)
+		if nil != err {
+			return time.Time{}, false
+		}
+		seconds = parsed
+	case int:
+		seconds = int64(v)
+	case int64:
+		seconds = v
+	case NumericDate:
+		seconds = int64(v)
+	case *NumericDate:
+		if nil == v {
+			return time.Time{}, false
+		}
+		seconds = int64(*v)
+	default:
+		return time.Time{}, false
+	}
+
+	return time.Unix(seconds, 0), true
+}
+
+// GetStringSlice returns a claim that is a string or an array of strings,
+// such as 'aud', as a slice.
+func (m MapClaims) GetStringSlice(name string) ([]string, bool) {
+	switch v := m[name].(type) {
+	case string:
+		return []string{v}, true
+	case []string:
+		return v, true
+	case []interface{}:
+		values := make([]string, 0, len(v))
+		for _, element := range v {
+			s, ok := element.(string)
+			if !ok {
+				return nil, false
+			}
+			values = append(values, s)
+		}
+		return values, true
+	}
+
+	return nil, false
+}
+
+// RegisteredClaims returns the registered claims of the claim set.
+func (m MapClaims) RegisteredClaims() (*Claims, error) {
+	encoded, err := json.Marshal(m)
+	if nil != err {
+		return nil, err
+	}
+
+	var claims Claims
+	if err := json.Unmarshal(encoded, &claims); nil != err {
+		return nil, fmt.Errorf("Invalid registered claims: %v", err)
+	}
+
+	return &claims, nil
+}
+
+// ValidateRegisteredClaims validates the registered claims of the claim
+// set, as Claims.ValidateRegisteredClaims does.
+func (m MapClaims) ValidateRegisteredClaims(validationClaims *ValidationClaims) (bool, error) {
+	claims, err := m.RegisteredClaims()
+	if nil != err {
+		return false, err
+	}
+
+	return claims.ValidateRegisteredClaims(validationClaims)
+}
@@ -0,0 +1,125 @@
+package jwt
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMapClaims_Accessors(t *testing.T) {
+	var claims MapClaims
+	if err := json.Unmarshal([]byte(`{
+		"sub": 10215588834517380,
+		"iss": "https://issuer.example.com",
+		"exp": 1600003600.5,
+		"iat": "1600000000",
+		"aud": ["api", "admin"],
+		"azp": "client",
+		"roles": ["admin", 1],
+		"active": true
+	}`), &claims); nil != err {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	stringTests := []struct {
+		name   string
+		claim  string
+		want   string
+		wantOk bool
+	}{
+		{"Must get a string", "iss", "https://issuer.example.com", true},
+		{"Must get a large number exactly", "sub", "10215588834517380", true},
+		{"Must not get a boolean", "active", "", false},
+		{"Must not get a missing claim", "jti", "", false},
+	}
+	for _, tt := range stringTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, ok := claims.GetString(tt.claim); got != tt.want || ok != tt.wantOk {
+				t.Errorf("GetString() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+
+	timeTests := []struct {
+		name   string
+		claim  string
+		want   time.Time
+		wantOk bool
+	}{
+		{"Must get a date with a fraction", "exp", time.Unix(1600003600, 0), true},
+		{"Must get a date held as a string", "iat", time.Unix(1600000000, 0), true},
+		{"Must not get a date that is not a number", "iss", time.Time{}, false},
+		{"Must not get a missing date", "nbf", time.Time{}, false},
+	}
+	for _, tt := range timeTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, ok := claims.GetTime(tt.claim); !got.Equal(tt.want) || ok != tt.wantOk {
+				t.Errorf("GetTime() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+
+	sliceTests := []struct {
+		name   string
+		claim  string
+		want   []string
+		wantOk bool
+	}{
+		{"Must get an array of strings", "aud", []string{"api", "admin"}, true},
+		{"Must get a string as a slice", "azp", []string{"client"}, true},
+		{"Must not get an array of mixed values", "roles", nil, false},
+		{"Must not get a missing claim", "scope", nil, false},
+	}
+	for _, tt := range sliceTests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, ok := claims.GetStringSlice(tt.claim); !reflect.DeepEqual(got, tt.want) || ok != tt.wantOk {
+				t.Errorf("GetStringSlice() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestMapClaims_SignAndValidate(t *testing.T) {
+	sv, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	criteria := &ValidationClaims{Issuer: []string{"https://issuer.example.com"}, Subject: []string{"alice"}}
+
+	tests := []struct {
+		name      string
+		claims    MapClaims
+		wantValid bool
+		wantErr   bool
+	}{
+		{"Must validate map claims", MapClaims{"iss": "https://issuer.example.com", "sub": "alice", "exp": NewNumericDate(time.Now().Add(time.Hour)), "tenant": "t1"}, true, false},
+		{"Must reject an unexpected issuer", MapClaims{"iss": "https://attacker.example.com", "sub": "alice"}, false, false},
+		{"Must reject an expired token", MapClaims{"iss": "https://issuer.example.com", "sub": "alice", "exp": time.Now().Add(-time.Hour).Unix()}, false, true},
+		{"Must reject a malformed registered claim", MapClaims{"iss": "https://issuer.example.com", "sub": "alice", "exp": "tomorrow"}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rawToken, err := sv.GenerateToken(Header{Algorithm: string(HS256)}, tt.claims)
+			if nil != err {
+				t.Fatalf("GenerateToken() error = %v", err)
+			}
+			token, signatureValid, err := sv.VerifySignature(rawToken)
+			if !signatureValid || nil != err {
+				t.Fatalf("VerifySignature() = %v, %v", signatureValid, err)
+			}
+
+			var claims MapClaims
+			if err := GetClaims(token, &claims); nil != err {
+				t.Fatalf("GetClaims() error = %v", err)
+			}
+			valid, err := claims.ValidateRegisteredClaims(criteria)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateRegisteredClaims() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if valid != tt.wantValid {
+				t.Errorf("ValidateRegisteredClaims() = %v, want %v", valid, tt.wantValid)
+			}
+			if tenant, ok := claims.GetString("tenant"); tt.wantValid && (!ok || tenant != "t1") {
+				t.Errorf("GetString() = %q, %v", tenant, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// MemoryBudget bounds the decoded payload bytes in flight across
+// concurrent verifications, so a verification service has a memory
+// ceiling under adversarial load. A budget may be shared by several
+// JOSESignerVerifiers, see WithMemoryBudget.
+type MemoryBudget struct {
+	limit    int64
+	inFlight int64
+}
+
+// MemoryBudgetError is returned when verifying a token would exceed the
+// memory budget. The token is rejected without being decoded; callers may
+// retry it once load subsides, unless Size exceeds Limit.
+type MemoryBudgetError struct {
+	Size     int64
+	InFlight int64
+	Limit    int64
+}
+
+func (e *MemoryBudgetError) Error() string {
+	return fmt.Sprintf(
+		"Token of %d decoded bytes exceeds the memory budget, %d of %d bytes in flight",
+		e.Size, e.InFlight, e.Limit,
+	)
+}
+
+// NewMemoryBudget creates a MemoryBudget of limit bytes.
+func NewMemoryBudget(limit int64) (*MemoryBudget, error) {
+	if limit <= 0 {
+		return nil, errors.New("Memory budget must be positive")
+	}
+
+	return &MemoryBudget{limit: limit}, nil
+}
+
+// Limit returns the budget in bytes.
+func (b *MemoryBudget) Limit() int64 {
+	return b.limit
+}
+
+// InFlight returns the decoded payload bytes of verifications in progress.
+func (b *MemoryBudget) InFlight() int64 {
+	return atomic.LoadInt64(&b.inFlight)
+}
+
+// acquire admits size bytes, or returns a MemoryBudgetError if they don't
+// fit in the budget. Admitted bytes must be released.
+func (b *MemoryBudget) acquire(size int64) error {
+	for {
+		inFlight := atomic.LoadInt64(&b.inFlight)
+		if size > b.limit-inFlight {
+			return &MemoryBudgetError{Size: size, InFlight: inFlight, Limit: b.limit}
+		}
+		if atomic.CompareAndSwapInt64(&b.inFlight, inFlight, inFlight+size) {
+			return nil
+		}
+	}
+}
+
+// release returns size admitted bytes to the budget.
+func (b *MemoryBudget) release(size int64) {
+	atomic.AddInt64(&b.inFlight, -size)
+}
+
+// WithMemoryBudget admits tokens for verification only while their decoded
+// size fits in the budget, rejecting others with a MemoryBudgetError.
+func WithMemoryBudget(budget *MemoryBudget) Option {
+	return func(sv *JOSESignerVerifier) error {
+		if nil == budget {
+			return errors.New("Memory budget cannot be nil")
+		}
+
+		sv.memoryBudget = budget
+		return nil
+	}
+}
+
+// admit reserves the decoded size of the token in the memory budget, if
+// any, returning a function releasing it.
+func (sv *JOSESignerVerifier) admit(rawToken []byte) (func(), error) {
+	if nil == sv.memoryBudget {
+		return func() {}, nil
+	}
+
+	// The header, body and signature decode to at most 3 bytes per 4
+	// encoded.
+	size := int64(len(rawToken)) * 3 / 4
+	if err := sv.memoryBudget.acquire(size); nil != err {
+		return nil, err
+	}
+
+	return func() { sv.memoryBudget.release(size) }, nil
+}
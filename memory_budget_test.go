@@ -0,0 +1,65 @@
+package jwt
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWithMemoryBudget(t *testing.T) {
+	key := bytes.Repeat([]byte{1}, 32)
+	budget, _ := NewMemoryBudget(1024)
+	sv, err := NewJOSESignerVerifier(HS256, key, WithMemoryBudget(budget))
+	if nil != err {
+		t.Fatalf("NewJOSESignerVerifier() error = %v", err)
+	}
+
+	token, _ := sv.GenerateToken(Header{Algorithm: "HS256"}, Claims{Subject: "alice"})
+	large, _ := sv.GenerateToken(Header{Algorithm: "HS256"}, map[string]string{"sub": strings.Repeat("a", 2048)})
+
+	tests := []struct {
+		name     string
+		token    []byte
+		held     int64
+		wantErr  bool
+		wantSize int64
+	}{
+		{"Must verify a token within the budget", token, 0, false, 0},
+		{"Must verify a token within the remaining budget", token, 512, false, 0},
+		{"Must reject a token exceeding the remaining budget", token, 1000, true, int64(len(token)) * 3 / 4},
+		{"Must reject a token exceeding the budget", large, 0, true, int64(len(large)) * 3 / 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := budget.acquire(tt.held); nil != err {
+				t.Fatalf("MemoryBudget.acquire() error = %v", err)
+			}
+			defer budget.release(tt.held)
+
+			for _, verify := range []func() error{
+				func() error { _, _, err := sv.VerifySignature(tt.token); return err },
+				func() error { _, _, err := sv.VerifyToken(tt.token, nil); return err },
+			} {
+				err := verify()
+				var budgetErr *MemoryBudgetError
+				if errors.As(err, &budgetErr) != tt.wantErr {
+					t.Fatalf("verification error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if tt.wantErr && (budgetErr.Size != tt.wantSize || budgetErr.InFlight != tt.held || budgetErr.Limit != 1024) {
+					t.Errorf("verification error = %+v", budgetErr)
+				}
+				if got := budget.InFlight(); got != tt.held {
+					t.Errorf("MemoryBudget.InFlight() = %d after verification, want %d", got, tt.held)
+				}
+			}
+		})
+	}
+
+	if _, err := NewMemoryBudget(0); nil == err {
+		t.Errorf("NewMemoryBudget() expected error for a zero budget")
+	}
+	if _, err := NewJOSESignerVerifier(HS256, key, WithMemoryBudget(nil)); nil == err {
+		t.Errorf("WithMemoryBudget() expected error for a nil budget")
+	}
+}
@@ -0,0 +1,91 @@
+package jwt
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+)
+
+// MessageTokenHeader is the message header the producer token is carried in.
+const MessageTokenHeader = "jwt"
+
+// MessageHeaders is the header carrier of a message bus client. http.Header
+// and NATS headers satisfy it directly; MessageHeaderMap can be used to
+// adapt Kafka record headers.
+type MessageHeaders interface {
+	Get(key string) string
+	Set(key, value string)
+}
+
+// MessageHeaderMap is a simple MessageHeaders implementation.
+type MessageHeaderMap map[string]string
+
+// Get returns the value of the header.
+func (h MessageHeaderMap) Get(key string) string {
+	return h[key]
+}
+
+// Set sets the value of the header.
+func (h MessageHeaderMap) Set(key, value string) {
+	h[key] = value
+}
+
+// messageClaims binds a producer token to the message it was attached to.
+type messageClaims struct {
+	Claims
+	PayloadHash string `json:"msg_sha256"`
+}
+
+// SignMessage attaches a token identifying the producer to the message
+// headers. The token carries the registered claims provided and a SHA-256
+// hash of the message payload, so it can't be replayed on other messages.
+func (sv *JOSESignerVerifier) SignMessage(headers MessageHeaders, payload []byte, claims Claims) error {
+	token, err := sv.GenerateToken(
+		Header{
+			Algorithm: string(sv.algorithm),
+			Type:      "JWT",
+		},
+		messageClaims{
+			Claims:      claims,
+			PayloadHash: messageHash(payload),
+		},
+	)
+	if nil != err {
+		return err
+	}
+
+	headers.Set(MessageTokenHeader, string(token))
+	return nil
+}
+
+// VerifyMessage verifies the producer token in the message headers, and
+// that it was issued for this message payload.
+func (sv *JOSESignerVerifier) VerifyMessage(headers MessageHeaders, payload []byte, validationCriteria *ValidationClaims) (*Token, bool, error) {
+	rawToken := headers.Get(MessageTokenHeader)
+	if rawToken == "" {
+		return nil, false, fmt.Errorf("Message has no %q header", MessageTokenHeader)
+	}
+
+	token, valid, err := sv.VerifyToken([]byte(rawToken), validationCriteria)
+	if nil != err || !valid {
+		return token, false, err
+	}
+
+	var claims messageClaims
+	err = GetClaims(token, &claims)
+	if nil != err {
+		return token, false, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(claims.PayloadHash), []byte(messageHash(payload))) != 1 {
+		return token, false, errors.New("Message payload does not match the token")
+	}
+
+	return token, true, nil
+}
+
+func messageHash(payload []byte) string {
+	sum := sha256.Sum256(payload)
+	return Base64URLEncode(sum[:])
+}
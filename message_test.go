@@ -0,0 +1,42 @@
+package jwt
+
+import (
+	"testing"
+)
+
+func TestJOSESignerVerifier_SignMessage_VerifyMessage(t *testing.T) {
+	sv, err := NewJOSESignerVerifier(HS256, exampleKey)
+	if nil != err {
+		t.Fatalf("NewJOSESignerVerifier() error = %v", err)
+	}
+
+	headers := MessageHeaderMap{}
+	err = sv.SignMessage(headers, plaintext, Claims{Issuer: "redania"})
+	if nil != err {
+		t.Fatalf("SignMessage() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		headers   MessageHeaders
+		payload   []byte
+		wantValid bool
+		wantErr   bool
+	}{
+		{"Must verify the signed message", headers, plaintext, true, false},
+		{"Must fail given a different payload", headers, incorrectPlaintext, false, true},
+		{"Must fail given no token header", MessageHeaderMap{}, plaintext, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, valid, err := sv.VerifyMessage(tt.headers, tt.payload, &ValidationClaims{Issuer: []string{"redania"}})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyMessage() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if valid != tt.wantValid {
+				t.Errorf("VerifyMessage() = %v, want %v", valid, tt.wantValid)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package jwt
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Kinds of direct identifier reported by claim analysis.
+const (
+	IdentifierEmail        = "email"
+	IdentifierPhone        = "phone"
+	IdentifierName         = "name"
+	IdentifierAddress      = "address"
+	IdentifierBirthdate    = "birthdate"
+	IdentifierGovernmentID = "government_id"
+	IdentifierIPAddress    = "ip_address"
+)
+
+// Claim minimization suggestions.
+const (
+	// SuggestHash suggests replacing the value with a keyed hash or a
+	// pseudonym, for identifiers relying parties correlate on.
+	SuggestHash = "hash"
+	// SuggestDrop suggests removing the claim, for identifiers relying
+	// parties can look up when needed.
+	SuggestDrop = "drop"
+)
+
+// ClaimFinding reports a claim that looks like a direct identifier of a
+// person.
+type ClaimFinding struct {
+	// Claim is the dotted path of the claim, e.g. "profile.email".
+	Claim      string
+	Identifier string
+	Suggestion string
+}
+
+func (f ClaimFinding) String() string {
+	return fmt.Sprintf("%s: looks like %s, suggest %s", f.Claim, f.Identifier, f.Suggestion)
+}
+
+var (
+	emailPattern = regexp.MustCompile(`^[^@\s]+@[^@\s]+\.[^@\s]+$`)
+	phonePattern = regexp.MustCompile(`^\+[0-9][0-9 ()\-.]{6,}[0-9]$`)
+)
+
+// identifyingClaimNames maps claim names, including the OpenID Connect
+// standard claims, to the identifiers they hold.
+var identifyingClaimNames = map[string]string{
+	"email":          IdentifierEmail,
+	"phone":          IdentifierPhone,
+	"phone_number":   IdentifierPhone,
+	"name":           IdentifierName,
+	"given_name":     IdentifierName,
+	"family_name":    IdentifierName,
+	"middle_name":    IdentifierName,
+	"address":        IdentifierAddress,
+	"street_address": IdentifierAddress,
+	"birthdate":      IdentifierBirthdate,
+	"ssn":            IdentifierGovernmentID,
+	"passport":       IdentifierGovernmentID,
+	"tax_id":         IdentifierGovernmentID,
+	"ip":             IdentifierIPAddress,
+	"ip_address":     IdentifierIPAddress,
+}
+
+// identifierSuggestions maps identifiers to the suggested minimization.
+var identifierSuggestions = map[string]string{
+	IdentifierEmail:        SuggestHash,
+	IdentifierPhone:        SuggestHash,
+	IdentifierName:         SuggestDrop,
+	IdentifierAddress:      SuggestDrop,
+	IdentifierBirthdate:    SuggestDrop,
+	IdentifierGovernmentID: SuggestDrop,
+	IdentifierIPAddress:    SuggestDrop,
+}
+
+// AnalyzeClaims reports claims in a claim set that look like direct
+// identifiers, by claim name or by value, so issuers can minimize the
+// personal data their tokens carry. Nested objects and arrays are
+// inspected. Findings are sorted by claim.
+//
+// The analysis is heuristic: it is meant to flag claims for review, for
+// example as a CI check over sample tokens, not to prove their absence.
+func AnalyzeClaims(claims interface{}) ([]ClaimFinding, error) {
+	jwsPayload, err := json.Marshal(claims)
+	if nil != err {
+		return nil, err
+	}
+
+	claimsMap, err := decodeClaimsMap(jwsPayload)
+	if nil != err {
+		return nil, err
+	}
+
+	var findings []ClaimFinding
+	for name, value := range claimsMap {
+		findings = analyzeClaim(findings, name, name, value)
+	}
+
+	sort.Slice(findings, func(i, j int) bool {
+		return findings[i].Claim < findings[j].Claim
+	})
+
+	return findings, nil
+}
+
+// AnalyzeToken reports the claims of a token that look like direct
+// identifiers. The token's signature is not verified.
+func AnalyzeToken(rawToken []byte) ([]ClaimFinding, error) {
+	token, err := GetRawTokenParts(rawToken)
+	if nil != err {
+		return nil, err
+	}
+
+	return AnalyzeClaims(json.RawMessage(token.DecodedBody))
+}
+
+// Analyze renders the template against a sample IssueContext and reports
+// the claims that look like direct identifiers.
+func (t *ClaimTemplate) Analyze(sample IssueContext) ([]ClaimFinding, error) {
+	claims, err := t.Render(sample)
+	if nil != err {
+		return nil, err
+	}
+
+	return AnalyzeClaims(claims)
+}
+
+// analyzeClaim appends findings for the claim at path and its members.
+func analyzeClaim(findings []ClaimFinding, path string, name string, value interface{}) []ClaimFinding {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if identifier, ok := identifyingClaimNames[strings.ToLower(name)]; ok {
+			return append(findings, newClaimFinding(path, identifier))
+		}
+		for member, memberValue := range v {
+			findings = analyzeClaim(findings, path+"."+member, member, memberValue)
+		}
+		return findings
+	case []interface{}:
+		for _, element := range v {
+			before := len(findings)
+			findings = analyzeClaim(findings, path, name, element)
+			if len(findings) > before {
+				// One finding per array is enough to flag the claim.
+				return findings
+			}
+		}
+		return findings
+	case string:
+		if identifier := identifyClaim(name, v); identifier != "" {
+			return append(findings, newClaimFinding(path, identifier))
+		}
+	}
+
+	return findings
+}
+
+// identifyClaim returns the identifier a claim looks like, if any.
+func identifyClaim(name string, value string) string {
+	if value == "" {
+		return ""
+	}
+
+	if identifier, ok := identifyingClaimNames[strings.ToLower(name)]; ok {
+		return identifier
+	}
+
+	switch {
+	case emailPattern.MatchString(value):
+		return IdentifierEmail
+	case phonePattern.MatchString(value):
+		return IdentifierPhone
+	case nil != net.ParseIP(value):
+		return IdentifierIPAddress
+	}
+
+	return ""
+}
+
+func newClaimFinding(path string, identifier string) ClaimFinding {
+	suggestion := identifierSuggestions[identifier]
+	if path == "sub" {
+		// The subject can't be dropped, but can be pseudonymized.
+		suggestion = SuggestHash
+	}
+
+	return ClaimFinding{
+		Claim:      path,
+		Identifier: identifier,
+		Suggestion: suggestion,
+	}
+}
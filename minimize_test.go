@@ -0,0 +1,89 @@
+package jwt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAnalyzeClaims(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims interface{}
+		want   []ClaimFinding
+	}{
+		{
+			"Must not report registered claims without identifiers",
+			Claims{Issuer: "https://issuer.example.com", Subject: "248289761001", Expiration: numericDate(1600000000)},
+			nil,
+		},
+		{
+			"Must report an email subject, suggesting a pseudonym",
+			Claims{Subject: "alice@example.com"},
+			[]ClaimFinding{{"sub", IdentifierEmail, SuggestHash}},
+		},
+		{
+			"Must report identifying claim names and values",
+			map[string]interface{}{
+				"given_name": "Alice",
+				"contact":    "+1 (415) 555-0100",
+				"client_ip":  "203.0.113.7",
+				"scope":      "read write",
+			},
+			[]ClaimFinding{
+				{"client_ip", IdentifierIPAddress, SuggestDrop},
+				{"contact", IdentifierPhone, SuggestHash},
+				{"given_name", IdentifierName, SuggestDrop},
+			},
+		},
+		{
+			"Must report nested identifiers",
+			map[string]interface{}{
+				"profile": map[string]interface{}{
+					"address": map[string]interface{}{"locality": "Anytown"},
+					"emails":  []string{"team", "alice@example.com", "bob@example.com"},
+				},
+			},
+			[]ClaimFinding{
+				{"profile.address", IdentifierAddress, SuggestDrop},
+				{"profile.emails", IdentifierEmail, SuggestHash},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AnalyzeClaims(tt.claims)
+			if nil != err {
+				t.Fatalf("AnalyzeClaims() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AnalyzeClaims() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeToken(t *testing.T) {
+	sv, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	token, _ := sv.GenerateToken(Header{Algorithm: string(HS256)}, map[string]string{"sub": "bob@example.com"})
+
+	got, err := AnalyzeToken(token)
+	if nil != err || len(got) != 1 || got[0].Claim != "sub" {
+		t.Errorf("AnalyzeToken() = %v, %v, want a finding for sub", got, err)
+	}
+}
+
+func TestClaimTemplate_Analyze(t *testing.T) {
+	tmpl, err := ParseClaimTemplate([]byte(`{
+		"sub": {"template": "{{.Subject}}"},
+		"email": {"template": "{{index .Extra \"email\"}}"}
+	}`))
+	if nil != err {
+		t.Fatalf("ParseClaimTemplate() error = %v", err)
+	}
+
+	got, err := tmpl.Analyze(IssueContext{Subject: "u-123", Extra: map[string]string{"email": "alice@example.com"}})
+	want := []ClaimFinding{{"email", IdentifierEmail, SuggestHash}}
+	if nil != err || !reflect.DeepEqual(got, want) {
+		t.Errorf("ClaimTemplate.Analyze() = %v, %v, want %v", got, err, want)
+	}
+}
@@ -0,0 +1,116 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// SignaturePolicy determines which signatures of a multi-signed JWS must
+// verify, see VerifyMultiSigned.
+type SignaturePolicy int
+
+const (
+	// AnySignature requires a signature verifying with any of the
+	// verifiers.
+	AnySignature SignaturePolicy = iota
+
+	// AllSignatures requires a signature verifying with each of the
+	// verifiers.
+	AllSignatures
+)
+
+// JSONSigner signs a multi-signed JWS, see GenerateMultiSigned.
+type JSONSigner struct {
+	SignerVerifier *JOSESignerVerifier
+
+	// Header is the JOSE header protected by the signature, and
+	// Unprotected, which may be nil, the unprotected header.
+	Header      interface{}
+	Unprotected map[string]interface{}
+}
+
+// GenerateMultiSigned generates a JWS in the general JSON serialization
+// with a signature by each of the signers over a JWS claim set body, for
+// migrations between signing keys or algorithms during which verifiers
+// accept either. The payload is encoded by the first signer, as
+// GenerateToken does, and signed as is by the others. Each protected
+// header is populated from its signer as GenerateToken does.
+func GenerateMultiSigned(body interface{}, signers ...JSONSigner) (*JSONWebSignature, error) {
+	if len(signers) == 0 {
+		return nil, errors.New("Multi-signed JWSs require at least one signer")
+	}
+	for _, signer := range signers {
+		if nil == signer.SignerVerifier {
+			return nil, errors.New("Multi-signed JWS signers require a signer verifier")
+		}
+	}
+
+	s, err := signers[0].SignerVerifier.GenerateJSON(signers[0].Header, signers[0].Unprotected, body)
+	if nil != err {
+		return nil, err
+	}
+
+	for _, signer := range signers[1:] {
+		protected, err := json.Marshal(signer.Header)
+		if nil != err {
+			return nil, err
+		}
+		protected, err = signer.SignerVerifier.populateHeader(protected)
+		if nil != err {
+			return nil, err
+		}
+		encodedHeader := Base64URLEncode(protected)
+
+		signature := JSONSignature{Protected: encodedHeader, Header: signer.Unprotected}
+		if err := signature.checkDisjoint(); nil != err {
+			return nil, err
+		}
+
+		signed, err := signer.SignerVerifier.sign(appendWithDot(encodedHeader, s.Payload))
+		if nil != err {
+			return nil, err
+		}
+		signature.Signature = Base64URLEncode(signed)
+
+		s.Signatures = append(s.Signatures, signature)
+	}
+
+	return s, nil
+}
+
+// VerifyMultiSigned verifies a JWS in the general or the flattened JSON
+// serialization against the policy, with each verifier verifying the
+// signatures and validating the claims as VerifyJSON does. With
+// AnySignature, the token verified by the first verifier able to is
+// returned; with AllSignatures, the token verified by the first verifier.
+func VerifyMultiSigned(data []byte, validationCriteria *ValidationClaims, policy SignaturePolicy, verifiers ...*JOSESignerVerifier) (*Token, bool, error) {
+	if len(verifiers) == 0 {
+		return nil, false, errors.New("Multi-signed JWSs require at least one verifier")
+	}
+	if policy != AnySignature && policy != AllSignatures {
+		return nil, false, fmt.Errorf("Unknown signature policy %d", policy)
+	}
+
+	var first *Token
+	var err error
+	for _, verifier := range verifiers {
+		token, valid, verifyErr := verifier.VerifyJSON(data, validationCriteria)
+		if valid && policy == AnySignature {
+			return token, true, nil
+		}
+		if !valid && policy == AllSignatures {
+			return token, false, verifyErr
+		}
+
+		if nil == first {
+			first = token
+		}
+		err = verifyErr
+	}
+
+	if policy == AnySignature {
+		return first, false, err
+	}
+	return first, true, nil
+}
@@ -0,0 +1,99 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+)
+
+func TestMultiSigned(t *testing.T) {
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	legacy, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	current, _ := NewJOSESignerVerifier(ES256, ecKey)
+	unknown, _ := NewJOSESignerVerifier(HS256, []byte("not a key of the issuer"))
+
+	if _, err := GenerateMultiSigned(Claims{}); nil == err {
+		t.Errorf("GenerateMultiSigned() expected error without signers")
+	}
+	if _, err := GenerateMultiSigned(Claims{}, JSONSigner{SignerVerifier: legacy, Header: Header{Algorithm: string(HS256)}}, JSONSigner{SignerVerifier: current, Header: Header{Algorithm: string(ES256), KeyID: "current"}, Unprotected: map[string]interface{}{"kid": "current"}}); nil == err {
+		t.Errorf("GenerateMultiSigned() expected error with a member both protected and unprotected")
+	}
+
+	claims := Claims{Subject: "alice", Issuer: "issuer"}
+	s, err := GenerateMultiSigned(claims,
+		JSONSigner{SignerVerifier: legacy, Header: Header{Algorithm: string(HS256)}, Unprotected: map[string]interface{}{"kid": "legacy"}},
+		JSONSigner{SignerVerifier: current, Header: Header{Algorithm: string(ES256), KeyID: "current"}},
+	)
+	if nil != err {
+		t.Fatalf("GenerateMultiSigned() error = %v", err)
+	}
+	if len(s.Signatures) != 2 {
+		t.Fatalf("GenerateMultiSigned() = %d signatures, want 2", len(s.Signatures))
+	}
+	multiSigned, _ := json.Marshal(s)
+	legacyOnly, _ := json.Marshal(JSONWebSignature{Payload: s.Payload, Signatures: s.Signatures[:1]})
+
+	tests := []struct {
+		name      string
+		data      []byte
+		policy    SignaturePolicy
+		verifiers []*JOSESignerVerifier
+		wantValid bool
+		wantErr   bool
+	}{
+		{"Must verify any signature with the current key", multiSigned, AnySignature, []*JOSESignerVerifier{current}, true, false},
+		{"Must verify any signature with either key", legacyOnly, AnySignature, []*JOSESignerVerifier{current, legacy}, true, false},
+		{"Must not verify any signature with an unknown key", multiSigned, AnySignature, []*JOSESignerVerifier{unknown}, false, false},
+		{"Must verify all signatures with both keys", multiSigned, AllSignatures, []*JOSESignerVerifier{legacy, current}, true, false},
+		{"Must not verify all signatures missing one", legacyOnly, AllSignatures, []*JOSESignerVerifier{legacy, current}, false, true},
+		{"Must not verify all signatures with an unknown key", multiSigned, AllSignatures, []*JOSESignerVerifier{legacy, unknown}, false, false},
+		{"Must fail without verifiers", multiSigned, AnySignature, nil, false, true},
+		{"Must fail an unknown policy", multiSigned, SignaturePolicy(7), []*JOSESignerVerifier{current}, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, valid, err := VerifyMultiSigned(tt.data, &ValidationClaims{Issuer: []string{"issuer"}, Subject: []string{"alice"}}, tt.policy, tt.verifiers...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifyMultiSigned() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if valid != tt.wantValid {
+				t.Errorf("VerifyMultiSigned() valid = %v, want %v", valid, tt.wantValid)
+			}
+			if valid && token.RegisteredClaims.Subject != "alice" {
+				t.Errorf("VerifyMultiSigned() subject = %q, want alice", token.RegisteredClaims.Subject)
+			}
+		})
+	}
+}
+
+func TestGenerateMultiSigned_PopulateHeader(t *testing.T) {
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	legacy, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	current, _ := NewJOSESignerVerifier(ES256, ecKey, WithKeyID("current"))
+
+	s, err := GenerateMultiSigned(Claims{Subject: "alice"},
+		JSONSigner{SignerVerifier: legacy, Header: Header{}},
+		JSONSigner{SignerVerifier: current, Header: Header{}},
+	)
+	if nil != err {
+		t.Fatalf("GenerateMultiSigned() error = %v", err)
+	}
+	protected, _ := Base64URLDecode(s.Signatures[1].Protected)
+	if string(protected) != `{"kid":"current","alg":"ES256"}` {
+		t.Errorf("GenerateMultiSigned() protected header = %s", protected)
+	}
+
+	multiSigned, _ := json.Marshal(s)
+	if _, valid, err := VerifyMultiSigned(multiSigned, &ValidationClaims{Subject: []string{"alice"}}, AllSignatures, legacy, current); !valid || nil != err {
+		t.Errorf("VerifyMultiSigned() = %v, %v", valid, err)
+	}
+
+	if _, err := GenerateMultiSigned(Claims{Subject: "alice"},
+		JSONSigner{SignerVerifier: legacy, Header: Header{}},
+		JSONSigner{SignerVerifier: current, Header: Header{Algorithm: string(HS256)}},
+	); nil == err {
+		t.Errorf("GenerateMultiSigned() expected error for a header conflicting with its signer")
+	}
+}
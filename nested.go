@@ -0,0 +1,64 @@
+package jwt
+
+import (
+	"errors"
+	"strings"
+)
+
+// NestedContentType is the 'cty' of a JWE whose plaintext is a JWT, as
+// RFC 7519, section 5.2 requires of nested JWTs.
+const NestedContentType = "JWT"
+
+// JWECipher encrypts and decrypts compact JWEs. JWEEncrypterDecrypter and
+// JWEKeyring implement it.
+type JWECipher interface {
+	Encrypt(header JWEHeader, plaintext []byte) ([]byte, error)
+	Decrypt(rawToken []byte) (*EncryptedToken, error)
+}
+
+// NestedJWT signs then encrypts tokens, and decrypts then verifies them
+// (RFC 7519, section 11.2), for claims that must be both confidential and
+// attributable to their issuer. Encryption alone doesn't authenticate the
+// issuer when public keys are used to encrypt.
+type NestedJWT struct {
+	sv     *JOSESignerVerifier
+	cipher JWECipher
+}
+
+// NewNestedJWT creates a NestedJWT signing and verifying the inner JWS
+// with sv, and encrypting and decrypting the outer JWE with cipher.
+func NewNestedJWT(sv *JOSESignerVerifier, cipher JWECipher) (*NestedJWT, error) {
+	if nil == sv || nil == cipher {
+		return nil, errors.New("Nested JWTs require a signer verifier and a cipher")
+	}
+
+	return &NestedJWT{sv: sv, cipher: cipher}, nil
+}
+
+// SignAndEncrypt signs the claims with the JWS header, then encrypts the
+// signed token as the plaintext of a JWE with a 'cty' of "JWT".
+func (n *NestedJWT) SignAndEncrypt(header interface{}, claims interface{}) ([]byte, error) {
+	signed, err := n.sv.GenerateToken(header, claims)
+	if nil != err {
+		return nil, err
+	}
+
+	return n.cipher.Encrypt(JWEHeader{ContentType: NestedContentType}, signed)
+}
+
+// DecryptAndVerify decrypts a nested JWT, which must have a 'cty' of
+// "JWT", then verifies the signed token it contains and validates its
+// claims against the criteria, see JOSESignerVerifier.VerifyToken.
+func (n *NestedJWT) DecryptAndVerify(rawToken []byte, validationCriteria *ValidationClaims) (*Token, bool, error) {
+	decrypted, err := n.cipher.Decrypt(rawToken)
+	if nil != err {
+		return nil, false, err
+	}
+
+	// Content types are compared case-insensitively (RFC 7515, section 4.1.10).
+	if !strings.EqualFold(decrypted.Header.ContentType, NestedContentType) {
+		return nil, false, errors.New("Encrypted token is not a nested JWT - its 'cty' must be \"JWT\"")
+	}
+
+	return n.sv.VerifyToken(decrypted.Plaintext, validationCriteria)
+}
@@ -0,0 +1,78 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"strings"
+	"testing"
+)
+
+func TestNestedJWT(t *testing.T) {
+	if _, err := NewNestedJWT(nil, nil); nil == err {
+		t.Errorf("NewNestedJWT() expected error without a signer verifier and cipher")
+	}
+
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	issuer, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	forger, _ := NewJOSESignerVerifier(HS256, []byte("not the key shared with the issuer"))
+	sender, _ := NewJWEEncrypterDecrypter(RSAOAEP256, A256GCM, &rsaKey.PublicKey)
+	recipient, _ := NewJWEEncrypterDecrypter(RSAOAEP256, A256GCM, rsaKey)
+	keyring := NewJWEKeyring()
+	session, _ := NewJWEEncrypterDecrypter(Direct, A256GCM, bytes.Repeat([]byte{1}, 32))
+	keyring.Add("session", session)
+
+	signing, _ := NewNestedJWT(issuer, sender)
+	forging, _ := NewNestedJWT(forger, sender)
+	receiving, _ := NewNestedJWT(issuer, recipient)
+	sessions, _ := NewNestedJWT(issuer, keyring)
+
+	header := Header{Algorithm: string(HS256), Type: "JWT"}
+	claims := Claims{Subject: "alice", Issuer: "issuer"}
+
+	nested, err := signing.SignAndEncrypt(header, claims)
+	if nil != err {
+		t.Fatalf("NestedJWT.SignAndEncrypt() error = %v", err)
+	}
+	if parts := strings.Split(string(nested), "."); len(parts) != 5 {
+		t.Fatalf("NestedJWT.SignAndEncrypt() = %s, want a five part JWE", nested)
+	}
+	decrypted, _ := recipient.Decrypt(nested)
+	if decrypted.Header.ContentType != "JWT" {
+		t.Errorf("NestedJWT.SignAndEncrypt() cty = %q, want JWT", decrypted.Header.ContentType)
+	}
+
+	forged, _ := forging.SignAndEncrypt(header, claims)
+	unnested, _ := recipient.GenerateToken(JWEHeader{}, claims)
+	sessionToken, _ := sessions.SignAndEncrypt(header, claims)
+	signed, _ := issuer.GenerateToken(header, claims)
+
+	tests := []struct {
+		name      string
+		nested    *NestedJWT
+		token     []byte
+		wantValid bool
+		wantErr   bool
+	}{
+		{"Must decrypt and verify a nested JWT", receiving, nested, true, false},
+		{"Must decrypt and verify a nested JWT from a keyring", sessions, sessionToken, true, false},
+		{"Must not verify a nested JWT signed with another key", receiving, forged, false, false},
+		{"Must not verify an encrypted token that is not nested", receiving, unnested, false, true},
+		{"Must not decrypt with only the public key", signing, nested, false, true},
+		{"Must not verify a signed token that is not encrypted", receiving, signed, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, valid, err := tt.nested.DecryptAndVerify(tt.token, &ValidationClaims{Issuer: []string{"issuer"}, Subject: []string{"alice"}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NestedJWT.DecryptAndVerify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if valid != tt.wantValid {
+				t.Errorf("NestedJWT.DecryptAndVerify() valid = %v, want %v", valid, tt.wantValid)
+			}
+			if valid && token.RegisteredClaims.Subject != "alice" {
+				t.Errorf("NestedJWT.DecryptAndVerify() subject = %q, want alice", token.RegisteredClaims.Subject)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// MinimumNonceLength is the smallest number of random bytes a nonce may be
+// generated from; 128 bits makes collisions and guessing impractical.
+const MinimumNonceLength = 16
+
+// maxNonceAttempts bounds regeneration when the uniqueness check rejects a
+// nonce. With at least 128 bits of entropy a single retry is already an
+// indication something is wrong.
+const maxNonceAttempts = 3
+
+// NonceEncoding is the text encoding of generated nonces.
+type NonceEncoding int
+
+const (
+	// NonceBase64URL encodes nonces as unpadded base64url.
+	NonceBase64URL NonceEncoding = iota
+	// NonceHex encodes nonces as lower-case hex.
+	NonceHex
+)
+
+// UniquenessChecker records issued or received values such as nonces and
+// JWT IDs, for replay protection.
+type UniquenessChecker interface {
+	// CheckAndStore records the value until expiry, returning false if
+	// the value is already recorded.
+	CheckAndStore(value string, expiry time.Time) (bool, error)
+}
+
+// NonceGenerator generates cryptographically random nonces and JWT IDs
+// ('jti'), for DPoP proofs, magic links, action tokens and the like.
+type NonceGenerator struct {
+	length   int
+	encoding NonceEncoding
+	checker  UniquenessChecker
+	ttl      time.Duration
+	rng      io.Reader
+}
+
+// NewNonceGenerator initializes a NonceGenerator producing nonces from
+// length random bytes. If checker is provided, every nonce is recorded
+// for ttl and regenerated should it ever collide.
+func NewNonceGenerator(length int, encoding NonceEncoding, checker UniquenessChecker, ttl time.Duration) (*NonceGenerator, error) {
+	if length < MinimumNonceLength {
+		return nil, fmt.Errorf("Nonce length must be at least %d bytes", MinimumNonceLength)
+	}
+
+	if encoding != NonceBase64URL && encoding != NonceHex {
+		return nil, fmt.Errorf("Unknown nonce encoding %d", encoding)
+	}
+
+	if nil != checker && ttl <= 0 {
+		return nil, errors.New("A positive TTL is required when checking nonce uniqueness")
+	}
+
+	return &NonceGenerator{
+		length:   length,
+		encoding: encoding,
+		checker:  checker,
+		ttl:      ttl,
+		rng:      rand.Reader,
+	}, nil
+}
+
+// Generate returns a new nonce.
+func (g *NonceGenerator) Generate() (string, error) {
+	for attempt := 0; attempt < maxNonceAttempts; attempt++ {
+		nonce, err := g.random()
+		if nil != err {
+			return "", err
+		}
+
+		if nil == g.checker {
+			return nonce, nil
+		}
+
+		unique, err := g.checker.CheckAndStore(nonce, time.Now().Add(g.ttl))
+		if nil != err {
+			return "", err
+		}
+		if unique {
+			return nonce, nil
+		}
+	}
+
+	return "", errors.New("Could not generate a unique nonce, the random source may be faulty")
+}
+
+func (g *NonceGenerator) random() (string, error) {
+	b := make([]byte, g.length)
+	if _, err := io.ReadFull(g.rng, b); nil != err {
+		return "", fmt.Errorf("Cannot read random bytes for nonce: %s", err)
+	}
+
+	if g.encoding == NonceHex {
+		return hex.EncodeToString(b), nil
+	}
+
+	return Base64URLEncode(b), nil
+}
+
+// GenerateNonce returns a base64url encoded nonce of MinimumNonceLength
+// random bytes, suitable for use as a 'jti' or 'nonce' claim.
+func GenerateNonce() (string, error) {
+	g, err := NewNonceGenerator(MinimumNonceLength, NonceBase64URL, nil, 0)
+	if nil != err {
+		return "", err
+	}
+
+	return g.Generate()
+}
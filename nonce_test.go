@@ -0,0 +1,78 @@
+package jwt
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestNewNonceGenerator(t *testing.T) {
+	type args struct {
+		length   int
+		encoding NonceEncoding
+		checker  UniquenessChecker
+		ttl      time.Duration
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantLen int
+		wantErr bool
+	}{
+		{"Must generate a base64url nonce", args{16, NonceBase64URL, nil, 0}, 22, false},
+		{"Must generate a hex nonce", args{32, NonceHex, nil, 0}, 64, false},
+		{"Must generate a checked nonce", args{16, NonceBase64URL, NewMemoryReplayStore(), time.Minute}, 22, false},
+		{"Must fail given a short length", args{8, NonceBase64URL, nil, 0}, 0, true},
+		{"Must fail given an unknown encoding", args{16, NonceEncoding(9), nil, 0}, 0, true},
+		{"Must fail given a checker without a TTL", args{16, NonceHex, NewMemoryReplayStore(), 0}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := NewNonceGenerator(tt.args.length, tt.args.encoding, tt.args.checker, tt.args.ttl)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewNonceGenerator() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			got, err := g.Generate()
+			if nil != err {
+				t.Errorf("NonceGenerator.Generate() error = %v", err)
+			}
+			if len(got) != tt.wantLen {
+				t.Errorf("NonceGenerator.Generate() length = %v, want %v", len(got), tt.wantLen)
+			}
+		})
+	}
+}
+
+func TestNonceGenerator_Generate_StuckRandomSource(t *testing.T) {
+	g, _ := NewNonceGenerator(16, NonceHex, NewMemoryReplayStore(), time.Minute)
+	g.rng = bytes.NewReader(make([]byte, 1024))
+
+	if _, err := g.Generate(); nil != err {
+		t.Fatalf("NonceGenerator.Generate() error = %v", err)
+	}
+	if _, err := g.Generate(); nil == err {
+		t.Errorf("NonceGenerator.Generate() expected error given a repeating random source")
+	}
+}
+
+func TestMemoryReplayStore_CheckAndStore(t *testing.T) {
+	s := NewMemoryReplayStore()
+
+	if unique, _ := s.CheckAndStore("jti-1", time.Now().Add(time.Minute)); !unique {
+		t.Errorf("MemoryReplayStore.CheckAndStore() = false for a new value")
+	}
+	if unique, _ := s.CheckAndStore("jti-1", time.Now().Add(time.Minute)); unique {
+		t.Errorf("MemoryReplayStore.CheckAndStore() = true for a replayed value")
+	}
+	if unique, _ := s.CheckAndStore("jti-2", time.Now().Add(-time.Minute)); !unique {
+		t.Errorf("MemoryReplayStore.CheckAndStore() = false for a new value")
+	}
+	if unique, _ := s.CheckAndStore("jti-2", time.Now().Add(time.Minute)); !unique {
+		t.Errorf("MemoryReplayStore.CheckAndStore() = false for an expired value")
+	}
+}
@@ -0,0 +1,74 @@
+package jwt
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// NumericDate is a JSON numeric date: seconds since the epoch (RFC 7519,
+// section 2). It is encoded as a JSON number, and decoded from a number,
+// which may have a fraction, or from a string holding a number, as earlier
+// versions of this package encoded it. Fractions of a second are dropped.
+// Claims hold a *NumericDate, which is nil when the claim is absent, so a
+// date of 0 is the epoch rather than a missing claim.
+type NumericDate int64
+
+// NewNumericDate returns the time, truncated to seconds, as a NumericDate.
+func NewNumericDate(t time.Time) *NumericDate {
+	date := NumericDate(t.Unix())
+	return &date
+}
+
+// Time returns the date as a time.
+func (date NumericDate) Time() time.Time {
+	return time.Unix(int64(date), 0)
+}
+
+// UnmarshalJSON decodes a date from a number or a string holding one.
+func (date *NumericDate) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+
+	var raw string
+	if err := json.Unmarshal(b, &raw); nil != err {
+		raw = string(b)
+	}
+
+	seconds, err := parseNumericDate(raw)
+	if nil != err {
+		return fmt.Errorf("Could not parse NumericDate %s", b)
+	}
+
+	*date = NumericDate(seconds)
+	return nil
+}
+
+// parseNumericDate parses seconds since the epoch, which may have a
+// fraction. Values that are not finite or don't fit in an int64 are
+// rejected, as their conversion is undefined.
+func parseNumericDate(raw string) (int64, error) {
+	if i, err := strconv.ParseInt(raw, 10, 64); nil == err {
+		return i, nil
+	}
+
+	f, err := strconv.ParseFloat(raw, 64)
+	if nil != err {
+		return 0, err
+	}
+
+	return numericDateSeconds(f)
+}
+
+// numericDateSeconds truncates a float of seconds since the epoch,
+// failing if it is not finite or doesn't fit in an int64.
+func numericDateSeconds(f float64) (int64, error) {
+	if math.IsNaN(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, fmt.Errorf("NumericDate %v is out of range", f)
+	}
+
+	return int64(f), nil
+}
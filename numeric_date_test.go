@@ -0,0 +1,105 @@
+package jwt
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// numericDate returns the seconds since the epoch as a *NumericDate.
+func numericDate(seconds int64) *NumericDate {
+	date := NumericDate(seconds)
+	return &date
+}
+
+// formatDate formats a date claim for test failures.
+func formatDate(date *NumericDate) string {
+	if nil == date {
+		return "absent"
+	}
+
+	return strconv.FormatInt(int64(*date), 10)
+}
+
+func TestNumericDate_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    NumericDate
+		wantErr bool
+	}{
+		{"Must decode a number", `1600000000`, 1600000000, false},
+		{"Must decode a number with a fraction", `1600000000.75`, 1600000000, false},
+		{"Must decode a number with an exponent", `1.6e9`, 1600000000, false},
+		{"Must decode a string", `"1600000000"`, 1600000000, false},
+		{"Must decode a string with a fraction", `"1600000000.5"`, 1600000000, false},
+		{"Must decode null as absent", `null`, 0, false},
+		{"Must fail a string that is not a number", `"yesterday"`, 0, true},
+		{"Must fail a boolean", `true`, 0, true},
+		{"Must fail NaN", `"NaN"`, 0, true},
+		{"Must fail infinity", `"-Inf"`, 0, true},
+		{"Must fail a number out of range", `1e300`, 0, true},
+		{"Must fail a string out of range", `"9223372036854775808.5"`, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var date NumericDate
+			err := json.Unmarshal([]byte(tt.data), &date)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if date != tt.want {
+				t.Errorf("UnmarshalJSON() = %v, want %v", date, tt.want)
+			}
+		})
+	}
+}
+
+func TestClaims_NumericDates(t *testing.T) {
+	issuedAt := time.Unix(1600000000, 0)
+	encoded, err := json.Marshal(Claims{IssuedAt: NewNumericDate(issuedAt), Expiration: NewNumericDate(issuedAt.Add(time.Hour))})
+	if nil != err {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if string(encoded) != `{"exp":1600003600,"iat":1600000000}` {
+		t.Errorf("Marshal() = %s", encoded)
+	}
+
+	var claims Claims
+	if err := json.Unmarshal([]byte(`{"exp":1600003600.5,"nbf":"1600000000","iat":1600000000}`), &claims); nil != err {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if !claims.Expiration.Time().Equal(issuedAt.Add(time.Hour)) || !claims.NotBefore.Time().Equal(issuedAt) || !claims.IssuedAt.Time().Equal(issuedAt) {
+		t.Errorf("Unmarshal() = %+v", claims)
+	}
+}
+
+func TestClaims_VerifyExpiration_Epoch(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		data      string
+		wantValid bool
+	}{
+		{"Must accept a token without an expiration", `{}`, true},
+		{"Must accept a null expiration as absent", `{"exp":null}`, true},
+		{"Must reject an expiration of 0", `{"exp":0}`, false},
+		{"Must reject an expiration within the first second", `{"exp":0.5}`, false},
+		{"Must reject an expiration of \"0\"", `{"exp":"0"}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var claims Claims
+			if err := json.Unmarshal([]byte(tt.data), &claims); nil != err {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+
+			valid, err := claims.VerifyExpiration(now, 0)
+			if nil != err || valid != tt.wantValid {
+				t.Errorf("VerifyExpiration() = %v, %v, want %v", valid, err, tt.wantValid)
+			}
+		})
+	}
+}
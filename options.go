@@ -0,0 +1,25 @@
+package jwt
+
+// Option configures optional behaviour of a JOSESignerVerifier.
+type Option func(sv *JOSESignerVerifier) error
+
+// applyOptions applies the options to the JOSESignerVerifier.
+func (sv *JOSESignerVerifier) applyOptions(opts []Option) (*JOSESignerVerifier, error) {
+	for _, opt := range opts {
+		if err := opt(sv); nil != err {
+			return nil, err
+		}
+	}
+
+	return sv, nil
+}
+
+// WithCanonicalClaims canonicalizes the claim set of generated tokens
+// using the JSON Canonicalization Scheme (RFC 8785) before it is signed,
+// for interop with systems that recompute claim hashes independently.
+func WithCanonicalClaims() Option {
+	return func(sv *JOSESignerVerifier) error {
+		sv.canonicalClaims = true
+		return nil
+	}
+}
@@ -0,0 +1,50 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+)
+
+// ErrInternal matches, with errors.Is, the errors returned in place of
+// panics within token signing and verification.
+var ErrInternal = errors.New("Internal error")
+
+// InternalError is returned when signing or verifying a token panics,
+// for example on a malformed key or in a third-party algorithm, so that a
+// single bad token can never crash a service.
+type InternalError struct {
+	// Op is the entry point that panicked, e.g. "VerifySignature".
+	Op string
+	// Panic is the recovered value, and Stack the stack trace of the
+	// panicking goroutine.
+	Panic interface{}
+	Stack []byte
+}
+
+func (e *InternalError) Error() string {
+	return fmt.Sprintf("Internal error in %s: %v", e.Op, e.Panic)
+}
+
+// Is reports whether target is ErrInternal.
+func (e *InternalError) Is(target error) bool {
+	return target == ErrInternal
+}
+
+// recoverInternal converts a panic into an InternalError for the
+// operation, stored in err. It must be deferred directly.
+func recoverInternal(op string, err *error) {
+	if r := recover(); nil != r {
+		*err = newInternalError(op, r)
+	}
+}
+
+// newInternalError returns an InternalError for a recovered panic, with
+// the stack trace of the current goroutine.
+func newInternalError(op string, r interface{}) error {
+	return &InternalError{
+		Op:    op,
+		Panic: r,
+		Stack: debug.Stack(),
+	}
+}
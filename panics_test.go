@@ -0,0 +1,55 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/georgejenkins/jwt/jwk"
+)
+
+// panickingSigner stands in for a faulty third-party algorithm.
+type panickingSigner struct{}
+
+func (panickingSigner) Sign(data []byte) ([]byte, error) {
+	panic("index out of range")
+}
+
+func TestPanicContainment(t *testing.T) {
+	signer := &JOSESignerVerifier{algorithm: HS256, signer: panickingSigner{}}
+	resolver := KeyResolverFunc(func(header Header) (*jwk.Key, error) {
+		var key *jwk.Key
+		return key, errors.New(key.KeyID)
+	})
+	verifier, _ := NewKeyResolvingVerifier(resolver)
+
+	hmacSigner, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	token, _ := hmacSigner.GenerateToken(Header{Algorithm: string(HS256), KeyID: "k"}, Claims{Subject: "alice"})
+
+	if _, err := signer.GenerateToken(Header{Algorithm: string(HS256)}, Claims{}); !errors.Is(err, ErrInternal) {
+		t.Errorf("GenerateToken() error = %v, want %v", err, ErrInternal)
+	}
+
+	results := signer.IssueBatch(Header{Algorithm: string(HS256)}, []IssueRequest{{Claims: Claims{}}})
+	if !errors.Is(results[0].Err, ErrInternal) {
+		t.Errorf("IssueBatch() error = %v, want %v", results[0].Err, ErrInternal)
+	}
+
+	got, valid, err := verifier.VerifySignature(token)
+	if nil != got || valid || !errors.Is(err, ErrInternal) {
+		t.Errorf("VerifySignature() = %v, %v, %v, want an internal error", got, valid, err)
+	}
+
+	before := RecentVerificationFailures()[FailureInternal]
+	got, valid, err = verifier.VerifyToken(token, &ValidationClaims{Subject: []string{"alice"}})
+	if nil != got || valid || !errors.Is(err, ErrInternal) {
+		t.Errorf("VerifyToken() = %v, %v, %v, want an internal error", got, valid, err)
+	}
+	if RecentVerificationFailures()[FailureInternal] != before+1 {
+		t.Errorf("VerifyToken() did not record an internal failure")
+	}
+
+	var internal *InternalError
+	if !errors.As(err, &internal) || internal.Op != "VerifySignature" || len(internal.Stack) == 0 {
+		t.Errorf("VerifyToken() error = %#v, want an InternalError with diagnostics", err)
+	}
+}
@@ -0,0 +1,59 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/georgejenkins/jwt/jwk"
+	"github.com/georgejenkins/jwt/jws"
+)
+
+// ErrKeyNotPinned is returned when a verification key's thumbprint is not
+// in the pin set.
+var ErrKeyNotPinned = errors.New("Key is not pinned")
+
+// WithPinnedKeys only accepts verification keys whose RFC 7638 thumbprints,
+// as returned by jwk.Thumbprint, are in the pin set. Keys selected by a
+// KeyResolver, for example from a JWKS, are checked at verification; a
+// single key is checked at construction. This is defense in depth against
+// a compromised key source for high-value verifiers.
+func WithPinnedKeys(thumbprints ...string) Option {
+	return func(sv *JOSESignerVerifier) error {
+		if len(thumbprints) == 0 {
+			return errors.New("At least one key must be pinned")
+		}
+
+		pins := make(map[string]bool, len(sv.pinnedKeys)+len(thumbprints))
+		for thumbprint := range sv.pinnedKeys {
+			pins[thumbprint] = true
+		}
+		for _, thumbprint := range thumbprints {
+			digest, err := jws.Base64URLDecode(thumbprint)
+			if nil != err || len(digest) != 32 {
+				return fmt.Errorf("Invalid key thumbprint %q", thumbprint)
+			}
+			pins[thumbprint] = true
+		}
+
+		sv.pinnedKeys = pins
+		return nil
+	}
+}
+
+// checkPinnedKey returns ErrKeyNotPinned if keys are pinned and key is not
+// one of them.
+func (sv *JOSESignerVerifier) checkPinnedKey(key interface{}) error {
+	if len(sv.pinnedKeys) == 0 {
+		return nil
+	}
+
+	thumbprint, err := jwk.Thumbprint(key)
+	if nil != err {
+		return err
+	}
+	if !sv.pinnedKeys[thumbprint] {
+		return ErrKeyNotPinned
+	}
+
+	return nil
+}
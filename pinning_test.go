@@ -0,0 +1,70 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+
+	"github.com/georgejenkins/jwt/jwk"
+)
+
+func TestWithPinnedKeys(t *testing.T) {
+	pinnedKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	rogueKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	pin, _ := jwk.Thumbprint(&pinnedKey.PublicKey)
+
+	set := &jwk.Set{Keys: []*jwk.Key{
+		{KeyID: "pinned", Key: &pinnedKey.PublicKey},
+		{KeyID: "rogue", Key: &rogueKey.PublicKey},
+	}}
+	verifier, err := NewKeyResolvingVerifier(KeySetResolver(set), WithPinnedKeys(pin))
+	if nil != err {
+		t.Fatalf("NewKeyResolvingVerifier() error = %v", err)
+	}
+
+	pinnedSigner, _ := NewJOSESignerVerifier(ES256, pinnedKey)
+	rogueSigner, _ := NewJOSESignerVerifier(ES256, rogueKey)
+	sign := func(sv *JOSESignerVerifier, kid string) []byte {
+		token, err := sv.GenerateToken(Header{Algorithm: string(ES256), KeyID: kid}, Claims{Subject: "alice"})
+		if nil != err {
+			t.Fatalf("GenerateToken() error = %v", err)
+		}
+		return token
+	}
+
+	tests := []struct {
+		name      string
+		token     []byte
+		wantValid bool
+		wantErr   error
+	}{
+		{"Must verify with a pinned key from the key set", sign(pinnedSigner, "pinned"), true, nil},
+		{"Must not verify with an unpinned key from the key set", sign(rogueSigner, "rogue"), false, ErrKeyNotPinned},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, valid, err := verifier.VerifySignature(tt.token)
+			if valid != tt.wantValid || (nil == tt.wantErr) != (nil == err) || (nil != tt.wantErr && !errors.Is(err, tt.wantErr)) {
+				t.Errorf("VerifySignature() = %v, %v, want %v, %v", valid, err, tt.wantValid, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := NewJOSESignerVerifier(ES256, &pinnedKey.PublicKey, WithPinnedKeys(pin)); nil != err {
+		t.Errorf("NewJOSESignerVerifier() error = %v with a pinned key", err)
+	}
+	if _, err := NewJOSESignerVerifier(ES256, pinnedKey, WithPinnedKeys(pin)); nil != err {
+		t.Errorf("NewJOSESignerVerifier() error = %v with the private key of a pinned key", err)
+	}
+	if _, err := NewJOSESignerVerifier(ES256, &rogueKey.PublicKey, WithPinnedKeys(pin)); !errors.Is(err, ErrKeyNotPinned) {
+		t.Errorf("NewJOSESignerVerifier() error = %v with an unpinned key, want %v", err, ErrKeyNotPinned)
+	}
+	if _, err := NewJOSESignerVerifier(ES256, pinnedKey, WithPinnedKeys("not-a-thumbprint")); nil == err {
+		t.Errorf("NewJOSESignerVerifier() expected error for an invalid thumbprint")
+	}
+	if _, err := NewJOSESignerVerifier(ES256, pinnedKey, WithPinnedKeys()); nil == err {
+		t.Errorf("NewJOSESignerVerifier() expected error for an empty pin set")
+	}
+}
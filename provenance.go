@@ -0,0 +1,131 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"time"
+
+	"github.com/georgejenkins/jwt/jwk"
+)
+
+// KeySource identifies where a verification key came from.
+type KeySource string
+
+// Key sources.
+const (
+	// KeySourceStatic is a key given to NewJOSESignerVerifier.
+	KeySourceStatic KeySource = "static"
+	// KeySourceJWKS is a key fetched from a JWKS URL.
+	KeySourceJWKS KeySource = "jwks"
+	// KeySourceResolver is a key from a KeyResolver that doesn't report
+	// its provenance.
+	KeySourceResolver KeySource = "resolver"
+)
+
+// Provenance records how the key that verified a token was obtained, so
+// audit logs can show how each token was trusted.
+type Provenance struct {
+	Source KeySource `json:"source"`
+	// Location is where the key was fetched from, e.g. the JWKS URL.
+	Location string `json:"location,omitempty"`
+	KeyID    string `json:"kid,omitempty"`
+	// Thumbprint is the RFC 7638 thumbprint of asymmetric keys.
+	Thumbprint string `json:"thumbprint,omitempty"`
+	// LoadedAt is when the key was loaded or last fetched, and KeyAge the
+	// time since then at verification.
+	LoadedAt time.Time     `json:"loaded_at"`
+	KeyAge   time.Duration `json:"key_age"`
+	// FetchLatency is how long the last fetch from Location took.
+	FetchLatency time.Duration `json:"fetch_latency,omitempty"`
+}
+
+// ProvenanceReporter is implemented by KeyResolvers that report where
+// their keys come from. JWKSFetcher is a ProvenanceReporter.
+type ProvenanceReporter interface {
+	// Provenance describes the resolver's current key source. The key ID,
+	// thumbprint and key age are filled in for each resolved key.
+	Provenance() Provenance
+}
+
+// Provenance returns the provenance of the key that verified the token's
+// signature, or nil if the signature hasn't been verified with a key.
+func (t *Token) Provenance() *Provenance {
+	return t.provenance
+}
+
+// Provenance reports the JWKS URL, when the set was last fetched or
+// revalidated, and how long that took.
+func (f *JWKSFetcher) Provenance() Provenance {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return Provenance{
+		Source:       KeySourceJWKS,
+		Location:     f.url,
+		LoadedAt:     f.fetchedAt,
+		FetchLatency: f.fetchLatency,
+	}
+}
+
+// staticProvenance returns the provenance of a key given at construction.
+func staticProvenance(key interface{}) *Provenance {
+	return &Provenance{
+		Source:     KeySourceStatic,
+		Thumbprint: publicThumbprint(key),
+		LoadedAt:   time.Now(),
+	}
+}
+
+// resolvedProvenance returns the provenance of a key selected by the
+// resolver.
+func resolvedProvenance(resolver KeyResolver, key *jwk.Key) *Provenance {
+	provenance := Provenance{Source: KeySourceResolver}
+	if reporter, ok := resolver.(ProvenanceReporter); ok {
+		provenance = reporter.Provenance()
+	}
+
+	provenance.KeyID = key.KeyID
+	provenance.Thumbprint = publicThumbprint(key.Key)
+	return &provenance
+}
+
+// verifiedAt returns a copy of the provenance with the key's age at now.
+func (p *Provenance) verifiedAt(now time.Time) *Provenance {
+	if nil == p {
+		return nil
+	}
+
+	verified := *p
+	if !verified.LoadedAt.IsZero() {
+		verified.KeyAge = now.Sub(verified.LoadedAt)
+	}
+	return &verified
+}
+
+// publicThumbprint returns the thumbprint of an asymmetric key, or "" for
+// symmetric keys, whose thumbprints would be a hash of the secret.
+func publicThumbprint(key interface{}) string {
+	switch key.(type) {
+	case *rsa.PublicKey, *rsa.PrivateKey, *ecdsa.PublicKey, *ecdsa.PrivateKey,
+		ed25519.PublicKey, *ed25519.PublicKey, ed25519.PrivateKey, *ed25519.PrivateKey:
+		thumbprint, _ := jwk.Thumbprint(key)
+		return thumbprint
+	}
+	return ""
+}
+
+// newVerificationResult returns the result of a verification, with the
+// provenance of the verification key.
+func newVerificationResult(token *Token, valid bool, err error) VerificationResult {
+	result := VerificationResult{
+		Token: token,
+		Valid: valid,
+		Err:   err,
+	}
+	if nil != token {
+		result.Provenance = token.Provenance()
+	}
+
+	return result
+}
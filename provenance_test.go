@@ -0,0 +1,77 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/georgejenkins/jwt/jwk"
+)
+
+func TestTokenProvenance(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	thumbprint, _ := jwk.Thumbprint(key)
+	keys, _ := json.Marshal(&jwk.Set{Keys: []*jwk.Key{{KeyID: "ec-1", Key: &key.PublicKey}}})
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(keys)
+	}))
+	defer server.Close()
+
+	fetcher, _ := NewJWKSFetcher(server.URL, time.Hour, server.Client())
+	jwksVerifier, _ := NewKeyResolvingVerifier(fetcher)
+	staticVerifier, _ := NewJOSESignerVerifier(ES256, &key.PublicKey)
+	hmacVerifier, _ := NewJOSESignerVerifier(HS256, exampleKey)
+
+	signer, _ := NewJOSESignerVerifier(ES256, key)
+	token, _ := signer.GenerateToken(Header{Algorithm: string(ES256), KeyID: "ec-1"}, Claims{Subject: "alice"})
+	hmacSigner, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	hmacToken, _ := hmacSigner.GenerateToken(Header{Algorithm: string(HS256)}, Claims{Subject: "alice"})
+
+	tests := []struct {
+		name     string
+		verifier *JOSESignerVerifier
+		token    []byte
+		want     Provenance
+	}{
+		{"Must record a static key", staticVerifier, token, Provenance{Source: KeySourceStatic, Thumbprint: thumbprint}},
+		{"Must record a key from a JWKS URL", jwksVerifier, token, Provenance{Source: KeySourceJWKS, Location: server.URL, KeyID: "ec-1", Thumbprint: thumbprint}},
+		{"Must not record the thumbprint of a symmetric key", hmacVerifier, hmacToken, Provenance{Source: KeySourceStatic}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verified, valid, err := tt.verifier.VerifySignature(tt.token)
+			if nil != err || !valid {
+				t.Fatalf("VerifySignature() = %v, %v", valid, err)
+			}
+
+			got := verified.Provenance()
+			if nil == got {
+				t.Fatalf("Token.Provenance() = nil")
+			}
+			if got.Source != tt.want.Source || got.Location != tt.want.Location ||
+				got.KeyID != tt.want.KeyID || got.Thumbprint != tt.want.Thumbprint {
+				t.Errorf("Token.Provenance() = %+v, want %+v", got, tt.want)
+			}
+			if got.LoadedAt.IsZero() || got.KeyAge < 0 {
+				t.Errorf("Token.Provenance() loaded at %v, key age %v", got.LoadedAt, got.KeyAge)
+			}
+			if got.Source == KeySourceJWKS && got.FetchLatency <= 0 {
+				t.Errorf("Token.Provenance() fetch latency = %v, want > 0", got.FetchLatency)
+			}
+		})
+	}
+
+	av, _ := NewAsyncVerifier(staticVerifier, &ValidationClaims{Subject: []string{"alice"}}, 1)
+	defer av.Close()
+	result := <-av.VerifyAsync(context.Background(), token)
+	if nil == result.Provenance || result.Provenance.Source != KeySourceStatic {
+		t.Errorf("VerificationResult.Provenance = %+v, want a static key", result.Provenance)
+	}
+}
@@ -0,0 +1,150 @@
+package jwt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// SubjectMapper maps a real subject to a pseudonymous subject for a
+// sector, such as a relying party. The same subject must map to the same
+// pseudonym within a sector, and to unlinkable pseudonyms across sectors.
+type SubjectMapper interface {
+	Pseudonymize(subject string, sector string) (string, error)
+}
+
+// ReversibleSubjectMapper is a SubjectMapper that can also recover the real
+// subject from a pseudonym, for internal services that must act on the
+// subject of a pseudonymous token.
+type ReversibleSubjectMapper interface {
+	SubjectMapper
+	Resolve(pseudonym string, sector string) (string, error)
+}
+
+// HMACSubjectMapper derives pairwise subjects as the base64url encoded
+// HMAC-SHA256 of the sector identifier and subject, in the manner of
+// OpenID Connect pairwise subject identifiers. Pseudonyms cannot be
+// reversed without recording them, see RecordingSubjectMapper.
+type HMACSubjectMapper struct {
+	key []byte
+}
+
+// NewHMACSubjectMapper creates an HMACSubjectMapper. The key must be kept
+// secret, as anyone holding it can link pseudonyms to known subjects.
+func NewHMACSubjectMapper(key []byte) (*HMACSubjectMapper, error) {
+	if len(key) < sha256.Size {
+		return nil, fmt.Errorf("Subject mapping key must be at least %d bytes", sha256.Size)
+	}
+
+	return &HMACSubjectMapper{
+		key: key,
+	}, nil
+}
+
+// Pseudonymize returns the pairwise subject for the subject and sector.
+func (m *HMACSubjectMapper) Pseudonymize(subject string, sector string) (string, error) {
+	mac := hmac.New(sha256.New, m.key)
+	// The sector is length prefixed so that sector and subject boundaries
+	// cannot be shifted to produce the same input.
+	fmt.Fprintf(mac, "%d:%s", len(sector), sector)
+	mac.Write([]byte(subject))
+
+	return Base64URLEncode(mac.Sum(nil)), nil
+}
+
+// RecordingSubjectMapper records the pseudonyms produced by a SubjectMapper
+// in memory so that they can be resolved back to their subjects.
+type RecordingSubjectMapper struct {
+	mapper SubjectMapper
+
+	mu       sync.RWMutex
+	subjects map[string]string
+}
+
+// NewRecordingSubjectMapper creates a RecordingSubjectMapper around mapper.
+func NewRecordingSubjectMapper(mapper SubjectMapper) *RecordingSubjectMapper {
+	return &RecordingSubjectMapper{
+		mapper:   mapper,
+		subjects: make(map[string]string),
+	}
+}
+
+// Pseudonymize returns and records the pseudonym for the subject and sector.
+func (m *RecordingSubjectMapper) Pseudonymize(subject string, sector string) (string, error) {
+	pseudonym, err := m.mapper.Pseudonymize(subject, sector)
+	if nil != err {
+		return "", err
+	}
+
+	m.mu.Lock()
+	m.subjects[recordedSubjectKey(pseudonym, sector)] = subject
+	m.mu.Unlock()
+
+	return pseudonym, nil
+}
+
+// Resolve returns the subject a pseudonym was produced for.
+func (m *RecordingSubjectMapper) Resolve(pseudonym string, sector string) (string, error) {
+	m.mu.RLock()
+	subject, ok := m.subjects[recordedSubjectKey(pseudonym, sector)]
+	m.mu.RUnlock()
+
+	if !ok {
+		return "", fmt.Errorf("Unknown pseudonym %q for sector %q", pseudonym, sector)
+	}
+
+	return subject, nil
+}
+
+func recordedSubjectKey(pseudonym string, sector string) string {
+	return fmt.Sprintf("%d:%s%s", len(sector), sector, pseudonym)
+}
+
+// WithPseudonymousSubjects replaces the 'sub' claim of generated tokens
+// with the pseudonym produced by mapper. The sector identifier is sector,
+// or when empty the token's 'aud' claim, giving each audience its own
+// pairwise subjects.
+func WithPseudonymousSubjects(mapper SubjectMapper, sector string) Option {
+	return func(sv *JOSESignerVerifier) error {
+		if nil == mapper {
+			return errors.New("Subject mapper cannot be nil")
+		}
+
+		sv.subjectMapper = mapper
+		sv.subjectSector = sector
+		return nil
+	}
+}
+
+// pseudonymizeSubject replaces the subject of the claims with its
+// pseudonym, returning the modified claims.
+func (sv *JOSESignerVerifier) pseudonymizeSubject(jwsPayload []byte) ([]byte, error) {
+	claims, err := decodeClaimsMap(jwsPayload)
+	if nil != err {
+		return nil, err
+	}
+
+	subject, ok := claims["sub"].(string)
+	if !ok || subject == "" {
+		return jwsPayload, nil
+	}
+
+	sector := sv.subjectSector
+	if sector == "" {
+		audience, ok := claims["aud"].(string)
+		if !ok || audience == "" {
+			return nil, errors.New("Pseudonymous subjects require a sector or a single 'aud' claim")
+		}
+		sector = audience
+	}
+
+	claims["sub"], err = sv.subjectMapper.Pseudonymize(subject, sector)
+	if nil != err {
+		return nil, err
+	}
+
+	return json.Marshal(claims)
+}
@@ -0,0 +1,72 @@
+package jwt
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWithPseudonymousSubjects(t *testing.T) {
+	mapperKey := bytes.Repeat([]byte{0x42}, 32)
+	hmacMapper, err := NewHMACSubjectMapper(mapperKey)
+	if nil != err {
+		t.Fatalf("NewHMACSubjectMapper() error = %v", err)
+	}
+	mapper := NewRecordingSubjectMapper(hmacMapper)
+
+	issue := func(sector string, claims Claims) (Claims, error) {
+		sv, err := NewJOSESignerVerifier(HS256, exampleKey, WithPseudonymousSubjects(mapper, sector))
+		if nil != err {
+			t.Fatalf("NewJOSESignerVerifier() error = %v", err)
+		}
+
+		raw, err := sv.GenerateToken(Header{Algorithm: string(HS256)}, claims)
+		if nil != err {
+			return Claims{}, err
+		}
+
+		token, _, err := sv.VerifySignature(raw)
+		if nil != err {
+			t.Fatalf("VerifySignature() error = %v", err)
+		}
+
+		var got Claims
+		GetClaims(token, &got)
+		return got, nil
+	}
+
+	forA, err := issue("", Claims{Subject: "alice", Audience: "rp-a"})
+	if nil != err {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+	againForA, _ := issue("", Claims{Subject: "alice", Audience: "rp-a"})
+	forB, _ := issue("", Claims{Subject: "alice", Audience: "rp-b"})
+	fixedSector, _ := issue("https://rp.example.com", Claims{Subject: "alice", Audience: "rp-b"})
+
+	if forA.Subject == "alice" || forA.Subject == "" {
+		t.Errorf("Subject was not pseudonymized: %q", forA.Subject)
+	}
+	if forA.Subject != againForA.Subject {
+		t.Errorf("Pseudonyms differ within a sector: %q, %q", forA.Subject, againForA.Subject)
+	}
+	if forA.Subject == forB.Subject {
+		t.Errorf("Pseudonyms match across sectors: %q", forA.Subject)
+	}
+	if fixedSector.Subject == forB.Subject {
+		t.Errorf("Configured sector was not used in place of the audience")
+	}
+
+	if subject, err := mapper.Resolve(forB.Subject, "rp-b"); nil != err || subject != "alice" {
+		t.Errorf("Resolve() = %v, %v, want alice", subject, err)
+	}
+	if _, err := mapper.Resolve(forB.Subject, "rp-a"); nil == err {
+		t.Errorf("Resolve() expected error for a pseudonym of another sector")
+	}
+
+	if _, err := issue("", Claims{Subject: "alice"}); nil == err {
+		t.Errorf("GenerateToken() expected error without a sector or audience")
+	}
+
+	if _, err := NewHMACSubjectMapper([]byte("short")); nil == err {
+		t.Errorf("NewHMACSubjectMapper() expected error for a short key")
+	}
+}
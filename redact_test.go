@@ -0,0 +1,58 @@
+package jwt
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRedactToken(t *testing.T) {
+	sv, err := NewJOSESignerVerifier(HS256, exampleKey)
+	if nil != err {
+		t.Fatalf("NewJOSESignerVerifier() error = %v", err)
+	}
+
+	rawToken, err := sv.GenerateToken(
+		Header{Algorithm: string(HS256), KeyID: "kid-1"},
+		map[string]interface{}{
+			"iss":   "vizima",
+			"sub":   "foltest",
+			"email": "foltest@temeria.example",
+		},
+	)
+	if nil != err {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	redacted, err := RedactToken(rawToken, []string{"iss"})
+	if nil != err {
+		t.Fatalf("RedactToken() error = %v", err)
+	}
+
+	token, err := GetRawTokenParts(redacted)
+	if nil != err {
+		t.Fatalf("GetRawTokenParts() error on redacted token = %v", err)
+	}
+
+	if !bytes.Equal(token.RawHeader, bytes.Split(rawToken, []byte("."))[0]) {
+		t.Errorf("RedactToken() header = %s, want it unchanged", token.DecodedHeader)
+	}
+	if len(token.DecodedSignature) != 0 {
+		t.Errorf("RedactToken() kept the signature")
+	}
+
+	body := string(token.DecodedBody)
+	if !strings.Contains(body, `"iss":"vizima"`) {
+		t.Errorf("RedactToken() body = %s, want allow-listed iss kept", body)
+	}
+	if strings.Contains(body, "foltest") {
+		t.Errorf("RedactToken() body = %s, want sub and email redacted", body)
+	}
+	if strings.Count(body, redactedClaimPrefix) != 2 {
+		t.Errorf("RedactToken() body = %s, want two redacted claims", body)
+	}
+
+	if _, valid, _ := sv.VerifySignature(redacted); valid {
+		t.Errorf("RedactToken() produced a token that passes verification")
+	}
+}
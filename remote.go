@@ -0,0 +1,427 @@
+package jwt
+
+import (
+	"container/list"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/georgejenkins/jwt/jwk"
+)
+
+// Defaults for RemoteKeyConfig.
+const (
+	defaultRemoteKeyTTL          = time.Hour
+	defaultRemoteMaxRedirects    = 3
+	defaultRemoteMaxResponseSize = 64 << 10
+	defaultRemoteTimeout         = 10 * time.Second
+	defaultRemoteFetchesPerHost  = 30
+)
+
+// maxRemoteKeySources bounds the number of jku and x5u URLs cached at once,
+// evicting the least recently used.
+const maxRemoteKeySources = 64
+
+// ErrRemoteURLNotAllowed is returned for 'jku' and 'x5u' URLs, or
+// redirects, outside the allow-list.
+var ErrRemoteURLNotAllowed = errors.New("Remote key URL is not allowed")
+
+// ErrRemoteFetchLimited is returned when a host's remote key fetches
+// exceed RemoteKeyConfig.MaxFetchesPerHost in a minute.
+var ErrRemoteFetchLimited = errors.New("Remote key fetches are rate limited")
+
+// RemoteKeyConfig configures a RemoteKeyResolver.
+type RemoteKeyConfig struct {
+	// AllowedURLs are the HTTPS URLs keys may be fetched from. A 'jku' or
+	// 'x5u' URL is allowed if it has the same host as an allowed URL, its
+	// path is the allowed path or below it, and its query is the allowed
+	// URL's query, if any. Required.
+	AllowedURLs []string
+
+	// Client fetches the keys. Its redirect policy is replaced to enforce
+	// MaxRedirects and the allow-list. Defaults to a client with a 10
+	// second timeout.
+	Client *http.Client
+
+	// TTL is how long fetched keys are cached. Defaults to an hour.
+	TTL time.Duration
+
+	// MaxRedirects is the number of redirects followed. Defaults to 3;
+	// negative values disable redirects.
+	MaxRedirects int
+
+	// MaxResponseSize bounds the size of fetched documents. Defaults to
+	// 64 KiB.
+	MaxResponseSize int64
+
+	// MaxFetchesPerHost bounds the requests made to each host per minute,
+	// as tokens choose which allowed URLs are fetched before their
+	// signature is checked. Defaults to 30.
+	MaxFetchesPerHost int
+
+	// Roots, if set, are the roots 'x5u' certificate chains must verify
+	// against. Otherwise 'x5u' keys are trusted by the allow-list alone.
+	Roots *x509.CertPool
+}
+
+// RemoteKeyResolver is a KeyResolver fetching keys from the 'jku' (JWK Set
+// URL) and 'x5u' (X.509 URL) headers of tokens, restricted to an
+// allow-list of URLs so tokens can't make the verifier fetch from
+// arbitrary hosts (SSRF). Use it with NewKeyResolvingVerifier.
+type RemoteKeyResolver struct {
+	allowed []*url.URL
+	client  *http.Client
+	ttl     time.Duration
+	maxSize int64
+	roots   *x509.CertPool
+
+	mu           sync.Mutex
+	jwks         *remoteKeySources
+	certificates *remoteKeySources
+}
+
+// remoteKeySources caches values by URL, evicting the least recently
+// used beyond maxRemoteKeySources.
+type remoteKeySources struct {
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+// remoteKeySource is a cached value, a *JWKSFetcher or remoteCertificate.
+type remoteKeySource struct {
+	url   string
+	value interface{}
+}
+
+// remoteCertificate is a cached 'x5u' key.
+type remoteCertificate struct {
+	key    interface{}
+	expiry time.Time
+}
+
+// NewRemoteKeyResolver creates a RemoteKeyResolver.
+func NewRemoteKeyResolver(config RemoteKeyConfig) (*RemoteKeyResolver, error) {
+	if len(config.AllowedURLs) == 0 {
+		return nil, errors.New("Remote key resolution requires an allow-list of URLs")
+	}
+
+	r := &RemoteKeyResolver{
+		ttl:          config.TTL,
+		maxSize:      config.MaxResponseSize,
+		roots:        config.Roots,
+		jwks:         newRemoteKeySources(),
+		certificates: newRemoteKeySources(),
+	}
+	if r.ttl <= 0 {
+		r.ttl = defaultRemoteKeyTTL
+	}
+	if r.maxSize <= 0 {
+		r.maxSize = defaultRemoteMaxResponseSize
+	}
+
+	for _, allowed := range config.AllowedURLs {
+		parsed, err := url.Parse(allowed)
+		if nil != err {
+			return nil, err
+		}
+		if parsed.Scheme != "https" || parsed.Host == "" || nil != parsed.User {
+			return nil, fmt.Errorf("Allowed URL %q must be an absolute HTTPS URL", allowed)
+		}
+		r.allowed = append(r.allowed, parsed)
+	}
+
+	client := &http.Client{Timeout: defaultRemoteTimeout}
+	if nil != config.Client {
+		copied := *config.Client
+		client = &copied
+	}
+	maxFetches := config.MaxFetchesPerHost
+	if maxFetches <= 0 {
+		maxFetches = defaultRemoteFetchesPerHost
+	}
+	client.Transport = &remoteFetchLimiter{
+		transport: client.Transport,
+		limit:     maxFetches,
+		windows:   make(map[string]*remoteFetchWindow),
+	}
+	maxRedirects := config.MaxRedirects
+	if maxRedirects == 0 {
+		maxRedirects = defaultRemoteMaxRedirects
+	}
+	client.CheckRedirect = func(request *http.Request, via []*http.Request) error {
+		if len(via) > maxRedirects {
+			return fmt.Errorf("Stopped after %d redirects", len(via)-1)
+		}
+		return r.checkURL(request.URL)
+	}
+	r.client = client
+
+	return r, nil
+}
+
+// ResolveKey fetches the key for the token from its 'jku' or 'x5u' URL.
+func (r *RemoteKeyResolver) ResolveKey(header Header) (*jwk.Key, error) {
+	switch {
+	case header.JWKSetURL != "" && header.X509URL != "":
+		return nil, errors.New("Token header has both 'jku' and 'x5u' URLs")
+	case header.JWKSetURL != "":
+		fetcher, err := r.fetcher(header.JWKSetURL)
+		if nil != err {
+			return nil, err
+		}
+		return fetcher.KeyForKid(header.KeyID)
+	case header.X509URL != "":
+		key, err := r.certificateKey(header.X509URL)
+		if nil != err {
+			return nil, err
+		}
+		return &jwk.Key{KeyID: header.KeyID, Key: key}, nil
+	}
+
+	return nil, errors.New("Token header has no 'jku' or 'x5u' URL")
+}
+
+// checkURL returns ErrRemoteURLNotAllowed unless the URL is an HTTPS URL
+// on the allow-list. Queries must match the allowed URL's exactly, so
+// tokens can't name unlimited distinct URLs to fetch.
+func (r *RemoteKeyResolver) checkURL(u *url.URL) error {
+	if u.Scheme != "https" || nil != u.User || u.Fragment != "" {
+		return ErrRemoteURLNotAllowed
+	}
+
+	// Dot segments could otherwise escape an allowed path.
+	for _, segment := range strings.Split(u.Path, "/") {
+		if segment == "." || segment == ".." {
+			return ErrRemoteURLNotAllowed
+		}
+	}
+
+	for _, allowed := range r.allowed {
+		if !strings.EqualFold(u.Host, allowed.Host) {
+			continue
+		}
+		if u.RawQuery != allowed.RawQuery || (u.ForceQuery && u.RawQuery == "") {
+			continue
+		}
+		prefix := strings.TrimSuffix(allowed.Path, "/")
+		if u.Path == allowed.Path || strings.HasPrefix(u.Path, prefix+"/") {
+			return nil
+		}
+	}
+
+	return ErrRemoteURLNotAllowed
+}
+
+// parseRemoteURL parses a 'jku' or 'x5u' URL, checking the allow-list, and
+// normalizes it, so equivalent URLs share a cache entry.
+func (r *RemoteKeyResolver) parseRemoteURL(rawURL string) (string, error) {
+	parsed, err := url.Parse(rawURL)
+	if nil != err {
+		return "", ErrRemoteURLNotAllowed
+	}
+	if err := r.checkURL(parsed); nil != err {
+		return "", err
+	}
+
+	parsed.Host = strings.ToLower(parsed.Host)
+	parsed.RawPath = ""
+	return parsed.String(), nil
+}
+
+// fetcher returns the cached JWKSFetcher for a 'jku' URL.
+func (r *RemoteKeyResolver) fetcher(rawURL string) (*JWKSFetcher, error) {
+	jwksURL, err := r.parseRemoteURL(rawURL)
+	if nil != err {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if fetcher, ok := r.jwks.get(jwksURL); ok {
+		return fetcher.(*JWKSFetcher), nil
+	}
+
+	fetcher, err := NewJWKSFetcher(jwksURL, r.ttl, r.client, WithMaxJWKSSize(r.maxSize))
+	if nil != err {
+		return nil, err
+	}
+	r.jwks.add(jwksURL, fetcher)
+
+	return fetcher, nil
+}
+
+// certificateKey returns the public key of the leaf certificate at an
+// 'x5u' URL, fetching it unless cached.
+func (r *RemoteKeyResolver) certificateKey(rawURL string) (interface{}, error) {
+	certificateURL, err := r.parseRemoteURL(rawURL)
+	if nil != err {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	cached, ok := r.certificates.get(certificateURL)
+	r.mu.Unlock()
+	if ok && time.Now().Before(cached.(remoteCertificate).expiry) {
+		return cached.(remoteCertificate).key, nil
+	}
+
+	key, err := r.fetchCertificateKey(certificateURL)
+	if nil != err {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.certificates.add(certificateURL, remoteCertificate{key: key, expiry: time.Now().Add(r.ttl)})
+	r.mu.Unlock()
+
+	return key, nil
+}
+
+// fetchCertificateKey fetches a PEM certificate chain, leaf first, and
+// returns the leaf's public key.
+func (r *RemoteKeyResolver) fetchCertificateKey(certificateURL string) (interface{}, error) {
+	response, err := r.client.Get(certificateURL)
+	if nil != err {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("x5u URL returned status %d", response.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(response.Body, r.maxSize+1))
+	if nil != err {
+		return nil, err
+	}
+	if int64(len(body)) > r.maxSize {
+		return nil, fmt.Errorf("x5u document exceeds %d bytes", r.maxSize)
+	}
+
+	var chain []*x509.Certificate
+	for rest := body; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if nil == block {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if nil != err {
+			return nil, err
+		}
+		chain = append(chain, certificate)
+	}
+	if len(chain) == 0 {
+		return nil, errors.New("x5u document holds no certificates")
+	}
+
+	if nil != r.roots {
+		intermediates := x509.NewCertPool()
+		for _, certificate := range chain[1:] {
+			intermediates.AddCert(certificate)
+		}
+		_, err := chain[0].Verify(x509.VerifyOptions{
+			Roots:         r.roots,
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		})
+		if nil != err {
+			return nil, err
+		}
+	}
+
+	return parseCertificateKey(chain[0].Raw)
+}
+
+func newRemoteKeySources() *remoteKeySources {
+	return &remoteKeySources{order: list.New(), entries: make(map[string]*list.Element)}
+}
+
+// get returns the value cached for the URL, marking it recently used.
+func (s *remoteKeySources) get(url string) (interface{}, bool) {
+	element, ok := s.entries[url]
+	if !ok {
+		return nil, false
+	}
+
+	s.order.MoveToFront(element)
+	return element.Value.(*remoteKeySource).value, true
+}
+
+// add caches the value for the URL, evicting the least recently used
+// value if the cache is full.
+func (s *remoteKeySources) add(url string, value interface{}) {
+	if element, ok := s.entries[url]; ok {
+		element.Value.(*remoteKeySource).value = value
+		s.order.MoveToFront(element)
+		return
+	}
+
+	if s.order.Len() >= maxRemoteKeySources {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*remoteKeySource).url)
+	}
+	s.entries[url] = s.order.PushFront(&remoteKeySource{url: url, value: value})
+}
+
+// remoteFetchLimiter is an http.RoundTripper allowing at most limit
+// requests per host each minute, including redirects. Hosts are those of
+// the allow-list, so the windows are bounded.
+type remoteFetchLimiter struct {
+	transport http.RoundTripper
+	limit     int
+
+	mu      sync.Mutex
+	windows map[string]*remoteFetchWindow
+}
+
+// remoteFetchWindow counts a host's requests since start.
+type remoteFetchWindow struct {
+	start    time.Time
+	requests int
+}
+
+func (l *remoteFetchLimiter) RoundTrip(request *http.Request) (*http.Response, error) {
+	if err := l.allow(strings.ToLower(request.URL.Host)); nil != err {
+		return nil, err
+	}
+
+	transport := l.transport
+	if nil == transport {
+		transport = http.DefaultTransport
+	}
+	return transport.RoundTrip(request)
+}
+
+// allow returns ErrRemoteFetchLimited if the host has been requested limit
+// times in the current minute, and otherwise counts the request.
+func (l *remoteFetchLimiter) allow(host string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	window, ok := l.windows[host]
+	if !ok || now.Sub(window.start) >= time.Minute {
+		window = &remoteFetchWindow{start: now}
+		l.windows[host] = window
+	}
+	if window.requests >= l.limit {
+		return ErrRemoteFetchLimited
+	}
+
+	window.requests++
+	return nil
+}
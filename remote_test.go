@@ -0,0 +1,176 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/georgejenkins/jwt/jwk"
+)
+
+func TestRemoteKeyResolver(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	keys, _ := json.Marshal(&jwk.Set{Keys: []*jwk.Key{{KeyID: "ec-1", Key: &key.PublicKey}}})
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: selfSignedCertificate(t, key)})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/keys/jwks.json", func(w http.ResponseWriter, r *http.Request) { w.Write(keys) })
+	mux.HandleFunc("/keys/cert.pem", func(w http.ResponseWriter, r *http.Request) { w.Write(certificate) })
+	mux.HandleFunc("/keys/large.json", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(strings.Repeat(" ", 1<<17))) })
+	mux.Handle("/keys/moved.json", http.RedirectHandler("/keys/jwks.json", http.StatusFound))
+	mux.Handle("/keys/escape.json", http.RedirectHandler("/internal/jwks.json", http.StatusFound))
+	mux.HandleFunc("/internal/jwks.json", func(w http.ResponseWriter, r *http.Request) { w.Write(keys) })
+	server := httptest.NewTLSServer(mux)
+	defer server.Close()
+
+	resolver, err := NewRemoteKeyResolver(RemoteKeyConfig{
+		AllowedURLs: []string{server.URL + "/keys/"},
+		Client:      server.Client(),
+	})
+	if nil != err {
+		t.Fatalf("NewRemoteKeyResolver() error = %v", err)
+	}
+	verifier, _ := NewKeyResolvingVerifier(resolver)
+	signer, _ := NewJOSESignerVerifier(ES256, key)
+
+	tests := []struct {
+		name      string
+		header    Header
+		wantValid bool
+	}{
+		{"Must verify with a key from an allowed jku", Header{JWKSetURL: server.URL + "/keys/jwks.json", KeyID: "ec-1"}, true},
+		{"Must verify with a key from an allowed x5u", Header{X509URL: server.URL + "/keys/cert.pem"}, true},
+		{"Must follow redirects within the allow-list", Header{JWKSetURL: server.URL + "/keys/moved.json", KeyID: "ec-1"}, true},
+		{"Must not follow redirects outside the allow-list", Header{JWKSetURL: server.URL + "/keys/escape.json", KeyID: "ec-1"}, false},
+		{"Must not fetch a jku outside the allow-list", Header{JWKSetURL: server.URL + "/internal/jwks.json", KeyID: "ec-1"}, false},
+		{"Must not fetch a jku escaping the allow-list with dot segments", Header{JWKSetURL: server.URL + "/keys/../internal/jwks.json", KeyID: "ec-1"}, false},
+		{"Must not fetch a jku on another host", Header{JWKSetURL: "https://attacker.example.com/keys/jwks.json", KeyID: "ec-1"}, false},
+		{"Must not fetch a plain HTTP jku", Header{JWKSetURL: strings.Replace(server.URL, "https", "http", 1) + "/keys/jwks.json", KeyID: "ec-1"}, false},
+		{"Must not fetch a document over the size cap", Header{JWKSetURL: server.URL + "/keys/large.json", KeyID: "ec-1"}, false},
+		{"Must not verify without a remote URL", Header{KeyID: "ec-1"}, false},
+		{"Must not verify with both jku and x5u", Header{JWKSetURL: server.URL + "/keys/jwks.json", X509URL: server.URL + "/keys/cert.pem", KeyID: "ec-1"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.header.Algorithm = string(ES256)
+			token, err := signer.GenerateToken(tt.header, Claims{Subject: "alice"})
+			if nil != err {
+				t.Fatalf("GenerateToken() error = %v", err)
+			}
+
+			_, valid, err := verifier.VerifySignature(token)
+			if valid != tt.wantValid || (nil == err) != tt.wantValid {
+				t.Errorf("VerifySignature() = %v, %v, want %v", valid, err, tt.wantValid)
+			}
+		})
+	}
+
+	other, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	untrusted := x509.NewCertPool()
+	otherCertificate, _ := x509.ParseCertificate(selfSignedCertificate(t, other))
+	untrusted.AddCert(otherCertificate)
+	rooted, _ := NewRemoteKeyResolver(RemoteKeyConfig{
+		AllowedURLs: []string{server.URL + "/keys/"},
+		Client:      server.Client(),
+		Roots:       untrusted,
+	})
+	if _, err := rooted.ResolveKey(Header{X509URL: server.URL + "/keys/cert.pem"}); nil == err {
+		t.Errorf("RemoteKeyResolver.ResolveKey() expected error for an x5u chain not issued by the roots")
+	}
+
+	if _, err := NewRemoteKeyResolver(RemoteKeyConfig{}); nil == err {
+		t.Errorf("NewRemoteKeyResolver() expected error without an allow-list")
+	}
+	if _, err := NewRemoteKeyResolver(RemoteKeyConfig{AllowedURLs: []string{"http://issuer.example.com/"}}); nil == err {
+		t.Errorf("NewRemoteKeyResolver() expected error for a non-HTTPS allowed URL")
+	}
+}
+
+func TestRemoteKeyResolver_Limits(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	keys, _ := json.Marshal(&jwk.Set{Keys: []*jwk.Key{{KeyID: "ec-1", Key: &key.PublicKey}}})
+
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.URL.RequestURI()]++
+		mu.Unlock()
+		w.Write(keys)
+	}))
+	defer server.Close()
+	fetched := func(uri string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return requests[uri]
+	}
+
+	resolver, _ := NewRemoteKeyResolver(RemoteKeyConfig{
+		AllowedURLs:       []string{server.URL + "/keys/", server.URL + "/tenant/jwks.json?tenant=a"},
+		Client:            server.Client(),
+		MaxFetchesPerHost: 1000,
+	})
+
+	tests := []struct {
+		name    string
+		jku     string
+		wantErr error
+	}{
+		{"Must fetch an allowed jku", server.URL + "/keys/jwks.json", nil},
+		{"Must not fetch a jku with a query", server.URL + "/keys/jwks.json?nonce=1", ErrRemoteURLNotAllowed},
+		{"Must not fetch a jku with an empty query", server.URL + "/keys/jwks.json?", ErrRemoteURLNotAllowed},
+		{"Must fetch a jku with the allowed query", server.URL + "/tenant/jwks.json?tenant=a", nil},
+		{"Must not fetch a jku with another query", server.URL + "/tenant/jwks.json?tenant=b", ErrRemoteURLNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := resolver.ResolveKey(Header{JWKSetURL: tt.jku, KeyID: "ec-1"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("RemoteKeyResolver.ResolveKey() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// The least recently used URL is evicted, rather than every URL.
+	resolve := func(path string) {
+		if _, err := resolver.ResolveKey(Header{JWKSetURL: server.URL + path, KeyID: "ec-1"}); nil != err {
+			t.Fatalf("RemoteKeyResolver.ResolveKey() error = %v", err)
+		}
+	}
+	for i := 0; i < maxRemoteKeySources; i++ {
+		resolve(fmt.Sprintf("/keys/%d.json", i))
+		resolve("/keys/jwks.json")
+	}
+	resolve("/keys/0.json")
+	if got := fetched("/keys/jwks.json"); got != 1 {
+		t.Errorf("RemoteKeyResolver fetched a recently used jku %d times, want 1", got)
+	}
+	if got := fetched("/keys/0.json"); got != 2 {
+		t.Errorf("RemoteKeyResolver fetched the least recently used jku %d times, want 2", got)
+	}
+
+	limited, _ := NewRemoteKeyResolver(RemoteKeyConfig{
+		AllowedURLs:       []string{server.URL + "/limited/"},
+		Client:            server.Client(),
+		MaxFetchesPerHost: 2,
+	})
+	for i, wantErr := range []error{nil, nil, ErrRemoteFetchLimited} {
+		_, err := limited.ResolveKey(Header{JWKSetURL: fmt.Sprintf("%s/limited/%d.json", server.URL, i), KeyID: "ec-1"})
+		if !errors.Is(err, wantErr) {
+			t.Errorf("RemoteKeyResolver.ResolveKey() error = %v, want %v", err, wantErr)
+		}
+	}
+	if got := fetched("/limited/2.json"); got != 0 {
+		t.Errorf("RemoteKeyResolver fetched over the rate limit %d times", got)
+	}
+}
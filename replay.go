@@ -0,0 +1,146 @@
+package jwt
+
+import (
+	"errors"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+)
+
+// defaultReplayResolution is the default width of the expiry buckets of a
+// MemoryReplayStore.
+const defaultReplayResolution = time.Second
+
+// MemoryReplayStore is an in-memory UniquenessChecker built for high
+// volumes of nonces and JWT IDs. Values are spread over shards, each with
+// its own lock, so concurrent checks rarely contend. Within a shard values
+// are also grouped into buckets by expiry, so expired values are swept a
+// bucket at a time as new values are stored, without scanning the store.
+type MemoryReplayStore struct {
+	shards     []replayShard
+	mask       uint32
+	resolution int64
+}
+
+// replayShard holds the values hashing to one shard of the store.
+type replayShard struct {
+	mu      sync.Mutex
+	entries map[string]time.Time
+	// buckets holds the values expiring within each bucket, and order the
+	// bucket numbers in ascending order.
+	buckets map[int64][]string
+	order   []int64
+}
+
+// NewMemoryReplayStore initializes an empty MemoryReplayStore with a
+// shard per four CPUs and one second expiry buckets.
+func NewMemoryReplayStore() *MemoryReplayStore {
+	store, _ := NewShardedReplayStore(4*runtime.NumCPU(), defaultReplayResolution)
+	return store
+}
+
+// NewShardedReplayStore initializes an empty MemoryReplayStore with the
+// given number of shards, rounded up to a power of two, and expiry
+// buckets of the given resolution. Values may be held for up to the
+// resolution past their expiry.
+func NewShardedReplayStore(shards int, resolution time.Duration) (*MemoryReplayStore, error) {
+	if shards < 1 {
+		return nil, errors.New("A replay store requires at least one shard")
+	}
+	if resolution <= 0 {
+		return nil, errors.New("Replay store resolution must be positive")
+	}
+
+	count := 1
+	for count < shards {
+		count <<= 1
+	}
+
+	s := &MemoryReplayStore{
+		shards:     make([]replayShard, count),
+		mask:       uint32(count - 1),
+		resolution: int64(resolution),
+	}
+	for i := range s.shards {
+		s.shards[i].entries = make(map[string]time.Time)
+		s.shards[i].buckets = make(map[int64][]string)
+	}
+
+	return s, nil
+}
+
+// CheckAndStore records the value until expiry, returning false if the
+// value is already recorded and has not expired.
+func (s *MemoryReplayStore) CheckAndStore(value string, expiry time.Time) (bool, error) {
+	shard := &s.shards[shardIndex(value)&s.mask]
+	now := time.Now()
+
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	shard.sweep(now.UnixNano(), s.resolution)
+
+	if e, seen := shard.entries[value]; seen && e.After(now) {
+		return false, nil
+	}
+
+	shard.entries[value] = expiry
+	shard.add(value, expiry.UnixNano()/s.resolution)
+	return true, nil
+}
+
+// Len returns the number of values recorded, including any expired values
+// not yet swept.
+func (s *MemoryReplayStore) Len() int {
+	n := 0
+	for i := range s.shards {
+		shard := &s.shards[i]
+		shard.mu.Lock()
+		n += len(shard.entries)
+		shard.mu.Unlock()
+	}
+
+	return n
+}
+
+// add records the value in its expiry bucket. shard.mu must be held.
+func (shard *replayShard) add(value string, bucket int64) {
+	if _, ok := shard.buckets[bucket]; !ok {
+		// Expiries mostly increase, so new buckets are usually appended.
+		i := sort.Search(len(shard.order), func(i int) bool { return shard.order[i] >= bucket })
+		shard.order = append(shard.order, 0)
+		copy(shard.order[i+1:], shard.order[i:])
+		shard.order[i] = bucket
+	}
+
+	shard.buckets[bucket] = append(shard.buckets[bucket], value)
+}
+
+// sweep removes the buckets that have wholly expired. shard.mu must be
+// held.
+func (shard *replayShard) sweep(now int64, resolution int64) {
+	for len(shard.order) > 0 && (shard.order[0]+1)*resolution <= now {
+		bucket := shard.order[0]
+		for _, value := range shard.buckets[bucket] {
+			// The value may have been stored again with a later expiry.
+			if e, ok := shard.entries[value]; ok && e.UnixNano()/resolution <= bucket {
+				delete(shard.entries, value)
+			}
+		}
+
+		delete(shard.buckets, bucket)
+		shard.order = shard.order[1:]
+	}
+}
+
+// shardIndex hashes the value with FNV-1a.
+func shardIndex(value string) uint32 {
+	hash := uint32(2166136261)
+	for i := 0; i < len(value); i++ {
+		hash ^= uint32(value[i])
+		hash *= 16777619
+	}
+
+	return hash
+}
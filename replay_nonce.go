@@ -0,0 +1,222 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ReplayNonceHeader is the HTTP response header servers of ACME-style
+// protocols deliver fresh nonces in (RFC 8555, section 6.5.1).
+const ReplayNonceHeader = "Replay-Nonce"
+
+// ErrBadNonce is returned for tokens whose 'nonce' header is missing, was
+// not issued, has expired, or was already used. ACME servers report it as
+// the "badNonce" error, after which clients retry with a fresh nonce.
+var ErrBadNonce = errors.New("Nonce is missing, invalid or already used")
+
+// NonceSource provides server-issued nonces for signing requests.
+type NonceSource interface {
+	Nonce() (string, error)
+}
+
+// NonceSourceFunc adapts a function to a NonceSource.
+type NonceSourceFunc func() (string, error)
+
+// Nonce returns the function's nonce.
+func (f NonceSourceFunc) Nonce() (string, error) {
+	return f()
+}
+
+// NonceValidator validates the 'nonce' header of received tokens, see
+// WithNonceValidator.
+type NonceValidator interface {
+	// ValidateNonce returns ErrBadNonce if the nonce was not issued, has
+	// expired or was already used, and otherwise consumes it.
+	ValidateNonce(nonce string) error
+}
+
+// WithNonceValidator requires tokens to carry a 'nonce' header accepted by
+// the validator, as servers of ACME-style protocols do to prevent replay.
+// The nonce is validated, and so consumed, only once the signature is.
+func WithNonceValidator(validator NonceValidator) Option {
+	return func(sv *JOSESignerVerifier) error {
+		if nil == validator {
+			return errors.New("Nonce validator cannot be nil")
+		}
+
+		sv.nonceValidator = validator
+		return nil
+	}
+}
+
+// GenerateTokenWithNonce generates a token as GenerateToken does, with the
+// 'nonce' header set to a nonce from source.
+func (sv *JOSESignerVerifier) GenerateTokenWithNonce(header Header, body interface{}, source NonceSource) ([]byte, error) {
+	nonce, err := source.Nonce()
+	if nil != err {
+		return nil, err
+	}
+	if !validNonceSyntax(nonce) {
+		return nil, fmt.Errorf("Invalid nonce %q", nonce)
+	}
+
+	header.Nonce = nonce
+	return sv.GenerateToken(header, body)
+}
+
+// NoncePool is a client-side NonceSource for ACME-style protocols. Servers
+// return a fresh nonce with every response, which Observe saves for the
+// next request; when none is saved, one is fetched from the server's
+// newNonce URL.
+type NoncePool struct {
+	newNonceURL string
+	client      *http.Client
+
+	mu     sync.Mutex
+	nonces []string
+}
+
+// NewNoncePool creates a NoncePool fetching nonces from newNonceURL with
+// client, or http.DefaultClient if nil.
+func NewNoncePool(newNonceURL string, client *http.Client) *NoncePool {
+	if nil == client {
+		client = http.DefaultClient
+	}
+
+	return &NoncePool{newNonceURL: newNonceURL, client: client}
+}
+
+// Observe saves the nonce of a server response, if it has one.
+func (p *NoncePool) Observe(response *http.Response) {
+	nonce := response.Header.Get(ReplayNonceHeader)
+	if !validNonceSyntax(nonce) {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.nonces = append(p.nonces, nonce)
+}
+
+// Nonce returns the most recently saved nonce, or fetches a new one. Each
+// nonce is returned once.
+func (p *NoncePool) Nonce() (string, error) {
+	p.mu.Lock()
+	if n := len(p.nonces); n > 0 {
+		nonce := p.nonces[n-1]
+		p.nonces = p.nonces[:n-1]
+		p.mu.Unlock()
+		return nonce, nil
+	}
+	p.mu.Unlock()
+
+	response, err := p.client.Head(p.newNonceURL)
+	if nil != err {
+		return "", err
+	}
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusNoContent {
+		return "", fmt.Errorf("Fetching a nonce failed with status %d", response.StatusCode)
+	}
+
+	nonce := response.Header.Get(ReplayNonceHeader)
+	if !validNonceSyntax(nonce) {
+		return "", fmt.Errorf("Server returned an invalid nonce %q", nonce)
+	}
+
+	return nonce, nil
+}
+
+// NonceRegistry is a server-side NonceValidator, issuing nonces and
+// accepting each once before it expires.
+type NonceRegistry struct {
+	generator *NonceGenerator
+	ttl       time.Duration
+
+	mu        sync.Mutex
+	issued    map[string]time.Time
+	lastSweep time.Time
+}
+
+// NewNonceRegistry creates a NonceRegistry whose nonces expire after ttl.
+func NewNonceRegistry(ttl time.Duration) (*NonceRegistry, error) {
+	if ttl <= 0 {
+		return nil, errors.New("Nonce TTL must be positive")
+	}
+
+	generator, err := NewNonceGenerator(MinimumNonceLength, NonceBase64URL, nil, 0)
+	if nil != err {
+		return nil, err
+	}
+
+	return &NonceRegistry{
+		generator: generator,
+		ttl:       ttl,
+		issued:    make(map[string]time.Time),
+		lastSweep: time.Now(),
+	}, nil
+}
+
+// Issue returns a new nonce, to be sent in the Replay-Nonce header.
+func (r *NonceRegistry) Issue() (string, error) {
+	nonce, err := r.generator.Generate()
+	if nil != err {
+		return "", err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(r.lastSweep) >= r.ttl {
+		for issued, expiry := range r.issued {
+			if now.After(expiry) {
+				delete(r.issued, issued)
+			}
+		}
+		r.lastSweep = now
+	}
+	r.issued[nonce] = now.Add(r.ttl)
+
+	return nonce, nil
+}
+
+// ValidateNonce consumes the nonce, returning ErrBadNonce if it was not
+// issued, has expired or was already used.
+func (r *NonceRegistry) ValidateNonce(nonce string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	expiry, ok := r.issued[nonce]
+	if !ok {
+		return ErrBadNonce
+	}
+	delete(r.issued, nonce)
+
+	if time.Now().After(expiry) {
+		return ErrBadNonce
+	}
+
+	return nil
+}
+
+// validNonceSyntax reports whether the nonce is a non-empty base64url
+// string without padding, as RFC 8555 requires.
+func validNonceSyntax(nonce string) bool {
+	if nonce == "" {
+		return false
+	}
+
+	for _, c := range nonce {
+		if !('A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_') {
+			return false
+		}
+	}
+
+	return true
+}
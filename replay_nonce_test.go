@@ -0,0 +1,105 @@
+package jwt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestNonceRegistry(t *testing.T) {
+	if _, err := NewNonceRegistry(0); nil == err {
+		t.Errorf("NewNonceRegistry() expected error without a TTL")
+	}
+
+	r, _ := NewNonceRegistry(time.Minute)
+	issued, _ := r.Issue()
+	expired, _ := r.Issue()
+	r.issued[expired] = time.Now().Add(-time.Second)
+
+	tests := []struct {
+		name    string
+		nonce   string
+		wantErr error
+	}{
+		{"Must accept an issued nonce", issued, nil},
+		{"Must not accept a nonce twice", issued, ErrBadNonce},
+		{"Must not accept an expired nonce", expired, ErrBadNonce},
+		{"Must not accept a nonce that was not issued", "bm90LWlzc3VlZA", ErrBadNonce},
+		{"Must not accept an empty nonce", "", ErrBadNonce},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := r.ValidateNonce(tt.nonce); err != tt.wantErr {
+				t.Errorf("NonceRegistry.ValidateNonce() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWithNonceValidator(t *testing.T) {
+	if _, err := NewJOSESignerVerifier(HS256, exampleKey, WithNonceValidator(nil)); nil == err {
+		t.Errorf("NewJOSESignerVerifier() expected error with a nil nonce validator")
+	}
+
+	registry, _ := NewNonceRegistry(time.Minute)
+	server, _ := NewJOSESignerVerifier(HS256, exampleKey, WithNonceValidator(registry))
+	client, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	forger, _ := NewJOSESignerVerifier(HS256, []byte("not the key shared with the server"))
+
+	header := Header{Algorithm: string(HS256)}
+	token, err := client.GenerateTokenWithNonce(header, Claims{Subject: "alice"}, NonceSourceFunc(registry.Issue))
+	if nil != err {
+		t.Fatalf("GenerateTokenWithNonce() error = %v", err)
+	}
+	withoutNonce, _ := client.GenerateToken(header, Claims{Subject: "alice"})
+
+	nonce, _ := registry.Issue()
+	forged, _ := forger.GenerateTokenWithNonce(header, Claims{Subject: "alice"}, NonceSourceFunc(func() (string, error) { return nonce, nil }))
+	if _, valid, _ := server.VerifyToken(forged, nil); valid {
+		t.Errorf("VerifyToken() = true for a forged token")
+	}
+	if err := registry.ValidateNonce(nonce); nil != err {
+		t.Errorf("NonceRegistry.ValidateNonce() error = %v, the forged token consumed the nonce", err)
+	}
+
+	if _, valid, err := server.VerifyToken(token, nil); !valid || nil != err {
+		t.Errorf("VerifyToken() = %v, %v with an issued nonce", valid, err)
+	}
+	if _, valid, err := server.VerifyToken(token, nil); valid || err != ErrBadNonce {
+		t.Errorf("VerifyToken() = %v, %v replaying a nonce, want %v", valid, err, ErrBadNonce)
+	}
+	if _, valid, err := server.VerifyToken(withoutNonce, nil); valid || err != ErrBadNonce {
+		t.Errorf("VerifyToken() = %v, %v without a nonce, want %v", valid, err, ErrBadNonce)
+	}
+
+	invalid := NonceSourceFunc(func() (string, error) { return "not base64url!", nil })
+	if _, err := client.GenerateTokenWithNonce(header, Claims{}, invalid); nil == err {
+		t.Errorf("GenerateTokenWithNonce() expected error with an invalid nonce")
+	}
+}
+
+func TestNoncePool(t *testing.T) {
+	fetched := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetched++
+		w.Header().Set(ReplayNonceHeader, "fetched-"+strconv.Itoa(fetched))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	pool := NewNoncePool(server.URL, server.Client())
+
+	response := &http.Response{Header: http.Header{}}
+	response.Header.Set(ReplayNonceHeader, "observed")
+	pool.Observe(response)
+	response.Header.Set(ReplayNonceHeader, "not base64url!")
+	pool.Observe(response)
+
+	for _, want := range []string{"observed", "fetched-1", "fetched-2"} {
+		if nonce, err := pool.Nonce(); nonce != want || nil != err {
+			t.Errorf("NoncePool.Nonce() = %q, %v, want %q", nonce, err, want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package jwt
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewShardedReplayStore(t *testing.T) {
+	type args struct {
+		shards     int
+		resolution time.Duration
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantShards int
+		wantErr    bool
+	}{
+		{"Must create a single shard", args{1, time.Second}, 1, false},
+		{"Must round shards up to a power of two", args{5, time.Second}, 8, false},
+		{"Must fail without shards", args{0, time.Second}, 0, true},
+		{"Must fail without a resolution", args{4, 0}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewShardedReplayStore(tt.args.shards, tt.args.resolution)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewShardedReplayStore() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if nil != got && len(got.shards) != tt.wantShards {
+				t.Errorf("NewShardedReplayStore() shards = %d, want %d", len(got.shards), tt.wantShards)
+			}
+		})
+	}
+}
+
+func TestMemoryReplayStore_Sweep(t *testing.T) {
+	s, _ := NewShardedReplayStore(1, time.Millisecond)
+
+	now := time.Now()
+	for i := 0; i < 100; i++ {
+		s.CheckAndStore("expired-"+strconv.Itoa(i), now.Add(-time.Second))
+	}
+	s.CheckAndStore("renewed", now.Add(-time.Second))
+	s.CheckAndStore("renewed", now.Add(time.Minute))
+	s.CheckAndStore("live", now.Add(time.Minute))
+
+	if got := s.Len(); got != 2 {
+		t.Errorf("MemoryReplayStore.Len() = %d after sweeping, want 2", got)
+	}
+	if unique, _ := s.CheckAndStore("renewed", now.Add(time.Minute)); unique {
+		t.Errorf("MemoryReplayStore.CheckAndStore() = true for a value renewed before its old bucket was swept")
+	}
+}
+
+func TestMemoryReplayStore_Concurrent(t *testing.T) {
+	s := NewMemoryReplayStore()
+	expiry := time.Now().Add(time.Minute)
+
+	var unique int64
+	var wg sync.WaitGroup
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				if ok, _ := s.CheckAndStore("jti-"+strconv.Itoa(i), expiry); ok {
+					atomic.AddInt64(&unique, 1)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if unique != 1000 {
+		t.Errorf("MemoryReplayStore accepted %d unique values from concurrent writers, want 1000", unique)
+	}
+}
+
+func BenchmarkMemoryReplayStore_CheckAndStore(b *testing.B) {
+	s := NewMemoryReplayStore()
+	jtis := make([]string, b.N)
+	for i := range jtis {
+		jtis[i] = "jti-" + strconv.Itoa(i)
+	}
+
+	var next int64 = -1
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			i := atomic.AddInt64(&next, 1)
+			s.CheckAndStore(jtis[i], time.Now().Add(time.Minute))
+		}
+	})
+}
+
+// BenchmarkMemoryReplayStore_Millions stores a million JWT IDs with
+// short expiries, so most stores also sweep expired buckets.
+func BenchmarkMemoryReplayStore_Millions(b *testing.B) {
+	jtis := make([]string, 1000000)
+	for i := range jtis {
+		jtis[i] = "jti-" + strconv.Itoa(i)
+	}
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		s, _ := NewShardedReplayStore(64, time.Millisecond)
+		for _, jti := range jtis {
+			s.CheckAndStore(jti, time.Now().Add(10*time.Millisecond))
+		}
+	}
+}
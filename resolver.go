@@ -0,0 +1,92 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/georgejenkins/jwt/jwk"
+	"github.com/georgejenkins/jwt/jws"
+)
+
+// KeyResolver selects the key to verify a token with from its JOSE header,
+// typically by its 'kid'. JWKSFetcher is a KeyResolver, and KeySetResolver
+// adapts a JWK Set.
+type KeyResolver interface {
+	ResolveKey(header Header) (*jwk.Key, error)
+}
+
+// KeyResolverFunc adapts a function to a KeyResolver.
+type KeyResolverFunc func(header Header) (*jwk.Key, error)
+
+// ResolveKey calls f(header).
+func (f KeyResolverFunc) ResolveKey(header Header) (*jwk.Key, error) {
+	return f(header)
+}
+
+// KeySetResolver returns a KeyResolver selecting signature keys from a JWK
+// Set by key ID.
+func KeySetResolver(set *jwk.Set) KeyResolver {
+	return KeyResolverFunc(func(header Header) (*jwk.Key, error) {
+		if header.KeyID == "" {
+			return nil, ErrUnknownKeyID
+		}
+
+		keys := set.Find(header.KeyID, Algorithm(header.Algorithm), jwk.UseSignature)
+		if len(keys) == 0 {
+			return nil, ErrUnknownKeyID
+		}
+
+		return keys[0], nil
+	})
+}
+
+// ResolveKey returns the key with the header's key ID.
+func (f *JWKSFetcher) ResolveKey(header Header) (*jwk.Key, error) {
+	return f.KeyForKid(header.KeyID)
+}
+
+// NewKeyResolvingVerifier creates a JOSESignerVerifier that verifies each
+// token with the key resolver selects for it, rather than a single key.
+// The token's algorithm must be allowed for the resolved key: it must
+// match the key's 'alg' when it has one, and suit the key's type. It can't
+// generate tokens.
+func NewKeyResolvingVerifier(resolver KeyResolver, opts ...Option) (*JOSESignerVerifier, error) {
+	if nil == resolver {
+		return nil, errors.New("Key resolver cannot be nil")
+	}
+
+	sv := &JOSESignerVerifier{
+		keyResolver: resolver,
+	}
+
+	return sv.applyOptions(opts)
+}
+
+// resolveVerifier returns the verifier for the key the resolver selects
+// for the header, and the key's provenance.
+func (sv *JOSESignerVerifier) resolveVerifier(header Header) (jws.TokenVerifier, *Provenance, error) {
+	alg := Algorithm(header.Algorithm)
+	if alg == None || alg == "" {
+		return nil, nil, fmt.Errorf("Cannot resolve a key for algorithm %q", alg)
+	}
+
+	if err := chaosKeyFetch(); nil != err {
+		return nil, nil, err
+	}
+
+	key, err := sv.keyResolver.ResolveKey(header)
+	if nil != err {
+		return nil, nil, err
+	}
+
+	if err := sv.checkPinnedKey(key.Key); nil != err {
+		return nil, nil, err
+	}
+
+	verifier, err := key.Verifier(alg)
+	if nil != err {
+		return nil, nil, err
+	}
+
+	return verifier, resolvedProvenance(sv.keyResolver, key), nil
+}
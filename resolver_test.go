@@ -0,0 +1,65 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"testing"
+
+	"github.com/georgejenkins/jwt/jwk"
+)
+
+func TestNewKeyResolvingVerifier(t *testing.T) {
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	edPublic := edKey.Public().(ed25519.PublicKey)
+
+	set := &jwk.Set{Keys: []*jwk.Key{
+		{KeyID: "ec", Algorithm: ES256, Use: jwk.UseSignature, Key: &ecKey.PublicKey},
+		{KeyID: "ed", Key: &edPublic},
+	}}
+	verifier, err := NewKeyResolvingVerifier(KeySetResolver(set))
+	if nil != err {
+		t.Fatalf("NewKeyResolvingVerifier() error = %v", err)
+	}
+
+	ecSigner, _ := NewJOSESignerVerifier(ES256, ecKey)
+	edSigner, _ := NewJOSESignerVerifier(EdDSA, &edKey)
+	hmacSigner, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	claims := Claims{Subject: "alice"}
+
+	sign := func(sv *JOSESignerVerifier, alg Algorithm, kid string) []byte {
+		token, err := sv.GenerateToken(Header{Algorithm: string(alg), KeyID: kid}, claims)
+		if nil != err {
+			t.Fatalf("GenerateToken() error = %v", err)
+		}
+		return token
+	}
+
+	tests := []struct {
+		name      string
+		token     []byte
+		wantValid bool
+		wantErr   bool
+	}{
+		{"Must verify with the EC key selected by kid", sign(ecSigner, ES256, "ec"), true, false},
+		{"Must verify with the Ed25519 key selected by kid", sign(edSigner, EdDSA, "ed"), true, false},
+		{"Must not verify with the key of another kid", sign(edSigner, EdDSA, "ec"), false, true},
+		{"Must not verify an unknown kid", sign(ecSigner, ES256, "missing"), false, true},
+		{"Must not verify without a kid", sign(ecSigner, ES256, ""), false, true},
+		{"Must not verify an algorithm unsuited to the key", sign(hmacSigner, HS256, "ed"), false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, valid, err := verifier.VerifySignature(tt.token)
+			if (err != nil) != tt.wantErr || valid != tt.wantValid {
+				t.Errorf("VerifySignature() = %v, %v, want %v, wantErr %v", valid, err, tt.wantValid, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := verifier.GenerateToken(Header{Algorithm: string(ES256)}, claims); nil == err {
+		t.Errorf("GenerateToken() expected error from a key resolving verifier")
+	}
+}
@@ -0,0 +1,124 @@
+package jwt
+
+import "fmt"
+
+// RoleExtractor extracts role names from a decoded claim set. Extractors
+// return nil when the claims they understand are absent.
+type RoleExtractor func(claims map[string]interface{}) []string
+
+// IssuerRoleExtractors maps an issuer ('iss') to the extractors used to
+// normalize the roles carried in tokens from that issuer, since every
+// identity provider lays out roles differently.
+type IssuerRoleExtractors map[string][]RoleExtractor
+
+// ClaimPathRoles extracts roles from a string or string array claim at
+// a dot separated path, e.g. "groups" or "realm_access.roles".
+func ClaimPathRoles(path string) RoleExtractor {
+	return func(claims map[string]interface{}) []string {
+		claim, ok := lookupClaimPath(claims, path)
+		if !ok {
+			return nil
+		}
+		return stringsFromClaim(claim)
+	}
+}
+
+// KeycloakRealmRoles extracts Keycloak realm roles ('realm_access.roles').
+func KeycloakRealmRoles() RoleExtractor {
+	return ClaimPathRoles("realm_access.roles")
+}
+
+// KeycloakClientRoles extracts Keycloak client roles for a single client
+// ('resource_access[clientID].roles'). Client IDs frequently contain dots,
+// so the client is looked up directly rather than as a claim path.
+func KeycloakClientRoles(clientID string) RoleExtractor {
+	return func(claims map[string]interface{}) []string {
+		resourceAccess, ok := claims["resource_access"].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		client, ok := resourceAccess[clientID].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		return stringsFromClaim(client["roles"])
+	}
+}
+
+// AzureRoles extracts Azure AD application roles ('roles') and directory
+// role template IDs ('wids').
+func AzureRoles() RoleExtractor {
+	roles := ClaimPathRoles("roles")
+	wids := ClaimPathRoles("wids")
+
+	return func(claims map[string]interface{}) []string {
+		return append(roles(claims), wids(claims)...)
+	}
+}
+
+// ExtractRoles runs each extractor over the token's claim set and returns
+// the combined roles, with duplicates removed and first-seen order kept.
+func ExtractRoles(token *Token, extractors ...RoleExtractor) ([]string, error) {
+	claims, err := decodeClaimsMap(token.DecodedBody)
+	if nil != err {
+		return nil, err
+	}
+
+	return extractRoles(claims, extractors), nil
+}
+
+// Extract returns the roles carried by the token, using the extractors
+// configured for the token's issuer.
+func (e IssuerRoleExtractors) Extract(token *Token) ([]string, error) {
+	claims, err := decodeClaimsMap(token.DecodedBody)
+	if nil != err {
+		return nil, err
+	}
+
+	issuer, _ := claims["iss"].(string)
+	extractors, ok := e[issuer]
+	if !ok {
+		return nil, fmt.Errorf("No role extractors configured for issuer %q", issuer)
+	}
+
+	return extractRoles(claims, extractors), nil
+}
+
+func extractRoles(claims map[string]interface{}, extractors []RoleExtractor) []string {
+	seen := make(map[string]bool)
+	roles := []string{}
+
+	for _, extractor := range extractors {
+		for _, role := range extractor(claims) {
+			if seen[role] {
+				continue
+			}
+			seen[role] = true
+			roles = append(roles, role)
+		}
+	}
+
+	return roles
+}
+
+// stringsFromClaim returns the string values of a claim which may be
+// either a single string or an array of strings. Non-string array
+// members are ignored.
+func stringsFromClaim(claim interface{}) []string {
+	switch value := claim.(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		values := make([]string, 0, len(value))
+		for _, member := range value {
+			if s, ok := member.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+
+	return nil
+}
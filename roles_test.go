@@ -0,0 +1,85 @@
+package jwt
+
+import (
+	"reflect"
+	"testing"
+)
+
+var exampleRoleClaims = []byte(`{
+	"iss": "https://keycloak.example.com/realms/kaermorhen",
+	"realm_access": {"roles": ["witcher", "offline_access"]},
+	"resource_access": {
+		"bestiary.api": {"roles": ["reader", "witcher"]},
+		"armoury": {"roles": ["smith"]}
+	},
+	"roles": "Contracts.Read",
+	"wids": ["62e90394-69f5-4237-9190-012177145e10"]
+}`)
+
+func TestExtractRoles(t *testing.T) {
+	type args struct {
+		extractors []RoleExtractor
+	}
+	tests := []struct {
+		name string
+		args args
+		want []string
+	}{
+		{
+			"Must extract Keycloak realm roles",
+			args{[]RoleExtractor{KeycloakRealmRoles()}},
+			[]string{"witcher", "offline_access"},
+		},
+		{
+			"Must extract Keycloak client roles for a dotted client ID",
+			args{[]RoleExtractor{KeycloakClientRoles("bestiary.api")}},
+			[]string{"reader", "witcher"},
+		},
+		{
+			"Must extract Azure roles and wids",
+			args{[]RoleExtractor{AzureRoles()}},
+			[]string{"Contracts.Read", "62e90394-69f5-4237-9190-012177145e10"},
+		},
+		{
+			"Must combine extractors and remove duplicates",
+			args{[]RoleExtractor{KeycloakRealmRoles(), KeycloakClientRoles("bestiary.api")}},
+			[]string{"witcher", "offline_access", "reader"},
+		},
+		{
+			"Must return no roles for an unknown client",
+			args{[]RoleExtractor{KeycloakClientRoles("unknown")}},
+			[]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtractRoles(&Token{DecodedBody: exampleRoleClaims}, tt.args.extractors...)
+			if nil != err {
+				t.Errorf("ExtractRoles() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractRoles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIssuerRoleExtractors_Extract(t *testing.T) {
+	presets := IssuerRoleExtractors{
+		"https://keycloak.example.com/realms/kaermorhen": {KeycloakRealmRoles()},
+	}
+
+	got, err := presets.Extract(&Token{DecodedBody: exampleRoleClaims})
+	if nil != err {
+		t.Errorf("IssuerRoleExtractors.Extract() error = %v", err)
+	}
+	if want := []string{"witcher", "offline_access"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("IssuerRoleExtractors.Extract() = %v, want %v", got, want)
+	}
+
+	_, err = presets.Extract(&Token{DecodedBody: []byte(`{"iss":"https://unknown.example.com"}`)})
+	if nil == err {
+		t.Errorf("IssuerRoleExtractors.Extract() expected error for an unconfigured issuer")
+	}
+}
@@ -0,0 +1,186 @@
+package jwt
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/georgejenkins/jwt/jwk"
+)
+
+// KeyGenerator generates a new key for rotation.
+type KeyGenerator func() (interface{}, error)
+
+// RotationConfig configures a KeyRotator.
+type RotationConfig struct {
+	// Interval is how often Start rotates keys. Required by Start.
+	Interval time.Duration
+
+	// Overlap is how long a new key is published before it signs, so
+	// verifiers caching the JWKS learn of it first.
+	Overlap time.Duration
+
+	// Keep is the number of previous keys kept for verification. Older
+	// keys are removed from the Keyring.
+	Keep int
+}
+
+// RotationEvent describes a rotation, or its failure.
+type RotationEvent struct {
+	Time time.Time
+	// Added is the new key's ID, which signs from SignsFrom.
+	Added     string
+	SignsFrom time.Time
+	// Retired is the previous key, which stops signing at SignsFrom, and
+	// Removed the keys no longer kept for verification.
+	Retired []string
+	Removed []string
+	Err     error
+}
+
+// KeyRotator rotates the keys of a Keyring, generating a new key on a
+// schedule or on demand. The new key signs once the overlap has passed,
+// when the previous key retires; the previous Keep keys remain available
+// for verification. Callbacks are invoked on every rotation, for example
+// to republish the JWKS.
+type KeyRotator struct {
+	keyring  *Keyring
+	alg      Algorithm
+	generate KeyGenerator
+	config   RotationConfig
+
+	mu        sync.Mutex
+	rotated   []string
+	callbacks []func(RotationEvent)
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewKeyRotator creates a KeyRotator adding keys for the algorithm,
+// generated by generate, to the keyring.
+func NewKeyRotator(keyring *Keyring, alg Algorithm, generate KeyGenerator, config RotationConfig) (*KeyRotator, error) {
+	if nil == keyring || nil == generate {
+		return nil, errors.New("Key rotation requires a keyring and a key generator")
+	}
+	if config.Overlap < 0 || config.Keep < 0 {
+		return nil, errors.New("Key rotation overlap and keep cannot be negative")
+	}
+
+	return &KeyRotator{
+		keyring:  keyring,
+		alg:      alg,
+		generate: generate,
+		config:   config,
+		done:     make(chan struct{}),
+	}, nil
+}
+
+// OnRotate registers a callback invoked after every rotation, including
+// failed scheduled rotations.
+func (r *KeyRotator) OnRotate(callback func(RotationEvent)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.callbacks = append(r.callbacks, callback)
+}
+
+// Rotate generates and adds a new key, identified by its thumbprint, which
+// signs once the overlap has passed. The previous key added by the
+// rotator retires at that time.
+func (r *KeyRotator) Rotate() (RotationEvent, error) {
+	event, err := r.rotate()
+	if nil != err {
+		event.Err = err
+	}
+
+	r.mu.Lock()
+	callbacks := append([]func(RotationEvent){}, r.callbacks...)
+	r.mu.Unlock()
+	for _, callback := range callbacks {
+		callback(event)
+	}
+
+	return event, err
+}
+
+func (r *KeyRotator) rotate() (RotationEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	event := RotationEvent{Time: now, SignsFrom: now.Add(r.config.Overlap)}
+
+	key, err := r.generate()
+	if nil != err {
+		return event, err
+	}
+	kid, err := jwk.Thumbprint(key)
+	if nil != err {
+		return event, err
+	}
+
+	err = r.keyring.Add(KeyringKey{
+		KeyID:     kid,
+		Algorithm: r.alg,
+		Key:       key,
+		NotBefore: event.SignsFrom,
+	})
+	if nil != err {
+		return event, err
+	}
+	event.Added = kid
+
+	if len(r.rotated) > 0 {
+		previous := r.rotated[len(r.rotated)-1]
+		if r.keyring.Retire(previous, event.SignsFrom) == nil {
+			event.Retired = append(event.Retired, previous)
+		}
+	}
+
+	r.rotated = append(r.rotated, kid)
+	for len(r.rotated) > r.config.Keep+1 {
+		r.keyring.Remove(r.rotated[0])
+		event.Removed = append(event.Removed, r.rotated[0])
+		r.rotated = r.rotated[1:]
+	}
+
+	return event, nil
+}
+
+// Start rotates immediately if the keyring has no signing key, then every
+// interval until Close is called.
+func (r *KeyRotator) Start() error {
+	if r.config.Interval <= 0 {
+		return errors.New("Scheduled key rotation requires a positive interval")
+	}
+
+	if _, err := r.keyring.SigningKey(); err == ErrNoSigningKey {
+		if _, err := r.Rotate(); nil != err {
+			return err
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(r.config.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-r.done:
+				return
+			case <-ticker.C:
+				r.Rotate()
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Close stops scheduled rotation.
+func (r *KeyRotator) Close() {
+	r.closeOnce.Do(func() {
+		close(r.done)
+	})
+}
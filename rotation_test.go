@@ -0,0 +1,128 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+)
+
+func generateP256() (interface{}, error) {
+	return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+}
+
+func TestKeyRotator_Rotate(t *testing.T) {
+	kr, _ := NewKeyring(time.Hour)
+	rotator, err := NewKeyRotator(kr, ES256, generateP256, RotationConfig{Keep: 1})
+	if nil != err {
+		t.Fatalf("NewKeyRotator() error = %v", err)
+	}
+
+	var events []RotationEvent
+	rotator.OnRotate(func(event RotationEvent) { events = append(events, event) })
+
+	var kids []string
+	var tokens [][]byte
+	for i := 0; i < 3; i++ {
+		event, err := rotator.Rotate()
+		if nil != err {
+			t.Fatalf("KeyRotator.Rotate() error = %v", err)
+		}
+		kids = append(kids, event.Added)
+
+		signing, _ := kr.SigningKey()
+		if signing.KeyID != event.Added {
+			t.Errorf("Keyring.SigningKey() = %q after rotation, want the new key %q", signing.KeyID, event.Added)
+		}
+		token, _ := kr.GenerateToken(Header{}, Claims{Subject: "alice"})
+		tokens = append(tokens, token)
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("OnRotate callback invoked %d times, want 3", len(events))
+	}
+	if len(events[2].Retired) != 1 || events[2].Retired[0] != kids[1] {
+		t.Errorf("RotationEvent.Retired = %v, want [%s]", events[2].Retired, kids[1])
+	}
+	if len(events[2].Removed) != 1 || events[2].Removed[0] != kids[0] {
+		t.Errorf("RotationEvent.Removed = %v, want [%s]", events[2].Removed, kids[0])
+	}
+
+	verifier, _ := NewKeyResolvingVerifier(kr)
+	for i, wantValid := range []bool{false, true, true} {
+		if _, valid, _ := verifier.VerifySignature(tokens[i]); valid != wantValid {
+			t.Errorf("VerifySignature() = %v for a token of rotation %d, want %v", valid, i, wantValid)
+		}
+	}
+
+	failing, _ := NewKeyRotator(kr, ES256, func() (interface{}, error) { return nil, errors.New("HSM unavailable") }, RotationConfig{})
+	var failed RotationEvent
+	failing.OnRotate(func(event RotationEvent) { failed = event })
+	if _, err := failing.Rotate(); nil == err || nil == failed.Err {
+		t.Errorf("KeyRotator.Rotate() error = %v, callback error = %v, want errors", err, failed.Err)
+	}
+}
+
+func TestKeyRotator_Overlap(t *testing.T) {
+	kr, _ := NewKeyring(time.Hour)
+	rotator, _ := NewKeyRotator(kr, ES256, generateP256, RotationConfig{Overlap: time.Hour, Keep: 1})
+
+	if err := kr.Add(KeyringKey{KeyID: "initial", Algorithm: ES256, Key: mustGenerateP256()}); nil != err {
+		t.Fatalf("Keyring.Add() error = %v", err)
+	}
+	event, _ := rotator.Rotate()
+
+	// The new key is published, but the initial key signs until the
+	// overlap has passed.
+	signing, _ := kr.SigningKey()
+	if signing.KeyID != "initial" {
+		t.Errorf("Keyring.SigningKey() = %q during the overlap, want the initial key", signing.KeyID)
+	}
+	if kr.KeyStates()[event.Added] != KeyPending {
+		t.Errorf("Keyring.KeyStates() = %v, want the new key pending", kr.KeyStates())
+	}
+	if !event.SignsFrom.After(event.Time) {
+		t.Errorf("RotationEvent.SignsFrom = %v, want after the overlap", event.SignsFrom)
+	}
+}
+
+func TestKeyRotator_Start(t *testing.T) {
+	kr, _ := NewKeyring(time.Hour)
+	rotator, _ := NewKeyRotator(kr, ES256, generateP256, RotationConfig{Interval: 10 * time.Millisecond, Keep: 2})
+	defer rotator.Close()
+
+	rotations := make(chan RotationEvent, 10)
+	rotator.OnRotate(func(event RotationEvent) {
+		select {
+		case rotations <- event:
+		default:
+		}
+	})
+
+	if err := rotator.Start(); nil != err {
+		t.Fatalf("KeyRotator.Start() error = %v", err)
+	}
+	if _, err := kr.SigningKey(); nil != err {
+		t.Errorf("Keyring.SigningKey() error = %v after Start", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-rotations:
+		case <-time.After(time.Second):
+			t.Fatalf("KeyRotator did not rotate on schedule")
+		}
+	}
+
+	unscheduled, _ := NewKeyRotator(kr, ES256, generateP256, RotationConfig{})
+	if err := unscheduled.Start(); nil == err {
+		t.Errorf("KeyRotator.Start() expected error without an interval")
+	}
+}
+
+func mustGenerateP256() *ecdsa.PrivateKey {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	return key
+}
@@ -0,0 +1,235 @@
+package jwt
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// SessionTokenType is the 'typ' of session cookie tokens, so tokens
+// issued for other purposes by the same key can't be used as sessions.
+const SessionTokenType = "session+jwt"
+
+// MaxCookieSize is the size in bytes of the largest cookie, name and
+// value, that browsers are required to store (RFC 6265, section 6.1).
+const MaxCookieSize = 4096
+
+// Session is the state of a client session, held in a cookie.
+type Session struct {
+	// Values are the session's state, encoded as JSON.
+	Values map[string]interface{}
+
+	// IsNew is set for sessions not loaded from a cookie.
+	IsNew bool
+
+	issuedAt time.Time
+	flashes  []string
+}
+
+// AddFlash adds a flash message, shown once by the next request reading
+// Flashes.
+func (s *Session) AddFlash(message string) {
+	s.flashes = append(s.flashes, message)
+}
+
+// Flashes returns and removes the flash messages. The session must be
+// saved for them to stay removed.
+func (s *Session) Flashes() []string {
+	flashes := s.flashes
+	s.flashes = nil
+	return flashes
+}
+
+// sessionClaims are the claims of a session cookie token.
+type sessionClaims struct {
+	Claims
+	Values  map[string]interface{} `json:"values,omitempty"`
+	Flashes []string               `json:"flashes,omitempty"`
+}
+
+// CookieStore stores sessions entirely in a signed cookie, optionally
+// encrypted as a nested JWT, as an alternative to server-side session
+// storage. Cookies expire maxAge after they were last saved, and Load
+// renews those older than the renewal interval, so active sessions live
+// on while idle ones expire.
+//
+// Session cookies can't be revoked before they expire. Keep maxAge short,
+// and keep state that must be revocable on the server.
+type CookieStore struct {
+	name   string
+	sv     *JOSESignerVerifier
+	maxAge time.Duration
+
+	// Cipher, if set, encrypts session cookies, which are otherwise only
+	// signed and so readable by the client.
+	Cipher JWECipher
+
+	// RenewAfter is the age after which Load renews a session's cookie,
+	// half of maxAge by default.
+	RenewAfter time.Duration
+
+	// Path and Domain scope the cookie. Path defaults to "/".
+	Path   string
+	Domain string
+
+	// Insecure allows the cookie to be sent over plain HTTP, for local
+	// development only.
+	Insecure bool
+
+	// SameSite restricts sending the cookie with cross-site requests. It
+	// defaults to http.SameSiteLaxMode.
+	SameSite http.SameSite
+}
+
+// NewCookieStore creates a CookieStore for the cookie name, signing
+// sessions with sv. Sessions expire maxAge after they were last saved.
+func NewCookieStore(name string, sv *JOSESignerVerifier, maxAge time.Duration) (*CookieStore, error) {
+	if name == "" || nil == sv {
+		return nil, errors.New("Cookie store requires a cookie name and a signer verifier")
+	}
+	if maxAge <= 0 {
+		return nil, errors.New("Session max age must be positive")
+	}
+
+	return &CookieStore{
+		name:       name,
+		sv:         sv,
+		maxAge:     maxAge,
+		RenewAfter: maxAge / 2,
+		Path:       "/",
+		SameSite:   http.SameSiteLaxMode,
+	}, nil
+}
+
+// New returns a new, empty session.
+func (s *CookieStore) New() *Session {
+	return &Session{Values: make(map[string]interface{}), IsNew: true}
+}
+
+// Load returns the session of the request's cookie, or a new session if
+// it has none. A cookie that is invalid or expired is reported with an
+// error alongside a new session. Sessions older than RenewAfter are saved
+// again, so Load must be called before the response is written.
+func (s *CookieStore) Load(w http.ResponseWriter, r *http.Request) (*Session, error) {
+	cookie, err := r.Cookie(s.name)
+	if nil != err {
+		return s.New(), nil
+	}
+
+	session, err := s.decode([]byte(cookie.Value))
+	if nil != err {
+		return s.New(), err
+	}
+
+	if time.Since(session.issuedAt) >= s.RenewAfter {
+		if err := s.Save(w, session); nil != err {
+			return session, err
+		}
+	}
+
+	return session, nil
+}
+
+// Save sets the session's cookie. Sessions whose cookie would exceed
+// MaxCookieSize are rejected with a TokenSizeError, leaving the previous
+// cookie in place.
+func (s *CookieStore) Save(w http.ResponseWriter, session *Session) error {
+	now := time.Now()
+	claims := sessionClaims{
+		Claims: Claims{
+			IssuedAt:   NewNumericDate(now),
+			Expiration: NewNumericDate(now.Add(s.maxAge)),
+		},
+		Values:  session.Values,
+		Flashes: session.flashes,
+	}
+	header := Header{Algorithm: string(s.sv.algorithm), Type: SessionTokenType}
+
+	var token []byte
+	var err error
+	if nil != s.Cipher {
+		token, err = (&NestedJWT{sv: s.sv, cipher: s.Cipher}).SignAndEncrypt(header, claims)
+	} else {
+		token, err = s.sv.GenerateToken(header, claims)
+	}
+	if nil != err {
+		return err
+	}
+
+	if size := len(s.name) + len(token); size > MaxCookieSize {
+		return &TokenSizeError{Size: size, SizeBudget: MaxCookieSize}
+	}
+
+	session.IsNew = false
+	session.issuedAt = now
+	s.setCookie(w, string(token), int(s.maxAge/time.Second))
+	return nil
+}
+
+// Delete removes the session's cookie.
+func (s *CookieStore) Delete(w http.ResponseWriter) {
+	s.setCookie(w, "", -1)
+}
+
+// decode verifies a session cookie token, decrypting it first if the
+// store encrypts sessions.
+func (s *CookieStore) decode(rawToken []byte) (*Session, error) {
+	var token *Token
+	var valid bool
+	var err error
+	if nil != s.Cipher {
+		token, valid, err = (&NestedJWT{sv: s.sv, cipher: s.Cipher}).DecryptAndVerify(rawToken, nil)
+	} else {
+		token, valid, err = s.sv.VerifyToken(rawToken, nil)
+	}
+	if nil != err {
+		return nil, err
+	}
+	if !valid || token.RegisteredHeader.Type != SessionTokenType {
+		return nil, errors.New("Session cookie is not a valid session token")
+	}
+
+	var claims sessionClaims
+	if err := GetClaims(token, &claims); nil != err {
+		return nil, err
+	}
+	if nil == claims.Values {
+		claims.Values = make(map[string]interface{})
+	}
+	// Sessions without an issue time are renewed on their next save.
+	var issuedAt time.Time
+	if nil != claims.IssuedAt {
+		issuedAt = claims.IssuedAt.Time()
+	}
+	return &Session{
+		Values:   claims.Values,
+		issuedAt: issuedAt,
+		flashes:  claims.Flashes,
+	}, nil
+}
+
+// setCookie sets the session cookie, replacing any set earlier in the
+// response.
+func (s *CookieStore) setCookie(w http.ResponseWriter, value string, maxAge int) {
+	prefix := s.name + "="
+	cookies := w.Header()["Set-Cookie"]
+	kept := cookies[:0]
+	for _, cookie := range cookies {
+		if !strings.HasPrefix(cookie, prefix) {
+			kept = append(kept, cookie)
+		}
+	}
+	w.Header()["Set-Cookie"] = kept
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     s.name,
+		Value:    value,
+		Path:     s.Path,
+		Domain:   s.Domain,
+		MaxAge:   maxAge,
+		Secure:   !s.Insecure,
+		HttpOnly: true,
+		SameSite: s.SameSite,
+	})
+}
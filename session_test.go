@@ -0,0 +1,129 @@
+package jwt
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCookieStore(t *testing.T) {
+	if _, err := NewCookieStore("session", nil, time.Hour); nil == err {
+		t.Errorf("NewCookieStore() expected error without a signer verifier")
+	}
+
+	sv, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	signed, _ := NewCookieStore("session", sv, time.Hour)
+	encrypted, _ := NewCookieStore("session", sv, time.Hour)
+	encrypted.Cipher, _ = NewJWEEncrypterDecrypter(Direct, A256GCM, bytes.Repeat([]byte{1}, 32))
+
+	for _, store := range []*CookieStore{signed, encrypted} {
+		session := store.New()
+		session.Values["user"] = "alice"
+		session.AddFlash("Welcome back")
+
+		w := httptest.NewRecorder()
+		if err := store.Save(w, session); nil != err {
+			t.Fatalf("CookieStore.Save() error = %v", err)
+		}
+		cookie := w.Result().Cookies()[0]
+		if !cookie.HttpOnly || !cookie.Secure || cookie.MaxAge != 3600 {
+			t.Errorf("CookieStore.Save() cookie = %+v", cookie)
+		}
+		if encryptedCookie := nil != store.Cipher; encryptedCookie == (strings.Count(cookie.Value, ".") == 2) {
+			t.Errorf("CookieStore.Save() cookie = %s, encrypted %v", cookie.Value, encryptedCookie)
+		}
+
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.AddCookie(cookie)
+		w = httptest.NewRecorder()
+		loaded, err := store.Load(w, r)
+		if nil != err || loaded.IsNew || loaded.Values["user"] != "alice" {
+			t.Fatalf("CookieStore.Load() = %+v, %v", loaded, err)
+		}
+		if len(w.Result().Cookies()) != 0 {
+			t.Errorf("CookieStore.Load() renewed a fresh session")
+		}
+		if flashes := loaded.Flashes(); len(flashes) != 1 || flashes[0] != "Welcome back" || len(loaded.Flashes()) != 0 {
+			t.Errorf("Session.Flashes() = %v, want the flash once", flashes)
+		}
+	}
+}
+
+func TestCookieStore_Load(t *testing.T) {
+	sv, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	store, _ := NewCookieStore("session", sv, time.Hour)
+
+	w := httptest.NewRecorder()
+	store.Save(w, store.New())
+	valid := w.Result().Cookies()[0].Value
+	notSession, _ := sv.GenerateToken(Header{Algorithm: string(HS256), Type: "JWT"}, Claims{})
+
+	tests := []struct {
+		name       string
+		cookie     string
+		renewAfter time.Duration
+		wantNew    bool
+		wantErr    bool
+		wantRenew  bool
+	}{
+		{"Must return a new session without a cookie", "", time.Hour, true, false, false},
+		{"Must load a session", valid, time.Hour, false, false, false},
+		{"Must renew a session older than the renewal interval", valid, 0, false, false, true},
+		{"Must not load a tampered session", valid[:len(valid)-2] + "AA", time.Hour, true, true, false},
+		{"Must not load a token that is not a session", string(notSession), time.Hour, true, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store.RenewAfter = tt.renewAfter
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.cookie != "" {
+				r.AddCookie(&http.Cookie{Name: "session", Value: tt.cookie})
+			}
+
+			w := httptest.NewRecorder()
+			session, err := store.Load(w, r)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CookieStore.Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if session.IsNew != tt.wantNew {
+				t.Errorf("CookieStore.Load() IsNew = %v, want %v", session.IsNew, tt.wantNew)
+			}
+			if renewed := len(w.Result().Cookies()) == 1; renewed != tt.wantRenew {
+				t.Errorf("CookieStore.Load() renewed = %v, want %v", renewed, tt.wantRenew)
+			}
+		})
+	}
+}
+
+func TestCookieStore_Save(t *testing.T) {
+	sv, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	store, _ := NewCookieStore("session", sv, time.Hour)
+
+	session := store.New()
+	session.Values["blob"] = strings.Repeat("x", MaxCookieSize)
+	w := httptest.NewRecorder()
+	err := store.Save(w, session)
+	if sizeErr, ok := err.(*TokenSizeError); !ok || sizeErr.SizeBudget != MaxCookieSize {
+		t.Errorf("CookieStore.Save() error = %v, want a TokenSizeError", err)
+	}
+	if len(w.Result().Cookies()) != 0 {
+		t.Errorf("CookieStore.Save() set a cookie exceeding the maximum size")
+	}
+
+	session.Values["blob"] = "small"
+	w = httptest.NewRecorder()
+	store.Save(w, session)
+	store.Save(w, session)
+	if cookies := w.Result().Cookies(); len(cookies) != 1 {
+		t.Errorf("CookieStore.Save() set %d cookies, want the last saved", len(cookies))
+	}
+
+	w = httptest.NewRecorder()
+	store.Delete(w)
+	if cookies := w.Result().Cookies(); len(cookies) != 1 || cookies[0].MaxAge >= 0 {
+		t.Errorf("CookieStore.Delete() cookies = %+v", cookies)
+	}
+}
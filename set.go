@@ -0,0 +1,201 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"time"
+)
+
+// SETType is the 'typ' header value of Security Event Tokens (RFC 8417).
+const SETType = "secevent+jwt"
+
+// SETContentType is the media type SETs are delivered with (RFC 8935).
+const SETContentType = "application/secevent+jwt"
+
+// maxSETSize bounds the size of a SET accepted by the push handler.
+const maxSETSize = 1 << 20
+
+// SET push delivery error codes, as per RFC 8935 Section 2.4.
+const (
+	SETErrInvalidRequest = "invalid_request"
+	SETErrInvalidKey     = "invalid_key"
+)
+
+// SETClaims is the claim set of a Security Event Token (RFC 8417).
+type SETClaims struct {
+	Claims
+
+	// Events maps event type URIs to event specific payloads, which must
+	// be JSON objects.
+	Events map[string]json.RawMessage `json:"events"`
+
+	// TransactionID ('txn') correlates SETs about the same transaction.
+	TransactionID string `json:"txn,omitempty"`
+
+	// TimeOfEvent ('toe') is when the event occurred, where that differs
+	// from when the SET was issued.
+	TimeOfEvent *NumericDate `json:"toe,omitempty"`
+
+	// SubjectID ('sub_id') identifies the subject of the events, as used by
+	// the Shared Signals Framework.
+	SubjectID *SubjectIdentifier `json:"sub_id,omitempty"`
+}
+
+// Validate checks the claim set is a well-formed SET: 'iss', 'iat', 'jti'
+// and at least one event are required, and 'exp' is rejected so a SET can
+// never be mistaken for an access token (RFC 8417 Section 4.3).
+func (claims *SETClaims) Validate() error {
+	if claims.Issuer == "" {
+		return errors.New("SETs must have an issuer")
+	}
+
+	if nil == claims.IssuedAt {
+		return errors.New("SETs must have an issued at time")
+	}
+
+	if claims.JWTID == "" {
+		return errors.New("SETs must have a JWT ID")
+	}
+
+	if nil != claims.Expiration {
+		return errors.New("SETs must not have an expiration, or they can be confused with access tokens")
+	}
+
+	if len(claims.Events) == 0 {
+		return errors.New("SETs must have at least one event")
+	}
+
+	for eventType, payload := range claims.Events {
+		if !bytes.HasPrefix(bytes.TrimSpace(payload), []byte("{")) {
+			return fmt.Errorf("Payload of event %q must be a JSON object", eventType)
+		}
+	}
+
+	return nil
+}
+
+// IssueSET issues a Security Event Token. 'iat' and 'jti' are populated
+// when not provided.
+func (sv *JOSESignerVerifier) IssueSET(claims SETClaims) ([]byte, error) {
+	if nil == claims.IssuedAt {
+		claims.IssuedAt = NewNumericDate(time.Now())
+	}
+
+	if claims.JWTID == "" {
+		jti, err := GenerateNonce()
+		if nil != err {
+			return nil, err
+		}
+		claims.JWTID = jti
+	}
+
+	if err := claims.Validate(); nil != err {
+		return nil, err
+	}
+
+	return sv.GenerateToken(
+		Header{
+			Algorithm: string(sv.algorithm),
+			Type:      SETType,
+		},
+		claims,
+	)
+}
+
+// VerifySET verifies a Security Event Token: its 'typ' header, signature,
+// registered claims and SET specific claims.
+func (sv *JOSESignerVerifier) VerifySET(rawToken []byte, validationCriteria *ValidationClaims) (*Token, *SETClaims, error) {
+	token, valid, err := sv.VerifyToken(rawToken, validationCriteria)
+	if nil != err {
+		return token, nil, err
+	}
+	if !valid {
+		return token, nil, errors.New("SET is not valid")
+	}
+
+	if token.RegisteredHeader.Type != SETType {
+		return token, nil, fmt.Errorf("Expected typ %q, received %q", SETType, token.RegisteredHeader.Type)
+	}
+
+	var claims SETClaims
+	if err := GetClaims(token, &claims); nil != err {
+		return token, nil, err
+	}
+
+	if err := claims.Validate(); nil != err {
+		return token, nil, err
+	}
+
+	return token, &claims, nil
+}
+
+// setDeliveryError is the error response body of RFC 8935 push delivery.
+type setDeliveryError struct {
+	Err         string `json:"err"`
+	Description string `json:"description"`
+}
+
+// NewSETPushHandler returns an http.Handler receiving SETs via push
+// delivery (RFC 8935). Verified SETs are passed to receive; the handler
+// responds 202 Accepted once receive returns successfully, 400 Bad Request
+// with an RFC 8935 error body for SETs that don't verify, and 500 Internal
+// Server Error if receive fails so the transmitter retries.
+func (sv *JOSESignerVerifier) NewSETPushHandler(validationCriteria *ValidationClaims, receive func(*Token, *SETClaims) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if nil != err || mediaType != SETContentType {
+			writeSETDeliveryError(w, SETErrInvalidRequest, "Content-Type must be "+SETContentType)
+			return
+		}
+
+		rawToken, err := ioutil.ReadAll(io.LimitReader(r.Body, maxSETSize+1))
+		if nil != err {
+			writeSETDeliveryError(w, SETErrInvalidRequest, "Cannot read request body")
+			return
+		}
+		if len(rawToken) > maxSETSize {
+			writeSETDeliveryError(w, SETErrInvalidRequest, "SET is too large")
+			return
+		}
+
+		// Tokens are verified once, as verification consumes any nonce.
+		// VerifySET only returns a token once its signature is valid.
+		token, claims, err := sv.VerifySET(bytes.TrimSpace(rawToken), validationCriteria)
+		if nil == token {
+			writeSETDeliveryError(w, SETErrInvalidKey, "SET signature could not be verified")
+			return
+		}
+		if nil != err {
+			writeSETDeliveryError(w, SETErrInvalidRequest, err.Error())
+			return
+		}
+
+		if err := receive(token, claims); nil != err {
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	})
+}
+
+func writeSETDeliveryError(w http.ResponseWriter, code string, description string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(setDeliveryError{
+		Err:         code,
+		Description: description,
+	})
+}
@@ -0,0 +1,199 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const exampleEventType = "https://schemas.openid.net/secevent/risc/event-type/account-disabled"
+
+func TestJOSESignerVerifier_IssueSET(t *testing.T) {
+	sv, err := NewJOSESignerVerifier(HS256, exampleKey)
+	if nil != err {
+		t.Fatalf("NewJOSESignerVerifier() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		claims  SETClaims
+		wantErr bool
+	}{
+		{
+			"Must issue a SET with an event",
+			SETClaims{
+				Claims: Claims{Issuer: "https://idp.example.com"},
+				Events: map[string]json.RawMessage{exampleEventType: json.RawMessage(`{"reason":"hijacking"}`)},
+			},
+			false,
+		},
+		{
+			"Must fail given no events",
+			SETClaims{Claims: Claims{Issuer: "https://idp.example.com"}},
+			true,
+		},
+		{
+			"Must fail given an event payload that isn't an object",
+			SETClaims{
+				Claims: Claims{Issuer: "https://idp.example.com"},
+				Events: map[string]json.RawMessage{exampleEventType: json.RawMessage(`"hijacking"`)},
+			},
+			true,
+		},
+		{
+			"Must fail given an expiration",
+			SETClaims{
+				Claims: Claims{Issuer: "https://idp.example.com", Expiration: numericDate(4102444800)},
+				Events: map[string]json.RawMessage{exampleEventType: json.RawMessage(`{}`)},
+			},
+			true,
+		},
+		{
+			"Must fail given no issuer",
+			SETClaims{
+				Events: map[string]json.RawMessage{exampleEventType: json.RawMessage(`{}`)},
+			},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rawToken, err := sv.IssueSET(tt.claims)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IssueSET() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			_, claims, err := sv.VerifySET(rawToken, &ValidationClaims{Issuer: []string{"https://idp.example.com"}})
+			if nil != err {
+				t.Errorf("VerifySET() error = %v", err)
+				return
+			}
+			if claims.JWTID == "" || nil == claims.IssuedAt {
+				t.Errorf("IssueSET() did not populate jti and iat: %+v", claims)
+			}
+		})
+	}
+}
+
+func TestJOSESignerVerifier_NewSETPushHandler(t *testing.T) {
+	sv, err := NewJOSESignerVerifier(HS256, exampleKey)
+	if nil != err {
+		t.Fatalf("NewJOSESignerVerifier() error = %v", err)
+	}
+
+	set, err := sv.IssueSET(SETClaims{
+		Claims: Claims{Issuer: "https://idp.example.com"},
+		Events: map[string]json.RawMessage{exampleEventType: json.RawMessage(`{}`)},
+	})
+	if nil != err {
+		t.Fatalf("IssueSET() error = %v", err)
+	}
+
+	notSET, _ := sv.GenerateToken(Header{Algorithm: string(HS256)}, Claims{Issuer: "https://idp.example.com"})
+	forged := append(append([]byte{}, set[:len(set)-2]...), "AA"...)
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+		receiveErr  error
+		wantStatus  int
+		wantErrCode string
+	}{
+		{"Must accept a valid SET", SETContentType, set, nil, http.StatusAccepted, ""},
+		{"Must reject an unexpected content type", "application/json", set, nil, http.StatusBadRequest, SETErrInvalidRequest},
+		{"Must reject a forged SET", SETContentType, forged, nil, http.StatusBadRequest, SETErrInvalidKey},
+		{"Must reject a token that isn't a SET", SETContentType, notSET, nil, http.StatusBadRequest, SETErrInvalidRequest},
+		{"Must fail when the receiver fails", SETContentType, set, errors.New("queue unavailable"), http.StatusInternalServerError, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := sv.NewSETPushHandler(
+				&ValidationClaims{Issuer: []string{"https://idp.example.com"}},
+				func(token *Token, claims *SETClaims) error {
+					return tt.receiveErr
+				},
+			)
+
+			request := httptest.NewRequest(http.MethodPost, "/events", bytes.NewReader(tt.body))
+			request.Header.Set("Content-Type", tt.contentType)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("SET push handler status = %v, want %v", recorder.Code, tt.wantStatus)
+			}
+
+			if tt.wantErrCode != "" {
+				var body setDeliveryError
+				json.Unmarshal(recorder.Body.Bytes(), &body)
+				if body.Err != tt.wantErrCode {
+					t.Errorf("SET push handler err = %v, want %v", body.Err, tt.wantErrCode)
+				}
+			}
+		})
+	}
+}
+
+func TestJOSESignerVerifier_VerifySET_TimeOfEvent(t *testing.T) {
+	sv, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	rawToken, err := sv.GenerateToken(
+		Header{Algorithm: string(HS256), Type: SETType},
+		json.RawMessage(`{"iss":"https://idp.example.com","iat":1700000100,"jti":"3d0c3cf797584bd193bd0fb1bd4e7d30","toe":1700000000,"events":{"`+exampleEventType+`":{}}}`),
+	)
+	if nil != err {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	_, claims, err := sv.VerifySET(rawToken, &ValidationClaims{Issuer: []string{"https://idp.example.com"}})
+	if nil != err {
+		t.Fatalf("VerifySET() error = %v", err)
+	}
+	if !reflect.DeepEqual(claims.TimeOfEvent, numericDate(1700000000)) {
+		t.Errorf("VerifySET() toe = %v, want %v", formatDate(claims.TimeOfEvent), 1700000000)
+	}
+}
+
+func TestJOSESignerVerifier_NewSETPushHandler_Nonce(t *testing.T) {
+	registry, _ := NewNonceRegistry(time.Minute)
+	sv, err := NewJOSESignerVerifier(HS256, exampleKey, WithNonceValidator(registry))
+	if nil != err {
+		t.Fatalf("NewJOSESignerVerifier() error = %v", err)
+	}
+
+	set, err := sv.GenerateTokenWithNonce(
+		Header{Algorithm: string(HS256), Type: SETType},
+		SETClaims{
+			Claims: Claims{Issuer: "https://idp.example.com", IssuedAt: NewNumericDate(time.Now()), JWTID: "3d0c3cf797584bd193bd0fb1bd4e7d30"},
+			Events: map[string]json.RawMessage{exampleEventType: json.RawMessage(`{}`)},
+		},
+		NonceSourceFunc(registry.Issue),
+	)
+	if nil != err {
+		t.Fatalf("GenerateTokenWithNonce() error = %v", err)
+	}
+
+	handler := sv.NewSETPushHandler(
+		&ValidationClaims{Issuer: []string{"https://idp.example.com"}},
+		func(token *Token, claims *SETClaims) error { return nil },
+	)
+	for _, wantStatus := range []int{http.StatusAccepted, http.StatusBadRequest} {
+		request := httptest.NewRequest(http.MethodPost, "/events", bytes.NewReader(set))
+		request.Header.Set("Content-Type", SETContentType)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		if recorder.Code != wantStatus {
+			t.Errorf("SET push handler status = %v, want %v", recorder.Code, wantStatus)
+		}
+	}
+}
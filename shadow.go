@@ -0,0 +1,123 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync/atomic"
+)
+
+// VerifyFunc verifies a token, returning an error if it is not valid.
+// StrictVerifier.Verify is a VerifyFunc, and VerifyTokenFunc adapts a
+// JOSESignerVerifier.
+type VerifyFunc func(rawToken []byte) (*Token, error)
+
+// VerifyTokenFunc returns a VerifyFunc verifying tokens with
+// sv.VerifyToken against the validation criteria.
+func VerifyTokenFunc(sv *JOSESignerVerifier, validationCriteria *ValidationClaims) VerifyFunc {
+	return func(rawToken []byte) (*Token, error) {
+		token, valid, err := sv.VerifyToken(rawToken, validationCriteria)
+		if nil != err {
+			return nil, err
+		}
+		if !valid {
+			return nil, errors.New("Token is invalid")
+		}
+		return token, nil
+	}
+}
+
+// ShadowDivergence describes a token the primary and shadow verifiers
+// disagreed on. It deliberately holds no token contents, as tokens are
+// credentials.
+type ShadowDivergence struct {
+	PrimaryErr error
+	ShadowErr  error
+}
+
+func (d ShadowDivergence) String() string {
+	outcome := func(err error) string {
+		if nil == err {
+			return "valid"
+		}
+		return fmt.Sprintf("invalid (%v)", err)
+	}
+
+	return fmt.Sprintf("primary %s, shadow %s", outcome(d.PrimaryErr), outcome(d.ShadowErr))
+}
+
+// ShadowVerifier verifies tokens with a primary verifier, and runs a shadow
+// verifier on the same tokens in the background, reporting when their
+// outcomes differ. Only the primary affects the outcome, so a new policy
+// or key source can be trialled against production traffic before it
+// replaces the primary.
+type ShadowVerifier struct {
+	primary VerifyFunc
+	shadow  VerifyFunc
+	report  func(ShadowDivergence)
+
+	divergences uint64
+}
+
+// NewShadowVerifier creates a ShadowVerifier. Divergences are passed to
+// report, which must be safe for concurrent use, or logged if report is
+// nil.
+func NewShadowVerifier(primary VerifyFunc, shadow VerifyFunc, report func(ShadowDivergence)) (*ShadowVerifier, error) {
+	if nil == primary || nil == shadow {
+		return nil, errors.New("Shadow verification requires a primary and a shadow verifier")
+	}
+
+	if nil == report {
+		report = func(d ShadowDivergence) {
+			log.Printf("Shadow verification diverged: %v", d)
+		}
+	}
+
+	return &ShadowVerifier{
+		primary: primary,
+		shadow:  shadow,
+		report:  report,
+	}, nil
+}
+
+// Verify verifies the token with the primary verifier and returns its
+// result, starting the shadow verification in the background.
+func (v *ShadowVerifier) Verify(rawToken []byte) (*Token, error) {
+	token, err := v.primary(rawToken)
+
+	// The token may be reused by the caller once Verify returns.
+	shadowToken := append([]byte(nil), rawToken...)
+	go v.runShadow(shadowToken, err)
+
+	return token, err
+}
+
+// Divergences returns the number of divergences reported so far.
+func (v *ShadowVerifier) Divergences() uint64 {
+	return atomic.LoadUint64(&v.divergences)
+}
+
+// runShadow runs the shadow verifier and reports a divergence from the
+// primary outcome. A panicking shadow is reported as a divergence rather
+// than crashing the process.
+func (v *ShadowVerifier) runShadow(rawToken []byte, primaryErr error) {
+	var shadowErr error
+	func() {
+		defer func() {
+			if r := recover(); nil != r {
+				shadowErr = fmt.Errorf("Shadow verifier panicked: %v", r)
+			}
+		}()
+		_, shadowErr = v.shadow(rawToken)
+	}()
+
+	if (nil == primaryErr) == (nil == shadowErr) {
+		return
+	}
+
+	atomic.AddUint64(&v.divergences, 1)
+	v.report(ShadowDivergence{
+		PrimaryErr: primaryErr,
+		ShadowErr:  shadowErr,
+	})
+}
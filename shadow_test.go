@@ -0,0 +1,85 @@
+package jwt
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestShadowVerifier_Verify(t *testing.T) {
+	criteria := &ValidationClaims{Issuer: []string{"issuer"}, Subject: []string{"alice"}}
+	oldKey, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	newKey, _ := NewJOSESignerVerifier(HS256, bytes.Repeat([]byte{9}, 64))
+
+	divergences := make(chan ShadowDivergence, 10)
+	v, err := NewShadowVerifier(
+		VerifyTokenFunc(oldKey, criteria),
+		VerifyTokenFunc(newKey, criteria),
+		func(d ShadowDivergence) { divergences <- d },
+	)
+	if nil != err {
+		t.Fatalf("NewShadowVerifier() error = %v", err)
+	}
+
+	claims := Claims{Issuer: "issuer", Subject: "alice"}
+	signedWithOld, _ := oldKey.GenerateToken(Header{Algorithm: string(HS256)}, claims)
+	signedWithNew, _ := newKey.GenerateToken(Header{Algorithm: string(HS256)}, claims)
+
+	tests := []struct {
+		name          string
+		token         []byte
+		wantErr       bool
+		wantDiverged  bool
+		wantShadowErr bool
+	}{
+		{"Must accept per the primary, reporting the shadow rejection", signedWithOld, false, true, true},
+		{"Must reject per the primary, reporting the shadow acceptance", signedWithNew, true, true, false},
+		{"Must not report agreement", []byte("e30.e30.AA"), true, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := v.Verify(tt.token); (err != nil) != tt.wantErr {
+				t.Errorf("ShadowVerifier.Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			select {
+			case d := <-divergences:
+				if !tt.wantDiverged {
+					t.Errorf("ShadowVerifier reported unexpected divergence: %v", d)
+				} else if (d.ShadowErr != nil) != tt.wantShadowErr {
+					t.Errorf("ShadowVerifier divergence = %v", d)
+				}
+			case <-time.After(100 * time.Millisecond):
+				if tt.wantDiverged {
+					t.Errorf("ShadowVerifier did not report a divergence")
+				}
+			}
+		})
+	}
+
+	if got := v.Divergences(); got != 2 {
+		t.Errorf("ShadowVerifier.Divergences() = %v, want 2", got)
+	}
+}
+
+func TestShadowVerifier_ShadowPanic(t *testing.T) {
+	divergences := make(chan ShadowDivergence, 1)
+	v, _ := NewShadowVerifier(
+		func([]byte) (*Token, error) { return &Token{}, nil },
+		func([]byte) (*Token, error) { panic("shadow bug") },
+		func(d ShadowDivergence) { divergences <- d },
+	)
+
+	if _, err := v.Verify([]byte("token")); nil != err {
+		t.Errorf("ShadowVerifier.Verify() error = %v", err)
+	}
+
+	select {
+	case d := <-divergences:
+		if nil == d.ShadowErr {
+			t.Errorf("ShadowVerifier divergence = %v, want the shadow panic", d)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("ShadowVerifier did not report the shadow panic")
+	}
+}
@@ -0,0 +1,155 @@
+// Package shamir splits secrets into shares with Shamir's secret sharing
+// over GF(2^8), so that any threshold of the shares reconstruct the secret
+// and fewer reveal nothing about it.
+//
+// Each share is the secret's length plus one byte: the evaluations of a
+// random polynomial for every byte of the secret, followed by the share's
+// x coordinate.
+package shamir
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// rng is the source of polynomial coefficients.
+var rng io.Reader = rand.Reader
+
+// Split splits the secret into n shares, any threshold of which
+// reconstruct it. Shares are between 2 and 255, and so is the threshold,
+// which can't exceed the shares.
+func Split(secret []byte, n int, threshold int) ([][]byte, error) {
+	if len(secret) == 0 {
+		return nil, errors.New("Cannot split an empty secret")
+	}
+	if threshold < 2 || threshold > n || n > 255 {
+		return nil, fmt.Errorf("Cannot split a secret into %d shares with a threshold of %d", n, threshold)
+	}
+
+	shares := make([][]byte, n)
+	for i := range shares {
+		shares[i] = make([]byte, len(secret)+1)
+		shares[i][len(secret)] = byte(i + 1)
+	}
+
+	coefficients := make([]byte, threshold)
+	defer zero(coefficients)
+	for b, secretByte := range secret {
+		coefficients[0] = secretByte
+		if _, err := io.ReadFull(rng, coefficients[1:]); nil != err {
+			return nil, err
+		}
+
+		for _, share := range shares {
+			share[b] = evaluate(coefficients, share[len(secret)])
+		}
+	}
+
+	return shares, nil
+}
+
+// Combine reconstructs the secret from at least the threshold of its
+// shares. Fewer shares, or shares of different secrets, reconstruct a
+// wrong secret without error, so secrets should be checked once
+// reconstructed.
+func Combine(shares [][]byte) ([]byte, error) {
+	if len(shares) < 2 {
+		return nil, errors.New("At least two shares are required")
+	}
+
+	size := len(shares[0])
+	if size < 2 {
+		return nil, errors.New("Shares are too short")
+	}
+
+	xs := make([]byte, len(shares))
+	seen := make(map[byte]bool, len(shares))
+	for i, share := range shares {
+		if len(share) != size {
+			return nil, errors.New("Shares must all be the same length")
+		}
+
+		x := share[size-1]
+		if x == 0 || seen[x] {
+			return nil, errors.New("Shares must have distinct, non-zero x coordinates")
+		}
+		seen[x] = true
+		xs[i] = x
+	}
+
+	secret := make([]byte, size-1)
+	ys := make([]byte, len(shares))
+	for b := range secret {
+		for i, share := range shares {
+			ys[i] = share[b]
+		}
+		secret[b] = interpolateAtZero(xs, ys)
+	}
+
+	return secret, nil
+}
+
+// evaluate evaluates the polynomial with the coefficients, lowest degree
+// first, at x using Horner's method.
+func evaluate(coefficients []byte, x byte) byte {
+	var y byte
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		y = add(mul(y, x), coefficients[i])
+	}
+	return y
+}
+
+// interpolateAtZero returns the value at zero of the Lagrange polynomial
+// through the points.
+func interpolateAtZero(xs []byte, ys []byte) byte {
+	var y byte
+	for i := range xs {
+		basis := byte(1)
+		for j := range xs {
+			if i == j {
+				continue
+			}
+			// At zero, the basis factor (0 - x_j) / (x_i - x_j) is
+			// x_j / (x_i + x_j), as subtraction is addition in GF(2^8).
+			basis = mul(basis, div(xs[j], add(xs[i], xs[j])))
+		}
+		y = add(y, mul(ys[i], basis))
+	}
+	return y
+}
+
+// add adds in GF(2^8).
+func add(a byte, b byte) byte {
+	return a ^ b
+}
+
+// mul multiplies in GF(2^8) modulo the AES polynomial x^8+x^4+x^3+x+1,
+// without branching on the operands.
+func mul(a byte, b byte) byte {
+	var product byte
+	for i := 0; i < 8; i++ {
+		product ^= -(b & 1) & a
+		b >>= 1
+		a = (a << 1) ^ (-(a >> 7) & 0x1b)
+	}
+	return product
+}
+
+// div divides in GF(2^8), multiplying by the inverse b^254. b must not be
+// zero.
+func div(a byte, b byte) byte {
+	inverse := b
+	for i := 0; i < 6; i++ {
+		inverse = mul(mul(inverse, inverse), b)
+	}
+	return mul(a, mul(inverse, inverse))
+}
+
+// zero zeroes the bytes.
+func zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
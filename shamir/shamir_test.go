@@ -0,0 +1,90 @@
+package shamir
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSplitCombine(t *testing.T) {
+	secret := []byte("The Blue Stripes will ambush Radovid on the bridge to Temple Isle")
+
+	shares, err := Split(secret, 5, 3)
+	if nil != err {
+		t.Fatalf("Split() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		shares [][]byte
+		want   bool
+	}{
+		{"Must combine the threshold of shares", [][]byte{shares[0], shares[1], shares[2]}, true},
+		{"Must combine any threshold of shares in any order", [][]byte{shares[4], shares[1], shares[3]}, true},
+		{"Must combine all shares", shares, true},
+		{"Must not reconstruct the secret from fewer than the threshold", [][]byte{shares[0], shares[4]}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Combine(tt.shares)
+			if nil != err {
+				t.Fatalf("Combine() error = %v", err)
+			}
+			if bytes.Equal(got, secret) != tt.want {
+				t.Errorf("Combine() = %q, want the secret %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitCombineErrors(t *testing.T) {
+	shares, _ := Split([]byte("secret"), 3, 2)
+
+	splits := []struct {
+		name      string
+		secret    []byte
+		n         int
+		threshold int
+	}{
+		{"Must not split an empty secret", nil, 3, 2},
+		{"Must not split with a threshold of 1", []byte("secret"), 3, 1},
+		{"Must not split with a threshold over the shares", []byte("secret"), 3, 4},
+		{"Must not split into more than 255 shares", []byte("secret"), 256, 2},
+	}
+	for _, tt := range splits {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Split(tt.secret, tt.n, tt.threshold); nil == err {
+				t.Errorf("Split() expected error")
+			}
+		})
+	}
+
+	combines := []struct {
+		name   string
+		shares [][]byte
+	}{
+		{"Must not combine a single share", shares[:1]},
+		{"Must not combine shares of different lengths", [][]byte{shares[0], shares[1][1:]}},
+		{"Must not combine a share twice", [][]byte{shares[0], shares[0]}},
+		{"Must not combine a share at zero", [][]byte{shares[0], append([]byte("secret"), 0)}},
+	}
+	for _, tt := range combines {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Combine(tt.shares); nil == err {
+				t.Errorf("Combine() expected error")
+			}
+		})
+	}
+}
+
+func TestFieldArithmetic(t *testing.T) {
+	// The AES field's known product {57} x {83} = {c1} (FIPS 197, 4.2).
+	if got := mul(0x57, 0x83); got != 0xc1 {
+		t.Errorf("mul(0x57, 0x83) = %#x, want 0xc1", got)
+	}
+
+	for a := 1; a < 256; a++ {
+		if got := mul(div(1, byte(a)), byte(a)); got != 1 {
+			t.Fatalf("%#x times its inverse = %#x, want 1", a, got)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package jwt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJOSESignerVerifier_SignedURLMiddleware(t *testing.T) {
+	sv, err := NewJOSESignerVerifier(HS256, exampleKey)
+	if nil != err {
+		t.Fatalf("NewJOSESignerVerifier() error = %v", err)
+	}
+
+	signed, err := sv.SignURL("https://Files.Example.com/downloads/report.pdf?b=2&a=1", time.Minute, Claims{})
+	if nil != err {
+		t.Fatalf("SignURL() error = %v", err)
+	}
+
+	// Validated an hour from now, this URL has expired.
+	expired, err := sv.SignURL("https://files.example.com/downloads/report.pdf", time.Minute, Claims{})
+	if nil != err {
+		t.Fatalf("SignURL() error = %v", err)
+	}
+
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	later := &ValidationClaims{Expiration: time.Now().Add(time.Hour)}
+
+	tests := []struct {
+		name       string
+		url        string
+		validation *ValidationClaims
+		want       int
+	}{
+		{"Must accept the signed URL", signed, nil, http.StatusOK},
+		{"Must accept the signed URL with reordered query parameters", strings.Replace(signed, "a=1&b=2", "b=2&a=1", 1), nil, http.StatusOK},
+		{"Must reject a modified path", strings.Replace(signed, "report.pdf", "secrets.pdf", 1), nil, http.StatusForbidden},
+		{"Must reject a modified query", strings.Replace(signed, "a=1", "a=9", 1), nil, http.StatusForbidden},
+		{"Must reject a different host", strings.Replace(signed, "Files.Example.com", "evil.example.com", 1), nil, http.StatusForbidden},
+		{"Must reject an unsigned URL", "https://files.example.com/downloads/report.pdf", nil, http.StatusForbidden},
+		{"Must reject an expired URL", expired, later, http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := sv.SignedURLMiddleware(tt.validation, ok)
+
+			recorder := httptest.NewRecorder()
+			h.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.url, nil))
+			if recorder.Code != tt.want {
+				t.Errorf("SignedURLMiddleware() status = %v, want %v", recorder.Code, tt.want)
+			}
+		})
+	}
+}
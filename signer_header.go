@@ -0,0 +1,148 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// WithKeyID sets the 'kid' header of generated tokens to the ID of the
+// signing key, when the caller's header has none. Headers with another key
+// ID are rejected.
+func WithKeyID(kid string) Option {
+	return func(sv *JOSESignerVerifier) error {
+		if kid == "" {
+			return errors.New("Key ID cannot be empty")
+		}
+
+		sv.keyID = kid
+		return nil
+	}
+}
+
+// WithDefaultType sets the 'typ' header of generated tokens, such as
+// JWTType or AccessTokenType, when the caller's header has none.
+func WithDefaultType(typ string) Option {
+	return func(sv *JOSESignerVerifier) error {
+		if normalizeMediaType(typ) == "" {
+			return fmt.Errorf("Invalid token type %q", typ)
+		}
+
+		sv.defaultType = typ
+		return nil
+	}
+}
+
+// signerHeaderParameter is a header parameter set from the signer.
+type signerHeaderParameter struct {
+	name  string
+	value string
+	// conflicts is whether a different value in the caller's header is an
+	// error, rather than taking precedence.
+	conflicts bool
+}
+
+// populateHeader sets the 'alg' of a JSON encoded JOSE header to the
+// signer's algorithm, and its 'typ' and 'kid' as configured, returning an
+// error if the caller's header conflicts with the signer. Parameters are
+// added at the start of the header, and the header is otherwise unchanged.
+func (sv *JOSESignerVerifier) populateHeader(joseHeader []byte) ([]byte, error) {
+	names, values, err := decodeHeaderMembers(joseHeader)
+	if nil != err {
+		return nil, err
+	}
+
+	parameters := []signerHeaderParameter{
+		{name: "alg", value: string(sv.algorithm), conflicts: true},
+		{name: "typ", value: sv.defaultType},
+		{name: "kid", value: sv.keyID, conflicts: true},
+	}
+
+	var added []string
+	var addedValues []json.RawMessage
+	changed := false
+	for _, parameter := range parameters {
+		if parameter.value == "" {
+			continue
+		}
+		value, _ := json.Marshal(parameter.value)
+
+		found := false
+		for i, name := range names {
+			if name != parameter.name {
+				continue
+			}
+			found = true
+
+			var current string
+			if err := json.Unmarshal(values[i], &current); nil != err {
+				return nil, fmt.Errorf("Header parameter %q must be a string", name)
+			}
+			if current == "" {
+				values[i] = value
+				changed = true
+			} else if parameter.conflicts && current != parameter.value {
+				return nil, fmt.Errorf("Header parameter %q is %q, conflicting with the signer's %q", name, current, parameter.value)
+			}
+		}
+
+		if !found {
+			added = append(added, parameter.name)
+			addedValues = append(addedValues, value)
+			changed = true
+		}
+	}
+
+	if !changed {
+		return joseHeader, nil
+	}
+
+	return encodeHeaderMembers(append(added, names...), append(addedValues, values...)), nil
+}
+
+// decodeHeaderMembers returns the names and values of the members of a
+// JSON encoded header, in order.
+func decodeHeaderMembers(joseHeader []byte) ([]string, []json.RawMessage, error) {
+	decoder := json.NewDecoder(bytes.NewReader(joseHeader))
+	if t, err := decoder.Token(); nil != err || t != json.Delim('{') {
+		return nil, nil, errors.New("JOSE header must be a JSON object")
+	}
+
+	var names []string
+	var values []json.RawMessage
+	for decoder.More() {
+		t, err := decoder.Token()
+		if nil != err {
+			return nil, nil, err
+		}
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); nil != err {
+			return nil, nil, err
+		}
+
+		names = append(names, t.(string))
+		values = append(values, value)
+	}
+
+	return names, values, nil
+}
+
+// encodeHeaderMembers encodes the members as a JSON object, in order.
+func encodeHeaderMembers(names []string, values []json.RawMessage) []byte {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		encodedName, _ := json.Marshal(name)
+		buf.Write(encodedName)
+		buf.WriteByte(':')
+		buf.Write(values[i])
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes()
+}
@@ -0,0 +1,71 @@
+package jwt
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestGenerateToken_PopulateHeader(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []Option
+		header     interface{}
+		wantHeader string
+		wantErr    bool
+	}{
+		{"Must set a missing algorithm", nil, struct{}{}, `{"alg":"HS256"}`, false},
+		{"Must set an empty algorithm", nil, Header{Type: "JWT"}, `{"alg":"HS256","typ":"JWT"}`, false},
+		{"Must keep a matching algorithm", nil, Header{Algorithm: "HS256", KeyID: "a"}, `{"alg":"HS256","kid":"a"}`, false},
+		{"Must reject a conflicting algorithm", nil, Header{Algorithm: "RS256"}, "", true},
+		{"Must reject a non-object header", nil, "HS256", "", true},
+		{"Must reject a non-string algorithm", nil, map[string]int{"alg": 1}, "", true},
+		{"Must set the key ID", []Option{WithKeyID("k1")}, Header{Algorithm: "HS256"}, `{"kid":"k1","alg":"HS256"}`, false},
+		{"Must keep a matching key ID", []Option{WithKeyID("k1")}, Header{Algorithm: "HS256", KeyID: "k1"}, `{"alg":"HS256","kid":"k1"}`, false},
+		{"Must reject a conflicting key ID", []Option{WithKeyID("k1")}, Header{Algorithm: "HS256", KeyID: "k2"}, "", true},
+		{"Must set the default type", []Option{WithDefaultType(JWTType)}, Header{Algorithm: "HS256"}, `{"typ":"JWT","alg":"HS256"}`, false},
+		{"Must keep the caller's type", []Option{WithDefaultType(JWTType)}, Header{Algorithm: "HS256", Type: AccessTokenType}, `{"alg":"HS256","typ":"at+jwt"}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sv, err := NewJOSESignerVerifier(HS256, exampleKey, tt.opts...)
+			if nil != err {
+				t.Fatalf("NewJOSESignerVerifier() error = %v", err)
+			}
+
+			token, err := sv.GenerateToken(tt.header, Claims{Subject: "alice"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GenerateToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			header, _ := Base64URLDecode(strings.Split(string(token), ".")[0])
+			if string(header) != tt.wantHeader {
+				t.Errorf("GenerateToken() header = %s, want %s", header, tt.wantHeader)
+			}
+			if _, valid, err := sv.VerifySignature(token); !valid || nil != err {
+				t.Errorf("VerifySignature() = %v, %v", valid, err)
+			}
+		})
+	}
+}
+
+func TestIssueBatch_PopulateHeader(t *testing.T) {
+	sv, _ := NewJOSESignerVerifier(HS256, exampleKey, WithKeyID("k1"))
+
+	results := sv.IssueBatch(json.RawMessage(`{}`), []IssueRequest{{Claims: Claims{Subject: "alice"}}})
+	if nil != results[0].Err {
+		t.Fatalf("IssueBatch() error = %v", results[0].Err)
+	}
+	header, _ := Base64URLDecode(strings.Split(string(results[0].Token), ".")[0])
+	if string(header) != `{"alg":"HS256","kid":"k1"}` {
+		t.Errorf("IssueBatch() header = %s", header)
+	}
+
+	results = sv.IssueBatch(Header{Algorithm: "ES256"}, []IssueRequest{{Claims: Claims{Subject: "alice"}}})
+	if nil == results[0].Err {
+		t.Errorf("IssueBatch() expected error for a conflicting algorithm")
+	}
+}
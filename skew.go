@@ -0,0 +1,83 @@
+package jwt
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Counters of time-based claim validation failures, see ClockSkewFailures.
+var (
+	notBeforeFailures  uint64
+	expirationFailures uint64
+)
+
+// ClockSkewCounts are the number of tokens rejected because of their
+// Not Before ('nbf') and Expiration ('exp') claims.
+type ClockSkewCounts struct {
+	NotBefore  uint64
+	Expiration uint64
+}
+
+// ClockSkewFailures returns the number of tokens rejected by time-based
+// claim validation since the process started. A rising NotBefore count in
+// particular points at clock drift between issuers and this host, since
+// tokens should never arrive before they were issued.
+func ClockSkewFailures() ClockSkewCounts {
+	return ClockSkewCounts{
+		NotBefore:  atomic.LoadUint64(&notBeforeFailures),
+		Expiration: atomic.LoadUint64(&expirationFailures),
+	}
+}
+
+// ClockSkewError is returned when a token is rejected because of its
+// Not Before ('nbf') or Expiration ('exp') claim. It records the times
+// compared, so NTP drift can be told apart from genuinely expired tokens.
+type ClockSkewError struct {
+	// Claim is the claim that failed validation, "nbf" or "exp".
+	Claim string
+
+	// TokenTime is the time in the claim, ServerTime the time it was
+	// validated against.
+	TokenTime  time.Time
+	ServerTime time.Time
+
+	// Leeway is the leeway that was applied.
+	Leeway time.Duration
+
+	// Skew is ServerTime - TokenTime. It is negative for tokens that are
+	// not yet valid, and positive for tokens that have expired.
+	Skew time.Duration
+}
+
+func (e *ClockSkewError) Error() string {
+	if e.Claim == "nbf" {
+		return fmt.Sprintf(
+			"Token is not valid for another %v (nbf %v, server time %v, leeway %v)",
+			-e.Skew-e.Leeway, e.TokenTime.UTC().Format(time.RFC3339), e.ServerTime.UTC().Format(time.RFC3339), e.Leeway,
+		)
+	}
+
+	return fmt.Sprintf(
+		"Token expired %v ago (exp %v, server time %v, leeway %v)",
+		e.Skew-e.Leeway, e.TokenTime.UTC().Format(time.RFC3339), e.ServerTime.UTC().Format(time.RFC3339), e.Leeway,
+	)
+}
+
+// newClockSkewError records a time-based claim validation failure.
+func newClockSkewError(claim string, tokenTime *NumericDate, serverTime time.Time, leeway time.Duration) error {
+	if claim == "nbf" {
+		atomic.AddUint64(&notBeforeFailures, 1)
+	} else {
+		atomic.AddUint64(&expirationFailures, 1)
+	}
+
+	t := tokenTime.Time()
+	return &ClockSkewError{
+		Claim:      claim,
+		TokenTime:  t,
+		ServerTime: serverTime,
+		Leeway:     leeway,
+		Skew:       serverTime.Sub(t),
+	}
+}
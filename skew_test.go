@@ -0,0 +1,59 @@
+package jwt
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClaims_ValidateRegisteredClaims_ClockSkew(t *testing.T) {
+	serverTime := time.Unix(1600000000, 0)
+	at := func(d time.Duration) *NumericDate {
+		return NewNumericDate(serverTime.Add(d))
+	}
+
+	tests := []struct {
+		name      string
+		claims    Claims
+		wantClaim string
+		wantSkew  time.Duration
+	}{
+		{"Must report the skew of an expired token", Claims{Expiration: at(-90 * time.Second)}, "exp", 90 * time.Second},
+		{"Must report the skew of a token that is not yet valid", Claims{NotBefore: at(2 * time.Minute)}, "nbf", -2 * time.Minute},
+		{"Must accept a token within leeway", Claims{Expiration: at(-20 * time.Second), NotBefore: at(20 * time.Second)}, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := ClockSkewFailures()
+
+			valid, err := tt.claims.ValidateRegisteredClaims(&ValidationClaims{
+				Expiration:       serverTime,
+				ExpirationLeeway: 30 * time.Second,
+				NotBefore:        serverTime,
+				NotBeforeLeeway:  30 * time.Second,
+			})
+
+			after := ClockSkewFailures()
+
+			if tt.wantClaim == "" {
+				if !valid || nil != err {
+					t.Errorf("ValidateRegisteredClaims() = %v, %v, want valid", valid, err)
+				}
+				if after != before {
+					t.Errorf("ClockSkewFailures() changed from %+v to %+v", before, after)
+				}
+				return
+			}
+
+			skewErr, ok := err.(*ClockSkewError)
+			if valid || !ok {
+				t.Fatalf("ValidateRegisteredClaims() = %v, %v, want a ClockSkewError", valid, err)
+			}
+			if skewErr.Claim != tt.wantClaim || skewErr.Skew != tt.wantSkew || skewErr.Leeway != 30*time.Second {
+				t.Errorf("ValidateRegisteredClaims() error = %+v, want claim %v with skew %v", skewErr, tt.wantClaim, tt.wantSkew)
+			}
+			if after.NotBefore+after.Expiration != before.NotBefore+before.Expiration+1 {
+				t.Errorf("ClockSkewFailures() changed from %+v to %+v, want one more failure", before, after)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/georgejenkins/jwt/jwk"
+)
+
+// DefaultSVIDTTL is the lifetime of a JWT-SVID minted without an explicit TTL.
+// Workload identities are refreshed continuously, so they should be short lived.
+const DefaultSVIDTTL = 5 * time.Minute
+
+// SVIDKeyUse is the 'use' of the JWT authority keys in a SPIFFE trust
+// bundle, which sign JWT-SVIDs.
+const SVIDKeyUse = "jwt-svid"
+
+// SPIFFEID is a SPIFFE workload identifier, spiffe://<trust domain><path>.
+type SPIFFEID struct {
+	TrustDomain string
+	Path        string
+}
+
+// ParseSPIFFEID parses and validates a SPIFFE ID as per the SPIFFE ID
+// specification.
+func ParseSPIFFEID(id string) (SPIFFEID, error) {
+	const scheme = "spiffe://"
+
+	if !strings.HasPrefix(id, scheme) {
+		return SPIFFEID{}, fmt.Errorf("SPIFFE ID %q must use the spiffe scheme", id)
+	}
+
+	rest := id[len(scheme):]
+	trustDomain, path := rest, ""
+	if i := strings.IndexByte(rest, '/'); i >= 0 {
+		trustDomain, path = rest[:i], rest[i:]
+	}
+
+	if trustDomain == "" {
+		return SPIFFEID{}, fmt.Errorf("SPIFFE ID %q has no trust domain", id)
+	}
+
+	for _, c := range trustDomain {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '.' || c == '-' || c == '_') {
+			return SPIFFEID{}, fmt.Errorf("SPIFFE ID %q has an invalid trust domain character %q", id, c)
+		}
+	}
+
+	if path != "" {
+		for _, segment := range strings.Split(path[1:], "/") {
+			if segment == "" || segment == "." || segment == ".." {
+				return SPIFFEID{}, fmt.Errorf("SPIFFE ID %q has an invalid path segment %q", id, segment)
+			}
+
+			for _, c := range segment {
+				if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '.' || c == '-' || c == '_') {
+					return SPIFFEID{}, fmt.Errorf("SPIFFE ID %q has an invalid path character %q", id, c)
+				}
+			}
+		}
+	}
+
+	return SPIFFEID{
+		TrustDomain: trustDomain,
+		Path:        path,
+	}, nil
+}
+
+// String returns the SPIFFE ID in its URI form.
+func (id SPIFFEID) String() string {
+	return "spiffe://" + id.TrustDomain + id.Path
+}
+
+// MintSVID mints a JWT-SVID identifying the workload to the audience. If
+// ttl is not positive, DefaultSVIDTTL is used.
+func (sv *JOSESignerVerifier) MintSVID(id SPIFFEID, audience string, ttl time.Duration) ([]byte, error) {
+	if err := validateSVIDAlgorithm(sv.algorithm); nil != err {
+		return nil, err
+	}
+
+	if _, err := ParseSPIFFEID(id.String()); nil != err {
+		return nil, err
+	}
+
+	if audience == "" {
+		return nil, errors.New("JWT-SVIDs must have an audience")
+	}
+
+	if ttl <= 0 {
+		ttl = DefaultSVIDTTL
+	}
+
+	now := time.Now()
+	return sv.GenerateToken(
+		Header{
+			Algorithm: string(sv.algorithm),
+			Type:      "JWT",
+		},
+		Claims{
+			Subject:    id.String(),
+			Audience:   audience,
+			IssuedAt:   NewNumericDate(now),
+			Expiration: NewNumericDate(now.Add(ttl)),
+		},
+	)
+}
+
+// VerifySVID verifies a JWT-SVID was issued to a workload in the trust
+// domain for the audience, and returns the workload's SPIFFE ID.
+func (sv *JOSESignerVerifier) VerifySVID(rawToken []byte, audience string, trustDomain string) (SPIFFEID, *Token, error) {
+	if err := validateSVIDAlgorithm(sv.algorithm); nil != err {
+		return SPIFFEID{}, nil, err
+	}
+
+	token, signatureValid, err := sv.VerifySignature(rawToken)
+	if nil != err {
+		return SPIFFEID{}, nil, err
+	}
+	if !signatureValid {
+		return SPIFFEID{}, nil, errors.New("JWT-SVID signature is invalid")
+	}
+
+	var claims Claims
+	err = GetClaims(token, &claims)
+	if nil != err {
+		return SPIFFEID{}, token, err
+	}
+	token.RegisteredClaims = claims
+
+	if nil == claims.Expiration {
+		return SPIFFEID{}, token, errors.New("JWT-SVIDs must have an expiration")
+	}
+
+	now := time.Now()
+	if valid, err := claims.VerifyExpiration(now, 0); !valid || nil != err {
+		return SPIFFEID{}, token, errors.New("JWT-SVID has expired")
+	}
+
+	if valid, err := claims.VerifyNotBefore(now, 0); !valid || nil != err {
+		return SPIFFEID{}, token, errors.New("JWT-SVID is not yet valid")
+	}
+
+	if claims.Audience == "" || !claims.VerifyAudience([]string{audience}) {
+		return SPIFFEID{}, token, fmt.Errorf("JWT-SVID was not issued for audience %q", audience)
+	}
+
+	id, err := ParseSPIFFEID(claims.Subject)
+	if nil != err {
+		return SPIFFEID{}, token, err
+	}
+
+	if id.TrustDomain != trustDomain {
+		return SPIFFEID{}, token, fmt.Errorf("JWT-SVID belongs to trust domain %q, expected %q", id.TrustDomain, trustDomain)
+	}
+
+	return id, token, nil
+}
+
+// NewSVIDBundleVerifier creates a JOSESignerVerifier verifying JWT-SVIDs,
+// with VerifySVID, against the JWT authorities of a SPIFFE trust bundle,
+// parsed as a JWK Set. Each JWT-SVID is verified with the bundle key its
+// 'kid' header names, which must be a JWT authority (use "jwt-svid") and
+// must not use a symmetric algorithm. The verifier can't mint JWT-SVIDs.
+func NewSVIDBundleVerifier(bundle *jwk.Set, opts ...Option) (*JOSESignerVerifier, error) {
+	if nil == bundle {
+		return nil, errors.New("SPIFFE trust bundle cannot be nil")
+	}
+
+	resolver := KeyResolverFunc(func(header Header) (*jwk.Key, error) {
+		if err := validateSVIDAlgorithm(Algorithm(header.Algorithm)); nil != err {
+			return nil, err
+		}
+		if header.KeyID == "" {
+			return nil, errors.New("JWT-SVIDs verified against a trust bundle must have a key ID")
+		}
+
+		keys := bundle.Find(header.KeyID, Algorithm(header.Algorithm), SVIDKeyUse)
+		if len(keys) == 0 {
+			return nil, ErrUnknownKeyID
+		}
+
+		return keys[0], nil
+	})
+
+	return NewKeyResolvingVerifier(resolver, opts...)
+}
+
+// validateSVIDAlgorithm rejects algorithms the JWT-SVID specification
+// doesn't allow: JWT-SVIDs must be signed with an asymmetric key.
+func validateSVIDAlgorithm(alg Algorithm) error {
+	switch alg {
+	case HS256, HS384, HS512, None:
+		return fmt.Errorf("JWT-SVIDs cannot use the %s algorithm", alg)
+	}
+
+	return nil
+}
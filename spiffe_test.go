@@ -0,0 +1,151 @@
+package jwt
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/georgejenkins/jwt/jwk"
+)
+
+func TestParseSPIFFEID(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      string
+		want    SPIFFEID
+		wantErr bool
+	}{
+		{"Must parse a SPIFFE ID with a path", "spiffe://kaer-morhen.example/ns/witchers/sa/geralt", SPIFFEID{"kaer-morhen.example", "/ns/witchers/sa/geralt"}, false},
+		{"Must parse a SPIFFE ID without a path", "spiffe://kaer-morhen.example", SPIFFEID{"kaer-morhen.example", ""}, false},
+		{"Must fail given another scheme", "https://kaer-morhen.example/geralt", SPIFFEID{}, true},
+		{"Must fail given an upper case trust domain", "spiffe://Kaer-Morhen.example/geralt", SPIFFEID{}, true},
+		{"Must fail given a port", "spiffe://kaer-morhen.example:443/geralt", SPIFFEID{}, true},
+		{"Must fail given an empty path segment", "spiffe://kaer-morhen.example//geralt", SPIFFEID{}, true},
+		{"Must fail given a dot segment", "spiffe://kaer-morhen.example/../geralt", SPIFFEID{}, true},
+		{"Must fail given a trailing slash", "spiffe://kaer-morhen.example/geralt/", SPIFFEID{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSPIFFEID(tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseSPIFFEID() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSPIFFEID() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJOSESignerVerifier_MintSVID_VerifySVID(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	sv, err := NewJOSESignerVerifier(ES256, key)
+	if nil != err {
+		t.Fatalf("NewJOSESignerVerifier() error = %v", err)
+	}
+
+	id, _ := ParseSPIFFEID("spiffe://kaer-morhen.example/ns/witchers/sa/geralt")
+	svid, err := sv.MintSVID(id, "spiffe://kaer-morhen.example/bestiary", 0)
+	if nil != err {
+		t.Fatalf("MintSVID() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		audience    string
+		trustDomain string
+		wantErr     bool
+	}{
+		{"Must verify the JWT-SVID", "spiffe://kaer-morhen.example/bestiary", "kaer-morhen.example", false},
+		{"Must fail given another audience", "spiffe://kaer-morhen.example/armoury", "kaer-morhen.example", true},
+		{"Must fail given another trust domain", "spiffe://kaer-morhen.example/bestiary", "novigrad.example", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := sv.VerifySVID(svid, tt.audience, tt.trustDomain)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifySVID() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != id {
+				t.Errorf("VerifySVID() = %v, want %v", got, id)
+			}
+		})
+	}
+
+	hmacSV, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	if _, err := hmacSV.MintSVID(id, "spiffe://kaer-morhen.example/bestiary", time.Minute); nil == err {
+		t.Errorf("MintSVID() expected error for HS256")
+	}
+}
+
+func TestNewSVIDBundleVerifier(t *testing.T) {
+	authority, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	x509Authority, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	publicKey, _ := jwk.Marshal(&authority.PublicKey)
+	x509Key, _ := jwk.Marshal(&x509Authority.PublicKey)
+
+	var member map[string]interface{}
+	json.Unmarshal(publicKey, &member)
+	member["kid"], member["use"] = "authority", SVIDKeyUse
+	publicKey, _ = json.Marshal(member)
+	json.Unmarshal(x509Key, &member)
+	member["kid"], member["use"] = "x509", "x509-svid"
+	x509Key, _ = json.Marshal(member)
+
+	bundle, err := jwk.ParseSet([]byte(`{"spiffe_sequence":1,"spiffe_refresh_hint":300,"keys":[` + string(publicKey) + `,` + string(x509Key) + `]}`))
+	if nil != err {
+		t.Fatalf("ParseSet() error = %v", err)
+	}
+	bundle.Keys = append(bundle.Keys, &jwk.Key{KeyID: "shared", Use: SVIDKeyUse, Key: exampleKey})
+
+	verifier, err := NewSVIDBundleVerifier(bundle)
+	if nil != err {
+		t.Fatalf("NewSVIDBundleVerifier() error = %v", err)
+	}
+
+	id, _ := ParseSPIFFEID("spiffe://kaer-morhen.example/ns/witchers/sa/geralt")
+	audience := "spiffe://kaer-morhen.example/bestiary"
+	mint := func(key *ecdsa.PrivateKey, kid string) []byte {
+		sv, _ := NewJOSESignerVerifier(ES256, key, WithKeyID(kid))
+		svid, err := sv.MintSVID(id, audience, 0)
+		if nil != err {
+			t.Fatalf("MintSVID() error = %v", err)
+		}
+		return svid
+	}
+	hmacSV, _ := NewJOSESignerVerifier(HS256, exampleKey, WithKeyID("shared"))
+	hmacSVID, _ := hmacSV.GenerateToken(Header{}, Claims{Subject: id.String(), Audience: audience, Expiration: NewNumericDate(time.Now().Add(time.Minute))})
+
+	tests := []struct {
+		name    string
+		svid    []byte
+		wantErr bool
+	}{
+		{"Must verify a JWT-SVID signed by a JWT authority", mint(authority, "authority"), false},
+		{"Must fail given an unknown key ID", mint(authority, "retired"), true},
+		{"Must fail given a key of another authority", mint(x509Authority, "authority"), true},
+		{"Must fail given an X.509 authority", mint(x509Authority, "x509"), true},
+		{"Must fail given a symmetric algorithm", hmacSVID, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := verifier.VerifySVID(tt.svid, audience, "kaer-morhen.example")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("VerifySVID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != id {
+				t.Errorf("VerifySVID() = %v, want %v", got, id)
+			}
+		})
+	}
+
+	if _, err := NewSVIDBundleVerifier(nil); nil == err {
+		t.Errorf("NewSVIDBundleVerifier() expected error given no bundle")
+	}
+}
@@ -0,0 +1,147 @@
+package jwt
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// tokenDigestLength is the number of SHA-256 bytes used to bind token
+// chunks together. 16 bytes is plenty to detect mixed or tampered chunks,
+// since the reassembled token is still signature verified.
+const tokenDigestLength = 16
+
+// SplitToken splits a token into chunks of at most maxChunkSize bytes,
+// for transports with header or cookie size limits.
+//
+// Each chunk has the form "<index>.<count>.<digest>.<data>", where digest
+// is a truncated SHA-256 of the whole token. Every chunk carries the same
+// digest, binding the chunks to each other and to the reassembled token.
+func SplitToken(rawToken []byte, maxChunkSize int) ([]string, error) {
+	if len(rawToken) == 0 {
+		return nil, errors.New("Cannot split an empty token")
+	}
+
+	digest := tokenDigest(rawToken)
+
+	// The prefix length depends on the number of chunks, so size the chunks
+	// for the widest prefix we could need.
+	overhead := len(digest) + 2*len(strconv.Itoa(len(rawToken))) + 3
+	dataSize := maxChunkSize - overhead
+	if dataSize <= 0 {
+		return nil, fmt.Errorf("Chunk size %d is too small, must be larger than %d", maxChunkSize, overhead)
+	}
+
+	count := (len(rawToken) + dataSize - 1) / dataSize
+	chunks := make([]string, 0, count)
+
+	for i := 0; i < count; i++ {
+		end := (i + 1) * dataSize
+		if end > len(rawToken) {
+			end = len(rawToken)
+		}
+
+		chunks = append(chunks, fmt.Sprintf("%d.%d.%s.%s", i, count, digest, rawToken[i*dataSize:end]))
+	}
+
+	return chunks, nil
+}
+
+// JoinTokenChunks reassembles a token split by SplitToken. Chunks may be
+// provided in any order. An error is returned if any chunk is missing,
+// duplicated, belongs to another token or has been modified.
+//
+// The reassembled token is NOT verified; use VerifyTokenChunks, or pass
+// the result to VerifyToken.
+func JoinTokenChunks(chunks []string) ([]byte, error) {
+	if len(chunks) == 0 {
+		return nil, errors.New("No token chunks provided")
+	}
+
+	var digest string
+	parts := make([]string, len(chunks))
+
+	for _, chunk := range chunks {
+		fields := strings.SplitN(chunk, ".", 4)
+		if len(fields) != 4 {
+			return nil, errors.New("Token chunk is malformed")
+		}
+
+		index, err := strconv.Atoi(fields[0])
+		if nil != err {
+			return nil, fmt.Errorf("Token chunk index is malformed: %s", err)
+		}
+
+		count, err := strconv.Atoi(fields[1])
+		if nil != err {
+			return nil, fmt.Errorf("Token chunk count is malformed: %s", err)
+		}
+
+		if count != len(chunks) {
+			return nil, fmt.Errorf("Expected %d token chunks, received %d", count, len(chunks))
+		}
+
+		if index < 0 || index >= count {
+			return nil, fmt.Errorf("Token chunk index %d out of range", index)
+		}
+
+		if digest == "" {
+			digest = fields[2]
+		} else if digest != fields[2] {
+			return nil, errors.New("Token chunks belong to different tokens")
+		}
+
+		if parts[index] != "" {
+			return nil, fmt.Errorf("Duplicate token chunk %d", index)
+		}
+		parts[index] = fields[3]
+	}
+
+	rawToken := []byte(strings.Join(parts, ""))
+	if subtle.ConstantTimeCompare([]byte(tokenDigest(rawToken)), []byte(digest)) != 1 {
+		return nil, errors.New("Reassembled token does not match the chunk digest")
+	}
+
+	return rawToken, nil
+}
+
+// SetTokenChunks sets each chunk as a numbered header, e.g. for the name
+// "X-Auth-Token", headers "X-Auth-Token-0" to "X-Auth-Token-<n-1>".
+func SetTokenChunks(header http.Header, name string, chunks []string) {
+	for i, chunk := range chunks {
+		header.Set(fmt.Sprintf("%s-%d", name, i), chunk)
+	}
+}
+
+// GetTokenChunks collects the numbered headers written by SetTokenChunks.
+func GetTokenChunks(header http.Header, name string) []string {
+	var chunks []string
+
+	for i := 0; ; i++ {
+		chunk := header.Get(fmt.Sprintf("%s-%d", name, i))
+		if chunk == "" {
+			return chunks
+		}
+		chunks = append(chunks, chunk)
+	}
+}
+
+// VerifyTokenChunks reassembles a split token and verifies it with
+// VerifyToken.
+func (sv *JOSESignerVerifier) VerifyTokenChunks(chunks []string, validationCriteria *ValidationClaims) (*Token, bool, error) {
+	rawToken, err := JoinTokenChunks(chunks)
+	if nil != err {
+		return nil, false, err
+	}
+
+	return sv.VerifyToken(rawToken, validationCriteria)
+}
+
+func tokenDigest(rawToken []byte) string {
+	sum := sha256.Sum256(rawToken)
+	return Base64URLEncode(sum[:tokenDigestLength])
+}
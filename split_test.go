@@ -0,0 +1,91 @@
+package jwt
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSplitToken_JoinTokenChunks(t *testing.T) {
+	rawToken := []byte(strings.Repeat(string(examplePayload), 20))
+
+	chunks, err := SplitToken(rawToken, 256)
+	if nil != err {
+		t.Fatalf("SplitToken() error = %v", err)
+	}
+
+	for _, chunk := range chunks {
+		if len(chunk) > 256 {
+			t.Errorf("SplitToken() chunk length %d exceeds 256", len(chunk))
+		}
+	}
+
+	reversed := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		reversed[len(chunks)-1-i] = chunk
+	}
+
+	tampered := append([]string{}, chunks...)
+	tampered[1] = tampered[1][:len(tampered[1])-1] + "x"
+
+	otherChunks, _ := SplitToken(append(rawToken, 'x'), 256)
+	mixed := append([]string{}, chunks...)
+	mixed[0] = otherChunks[0]
+
+	tests := []struct {
+		name    string
+		chunks  []string
+		want    []byte
+		wantErr bool
+	}{
+		{"Must reassemble chunks in order", chunks, rawToken, false},
+		{"Must reassemble chunks out of order", reversed, rawToken, false},
+		{"Must fail given a missing chunk", chunks[1:], nil, true},
+		{"Must fail given a tampered chunk", tampered, nil, true},
+		{"Must fail given chunks from different tokens", mixed, nil, true},
+		{"Must fail given no chunks", nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JoinTokenChunks(tt.chunks)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("JoinTokenChunks() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("JoinTokenChunks() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJOSESignerVerifier_VerifyTokenChunks(t *testing.T) {
+	sv, err := NewJOSESignerVerifier(HS256, exampleKey)
+	if nil != err {
+		t.Fatalf("NewJOSESignerVerifier() error = %v", err)
+	}
+
+	subject := strings.Repeat("ciri", 100)
+	rawToken, err := sv.GenerateToken(Header{Algorithm: string(HS256)}, Claims{Subject: subject})
+	if nil != err {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	chunks, err := SplitToken(rawToken, 128)
+	if nil != err {
+		t.Fatalf("SplitToken() error = %v", err)
+	}
+
+	header := http.Header{}
+	SetTokenChunks(header, "X-Auth-Token", chunks)
+
+	_, valid, err := sv.VerifyTokenChunks(GetTokenChunks(header, "X-Auth-Token"), &ValidationClaims{
+		Subject:    []string{subject},
+		Expiration: time.Now(),
+	})
+	if nil != err || !valid {
+		t.Errorf("VerifyTokenChunks() = %v, %v, want valid", valid, err)
+	}
+}
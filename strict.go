@@ -0,0 +1,207 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// StrictVerifier verifies tokens following the JSON Web Token Best Current
+// Practices (RFC 8725). Every token must:
+//
+//   - be signed with the single algorithm the verifier was created for,
+//     never 'none'
+//   - have a header and claim set that are well-formed JSON objects with no
+//     duplicate members or trailing data
+//   - carry 'iss', 'aud' and 'exp' claims matching the validation criteria
+//   - have a lifetime no longer than the configured maximum TTL
+type StrictVerifier struct {
+	sv       *JOSESignerVerifier
+	criteria ValidationClaims
+	maxTTL   time.Duration
+}
+
+// NewStrictVerifier creates a StrictVerifier for an algorithm and key, as
+// accepted by NewJOSESignerVerifier. The validation criteria must name the
+// expected issuers and audiences, and maxTTL bounds the lifetime of
+// accepted tokens.
+func NewStrictVerifier(alg Algorithm, key interface{}, criteria ValidationClaims, maxTTL time.Duration, opts ...Option) (*StrictVerifier, error) {
+	if alg == None {
+		return nil, errors.New("Strict verification cannot use the 'none' algorithm")
+	}
+
+	if len(criteria.Issuer) == 0 {
+		return nil, errors.New("Strict verification requires expected issuers")
+	}
+
+	if len(criteria.Audience) == 0 {
+		return nil, errors.New("Strict verification requires expected audiences")
+	}
+
+	if maxTTL <= 0 {
+		return nil, errors.New("Strict verification requires a positive maximum TTL")
+	}
+
+	sv, err := NewJOSESignerVerifier(alg, key, opts...)
+	if nil != err {
+		return nil, err
+	}
+
+	return &StrictVerifier{
+		sv:       sv,
+		criteria: criteria,
+		maxTTL:   maxTTL,
+	}, nil
+}
+
+// Verify verifies the token and its claims. The token is only valid if
+// no error is returned.
+func (v *StrictVerifier) Verify(rawToken []byte) (*Token, error) {
+	token, err := GetRawTokenParts(rawToken)
+	if nil != err {
+		return nil, err
+	}
+
+	if len(token.RawSignature) == 0 {
+		return nil, errors.New("Strict verification requires a signed token")
+	}
+
+	if err := checkStrictJSON(token.DecodedHeader); nil != err {
+		return nil, fmt.Errorf("Invalid token header: %v", err)
+	}
+
+	if err := checkStrictJSON(token.DecodedBody); nil != err {
+		return nil, fmt.Errorf("Invalid token claims: %v", err)
+	}
+
+	var header Header
+	if err := GetHeader(token, &header); nil != err {
+		return nil, err
+	}
+
+	if Algorithm(header.Algorithm) != v.sv.algorithm {
+		return nil, fmt.Errorf("Token algorithm %q is not allowed, expected %q", header.Algorithm, v.sv.algorithm)
+	}
+
+	token, valid, err := v.sv.VerifyToken(rawToken, &v.criteria)
+	if nil != err {
+		return nil, err
+	}
+	if !valid {
+		return nil, errors.New("Token is invalid")
+	}
+
+	claims := token.RegisteredClaims
+	if claims.Issuer == "" {
+		return nil, errors.New("Token has no issuer")
+	}
+
+	if claims.Audience == "" {
+		return nil, errors.New("Token has no audience")
+	}
+
+	if nil == claims.Expiration {
+		return nil, errors.New("Token has no expiration")
+	}
+
+	if err := v.checkTTL(claims); nil != err {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// checkTTL checks the lifetime of the token is within the maximum TTL,
+// measured from the issue time when present, or otherwise from now. An
+// issue time in the future is measured from now, so it can't be used to
+// stretch the lifetime.
+func (v *StrictVerifier) checkTTL(claims Claims) error {
+	issuedAt := time.Now()
+	if !v.criteria.Expiration.IsZero() {
+		issuedAt = v.criteria.Expiration
+	}
+	if nil != claims.IssuedAt && claims.IssuedAt.Time().Before(issuedAt) {
+		issuedAt = claims.IssuedAt.Time()
+	}
+
+	if ttl := claims.Expiration.Time().Sub(issuedAt); ttl > v.maxTTL {
+		return fmt.Errorf("Token TTL %v exceeds the maximum of %v", ttl, v.maxTTL)
+	}
+
+	return nil
+}
+
+// checkStrictJSON checks data is a single JSON object, with no duplicate
+// member names at any depth.
+func checkStrictJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+
+	t, err := decoder.Token()
+	if nil != err {
+		return err
+	}
+	if delim, ok := t.(json.Delim); !ok || delim != '{' {
+		return errors.New("JSON value must be an object")
+	}
+
+	if err := checkStrictObject(decoder); nil != err {
+		return err
+	}
+
+	if _, err := decoder.Token(); err != io.EOF {
+		return errors.New("Unexpected data after JSON object")
+	}
+
+	return nil
+}
+
+// checkStrictObject checks the members of an object whose opening brace
+// has been read, up to and including the closing brace.
+func checkStrictObject(decoder *json.Decoder) error {
+	names := make(map[string]bool)
+	for decoder.More() {
+		t, err := decoder.Token()
+		if nil != err {
+			return err
+		}
+
+		name := t.(string)
+		if names[name] {
+			return fmt.Errorf("Duplicate JSON member %q", name)
+		}
+		names[name] = true
+
+		if err := checkStrictValue(decoder); nil != err {
+			return err
+		}
+	}
+
+	_, err := decoder.Token()
+	return err
+}
+
+// checkStrictValue checks the next JSON value.
+func checkStrictValue(decoder *json.Decoder) error {
+	t, err := decoder.Token()
+	if nil != err {
+		return err
+	}
+
+	switch t {
+	case json.Delim('{'):
+		return checkStrictObject(decoder)
+	case json.Delim('['):
+		for decoder.More() {
+			if err := checkStrictValue(decoder); nil != err {
+				return err
+			}
+		}
+		_, err = decoder.Token()
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,128 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Strict JSON decoding rules, configured with RejectDuplicateKeys,
+// RejectUnknownHeaders and RequireObjectPayload.
+const (
+	rejectDuplicateKeys = 1 << iota
+	rejectUnknownHeaders
+	requireObjectPayload
+)
+
+// RejectDuplicateKeys rejects tokens whose header or payload has duplicate
+// JSON member names at any depth, or data after the JSON value. Decoders
+// disagree on which duplicate wins, so a header or claim may be read one
+// way when checked and another when used.
+func RejectDuplicateKeys() Option {
+	return func(sv *JOSESignerVerifier) error {
+		sv.strictJSON |= rejectDuplicateKeys
+		return nil
+	}
+}
+
+// RejectUnknownHeaders rejects tokens with header parameters other than
+// those registered for JWSs, 'b64', those with a handler registered by
+// WithCriticalHeader, 'nonce' for verifiers with WithNonceValidator, and
+// the names given. Unknown parameters, such as 'jwk' or 'x5u' variants a
+// library might act on, are rejected before they are trusted anywhere.
+func RejectUnknownHeaders(names ...string) Option {
+	return func(sv *JOSESignerVerifier) error {
+		sv.strictJSON |= rejectUnknownHeaders
+
+		if nil == sv.knownHeaders {
+			sv.knownHeaders = make(map[string]bool)
+		}
+		for _, name := range names {
+			sv.knownHeaders[name] = true
+		}
+		return nil
+	}
+}
+
+// RequireObjectPayload rejects compact tokens whose payload is not a JSON
+// object, as the claim set of a JWT must be (RFC 7519, section 7.2).
+func RequireObjectPayload() Option {
+	return func(sv *JOSESignerVerifier) error {
+		sv.strictJSON |= requireObjectPayload
+		return nil
+	}
+}
+
+// checkStrictHeader applies the strict JSON decoding rules to the decoded
+// header.
+func (sv *JOSESignerVerifier) checkStrictHeader(decodedHeader []byte) error {
+	if sv.strictJSON&rejectDuplicateKeys != 0 {
+		if err := checkDuplicateKeys(decodedHeader); nil != err {
+			return fmt.Errorf("Invalid token header: %v", err)
+		}
+	}
+
+	if sv.strictJSON&rejectUnknownHeaders != 0 {
+		var parameters map[string]json.RawMessage
+		if err := json.Unmarshal(decodedHeader, &parameters); nil != err {
+			return err
+		}
+
+		for name := range parameters {
+			if !sv.knownHeader(name) {
+				return fmt.Errorf("Unknown header parameter %q", name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkStrictPayload applies the strict JSON decoding rules to the decoded
+// payload.
+func (sv *JOSESignerVerifier) checkStrictPayload(decodedBody []byte) error {
+	if sv.strictJSON&requireObjectPayload != 0 {
+		var claims map[string]json.RawMessage
+		if err := json.Unmarshal(decodedBody, &claims); nil != err || nil == claims {
+			return errors.New("Invalid token claims: JSON value must be an object")
+		}
+	}
+
+	if sv.strictJSON&rejectDuplicateKeys != 0 {
+		if err := checkDuplicateKeys(decodedBody); nil != err {
+			return fmt.Errorf("Invalid token claims: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// knownHeader reports whether the header parameter is understood by the
+// verifier.
+func (sv *JOSESignerVerifier) knownHeader(name string) bool {
+	if registeredHeaderParameters[name] || name == "b64" || sv.knownHeaders[name] {
+		return true
+	}
+	if _, ok := sv.criticalHeaders[name]; ok {
+		return true
+	}
+
+	return name == "nonce" && nil != sv.nonceValidator
+}
+
+// checkDuplicateKeys checks data is a single JSON value, with no duplicate
+// member names at any depth.
+func checkDuplicateKeys(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if err := checkStrictValue(decoder); nil != err {
+		return err
+	}
+
+	if _, err := decoder.Token(); err != io.EOF {
+		return errors.New("Unexpected data after JSON value")
+	}
+
+	return nil
+}
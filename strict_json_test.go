@@ -0,0 +1,52 @@
+package jwt
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestStrictJSONDecoding(t *testing.T) {
+	signer, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	sign := func(header, body string) []byte {
+		token, err := signer.GenerateToken(json.RawMessage(header), json.RawMessage(body))
+		if nil != err {
+			t.Fatalf("GenerateToken() error = %v", err)
+		}
+		return token
+	}
+
+	tests := []struct {
+		name      string
+		opts      []Option
+		token     []byte
+		wantValid bool
+	}{
+		{"Must verify duplicate keys by default", nil, sign(`{"alg":"HS256","kid":"a","kid":"b"}`, `{"sub":"alice"}`), true},
+		{"Must verify a well-formed token", []Option{RejectDuplicateKeys()}, sign(`{"alg":"HS256"}`, `{"sub":"alice","roles":{"a":1,"b":2}}`), true},
+		{"Must reject duplicate header keys", []Option{RejectDuplicateKeys()}, sign(`{"alg":"HS256","typ":"JWT","typ":"at+jwt"}`, `{"sub":"alice"}`), false},
+		{"Must reject duplicate claims", []Option{RejectDuplicateKeys()}, sign(`{"alg":"HS256"}`, `{"sub":"alice","sub":"bob"}`), false},
+		{"Must reject nested duplicate claims", []Option{RejectDuplicateKeys()}, sign(`{"alg":"HS256"}`, `{"sub":"alice","cnf":{"jkt":"a","jkt":"b"}}`), false},
+		{"Must verify unknown headers by default", nil, sign(`{"alg":"HS256","x-tenant":"a"}`, `{"sub":"alice"}`), true},
+		{"Must verify registered headers", []Option{RejectUnknownHeaders()}, sign(`{"alg":"HS256","typ":"JWT","kid":"a"}`, `{"sub":"alice"}`), true},
+		{"Must verify named headers", []Option{RejectUnknownHeaders("x-tenant")}, sign(`{"alg":"HS256","x-tenant":"a"}`, `{"sub":"alice"}`), true},
+		{"Must reject unknown headers", []Option{RejectUnknownHeaders("x-tenant")}, sign(`{"alg":"HS256","x-region":"a"}`, `{"sub":"alice"}`), false},
+		{"Must reject a nonce without a nonce validator", []Option{RejectUnknownHeaders()}, sign(`{"alg":"HS256","nonce":"a"}`, `{"sub":"alice"}`), false},
+		{"Must verify non-object payloads by default", nil, sign(`{"alg":"HS256"}`, `["alice"]`), true},
+		{"Must verify an object payload", []Option{RequireObjectPayload()}, sign(`{"alg":"HS256"}`, `{"sub":"alice"}`), true},
+		{"Must reject an array payload", []Option{RequireObjectPayload()}, sign(`{"alg":"HS256"}`, `["alice"]`), false},
+		{"Must reject a null payload", []Option{RequireObjectPayload()}, sign(`{"alg":"HS256"}`, `null`), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sv, err := NewJOSESignerVerifier(HS256, exampleKey, tt.opts...)
+			if nil != err {
+				t.Fatalf("NewJOSESignerVerifier() error = %v", err)
+			}
+
+			_, valid, err := sv.VerifySignature(tt.token)
+			if valid != tt.wantValid || (nil == err) != tt.wantValid {
+				t.Errorf("VerifySignature() = %v, %v, want valid %v", valid, err, tt.wantValid)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package jwt
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestStrictVerifier_Verify(t *testing.T) {
+	criteria := ValidationClaims{
+		Issuer:   []string{"https://issuer.example.com"},
+		Subject:  []string{"alice"},
+		Audience: []string{"api"},
+	}
+	v, err := NewStrictVerifier(HS256, exampleKey, criteria, time.Hour)
+	if nil != err {
+		t.Fatalf("NewStrictVerifier() error = %v", err)
+	}
+
+	hs256, _ := NewJOSESignerVerifier(HS256, exampleKey)
+	hs384, _ := NewJOSESignerVerifier(HS384, exampleKey)
+	none, _ := NewInsecureJOSESignerVerifier(None)
+
+	now := time.Now()
+	unix := func(d time.Duration) *NumericDate {
+		return NewNumericDate(now.Add(d))
+	}
+	valid := Claims{
+		Issuer:     "https://issuer.example.com",
+		Subject:    "alice",
+		Audience:   "api",
+		IssuedAt:   unix(0),
+		Expiration: unix(30 * time.Minute),
+	}
+	with := func(modify func(c *Claims)) Claims {
+		c := valid
+		modify(&c)
+		return c
+	}
+
+	type args struct {
+		sv     *JOSESignerVerifier
+		header Header
+		claims interface{}
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{"Must verify a token following best practices", args{hs256, Header{Algorithm: string(HS256)}, valid}, false},
+		{"Must reject an unsigned token", args{none, Header{Algorithm: string(None)}, valid}, true},
+		{"Must reject an unexpected algorithm", args{hs384, Header{Algorithm: string(HS384)}, valid}, true},
+		{"Must reject a token without an issuer", args{hs256, Header{Algorithm: string(HS256)}, with(func(c *Claims) { c.Issuer = "" })}, true},
+		{"Must reject a token without an audience", args{hs256, Header{Algorithm: string(HS256)}, with(func(c *Claims) { c.Audience = "" })}, true},
+		{"Must reject a token for another audience", args{hs256, Header{Algorithm: string(HS256)}, with(func(c *Claims) { c.Audience = "other" })}, true},
+		{"Must reject a token without an expiration", args{hs256, Header{Algorithm: string(HS256)}, with(func(c *Claims) { c.Expiration = nil })}, true},
+		{"Must reject a token exceeding the maximum TTL", args{hs256, Header{Algorithm: string(HS256)}, with(func(c *Claims) { c.Expiration = unix(2 * time.Hour) })}, true},
+		{"Must reject a future issue time stretching the maximum TTL", args{hs256, Header{Algorithm: string(HS256)}, with(func(c *Claims) { c.IssuedAt, c.Expiration = unix(3*time.Hour), unix(3*time.Hour+30*time.Minute) })}, true},
+		{
+			"Must reject duplicate claims",
+			args{hs256, Header{Algorithm: string(HS256)}, json.RawMessage(`{"iss":"https://issuer.example.com","sub":"alice","aud":"api","exp":` + strconv.FormatInt(int64(*unix(time.Minute)), 10) + `,"aud":"api"}`)},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := tt.args.sv.GenerateToken(tt.args.header, tt.args.claims)
+			if nil != err {
+				t.Fatalf("GenerateToken() error = %v", err)
+			}
+
+			if _, err := v.Verify(token); (err != nil) != tt.wantErr {
+				t.Errorf("StrictVerifier.Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewStrictVerifier(t *testing.T) {
+	criteria := ValidationClaims{
+		Issuer:   []string{"https://issuer.example.com"},
+		Audience: []string{"api"},
+	}
+
+	type args struct {
+		alg      Algorithm
+		criteria ValidationClaims
+		maxTTL   time.Duration
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{"Must create a strict verifier", args{HS256, criteria, time.Hour}, false},
+		{"Must not create a strict verifier for none", args{None, criteria, time.Hour}, true},
+		{"Must require expected issuers", args{HS256, ValidationClaims{Audience: []string{"api"}}, time.Hour}, true},
+		{"Must require expected audiences", args{HS256, ValidationClaims{Issuer: []string{"iss"}}, time.Hour}, true},
+		{"Must require a maximum TTL", args{HS256, criteria, 0}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewStrictVerifier(tt.args.alg, exampleKey, tt.args.criteria, tt.args.maxTTL); (err != nil) != tt.wantErr {
+				t.Errorf("NewStrictVerifier() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// SubTokenClaims are the claims of a sub-token derived by Attenuate.
+type SubTokenClaims struct {
+	Claims
+
+	// Scope is the space separated list of scopes granted, a subset of the
+	// parent's.
+	Scope string `json:"scope,omitempty"`
+
+	// ParentJTI is the JWT ID of the parent token, so sub-tokens can be
+	// traced to, and revoked with, their parent.
+	ParentJTI string `json:"parent_jti"`
+}
+
+// Scopes returns the scopes granted by the sub-token.
+func (c SubTokenClaims) Scopes() []string {
+	return strings.Fields(c.Scope)
+}
+
+// Attenuation narrows the permissions of a sub-token. Zero fields keep
+// the parent's value.
+type Attenuation struct {
+	// Issuer identifies the local service issuing the sub-token.
+	Issuer string
+
+	// Scopes granted, which must all be granted by the parent.
+	Scopes []string
+
+	// TTL of the sub-token. The sub-token never outlives its parent.
+	TTL time.Duration
+
+	// Audience restricts the sub-token to a single audience. If the parent
+	// has an audience, it must match.
+	Audience string
+}
+
+// Attenuate derives a sub-token from a verified parent token, granting at
+// most the parent's scopes, audience and lifetime, for least privilege
+// delegation between services. The sub-token is signed by sv, keeps the
+// parent's subject, and links to the parent with a 'parent_jti' claim, so
+// the parent must have a JWT ID. Sub-tokens carry a JWT ID of their own,
+// so they can be attenuated in turn.
+func (sv *JOSESignerVerifier) Attenuate(parent *Token, attenuation Attenuation) ([]byte, error) {
+	if nil == parent || !parent.signatureValid {
+		return nil, errors.New("Sub-tokens can only be derived from a verified parent token")
+	}
+
+	var parentClaims SubTokenClaims
+	if err := json.Unmarshal(parent.DecodedBody, &parentClaims); nil != err {
+		return nil, err
+	}
+	if parentClaims.JWTID == "" {
+		return nil, errors.New("Sub-tokens require a parent token with a JWT ID")
+	}
+
+	now := time.Now()
+	if valid, err := parentClaims.VerifyExpiration(now, 0); !valid || nil != err {
+		return nil, errors.New("Sub-tokens cannot be derived from an expired parent token")
+	}
+
+	scope, err := attenuateScope(parentClaims.Scopes(), attenuation.Scopes)
+	if nil != err {
+		return nil, err
+	}
+
+	audience := parentClaims.Audience
+	if attenuation.Audience != "" {
+		if audience != "" && audience != attenuation.Audience {
+			return nil, fmt.Errorf("Sub-token audience %q is not the parent's audience", attenuation.Audience)
+		}
+		audience = attenuation.Audience
+	}
+
+	expiration, err := attenuateExpiration(parentClaims.Expiration, now, attenuation.TTL)
+	if nil != err {
+		return nil, err
+	}
+
+	jti, err := randomJTI()
+	if nil != err {
+		return nil, err
+	}
+
+	return sv.GenerateToken(
+		Header{
+			Algorithm: string(sv.algorithm),
+			Type:      "JWT",
+		},
+		SubTokenClaims{
+			Claims: Claims{
+				Issuer:     attenuation.Issuer,
+				Subject:    parentClaims.Subject,
+				Audience:   audience,
+				IssuedAt:   NewNumericDate(now),
+				Expiration: expiration,
+				JWTID:      jti,
+			},
+			Scope:     scope,
+			ParentJTI: parentClaims.JWTID,
+		},
+	)
+}
+
+// attenuateScope returns the requested scopes, or the parent's if none are
+// requested, failing if any is not granted by the parent.
+func attenuateScope(parent []string, requested []string) (string, error) {
+	if len(requested) == 0 {
+		return strings.Join(parent, " "), nil
+	}
+
+	for _, scope := range requested {
+		if !anyEquals(parent, scope) {
+			return "", fmt.Errorf("Scope %q is not granted by the parent token", scope)
+		}
+	}
+
+	return strings.Join(requested, " "), nil
+}
+
+// attenuateExpiration returns the earlier of the parent's expiration and
+// now plus ttl. Sub-tokens must expire, so either must be set.
+func attenuateExpiration(parent *NumericDate, now time.Time, ttl time.Duration) (*NumericDate, error) {
+	if nil == parent && ttl <= 0 {
+		return nil, errors.New("Sub-tokens of parent tokens without an expiration require a TTL")
+	}
+	if ttl <= 0 {
+		return parent, nil
+	}
+
+	expiration := NewNumericDate(now.Add(ttl))
+	if nil != parent && *parent < *expiration {
+		expiration = parent
+	}
+
+	return expiration, nil
+}
+
+// randomJTI returns a random JWT ID.
+func randomJTI() (string, error) {
+	b := make([]byte, MinimumNonceLength)
+	if _, err := io.ReadFull(rand.Reader, b); nil != err {
+		return "", fmt.Errorf("Cannot read random bytes for JWT ID: %s", err)
+	}
+
+	return Base64URLEncode(b), nil
+}
@@ -0,0 +1,88 @@
+package jwt
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestJOSESignerVerifier_Attenuate(t *testing.T) {
+	upstream, _ := NewJOSESignerVerifier(ES256, mustGenerateP256())
+	local, _ := NewJOSESignerVerifier(ES256, mustGenerateP256())
+
+	now := time.Now()
+	parentExpiration := now.Add(time.Hour).Unix()
+	parentToken := func(claims SubTokenClaims) *Token {
+		raw, _ := upstream.GenerateToken(Header{Algorithm: string(ES256)}, claims)
+		token, valid, err := upstream.VerifyToken(raw, nil)
+		if !valid || nil != err {
+			t.Fatalf("VerifyToken() = %v, %v for the parent token", valid, err)
+		}
+		return token
+	}
+	parent := parentToken(SubTokenClaims{
+		Claims: Claims{
+			Subject:    "alice",
+			Audience:   "orders",
+			Expiration: numericDate(parentExpiration),
+			JWTID:      "parent-1",
+		},
+		Scope: "orders:read orders:write",
+	})
+	unverified, _ := GetRawTokenParts(parent.RawToken)
+	expired := parentToken(SubTokenClaims{Claims: Claims{JWTID: "parent-2"}, Scope: "orders:read"})
+	expired.DecodedBody = []byte(`{"jti":"parent-2","exp":"` + strconv.FormatInt(now.Add(-time.Minute).Unix(), 10) + `"}`)
+
+	tests := []struct {
+		name           string
+		parent         *Token
+		attenuation    Attenuation
+		wantScope      string
+		wantAudience   string
+		wantExpiration int64
+		wantErr        bool
+	}{
+		{"Must keep the parent's permissions without attenuation", parent, Attenuation{}, "orders:read orders:write", "orders", parentExpiration, false},
+		{"Must narrow the scopes and lifetime", parent, Attenuation{Scopes: []string{"orders:read"}, TTL: time.Minute}, "orders:read", "orders", now.Add(time.Minute).Unix(), false},
+		{"Must not outlive the parent", parent, Attenuation{TTL: 2 * time.Hour}, "orders:read orders:write", "orders", parentExpiration, false},
+		{"Must not grant scopes the parent lacks", parent, Attenuation{Scopes: []string{"orders:read", "orders:delete"}}, "", "", 0, true},
+		{"Must not change the parent's audience", parent, Attenuation{Audience: "billing"}, "", "", 0, true},
+		{"Must restrict a parent without an audience", parentToken(SubTokenClaims{Claims: Claims{JWTID: "parent-3"}}), Attenuation{Audience: "billing", TTL: time.Minute}, "", "billing", now.Add(time.Minute).Unix(), false},
+		{"Must require a TTL for a parent without an expiration", parentToken(SubTokenClaims{Claims: Claims{JWTID: "parent-4"}}), Attenuation{}, "", "", 0, true},
+		{"Must require a parent JWT ID", parentToken(SubTokenClaims{Claims: Claims{Subject: "alice"}}), Attenuation{TTL: time.Minute}, "", "", 0, true},
+		{"Must not derive from an expired parent", expired, Attenuation{}, "", "", 0, true},
+		{"Must not derive from an unverified parent", unverified, Attenuation{}, "", "", 0, true},
+		{"Must not derive from a nil parent", nil, Attenuation{}, "", "", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := local.Attenuate(tt.parent, tt.attenuation)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("JOSESignerVerifier.Attenuate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			token, valid, err := local.VerifyToken(raw, nil)
+			if !valid || nil != err {
+				t.Fatalf("VerifyToken() = %v, %v for the sub-token", valid, err)
+			}
+			var claims SubTokenClaims
+			GetClaims(token, &claims)
+
+			if claims.Scope != tt.wantScope {
+				t.Errorf("SubTokenClaims.Scope = %q, want %q", claims.Scope, tt.wantScope)
+			}
+			if claims.Audience != tt.wantAudience {
+				t.Errorf("SubTokenClaims.Audience = %q, want %q", claims.Audience, tt.wantAudience)
+			}
+			if expiration := int64(*claims.Expiration); expiration < tt.wantExpiration-1 || expiration > tt.wantExpiration+1 {
+				t.Errorf("SubTokenClaims.Expiration = %d, want %d", expiration, tt.wantExpiration)
+			}
+			if claims.ParentJTI != tt.parent.RegisteredClaims.JWTID || claims.JWTID == "" || claims.JWTID == claims.ParentJTI {
+				t.Errorf("SubTokenClaims JWT IDs = %q, parent %q", claims.JWTID, claims.ParentJTI)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// IssueContext carries the request fields claim templates may reference,
+// e.g. {{.Subject}} or {{join .Scopes " "}}.
+type IssueContext struct {
+	Subject  string
+	ClientID string
+	Scopes   []string
+	Extra    map[string]string
+}
+
+// Claim template value types.
+const (
+	ClaimTypeString  = "string"
+	ClaimTypeInt     = "int"
+	ClaimTypeBool    = "bool"
+	ClaimTypeStrings = "strings"
+)
+
+// ClaimTemplateField configures a single claim of a ClaimTemplate.
+//
+// Template is a text/template rendered against the IssueContext. The
+// rendered text is coerced into Type: "string" (the default), "int",
+// "bool", or "strings", a whitespace separated list.
+type ClaimTemplateField struct {
+	Template string `json:"template"`
+	Type     string `json:"type,omitempty"`
+}
+
+// ClaimTemplate renders the claim set of a token type from an
+// IssueContext, so token types can be defined in configuration.
+//
+// Rendered values are always coerced to JSON strings, numbers, booleans
+// or string arrays before the claim set is encoded, so request fields
+// can't inject additional JSON structure into the claims.
+type ClaimTemplate struct {
+	fields map[string]claimTemplateField
+}
+
+type claimTemplateField struct {
+	template  *template.Template
+	claimType string
+}
+
+var claimTemplateFuncs = template.FuncMap{
+	"join": strings.Join,
+	"has": func(values []string, value string) bool {
+		return anyEquals(values, value)
+	},
+}
+
+// ParseClaimTemplate parses a JSON claim template configuration of the
+// form {"<claim>": {"template": "...", "type": "..."}}.
+func ParseClaimTemplate(config []byte) (*ClaimTemplate, error) {
+	var fields map[string]ClaimTemplateField
+	if err := json.Unmarshal(config, &fields); nil != err {
+		return nil, err
+	}
+
+	return NewClaimTemplate(fields)
+}
+
+// NewClaimTemplate compiles a ClaimTemplate from its fields.
+func NewClaimTemplate(fields map[string]ClaimTemplateField) (*ClaimTemplate, error) {
+	compiled := make(map[string]claimTemplateField, len(fields))
+
+	for claim, field := range fields {
+		claimType := field.Type
+		if claimType == "" {
+			claimType = ClaimTypeString
+		}
+
+		switch claimType {
+		case ClaimTypeString, ClaimTypeInt, ClaimTypeBool, ClaimTypeStrings:
+		default:
+			return nil, fmt.Errorf("Claim %q has unknown type %q", claim, field.Type)
+		}
+
+		tmpl, err := template.New(claim).
+			Option("missingkey=error").
+			Funcs(claimTemplateFuncs).
+			Parse(field.Template)
+		if nil != err {
+			return nil, fmt.Errorf("Cannot parse template for claim %q: %s", claim, err)
+		}
+
+		compiled[claim] = claimTemplateField{
+			template:  tmpl,
+			claimType: claimType,
+		}
+	}
+
+	return &ClaimTemplate{fields: compiled}, nil
+}
+
+// Render renders the claim set for the context.
+func (t *ClaimTemplate) Render(ctx IssueContext) (map[string]interface{}, error) {
+	claims := make(map[string]interface{}, len(t.fields))
+
+	for claim, field := range t.fields {
+		var rendered bytes.Buffer
+		if err := field.template.Execute(&rendered, ctx); nil != err {
+			return nil, fmt.Errorf("Cannot render claim %q: %s", claim, err)
+		}
+
+		value, err := coerceClaim(rendered.String(), field.claimType)
+		if nil != err {
+			return nil, fmt.Errorf("Cannot render claim %q: %s", claim, err)
+		}
+
+		claims[claim] = value
+	}
+
+	return claims, nil
+}
+
+// IssueFromTemplate renders the template for the context and generates
+// a signed token from the resulting claims.
+func (sv *JOSESignerVerifier) IssueFromTemplate(t *ClaimTemplate, ctx IssueContext) ([]byte, error) {
+	claims, err := t.Render(ctx)
+	if nil != err {
+		return nil, err
+	}
+
+	return sv.GenerateToken(
+		Header{
+			Algorithm: string(sv.algorithm),
+			Type:      "JWT",
+		},
+		claims,
+	)
+}
+
+func coerceClaim(rendered string, claimType string) (interface{}, error) {
+	switch claimType {
+	case ClaimTypeInt:
+		return strconv.ParseInt(strings.TrimSpace(rendered), 10, 64)
+	case ClaimTypeBool:
+		return strconv.ParseBool(strings.TrimSpace(rendered))
+	case ClaimTypeStrings:
+		return strings.Fields(rendered), nil
+	}
+
+	return rendered, nil
+}
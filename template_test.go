@@ -0,0 +1,76 @@
+package jwt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClaimTemplate_Render(t *testing.T) {
+	ctx := IssueContext{
+		Subject:  `geralt", "admin": true, "x": "`,
+		ClientID: "bestiary",
+		Scopes:   []string{"read", "write"},
+		Extra:    map[string]string{"level": "42"},
+	}
+
+	tests := []struct {
+		name    string
+		config  string
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			"Must render and coerce claims",
+			`{
+				"sub":   {"template": "{{.Subject}}"},
+				"azp":   {"template": "{{.ClientID}}"},
+				"scope": {"template": "{{join .Scopes \" \"}}"},
+				"scp":   {"template": "{{join .Scopes \" \"}}", "type": "strings"},
+				"write": {"template": "{{has .Scopes \"write\"}}", "type": "bool"},
+				"level": {"template": "{{index .Extra \"level\"}}", "type": "int"}
+			}`,
+			map[string]interface{}{
+				"sub":   `geralt", "admin": true, "x": "`,
+				"azp":   "bestiary",
+				"scope": "read write",
+				"scp":   []string{"read", "write"},
+				"write": true,
+				"level": int64(42),
+			},
+			false,
+		},
+		{
+			"Must fail given a value that can't be coerced",
+			`{"level": {"template": "{{.Subject}}", "type": "int"}}`,
+			nil,
+			true,
+		},
+		{
+			"Must fail given a missing context field",
+			`{"tenant": {"template": "{{.Tenant}}"}}`,
+			nil,
+			true,
+		},
+		{
+			"Must fail given an unknown type",
+			`{"sub": {"template": "{{.Subject}}", "type": "object"}}`,
+			nil,
+			true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := ParseClaimTemplate([]byte(tt.config))
+			if nil == err {
+				var got map[string]interface{}
+				got, err = tmpl.Render(ctx)
+				if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("ClaimTemplate.Render() = %v, want %v", got, tt.want)
+				}
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ClaimTemplate.Render() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
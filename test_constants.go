@@ -1,7 +1,14 @@
-package main
+package jwt
 
 // Example payload to be used for signature testing
 var plaintext = []byte("The Blue Stripes will ambush Radovid on the bridge to Temple Isle")
 
 // Example payload to be used for invalid signature testing
 var incorrectPlaintext = []byte("Long live Radovid the Stern!")
+
+// Key and Payload from RFC 7515 - Example JWS Using HMAC SHA-256
+// https://tools.ietf.org/html/rfc7515#appendix-A.1.1
+var exampleKey = []byte{3, 35, 53, 75, 43, 15, 165, 188, 131, 126, 6, 101, 119, 123, 166, 143, 90, 179, 40, 230, 240, 84, 201, 40, 169, 15, 132, 178, 210, 80,
+	46, 191, 211, 251, 90, 146, 210, 6, 71, 239, 150, 138, 180, 195, 119, 98, 61, 34, 61, 46, 33, 114, 5, 46, 79, 8, 192, 205, 154, 245, 103, 208, 128, 163}
+
+var examplePayload = []byte("eyJ0eXAiOiJKV1QiLA0KICJhbGciOiJIUzI1NiJ9.eyJpc3MiOiJqb2UiLA0KICJleHAiOjEzMDA4MTkzODAsDQogImh0dHA6Ly9leGFtcGxlLmNvbS9pc19yb290Ijp0cnVlfQ")
@@ -0,0 +1,234 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+)
+
+// throughputCalibrationTime is how long each operation is run for when
+// estimating throughput.
+const throughputCalibrationTime = 100 * time.Millisecond
+
+// Throughput is the estimated rate at which a single core of this machine
+// signs and verifies tokens with an algorithm, key size and payload size,
+// measured by EstimateThroughput.
+type Throughput struct {
+	Algorithm   Algorithm
+	KeySize     int
+	PayloadSize int
+
+	// SignsPerSecond and VerifiesPerSecond are per core, and SignLatency
+	// and VerifyLatency the time a single operation takes.
+	SignsPerSecond    float64
+	VerifiesPerSecond float64
+	SignLatency       time.Duration
+	VerifyLatency     time.Duration
+}
+
+// VerifyingCores returns the number of cores needed to verify tokens at
+// the rate, in tokens per second. The estimate covers signature
+// verification alone, so fleets should be sized with headroom for the
+// rest of the request.
+func (t *Throughput) VerifyingCores(tokensPerSecond float64) float64 {
+	return tokensPerSecond / t.VerifiesPerSecond
+}
+
+// SigningCores returns the number of cores needed to sign tokens at the
+// rate, in tokens per second.
+func (t *Throughput) SigningCores(tokensPerSecond float64) float64 {
+	return tokensPerSecond / t.SignsPerSecond
+}
+
+type throughputKey struct {
+	alg         Algorithm
+	keySize     int
+	payloadSize int
+}
+
+var throughputEstimates struct {
+	mu        sync.Mutex
+	estimates map[throughputKey]*Throughput
+}
+
+// EstimateThroughput measures how fast a single core signs and verifies
+// tokens with the algorithm, a key of keySize bits and a claim set of
+// about payloadSize bytes, so platform teams can size verification
+// fleets and compare algorithms from data. A keySize of 0 selects the key
+// size GenerateKey uses. Each estimate runs the operations for a fraction
+// of a second on first use, and is cached for the life of the process; run
+// it at startup or from a capacity planning tool rather than while
+// serving.
+func EstimateThroughput(alg Algorithm, keySize int, payloadSize int) (*Throughput, error) {
+	if payloadSize < 0 {
+		return nil, errors.New("Payload size cannot be negative")
+	}
+
+	keySize, err := throughputKeySize(alg, keySize)
+	if nil != err {
+		return nil, err
+	}
+
+	cacheKey := throughputKey{alg: alg, keySize: keySize, payloadSize: payloadSize}
+	throughputEstimates.mu.Lock()
+	defer throughputEstimates.mu.Unlock()
+
+	if estimate, ok := throughputEstimates.estimates[cacheKey]; ok {
+		copied := *estimate
+		return &copied, nil
+	}
+
+	key, err := generateThroughputKey(alg, keySize)
+	if nil != err {
+		return nil, err
+	}
+	estimate, err := measureThroughput(alg, key, payloadSize)
+	if nil != err {
+		return nil, err
+	}
+	estimate.KeySize = keySize
+
+	if nil == throughputEstimates.estimates {
+		throughputEstimates.estimates = make(map[throughputKey]*Throughput)
+	}
+	throughputEstimates.estimates[cacheKey] = estimate
+
+	copied := *estimate
+	return &copied, nil
+}
+
+// throughputKeySize returns the key size in bits, or the default for the
+// algorithm if 0, failing if the algorithm can't use a key of that size.
+func throughputKeySize(alg Algorithm, keySize int) (int, error) {
+	var defaultSize, minimumSize int
+	switch alg {
+	case RS256, RS384, RS512, PS256, PS384, PS512:
+		defaultSize, minimumSize = GeneratedRSAKeySize, 2048
+	case HS256:
+		defaultSize, minimumSize = 256, 256
+	case HS384:
+		defaultSize, minimumSize = 384, 384
+	case HS512:
+		defaultSize, minimumSize = 512, 512
+	case ES256, EdDSA:
+		defaultSize = 256
+	case ES384:
+		defaultSize = 384
+	case ES512:
+		defaultSize = 521
+	default:
+		return 0, fmt.Errorf("Cannot estimate throughput for algorithm %q", alg)
+	}
+
+	if keySize == 0 {
+		return defaultSize, nil
+	}
+	if minimumSize == 0 && keySize != defaultSize {
+		return 0, fmt.Errorf("Algorithm %s requires a %d bit key", alg, defaultSize)
+	}
+	if keySize < minimumSize || keySize%8 != 0 {
+		return 0, fmt.Errorf("Algorithm %s requires a key of at least %d bits, in whole bytes", alg, minimumSize)
+	}
+
+	return keySize, nil
+}
+
+// generateThroughputKey generates a key of keySize bits for the algorithm.
+func generateThroughputKey(alg Algorithm, keySize int) (interface{}, error) {
+	switch alg {
+	case RS256, RS384, RS512, PS256, PS384, PS512:
+		return rsa.GenerateKey(rand.Reader, keySize)
+	case HS256, HS384, HS512:
+		return generateSecret(keySize / 8)
+	}
+
+	return generateKey(alg)
+}
+
+// measureThroughput measures signing and verifying tokens with a claim
+// set of payloadSize bytes. Signing is measured over a prepared signing
+// input, and verification from the compact token, each on one goroutine.
+func measureThroughput(alg Algorithm, key interface{}, payloadSize int) (*Throughput, error) {
+	sv, err := NewJOSESignerVerifier(alg, key)
+	if nil != err {
+		return nil, err
+	}
+
+	header, err := json.Marshal(Header{Algorithm: string(alg), Type: "JWT"})
+	if nil != err {
+		return nil, err
+	}
+	body, err := json.Marshal(map[string]string{"sub": strings.Repeat("a", payloadSize)})
+	if nil != err {
+		return nil, err
+	}
+	signingInput := appendWithDot(Base64URLEncode(header), Base64URLEncode(body))
+
+	signature, err := sv.signer.Sign(signingInput)
+	if nil != err {
+		return nil, err
+	}
+	rawToken := []byte(string(signingInput) + "." + Base64URLEncode(signature))
+
+	signLatency, err := measureOperation(func() error {
+		signature, err := sv.signer.Sign(signingInput)
+		Base64URLEncode(signature)
+		return err
+	})
+	if nil != err {
+		return nil, err
+	}
+
+	verifyLatency, err := measureOperation(func() error {
+		token, err := GetRawTokenParts(rawToken)
+		if nil != err {
+			return err
+		}
+		var header Header
+		if err := GetHeader(token, &header); nil != err {
+			return err
+		}
+		valid, err := sv.verifier.Verify(appendWithDot(token.RawHeader, token.RawBody), token.DecodedSignature)
+		if !valid && nil == err {
+			err = errors.New("Signature did not verify")
+		}
+		return err
+	})
+	if nil != err {
+		return nil, err
+	}
+
+	return &Throughput{
+		Algorithm:         alg,
+		PayloadSize:       payloadSize,
+		SignsPerSecond:    float64(time.Second) / float64(signLatency),
+		VerifiesPerSecond: float64(time.Second) / float64(verifyLatency),
+		SignLatency:       signLatency,
+		VerifyLatency:     verifyLatency,
+	}, nil
+}
+
+// measureOperation returns the mean time the operation takes, running it
+// in batches of doubling size until a batch takes the calibration time.
+func measureOperation(operation func() error) (time.Duration, error) {
+	runtime.GC()
+
+	for n := 1; ; n *= 2 {
+		start := time.Now()
+		for i := 0; i < n; i++ {
+			if err := operation(); nil != err {
+				return 0, err
+			}
+		}
+
+		if elapsed := time.Since(start); elapsed >= throughputCalibrationTime {
+			return elapsed / time.Duration(n), nil
+		}
+	}
+}
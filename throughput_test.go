@@ -0,0 +1,44 @@
+package jwt
+
+import "testing"
+
+func TestEstimateThroughput(t *testing.T) {
+	tests := []struct {
+		name        string
+		alg         Algorithm
+		keySize     int
+		payloadSize int
+		wantKeySize int
+		wantErr     bool
+	}{
+		{"Must estimate HS256 with the default key size", HS256, 0, 512, 256, false},
+		{"Must estimate ES256", ES256, 256, 512, 256, false},
+		{"Must not estimate ES256 with a P-384 key", ES256, 384, 512, 0, true},
+		{"Must not estimate RS256 with a 1024 bit key", RS256, 1024, 512, 0, true},
+		{"Must not estimate HS512 with a short key", HS512, 256, 512, 0, true},
+		{"Must not estimate none", None, 0, 512, 0, true},
+		{"Must not estimate a negative payload size", HS256, 0, -1, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EstimateThroughput(tt.alg, tt.keySize, tt.payloadSize)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EstimateThroughput() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.KeySize != tt.wantKeySize || got.SignsPerSecond <= 0 || got.VerifiesPerSecond <= 0 {
+				t.Errorf("EstimateThroughput() = %+v", got)
+			}
+			if cores := got.VerifyingCores(2 * got.VerifiesPerSecond); cores < 1.99 || cores > 2.01 {
+				t.Errorf("Throughput.VerifyingCores() = %v, want 2", cores)
+			}
+
+			cached, _ := EstimateThroughput(tt.alg, tt.keySize, tt.payloadSize)
+			if *cached != *got {
+				t.Errorf("EstimateThroughput() = %+v, want the cached %+v", cached, got)
+			}
+		})
+	}
+}
@@ -1,4 +1,4 @@
-package main
+package jwt
 
 // Token is a wrapper type for a JSON Web Signature token.
 type Token struct {
@@ -21,4 +21,7 @@ type Token struct {
 	// Internal validation flags
 	signatureValid bool
 	claimsValid    bool
+
+	// Provenance of the verification key
+	provenance *Provenance
 }
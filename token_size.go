@@ -0,0 +1,96 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Default limits applied by GetRawTokenParts, and by verifiers without
+// WithTokenSizeLimits. They leave room for JWSs with large attached
+// payloads, while bounding the memory used decoding a hostile token.
+// Verifiers of tokens carried in HTTP headers should set far lower limits.
+const (
+	DefaultMaxTokenSize     = 8 << 20
+	DefaultMaxTokenPartSize = 8 << 20
+)
+
+// TokenSizeLimits bounds the size of tokens accepted for verification,
+// which are checked before anything is decoded. A zero limit takes the
+// default.
+type TokenSizeLimits struct {
+	// MaxTokenSize is the maximum size, in bytes, of the compact token.
+	MaxTokenSize int
+	// MaxPartSize is the maximum size, in bytes, of each of the base64url
+	// encoded header, payload and signature.
+	MaxPartSize int
+}
+
+// TokenTooLargeError is returned for tokens exceeding their
+// TokenSizeLimits. Part is "header", "payload" or "signature", or empty
+// when the token as a whole exceeds the limit.
+type TokenTooLargeError struct {
+	Part  string
+	Size  int
+	Limit int
+}
+
+func (e *TokenTooLargeError) Error() string {
+	if e.Part == "" {
+		return fmt.Sprintf("Token is %d bytes, exceeding the limit of %d bytes", e.Size, e.Limit)
+	}
+
+	return fmt.Sprintf("Token %s is %d bytes, exceeding the limit of %d bytes", e.Part, e.Size, e.Limit)
+}
+
+// WithTokenSizeLimits rejects tokens exceeding limits with a
+// TokenTooLargeError, in place of the default limits.
+func WithTokenSizeLimits(limits TokenSizeLimits) Option {
+	return func(sv *JOSESignerVerifier) error {
+		if limits.MaxTokenSize < 0 || limits.MaxPartSize < 0 {
+			return errors.New("Token size limits cannot be negative")
+		}
+
+		sv.tokenSizeLimits = limits
+		return nil
+	}
+}
+
+// withDefaults returns the limits with zero limits set to the defaults.
+func (limits TokenSizeLimits) withDefaults() TokenSizeLimits {
+	if limits.MaxTokenSize == 0 {
+		limits.MaxTokenSize = DefaultMaxTokenSize
+	}
+	if limits.MaxPartSize == 0 {
+		limits.MaxPartSize = DefaultMaxTokenPartSize
+	}
+
+	return limits
+}
+
+// checkTokenSize checks the size of a compact token against the limits.
+func (limits TokenSizeLimits) checkTokenSize(rawToken []byte) error {
+	if len(rawToken) > limits.MaxTokenSize {
+		return &TokenTooLargeError{
+			Size:  len(rawToken),
+			Limit: limits.MaxTokenSize,
+		}
+	}
+
+	return nil
+}
+
+// checkPartSizes checks the size of the encoded parts of a token against
+// the limits.
+func (limits TokenSizeLimits) checkPartSizes(parts []string) error {
+	for i, part := range parts {
+		if len(part) > limits.MaxPartSize {
+			return &TokenTooLargeError{
+				Part:  []string{"header", "payload", "signature"}[i],
+				Size:  len(part),
+				Limit: limits.MaxPartSize,
+			}
+		}
+	}
+
+	return nil
+}
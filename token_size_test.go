@@ -0,0 +1,73 @@
+package jwt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGetRawTokenParts_Limits(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    string
+		wantErr  bool
+		wantPart string
+	}{
+		{"Must split a token", "eyJ9.eyJ9.c2ln", false, ""},
+		{"Must split an unsigned token", "eyJ9.eyJ9", false, ""},
+		{"Must reject a token without a '.'", "eyJ9", true, ""},
+		{"Must reject a token with more than two '.'", "eyJ9.eyJ9.c2ln.c2ln", true, ""},
+		{"Must reject a token over the size limit", strings.Repeat("a", DefaultMaxTokenSize+1), true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GetRawTokenParts([]byte(tt.token))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetRawTokenParts() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWithTokenSizeLimits(t *testing.T) {
+	if _, err := NewJOSESignerVerifier(HS256, exampleKey, WithTokenSizeLimits(TokenSizeLimits{MaxTokenSize: -1})); nil == err {
+		t.Errorf("WithTokenSizeLimits() expected error for a negative limit")
+	}
+
+	sv, err := NewJOSESignerVerifier(HS256, exampleKey, WithTokenSizeLimits(TokenSizeLimits{MaxTokenSize: 512, MaxPartSize: 256}))
+	if nil != err {
+		t.Fatalf("NewJOSESignerVerifier() error = %v", err)
+	}
+	token, _ := sv.GenerateToken(Header{Algorithm: string(HS256)}, Claims{Subject: "alice"})
+	largeBody, _ := sv.GenerateToken(Header{Algorithm: string(HS256)}, Claims{Subject: strings.Repeat("a", 256)})
+	largeHeader, _ := sv.GenerateToken(Header{Algorithm: string(HS256), KeyID: strings.Repeat("a", 256)}, Claims{Subject: "alice"})
+	largeToken, _ := sv.GenerateToken(Header{Algorithm: string(HS256), KeyID: strings.Repeat("a", 160)}, Claims{Subject: strings.Repeat("a", 160)})
+
+	tests := []struct {
+		name      string
+		token     []byte
+		wantValid bool
+		wantErr   *TokenTooLargeError
+	}{
+		{"Must verify a token within the limits", token, true, nil},
+		{"Must reject a payload over the part limit", largeBody, false, &TokenTooLargeError{Part: "payload", Size: len(strings.Split(string(largeBody), ".")[1]), Limit: 256}},
+		{"Must reject a header over the part limit", largeHeader, false, &TokenTooLargeError{Part: "header", Size: len(strings.Split(string(largeHeader), ".")[0]), Limit: 256}},
+		{"Must reject a token over the token limit", largeToken, false, &TokenTooLargeError{Size: len(largeToken), Limit: 512}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, valid, err := sv.VerifySignature(tt.token)
+			if valid != tt.wantValid {
+				t.Errorf("VerifySignature() valid = %v, want %v", valid, tt.wantValid)
+			}
+
+			var sizeErr *TokenTooLargeError
+			if errors.As(err, &sizeErr) != (nil != tt.wantErr) {
+				t.Fatalf("VerifySignature() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if nil != tt.wantErr && *sizeErr != *tt.wantErr {
+				t.Errorf("VerifySignature() error = %+v, want %+v", sizeErr, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package jwt
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// TTLBudget bounds the lifetime ('exp' - 'iat') of issued tokens.
+type TTLBudget struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+// TTLBudgetError is returned when a token's lifetime is outside of the
+// budget configured for its type.
+type TTLBudgetError struct {
+	TokenType string
+	TTL       time.Duration
+	Budget    TTLBudget
+}
+
+func (e *TTLBudgetError) Error() string {
+	return fmt.Sprintf(
+		"Token TTL %v for type %q is outside of the budget [%v, %v]",
+		e.TTL, e.TokenType, e.Budget.Min, e.Budget.Max,
+	)
+}
+
+// WithTTLBudget enforces a minimum and maximum lifetime on generated tokens
+// whose 'typ' header matches tokenType. A budget for the empty token type
+// applies to tokens with no more specific budget. A zero Min or Max is not
+// enforced; when Max is set, tokens must carry an expiration.
+//
+// Tokens outside of the budget are rejected with a TTLBudgetError, unless
+// WithTTLClamping is also provided.
+func WithTTLBudget(tokenType string, budget TTLBudget) Option {
+	return func(sv *JOSESignerVerifier) error {
+		if budget.Min < 0 || budget.Max < 0 || (budget.Max > 0 && budget.Min > budget.Max) {
+			return fmt.Errorf("Invalid TTL budget [%v, %v] for type %q", budget.Min, budget.Max, tokenType)
+		}
+
+		if nil == sv.ttlBudgets {
+			sv.ttlBudgets = make(map[string]TTLBudget)
+		}
+		sv.ttlBudgets[tokenType] = budget
+		return nil
+	}
+}
+
+// WithTTLClamping clamps the expiration of generated tokens into their TTL
+// budget rather than rejecting them.
+func WithTTLClamping() Option {
+	return func(sv *JOSESignerVerifier) error {
+		sv.clampTTL = true
+		return nil
+	}
+}
+
+// enforceTTLBudget applies the TTL budget for the token type to the claims,
+// returning the (possibly clamped) claims.
+func (sv *JOSESignerVerifier) enforceTTLBudget(joseHeader []byte, jwsPayload []byte) ([]byte, error) {
+	var header struct {
+		Type string `json:"typ"`
+	}
+	if err := json.Unmarshal(joseHeader, &header); nil != err {
+		return nil, err
+	}
+
+	budget, ok := sv.ttlBudgets[header.Type]
+	if !ok {
+		budget, ok = sv.ttlBudgets[""]
+	}
+	if !ok {
+		return jwsPayload, nil
+	}
+
+	claims, err := decodeClaimsMap(jwsPayload)
+	if nil != err {
+		return nil, err
+	}
+
+	// The TTL is measured from the issue time, unless that is in the
+	// future, so a forged 'iat' can't stretch the budget.
+	issuedAt := time.Now()
+	if iat, present, err := numericClaim(claims, "iat"); nil != err {
+		return nil, err
+	} else if present && time.Unix(iat, 0).Before(issuedAt) {
+		issuedAt = time.Unix(iat, 0)
+	}
+
+	exp, present, err := numericClaim(claims, "exp")
+	if nil != err {
+		return nil, err
+	}
+	if !present {
+		if budget.Max > 0 {
+			return nil, fmt.Errorf("Tokens of type %q must have an expiration", header.Type)
+		}
+		return jwsPayload, nil
+	}
+
+	ttl := time.Unix(exp, 0).Sub(issuedAt)
+	clamped := ttl
+	if budget.Max > 0 && ttl > budget.Max {
+		clamped = budget.Max
+	}
+	if ttl < budget.Min {
+		clamped = budget.Min
+	}
+
+	if clamped == ttl {
+		return jwsPayload, nil
+	}
+
+	if !sv.clampTTL {
+		return nil, &TTLBudgetError{
+			TokenType: header.Type,
+			TTL:       ttl,
+			Budget:    budget,
+		}
+	}
+
+	// Keep the claim in the representation it was provided in.
+	clampedExp := issuedAt.Add(clamped).Unix()
+	if _, isString := claims["exp"].(string); isString {
+		claims["exp"] = strconv.FormatInt(clampedExp, 10)
+	} else {
+		claims["exp"] = clampedExp
+	}
+
+	return json.Marshal(claims)
+}
+
+// numericClaim reads a NumericDate claim, which may be provided as a JSON
+// number or a string.
+func numericClaim(claims map[string]interface{}, name string) (int64, bool, error) {
+	value, ok := claims[name]
+	if !ok {
+		return 0, false, nil
+	}
+
+	var raw string
+	switch v := value.(type) {
+	case json.Number:
+		raw = string(v)
+	case string:
+		raw = v
+	default:
+		return 0, true, fmt.Errorf("Claim %q must be a number", name)
+	}
+
+	seconds, err := parseNumericDate(raw)
+	if nil != err {
+		return 0, true, fmt.Errorf("Claim %q must be a number", name)
+	}
+
+	return seconds, true, nil
+}
@@ -0,0 +1,92 @@
+package jwt
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWithTTLBudget(t *testing.T) {
+	now := time.Now().Unix()
+	iat := numericDate(now)
+	in := func(d time.Duration) *NumericDate {
+		return numericDate(now + int64(d/time.Second))
+	}
+
+	budgets := []Option{
+		WithTTLBudget("", TTLBudget{Max: time.Hour}),
+		WithTTLBudget("at+jwt", TTLBudget{Min: time.Minute, Max: 10 * time.Minute}),
+	}
+
+	tests := []struct {
+		name    string
+		clamp   bool
+		typ     string
+		claims  Claims
+		wantExp *NumericDate
+		wantErr bool
+	}{
+		{"Must accept a token within the default budget", false, "JWT", Claims{IssuedAt: iat, Expiration: in(time.Hour)}, in(time.Hour), false},
+		{"Must reject a token exceeding the default budget", false, "JWT", Claims{IssuedAt: iat, Expiration: in(2 * time.Hour)}, nil, true},
+		{"Must reject a token without an expiration", false, "JWT", Claims{IssuedAt: iat}, nil, true},
+		{"Must reject a token exceeding its type's budget", false, "at+jwt", Claims{IssuedAt: iat, Expiration: in(time.Hour)}, nil, true},
+		{"Must reject a token under its type's budget", false, "at+jwt", Claims{IssuedAt: iat, Expiration: in(time.Second)}, nil, true},
+		{"Must clamp a token exceeding its type's budget", true, "at+jwt", Claims{IssuedAt: iat, Expiration: in(time.Hour)}, in(10 * time.Minute), false},
+		{"Must clamp a token under its type's budget", true, "at+jwt", Claims{IssuedAt: iat, Expiration: in(time.Second)}, in(time.Minute), false},
+		{"Must reject a future issue time stretching the budget", false, "at+jwt", Claims{IssuedAt: in(2 * time.Hour), Expiration: in(2*time.Hour + 5*time.Minute)}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := budgets
+			if tt.clamp {
+				opts = append(opts[:len(opts):len(opts)], WithTTLClamping())
+			}
+
+			sv, err := NewJOSESignerVerifier(HS256, exampleKey, opts...)
+			if nil != err {
+				t.Fatalf("NewJOSESignerVerifier() error = %v", err)
+			}
+
+			rawToken, err := sv.GenerateToken(Header{Algorithm: string(HS256), Type: tt.typ}, tt.claims)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GenerateToken() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+
+			token, _ := GetRawTokenParts(rawToken)
+			var claims Claims
+			if err := GetClaims(token, &claims); nil != err {
+				t.Fatalf("GetClaims() error = %v", err)
+			}
+			if !reflect.DeepEqual(claims.Expiration, tt.wantExp) {
+				t.Errorf("GenerateToken() exp = %v, want %v", formatDate(claims.Expiration), formatDate(tt.wantExp))
+			}
+		})
+	}
+
+	if _, err := NewJOSESignerVerifier(HS256, exampleKey, WithTTLBudget("", TTLBudget{Min: time.Hour, Max: time.Minute})); nil == err {
+		t.Errorf("NewJOSESignerVerifier() expected error given an inverted budget")
+	}
+}
+
+func TestWithTTLBudget_FutureIssuedAt(t *testing.T) {
+	sv, _ := NewJOSESignerVerifier(HS256, exampleKey, WithTTLBudget("", TTLBudget{Max: 10 * time.Minute}), WithTTLClamping())
+	issuedAt := time.Now().Add(2 * time.Hour)
+
+	rawToken, err := sv.GenerateToken(Header{Algorithm: string(HS256)}, Claims{IssuedAt: NewNumericDate(issuedAt), Expiration: NewNumericDate(issuedAt.Add(5 * time.Minute))})
+	if nil != err {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	token, _ := GetRawTokenParts(rawToken)
+	var claims Claims
+	if err := GetClaims(token, &claims); nil != err {
+		t.Fatalf("GetClaims() error = %v", err)
+	}
+	if latest := NewNumericDate(time.Now().Add(10 * time.Minute)); *claims.Expiration > *latest {
+		t.Errorf("GenerateToken() exp = %v, want no later than %v", formatDate(claims.Expiration), formatDate(latest))
+	}
+}
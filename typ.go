@@ -0,0 +1,74 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Token types of the 'typ' header registered for JWTs.
+const (
+	// JWTType is the type of JWTs in general (RFC 7519, section 5.1).
+	JWTType = "JWT"
+
+	// AccessTokenType is the type of OAuth 2.0 access tokens (RFC 9068).
+	AccessTokenType = "at+jwt"
+)
+
+// UnexpectedTypeError is returned for tokens whose 'typ' header is not one
+// required by WithRequiredType.
+type UnexpectedTypeError struct {
+	Type     string
+	Expected []string
+}
+
+func (e *UnexpectedTypeError) Error() string {
+	return fmt.Sprintf("Token type %q is not one of %q", e.Type, e.Expected)
+}
+
+// WithRequiredType rejects tokens whose 'typ' header is not one of the
+// media types with an UnexpectedTypeError, so tokens of one kind, such as
+// ID tokens, can't be substituted for another, such as access tokens (RFC
+// 8725, section 3.11). Types are compared as RFC 7515 requires: ignoring
+// case, and with any "application/" prefix removed, so "at+jwt" matches
+// "application/at+JWT".
+func WithRequiredType(types ...string) Option {
+	return func(sv *JOSESignerVerifier) error {
+		if len(types) == 0 {
+			return errors.New("At least one token type is required")
+		}
+
+		for _, typ := range types {
+			if normalizeMediaType(typ) == "" {
+				return fmt.Errorf("Invalid token type %q", typ)
+			}
+		}
+
+		sv.requiredTypes = append([]string{}, types...)
+		return nil
+	}
+}
+
+// checkType returns an UnexpectedTypeError unless the header's 'typ' is
+// one of the required types, if any are.
+func (sv *JOSESignerVerifier) checkType(header Header) error {
+	if len(sv.requiredTypes) == 0 {
+		return nil
+	}
+
+	typ := normalizeMediaType(header.Type)
+	for _, required := range sv.requiredTypes {
+		if typ != "" && typ == normalizeMediaType(required) {
+			return nil
+		}
+	}
+
+	return &UnexpectedTypeError{Type: header.Type, Expected: sv.requiredTypes}
+}
+
+// normalizeMediaType lower cases a media type, removing any "application/"
+// prefix (RFC 7515, section 4.1.9).
+func normalizeMediaType(mediaType string) string {
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+	return strings.TrimPrefix(mediaType, "application/")
+}
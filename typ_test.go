@@ -0,0 +1,51 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWithRequiredType(t *testing.T) {
+	if _, err := NewJOSESignerVerifier(HS256, exampleKey, WithRequiredType()); nil == err {
+		t.Errorf("WithRequiredType() expected error without types")
+	}
+	if _, err := NewJOSESignerVerifier(HS256, exampleKey, WithRequiredType("application/")); nil == err {
+		t.Errorf("WithRequiredType() expected error for an empty type")
+	}
+
+	signer, _ := NewJOSESignerVerifier(HS256, exampleKey)
+
+	tests := []struct {
+		name      string
+		types     []string
+		typ       string
+		wantValid bool
+	}{
+		{"Must accept the required type", []string{JWTType}, "JWT", true},
+		{"Must accept any of the required types", []string{JWTType, AccessTokenType}, "at+jwt", true},
+		{"Must ignore case", []string{AccessTokenType}, "AT+JWT", true},
+		{"Must accept an application prefix on the token", []string{AccessTokenType}, "application/at+jwt", true},
+		{"Must accept an application prefix on the required type", []string{"application/secevent+jwt"}, "secevent+jwt", true},
+		{"Must reject another type", []string{AccessTokenType}, "JWT", false},
+		{"Must reject a missing type", []string{JWTType}, "", false},
+		{"Must not match a type by suffix", []string{"jwt"}, "at+jwt", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier, err := NewJOSESignerVerifier(HS256, exampleKey, WithRequiredType(tt.types...))
+			if nil != err {
+				t.Fatalf("NewJOSESignerVerifier() error = %v", err)
+			}
+			token, _ := signer.GenerateToken(Header{Algorithm: string(HS256), Type: tt.typ}, Claims{Subject: "alice"})
+
+			_, valid, err := verifier.VerifySignature(token)
+			if valid != tt.wantValid {
+				t.Errorf("VerifySignature() valid = %v, want %v", valid, tt.wantValid)
+			}
+			var typeErr *UnexpectedTypeError
+			if errors.As(err, &typeErr) == tt.wantValid {
+				t.Errorf("VerifySignature() error = %v, wantValid %v", err, tt.wantValid)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// WithExpiresIn sets the 'exp' claim of generated tokens to ttl after the
+// time they are signed, when the claim set has none.
+func WithExpiresIn(ttl time.Duration) Option {
+	return func(sv *JOSESignerVerifier) error {
+		if ttl <= 0 {
+			return errors.New("Expiration TTL must be positive")
+		}
+
+		sv.expiresIn = ttl
+		return nil
+	}
+}
+
+// WithNotBeforeSkew sets the 'nbf' claim of generated tokens to skew
+// before the time they are signed, when the claim set has none, so
+// verifiers whose clocks lag the issuer's still accept new tokens.
+func WithNotBeforeSkew(skew time.Duration) Option {
+	return func(sv *JOSESignerVerifier) error {
+		if skew < 0 {
+			return errors.New("Not before skew cannot be negative")
+		}
+
+		sv.notBeforeSkew = skew
+		sv.setNotBefore = true
+		return nil
+	}
+}
+
+// WithSigningClock sets the clock the 'exp' and 'nbf' claims of
+// WithExpiresIn and WithNotBeforeSkew are computed from, in place of the
+// system time.
+func WithSigningClock(now func() time.Time) Option {
+	return func(sv *JOSESignerVerifier) error {
+		if nil == now {
+			return errors.New("Signing clock cannot be nil")
+		}
+
+		sv.signingClock = now
+		return nil
+	}
+}
+
+// applyValidity sets the 'exp' and 'nbf' claims missing from the encoded
+// claim set, as configured.
+func (sv *JOSESignerVerifier) applyValidity(jwsPayload []byte) ([]byte, error) {
+	claims, err := decodeClaimsMap(jwsPayload)
+	if nil != err || nil == claims {
+		return nil, errors.New("Claims must be a JSON object to set 'exp' or 'nbf'")
+	}
+
+	now := time.Now()
+	if nil != sv.signingClock {
+		now = sv.signingClock()
+	}
+
+	changed := false
+	if _, ok := claims["exp"]; !ok && sv.expiresIn > 0 {
+		claims["exp"] = NewNumericDate(now.Add(sv.expiresIn))
+		changed = true
+	}
+	if _, ok := claims["nbf"]; !ok && sv.setNotBefore {
+		claims["nbf"] = NewNumericDate(now.Add(-sv.notBeforeSkew))
+		changed = true
+	}
+
+	if !changed {
+		return jwsPayload, nil
+	}
+
+	return json.Marshal(claims)
+}
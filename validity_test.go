@@ -0,0 +1,76 @@
+package jwt
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWithExpiresIn(t *testing.T) {
+	signedAt := time.Unix(1600000000, 0)
+	clock := WithSigningClock(func() time.Time { return signedAt })
+
+	if _, err := NewJOSESignerVerifier(HS256, exampleKey, WithExpiresIn(0)); nil == err {
+		t.Errorf("WithExpiresIn() expected error for a zero TTL")
+	}
+	if _, err := NewJOSESignerVerifier(HS256, exampleKey, WithNotBeforeSkew(-time.Second)); nil == err {
+		t.Errorf("WithNotBeforeSkew() expected error for a negative skew")
+	}
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		claims  interface{}
+		wantExp *NumericDate
+		wantNbf *NumericDate
+		wantErr bool
+	}{
+		{"Must set the expiration", []Option{clock, WithExpiresIn(time.Hour)}, Claims{Subject: "alice"}, numericDate(1600003600), nil, false},
+		{"Must set the not before time", []Option{clock, WithNotBeforeSkew(30 * time.Second)}, Claims{Subject: "alice"}, nil, numericDate(1599999970), false},
+		{"Must set the not before time without skew", []Option{clock, WithNotBeforeSkew(0)}, Claims{Subject: "alice"}, nil, numericDate(1600000000), false},
+		{"Must set both", []Option{clock, WithExpiresIn(time.Minute), WithNotBeforeSkew(time.Minute)}, Claims{Subject: "alice"}, numericDate(1600000060), numericDate(1599999940), false},
+		{"Must keep the caller's claims", []Option{clock, WithExpiresIn(time.Hour), WithNotBeforeSkew(time.Minute)}, Claims{Expiration: numericDate(1600000600), NotBefore: numericDate(1600000000)}, numericDate(1600000600), numericDate(1600000000), false},
+		{"Must set map claims", []Option{clock, WithExpiresIn(time.Hour)}, MapClaims{"sub": "alice"}, numericDate(1600003600), nil, false},
+		{"Must reject claims that are not an object", []Option{clock, WithExpiresIn(time.Hour)}, []string{"alice"}, nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sv, err := NewJOSESignerVerifier(HS256, exampleKey, tt.opts...)
+			if nil != err {
+				t.Fatalf("NewJOSESignerVerifier() error = %v", err)
+			}
+
+			rawToken, err := sv.GenerateToken(Header{Algorithm: string(HS256)}, tt.claims)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GenerateToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			token, _ := GetRawTokenParts(rawToken)
+			var claims Claims
+			if err := GetClaims(token, &claims); nil != err {
+				t.Fatalf("GetClaims() error = %v", err)
+			}
+			if !reflect.DeepEqual(claims.Expiration, tt.wantExp) || !reflect.DeepEqual(claims.NotBefore, tt.wantNbf) {
+				t.Errorf("GenerateToken() exp = %v, nbf = %v, want %v, %v", formatDate(claims.Expiration), formatDate(claims.NotBefore), formatDate(tt.wantExp), formatDate(tt.wantNbf))
+			}
+		})
+	}
+}
+
+func TestWithExpiresIn_Verify(t *testing.T) {
+	sv, _ := NewJOSESignerVerifier(HS256, exampleKey, WithExpiresIn(time.Minute), WithNotBeforeSkew(5*time.Second))
+	token, err := sv.GenerateToken(Header{Algorithm: string(HS256)}, Claims{Subject: "alice"})
+	if nil != err {
+		t.Fatalf("GenerateToken() error = %v", err)
+	}
+
+	if _, valid, err := sv.VerifyToken(token, nil); !valid || nil != err {
+		t.Errorf("VerifyToken() = %v, %v", valid, err)
+	}
+	if _, valid, _ := sv.VerifyToken(token, &ValidationClaims{Expiration: time.Now().Add(2 * time.Minute)}); valid {
+		t.Errorf("VerifyToken() = true after the expiration")
+	}
+}